@@ -0,0 +1,62 @@
+package main
+
+import (
+	"io"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/craine-io/openribcage/internal/agentlist"
+	"github.com/craine-io/openribcage/internal/config"
+	"github.com/craine-io/openribcage/pkg/a2a/types"
+	"github.com/craine-io/openribcage/pkg/registry"
+)
+
+// openRegistry loads the registry shared with openribcage discover
+func openRegistry() (*registry.Registry, error) {
+	return agentlist.Open(config.Default().Registry.CleanupInterval)
+}
+
+// recordAgents saves discovered agents to the local registry so that
+// discovery list can show them. Failures are logged rather than
+// returned, as the discovery itself succeeded.
+func recordAgents(results []scanResult) {
+	if len(results) == 0 {
+		return
+	}
+	reg, err := openRegistry()
+	if err != nil {
+		logrus.Warnf("Failed to open the local registry: %v", err)
+		return
+	}
+	for _, r := range results {
+		if err := agentlist.Record(reg, r.URL, r.Card); err != nil {
+			logrus.Warnf("Failed to save agent %s to the local registry: %v", r.URL, err)
+		}
+	}
+}
+
+// recordAgent saves a single validated agent to the local registry
+func recordAgent(agentURL string, card *types.AgentCard) {
+	recordAgents([]scanResult{{URL: agentURL, Card: card}})
+}
+
+// runList prints the agents in the local registry in the selected
+// --output format, hiding agents not seen within stale when it is positive.
+// When exportPath is set, the whole registry is also written to that file
+// as a JSON snapshot.
+func runList(stale time.Duration, exportPath string) error {
+	reg, err := openRegistry()
+	if err != nil {
+		return err
+	}
+
+	if exportPath != "" {
+		if err := agentlist.Export(reg, exportPath); err != nil {
+			return err
+		}
+	}
+
+	summaries := agentlist.Summaries(reg, stale)
+	return printOutput(summaries, func(w io.Writer) { agentlist.WriteTable(w, summaries) })
+}
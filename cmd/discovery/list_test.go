@@ -0,0 +1,115 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/craine-io/openribcage/pkg/a2a/types"
+	"github.com/craine-io/openribcage/pkg/agentcard"
+)
+
+// newCardServer starts an agent serving a minimal AgentCard named name
+func newCardServer(t *testing.T, name string) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != agentcard.WellKnownPath {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"name":         name,
+			"version":      "1.0.0",
+			"url":          "http://" + r.Host,
+			"capabilities": map[string]bool{"streaming": true},
+		})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// execute runs the discovery CLI with args and returns its standard
+// output. Flags not in args are reset to their defaults.
+func execute(t *testing.T, args ...string) string {
+	var out bytes.Buffer
+	stdout, outputFormat, listStale, listExport, validateStrict = &out, "table", 0, "", false
+	t.Cleanup(func() { stdout = os.Stdout })
+	rootCmd.SetArgs(args)
+	require.NoError(t, rootCmd.Execute())
+	return out.String()
+}
+
+// TestScanAndValidatePersist tests that scan and validate record agents
+// that list then shows
+func TestScanAndValidatePersist(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	scanned := newCardServer(t, "Scanned Agent")
+	validated := newCardServer(t, "Validated Agent")
+
+	execute(t, "scan", scanned.URL, "--paths", "")
+	execute(t, "validate", validated.URL)
+
+	var summaries []types.AgentSummary
+	require.NoError(t, json.Unmarshal([]byte(execute(t, "list", "-o", "json")), &summaries))
+	require.Len(t, summaries, 2)
+	byName := map[string]types.AgentSummary{}
+	for _, summary := range summaries {
+		byName[summary.Name] = summary
+	}
+	assert.Equal(t, scanned.URL, byName["Scanned Agent"].URL)
+	assert.Equal(t, validated.URL, byName["Validated Agent"].URL)
+	assert.True(t, byName["Scanned Agent"].Streaming)
+	assert.Equal(t, types.AgentStatusOnline, byName["Validated Agent"].Status)
+
+	table := execute(t, "list")
+	assert.Contains(t, table, "NAME")
+	assert.Contains(t, table, "Scanned Agent")
+	assert.Contains(t, table, "streaming")
+
+	// Rescanning updates the agent instead of adding it twice
+	execute(t, "scan", scanned.URL, "--paths", "")
+	require.NoError(t, json.Unmarshal([]byte(execute(t, "list", "-o", "json")), &summaries))
+	assert.Len(t, summaries, 2)
+}
+
+// TestListStale tests that --stale hides agents not seen recently
+func TestListStale(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	reg, err := openRegistry()
+	require.NoError(t, err)
+	require.NoError(t, reg.Register(&types.Agent{ID: "fresh", Name: "fresh", URL: "http://fresh", LastSeen: time.Now()}))
+	require.NoError(t, reg.Register(&types.Agent{ID: "old", Name: "old", URL: "http://old", LastSeen: time.Now().Add(-48 * time.Hour)}))
+
+	var summaries []types.AgentSummary
+	require.NoError(t, json.Unmarshal([]byte(execute(t, "list", "--stale", "24h", "-o", "json")), &summaries))
+	require.Len(t, summaries, 1)
+	assert.Equal(t, "fresh", summaries[0].ID)
+}
+
+// TestListExport tests that --export writes the registry snapshot
+// alongside the listing
+func TestListExport(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	reg, err := openRegistry()
+	require.NoError(t, err)
+	require.NoError(t, reg.Register(&types.Agent{ID: "k8s", Name: "k8s", URL: "http://k8s:8083", Status: types.AgentStatusOnline}))
+
+	path := filepath.Join(t.TempDir(), "agents-snapshot.json")
+	assert.Contains(t, execute(t, "list", "--export", path), "http://k8s:8083")
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var agents []*types.Agent
+	require.NoError(t, json.Unmarshal(data, &agents))
+	require.Len(t, agents, 1)
+	assert.Equal(t, "k8s", agents[0].ID)
+	assert.Equal(t, types.AgentStatusOnline, agents[0].Status)
+}
@@ -6,11 +6,16 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"sort"
+	"time"
 
-	"github.com/spf13/cobra"
 	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
 
 	"github.com/craine-io/openribcage/pkg/agentcard"
 )
@@ -20,6 +25,19 @@ var (
 	outputFormat string
 	timeout      int
 	verbose      bool
+
+	// Scan flags
+	scanPorts       []int
+	scanPaths       []string
+	scanConcurrency int
+	scanHeadCheck   bool
+
+	// Validate flags
+	validateStrict bool
+
+	// List flags
+	listStale  time.Duration
+	listExport string
 )
 
 // rootCmd represents the base command
@@ -36,19 +54,60 @@ Useful for development, testing, and operational monitoring.`,
 
 // scanCmd scans for agents
 var scanCmd = &cobra.Command{
-	Use:   "scan [base-url]",
+	Use:   "scan [base-url | -]",
 	Short: "Scan for A2A agents",
 	Long: `Scan for A2A agents starting from a base URL.
-Discovered agents will be validated and their capabilities parsed.`,
-	Args: cobra.ExactArgs(1),
+Discovered agents will be validated and their capabilities parsed,
+and saved to the local registry; see discovery list.
+
+Candidate agent URLs are built by combining each --ports value with each
+--paths value on the base URL's host. Each candidate is probed for an
+AgentCard with the per-probe --timeout.
+
+Examples:
+  # Probe the default A2A paths on the base URL
+  discovery scan http://localhost:8083
+
+  # Probe several ports and emit JSON
+  discovery scan http://10.0.0.5 --ports 8080,8083 -o json
+
+  # Discover agent URLs listed one per line on stdin
+  cat agents.txt | discovery scan -`,
+	Args: cobra.MaximumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
-		baseURL := args[0]
-		logrus.Infof("Scanning for A2A agents from base URL: %s", baseURL)
-		
-		// TODO: Implement agent scanning
-		// This will be implemented in Issue #3
-		fmt.Printf("Agent scanning functionality coming in Issue #3!\n")
-		fmt.Printf("Will scan: %s\n", baseURL)
+		var candidates []string
+		var err error
+
+		if (len(args) == 1 && args[0] == "-") || (len(args) == 0 && stdinIsPipe()) {
+			// URLs from stdin are probed as-is, without port/path expansion
+			logrus.Info("Reading agent URLs from stdin")
+			candidates, err = readURLs(os.Stdin)
+		} else if len(args) == 1 {
+			logrus.Infof("Scanning for A2A agents from base URL: %s", args[0])
+			candidates, err = candidateURLs(args[0], scanPorts, scanPaths)
+		} else {
+			err = fmt.Errorf("a base URL or '-' (read URLs from stdin) is required")
+		}
+		if err != nil {
+			logrus.Errorf("Agent scan failed: %v", err)
+			os.Exit(1)
+		}
+		logrus.Debugf("Probing %d candidate URLs", len(candidates))
+
+		probeTimeout := time.Duration(timeout) * time.Second
+		results, failures := scanURLs(context.Background(), candidates, scanConcurrency, probeTimeout)
+
+		sort.Slice(results, func(i, j int) bool { return results[i].URL < results[j].URL })
+		recordAgents(results)
+		if err := printOutput(results, func(w io.Writer) { writeScanTable(w, results) }); err != nil {
+			logrus.Errorf("%v", err)
+			os.Exit(1)
+		}
+
+		for _, f := range failures {
+			logrus.Debugf("Probe failed: %s: %v", f.URL, f.Err)
+		}
+		fmt.Fprintf(os.Stderr, "Found %d agent(s); %d of %d probes failed\n", len(results), len(failures), len(candidates))
 	},
 }
 
@@ -57,31 +116,110 @@ var validateCmd = &cobra.Command{
 	Use:   "validate [agent-url]",
 	Short: "Validate an AgentCard",
 	Long: `Validate an A2A AgentCard by fetching and parsing the
-.well-known/agent.json endpoint from the specified agent URL.`,
+.well-known/agent.json endpoint from the specified agent URL.
+
+With --strict, the card is also checked against the A2A AgentCard JSON
+Schema and every violation is reported. Valid agents are saved to the
+local registry; see discovery list.`,
 	Args: cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		agentURL := args[0]
 		logrus.Infof("Validating AgentCard at: %s", agentURL)
-		
-		// TODO: Implement AgentCard validation
-		// This will be implemented in Issue #3
-		fmt.Printf("AgentCard validation functionality coming in Issue #3!\n")
-		fmt.Printf("Will validate: %s/.well-known/agent.json\n", agentURL)
+
+		requestTimeout := time.Duration(timeout) * time.Second
+		discoverer := agentcard.NewDiscoverer(requestTimeout)
+
+		ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+		defer cancel()
+
+		if !validateStrict {
+			card, err := discoverer.Discover(ctx, agentURL)
+			if err != nil {
+				logrus.Errorf("AgentCard is invalid: %v", err)
+				os.Exit(1)
+			}
+			fmt.Printf("AgentCard is valid: %s (version: %s)\n", card.Name, card.Version)
+			recordAgent(agentURL, card)
+			return
+		}
+
+		violations, err := runValidateStrict(ctx, discoverer, agentURL)
+		if err != nil {
+			logrus.Errorf("%v", err)
+			os.Exit(1)
+		}
+		if len(violations) > 0 {
+			fmt.Fprintf(os.Stderr, "AgentCard has %d schema violation(s)\n", len(violations))
+			os.Exit(1)
+		}
+		fmt.Fprintln(os.Stderr, "AgentCard conforms to the A2A schema")
 	},
 }
 
+// runValidateStrict fetches the AgentCard at agentURL, checks it against
+// the A2A schema and prints the violations in the selected --output format.
+// A conforming card is recorded in the local registry.
+func runValidateStrict(ctx context.Context, discoverer *agentcard.Discoverer, agentURL string) ([]agentcard.SchemaViolation, error) {
+	raw, err := discoverer.FetchRaw(ctx, agentURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch AgentCard: %w", err)
+	}
+
+	violations := []agentcard.SchemaViolation{}
+	var schemaErr *agentcard.SchemaError
+	if err := discoverer.ValidateStrict(nil, raw); errors.As(err, &schemaErr) {
+		violations = schemaErr.Violations
+	} else if err != nil {
+		return nil, fmt.Errorf("strict validation failed: %w", err)
+	}
+
+	if err := printOutput(violations, func(w io.Writer) { writeViolationTable(w, violations) }); err != nil {
+		return nil, err
+	}
+	if len(violations) == 0 {
+		if card, err := discoverer.Parse(raw); err == nil {
+			recordAgent(agentURL, card)
+		}
+	}
+	return violations, nil
+}
+
+// writeViolationTable renders strict validation violations as a table
+func writeViolationTable(w io.Writer, violations []agentcard.SchemaViolation) {
+	fmt.Fprintln(w, "POINTER\tVIOLATION")
+	for _, v := range violations {
+		pointer := v.Pointer
+		if pointer == "" {
+			pointer = "/"
+		}
+		fmt.Fprintf(w, "%s\t%s\n", pointer, v.Message)
+	}
+}
+
 // listCmd lists discovered agents
 var listCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List discovered agents",
 	Long: `List all agents that have been discovered and registered
-in the local agent registry.`,
+in the local agent registry (~/.openribcage/agents.json). Agents are
+recorded by scan, validate and openribcage discover.
+
+Shows each agent's name, URL, status, capabilities and when it was last
+seen; --stale hides agents not seen within the given duration.
+
+With --export, the full registry, including AgentCards and status, is
+also written to a JSON snapshot that can be loaded with Registry.Import.
+
+Examples:
+  discovery list
+  discovery list --stale 24h -o json
+  discovery list --export agents-snapshot.json`,
+	Args: cobra.NoArgs,
 	Run: func(cmd *cobra.Command, args []string) {
-		logrus.Info("Listing discovered agents...")
-		
-		// TODO: Implement agent listing
-		// This will be implemented in Issue #3
-		fmt.Println("Agent listing functionality coming in Issue #3!")
+		if err := runList(listStale, listExport); err != nil {
+			logrus.Errorf("%v", err)
+			os.Exit(1)
+		}
 	},
 }
 
@@ -91,6 +229,17 @@ func init() {
 	rootCmd.PersistentFlags().StringVarP(&outputFormat, "output", "o", "table", "output format (table, json, yaml)")
 	rootCmd.PersistentFlags().IntVarP(&timeout, "timeout", "t", 30, "request timeout in seconds")
 
+	// Scan command flags
+	scanCmd.Flags().IntSliceVar(&scanPorts, "ports", nil, "ports to probe (default: the base URL's port)")
+	scanCmd.Flags().StringSliceVar(&scanPaths, "paths", []string{"", "/a2a", "/api/a2a"}, "agent paths to probe on each port")
+	scanCmd.Flags().IntVar(&scanConcurrency, "concurrency", 8, "maximum number of concurrent probes")
+	validateCmd.Flags().BoolVar(&validateStrict, "strict", false, "check the AgentCard against the A2A JSON Schema")
+	scanCmd.Flags().BoolVar(&scanHeadCheck, "head-check", false, "issue a HEAD request before fetching each AgentCard")
+
+	// List command flags
+	listCmd.Flags().DurationVar(&listStale, "stale", 0, "hide agents not seen within this duration (0 shows all)")
+	listCmd.Flags().StringVar(&listExport, "export", "", "also write the registry to this file as a JSON snapshot")
+
 	// Add subcommands
 	rootCmd.AddCommand(scanCmd)
 	rootCmd.AddCommand(validateCmd)
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/craine-io/openribcage/pkg/agentcard"
+	"github.com/craine-io/openribcage/test/fixtures"
+)
+
+// newStrictCardServer starts an agent serving the full fixture card with
+// capabilities replaced
+func newStrictCardServer(t *testing.T, capabilities map[string]bool) *httptest.Server {
+	raw, err := fixtures.Raw("full")
+	require.NoError(t, err)
+	var card map[string]interface{}
+	require.NoError(t, json.Unmarshal(raw, &card))
+	card["capabilities"] = capabilities
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(card)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// TestValidateStrict tests that validate --strict accepts a conforming card
+// and records it
+func TestValidateStrict(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	server := newStrictCardServer(t, map[string]bool{"streaming": true})
+
+	var violations []agentcard.SchemaViolation
+	require.NoError(t, json.Unmarshal([]byte(execute(t, "validate", server.URL, "--strict", "-o", "json")), &violations))
+	assert.Empty(t, violations)
+	assert.Contains(t, execute(t, "list"), server.URL)
+}
+
+// TestValidateStrictUnknownKey tests that validate --strict reports
+// properties the schema does not allow and does not record the agent
+func TestValidateStrictUnknownKey(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	server := newStrictCardServer(t, map[string]bool{"streaming": true, "teleport": true})
+
+	var out bytes.Buffer
+	stdout, outputFormat = &out, "json"
+	t.Cleanup(func() { stdout, outputFormat = os.Stdout, "table" })
+
+	violations, err := runValidateStrict(context.Background(), agentcard.NewDiscoverer(5*time.Second), server.URL)
+	require.NoError(t, err)
+	require.Len(t, violations, 1)
+	assert.Equal(t, "/capabilities/teleport", violations[0].Pointer)
+	assert.Equal(t, "property is not allowed", violations[0].Message)
+
+	var printed []agentcard.SchemaViolation
+	require.NoError(t, json.Unmarshal(out.Bytes(), &printed))
+	assert.Equal(t, violations, printed)
+	assert.NotContains(t, execute(t, "list"), server.URL)
+}
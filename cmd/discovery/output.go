@@ -0,0 +1,49 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"gopkg.in/yaml.v3"
+)
+
+// stdout is where command output is written
+var stdout io.Writer = os.Stdout
+
+// printOutput writes v to stdout in the selected --output format.
+// The table format is delegated to writeTable.
+func printOutput(v interface{}, writeTable func(w io.Writer)) error {
+	switch outputFormat {
+	case "json":
+		data, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON output: %w", err)
+		}
+		fmt.Fprintln(stdout, string(data))
+	case "yaml":
+		// Round-trip through JSON so YAML keys match the JSON field names
+		data, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Errorf("failed to marshal YAML output: %w", err)
+		}
+		var generic interface{}
+		if err := json.Unmarshal(data, &generic); err != nil {
+			return fmt.Errorf("failed to marshal YAML output: %w", err)
+		}
+		out, err := yaml.Marshal(generic)
+		if err != nil {
+			return fmt.Errorf("failed to marshal YAML output: %w", err)
+		}
+		fmt.Fprint(stdout, string(out))
+	case "table":
+		w := tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0)
+		writeTable(w)
+		return w.Flush()
+	default:
+		return fmt.Errorf("unsupported output format: %s (supported: table, json, yaml)", outputFormat)
+	}
+	return nil
+}
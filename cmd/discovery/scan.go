@@ -0,0 +1,144 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/craine-io/openribcage/pkg/a2a/retry"
+	"github.com/craine-io/openribcage/pkg/a2a/types"
+	"github.com/craine-io/openribcage/pkg/agentcard"
+)
+
+// scanResult is a single agent found during a scan
+type scanResult struct {
+	URL  string           `json:"url"`
+	Card *types.AgentCard `json:"card"`
+}
+
+// scanFailure is a probe that did not yield a valid AgentCard
+type scanFailure struct {
+	URL string
+	Err error
+}
+
+// candidateURLs derives the agent URLs to probe from a base URL by
+// combining each port with each path. With no ports, the base URL's
+// own port is used.
+func candidateURLs(baseURL string, ports []int, paths []string) ([]string, error) {
+	if !strings.HasPrefix(baseURL, "http://") && !strings.HasPrefix(baseURL, "https://") {
+		baseURL = "http://" + baseURL
+	}
+
+	base, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base URL: %w", err)
+	}
+	if base.Hostname() == "" {
+		return nil, fmt.Errorf("invalid base URL: missing host")
+	}
+
+	hosts := []string{base.Host}
+	if len(ports) > 0 {
+		hosts = hosts[:0]
+		for _, port := range ports {
+			hosts = append(hosts, net.JoinHostPort(base.Hostname(), strconv.Itoa(port)))
+		}
+	}
+	if len(paths) == 0 {
+		paths = []string{""}
+	}
+
+	seen := make(map[string]bool)
+	var candidates []string
+	for _, host := range hosts {
+		for _, path := range paths {
+			u := *base
+			u.Host = host
+			u.Path = strings.TrimSuffix(base.Path, "/") + "/" + strings.Trim(path, "/")
+			u.Path = strings.TrimSuffix(u.Path, "/")
+
+			candidate := u.String()
+			if !seen[candidate] {
+				seen[candidate] = true
+				candidates = append(candidates, candidate)
+			}
+		}
+	}
+
+	return candidates, nil
+}
+
+// readURLs reads agent URLs from r, one per line. Blank lines and
+// lines starting with '#' are skipped.
+func readURLs(r io.Reader) ([]string, error) {
+	var urls []string
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		urls = append(urls, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read URLs: %w", err)
+	}
+
+	return urls, nil
+}
+
+// stdinIsPipe reports whether standard input is redirected from a pipe or file
+func stdinIsPipe() bool {
+	info, err := os.Stdin.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice == 0
+}
+
+// scanURLs probes each URL for an AgentCard using a bounded worker pool.
+// Each probe gets its own timeout and no retries, so unresponsive
+// candidates fail fast.
+func scanURLs(ctx context.Context, urls []string, workers int, probeTimeout time.Duration) ([]scanResult, []scanFailure) {
+	discoverer := agentcard.NewDiscoverer(probeTimeout)
+	discoverer.SetRetryPolicy(retry.Policy{})
+	discoverer.SetHeadCheck(scanHeadCheck)
+	discoverer.SetConcurrency(workers)
+
+	cards, errs := discoverer.DiscoverMany(ctx, urls)
+
+	results := make([]scanResult, 0, len(cards))
+	for u, card := range cards {
+		results = append(results, scanResult{URL: u, Card: card})
+	}
+
+	failures := make([]scanFailure, 0, len(errs))
+	for u, err := range errs {
+		failures = append(failures, scanFailure{URL: u, Err: err})
+	}
+	sort.Slice(failures, func(i, j int) bool { return failures[i].URL < failures[j].URL })
+
+	return results, failures
+}
+
+// writeScanTable renders scan results as a table
+func writeScanTable(w io.Writer, results []scanResult) {
+	fmt.Fprintln(w, "URL\tNAME\tVERSION\tCAPABILITIES")
+	for _, r := range results {
+		caps := strings.Join(r.Card.GetCapabilities(), ",")
+		if caps == "" {
+			caps = "-"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", r.URL, r.Card.Name, r.Card.Version, caps)
+	}
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestReadURLs tests that blank lines and comments are skipped and that
+// URLs are trimmed
+func TestReadURLs(t *testing.T) {
+	input := "# agents\nhttp://a.example.com\n\n  http://b.example.com:8083/api  \n\t# disabled\n"
+	urls, err := readURLs(strings.NewReader(input))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"http://a.example.com", "http://b.example.com:8083/api"}, urls)
+
+	urls, err = readURLs(strings.NewReader(""))
+	require.NoError(t, err)
+	assert.Empty(t, urls)
+}
+
+// TestScanStdin tests that scan - probes the URLs read from stdin as-is
+func TestScanStdin(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	first := newCardServer(t, "First Agent")
+	second := newCardServer(t, "Second Agent")
+
+	path := filepath.Join(t.TempDir(), "agents.txt")
+	require.NoError(t, os.WriteFile(path, []byte("# agents\n"+first.URL+"\n"+second.URL+"\n"), 0o644))
+	stdin, err := os.Open(path)
+	require.NoError(t, err)
+	defer stdin.Close()
+	os.Stdin, stdin = stdin, os.Stdin
+	defer func() { os.Stdin = stdin }()
+
+	var results []scanResult
+	require.NoError(t, json.Unmarshal([]byte(execute(t, "scan", "-", "-o", "json")), &results))
+	require.Len(t, results, 2)
+	byURL := map[string]string{}
+	for _, result := range results {
+		byURL[result.URL] = result.Card.Name
+	}
+	assert.Equal(t, map[string]string{first.URL: "First Agent", second.URL: "Second Agent"}, byURL)
+}
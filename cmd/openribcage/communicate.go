@@ -0,0 +1,208 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/craine-io/openribcage/internal/auth"
+	"github.com/craine-io/openribcage/internal/config"
+	"github.com/craine-io/openribcage/internal/logging"
+	"github.com/craine-io/openribcage/pkg/a2a/client"
+	"github.com/craine-io/openribcage/pkg/a2a/types"
+	"github.com/craine-io/openribcage/pkg/agentcard"
+)
+
+// capabilityError reports that an agent lacks a capability a command needs
+type capabilityError struct {
+	Agent      string
+	Capability string
+}
+
+// Error implements the error interface
+func (e *capabilityError) Error() string {
+	return fmt.Sprintf("agent %s does not support %s (use --force to try anyway)", e.Agent, e.Capability)
+}
+
+// preflight discovers the agent's card and checks that it advertises every
+// required capability. With force set, failures are logged and ignored.
+func preflight(ctx context.Context, agentURL string, creds *auth.Credentials, force bool, required ...string) error {
+	if len(required) == 0 {
+		return nil
+	}
+
+	discoverer, err := newDiscoverer(communicateTimeout)
+	if err != nil {
+		return err
+	}
+	discoverer.SetCredentials(creds)
+
+	err = checkCapabilities(ctx, discoverer, agentURL, required)
+	if err != nil && force {
+		logrus.Warnf("Ignoring failed capability check (--force): %v", err)
+		return nil
+	}
+	return err
+}
+
+// checkCapabilities returns a *capabilityError for the first required
+// capability missing from the agent's card
+func checkCapabilities(ctx context.Context, discoverer *agentcard.Discoverer, agentURL string, required []string) error {
+	card, err := discoverer.Discover(ctx, agentURL)
+	if err != nil {
+		return fmt.Errorf("capability check failed: %w", err)
+	}
+
+	for _, capability := range required {
+		if !card.Capabilities.Has(capability) {
+			return &capabilityError{Agent: card.Name, Capability: capability}
+		}
+	}
+	return nil
+}
+
+// runCommunicate sends text to the agent at agentURL and prints the reply
+func runCommunicate(agentURL, text string) error {
+	creds, err := auth.NewAuthenticator().LoadCredentialsFromEnv(envPrefix)
+	if err != nil {
+		return fmt.Errorf("failed to load credentials: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), communicateTimeout)
+	defer cancel()
+
+	var required []string
+	if communicateStream && !communicateDryRun {
+		required = append(required, "streaming")
+	}
+	if err := preflight(ctx, agentURL, creds, communicateForce, required...); err != nil {
+		return err
+	}
+
+	clientConfig := newClientConfig(agentURL, communicateTimeout, creds)
+	clientConfig.DryRun = communicateDryRun
+	a2aClient := client.New(clientConfig)
+	defer a2aClient.Close()
+
+	req := types.NewTaskRequest(&types.Message{
+		Role:  "user",
+		Parts: []types.Part{{Type: "text", Text: text}},
+	})
+	logrus.Debugf("Sending task %s to %s", req.ID, agentURL)
+
+	if communicateStream {
+		return streamTask(ctx, a2aClient, req)
+	}
+
+	resp, err := a2aClient.SendTask(ctx, "", req)
+	if dryRun := (*client.DryRunError)(nil); errors.As(err, &dryRun) {
+		return printRequest(dryRun)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to send task: %w", err)
+	}
+
+	fmt.Printf("Task %s: %s\n", resp.ID, resp.Status)
+	if resp.Message != nil {
+		printMessage(resp.Message)
+	}
+	printArtifacts(resp.Artifacts)
+	if resp.Error != nil {
+		retry := ""
+		if resp.Error.IsRetryable() {
+			retry = " (retryable)"
+		}
+		fmt.Printf("Error: %s%s\n", resp.Error.Message, retry)
+	}
+	return nil
+}
+
+// streamTask prints each event of a streamed task as it arrives
+func streamTask(ctx context.Context, a2aClient *client.Client, req *types.TaskRequest) error {
+	events, errs := a2aClient.StreamTask(ctx, "", req)
+	var artifacts []types.Artifact
+	for event := range events {
+		if artifact, ok := event.Artifact(); ok {
+			artifacts = append(artifacts, *artifact)
+		}
+		data, err := json.Marshal(event.Data)
+		if err != nil {
+			data = []byte(fmt.Sprint(event.Data))
+		}
+		fmt.Printf("[%s] %s\n", event.Type, data)
+	}
+	if err := <-errs; err != nil {
+		if dryRun := (*client.DryRunError)(nil); errors.As(err, &dryRun) {
+			return printRequest(dryRun)
+		}
+		return fmt.Errorf("stream failed: %w", err)
+	}
+	printArtifacts(types.MergeArtifacts(artifacts))
+	return nil
+}
+
+// printRequest prints the request of a dry run with its headers sorted and
+// sensitive values redacted, followed by the indented JSON-RPC body
+func printRequest(dryRun *client.DryRunError) error {
+	req := dryRun.Request
+	fmt.Printf("%s %s\n", req.Method, req.URL)
+
+	headers := logging.NewRedactor(config.Get().Logging.RedactFields...).Headers(req.Header)
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		for _, value := range headers[name] {
+			fmt.Printf("%s: %s\n", name, value)
+		}
+	}
+
+	var body bytes.Buffer
+	if err := json.Indent(&body, dryRun.Body, "", "  "); err != nil {
+		return fmt.Errorf("failed to format request body: %w", err)
+	}
+	fmt.Printf("\n%s\n", body.String())
+	return nil
+}
+
+// printArtifacts lists the names of a task's artifacts
+func printArtifacts(artifacts []types.Artifact) {
+	if len(artifacts) == 0 {
+		return
+	}
+	fmt.Println("Artifacts:")
+	for _, artifact := range artifacts {
+		name := artifact.Name
+		if name == "" {
+			name = fmt.Sprintf("(artifact %d)", artifact.Index)
+		}
+		fmt.Printf("  - %s (%d parts)\n", name, len(artifact.Parts))
+	}
+}
+
+// printMessage prints the text parts of a message, followed by a summary
+// line for each other part
+func printMessage(msg *types.Message) {
+	var texts, others []string
+	for _, part := range msg.Parts {
+		if part.Kind() != types.PartKindText {
+			others = append(others, part.Summary())
+		} else if part.Text != "" {
+			texts = append(texts, part.Text)
+		}
+	}
+	if len(texts) > 0 {
+		fmt.Printf("%s: %s\n", msg.Role, strings.Join(texts, "\n"))
+	}
+	for _, summary := range others {
+		fmt.Printf("  [%s]\n", summary)
+	}
+}
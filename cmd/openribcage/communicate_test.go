@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/craine-io/openribcage/internal/config"
+)
+
+// newCapabilityAgent starts an agent whose card advertises streaming only
+// when streaming is set
+func newCapabilityAgent(t *testing.T, streaming bool) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"name":         "capability-agent",
+			"version":      "1.0.0",
+			"url":          "http://" + r.Host,
+			"capabilities": map[string]bool{"streaming": streaming},
+		})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// TestPreflight tests that the capability pre-flight check rejects agents
+// without a required capability unless forced
+func TestPreflight(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	require.NoError(t, config.Init(""))
+	ctx := context.Background()
+
+	streaming := newCapabilityAgent(t, true)
+	assert.NoError(t, preflight(ctx, streaming.URL, nil, false, "streaming"))
+
+	plain := newCapabilityAgent(t, false)
+	err := preflight(ctx, plain.URL, nil, false, "streaming")
+	var capErr *capabilityError
+	require.True(t, errors.As(err, &capErr))
+	assert.Equal(t, &capabilityError{Agent: "capability-agent", Capability: "streaming"}, capErr)
+	assert.ErrorContains(t, err, "use --force to try anyway")
+
+	assert.NoError(t, preflight(ctx, plain.URL, nil, true, "streaming"))
+	assert.NoError(t, preflight(ctx, "http://127.0.0.1:1", nil, false))
+}
+
+// TestPreflightDiscoveryError tests that an unreachable card fails the
+// check unless forced
+func TestPreflightDiscoveryError(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	require.NoError(t, config.Init(""))
+	missing := httptest.NewServer(http.NotFoundHandler())
+	defer missing.Close()
+
+	err := preflight(context.Background(), missing.URL, nil, false, "streaming")
+	assert.ErrorContains(t, err, "capability check failed")
+	assert.NoError(t, preflight(context.Background(), missing.URL, nil, true, "streaming"))
+}
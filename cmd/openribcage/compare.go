@@ -0,0 +1,217 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/craine-io/openribcage/internal/auth"
+	"github.com/craine-io/openribcage/pkg/a2a/client"
+	"github.com/craine-io/openribcage/pkg/a2a/types"
+	"github.com/craine-io/openribcage/pkg/jsondiff"
+)
+
+// comparisonRow is one compared attribute of two agents
+type comparisonRow struct {
+	Field   string `json:"field"`
+	A       string `json:"a"`
+	B       string `json:"b"`
+	Differs bool   `json:"differs"`
+}
+
+// comparison is the side-by-side result of comparing two AgentCards
+type comparison struct {
+	A           string                `json:"a"`
+	B           string                `json:"b"`
+	Rows        []comparisonRow       `json:"rows"`
+	Differences []jsondiff.Difference `json:"differences"`
+}
+
+// runCompare discovers the agents at urlA and urlB and prints a comparison
+func runCompare(w io.Writer, urlA, urlB, output string) error {
+	if output != "table" && output != "json" {
+		return fmt.Errorf("unsupported output format: %s (supported: table, json)", output)
+	}
+
+	creds, err := auth.NewAuthenticator().LoadCredentialsFromEnv(envPrefix)
+	if err != nil {
+		return fmt.Errorf("failed to load credentials: %w", err)
+	}
+
+	discoverer, err := newDiscoverer(compareTimeout)
+	if err != nil {
+		return err
+	}
+	discoverer.SetCredentials(creds)
+
+	ctx, cancel := context.WithTimeout(context.Background(), compareTimeout)
+	defer cancel()
+
+	cards, errs := discoverer.DiscoverMany(ctx, []string{urlA, urlB})
+	for _, u := range []string{urlA, urlB} {
+		if err := errs[u]; err != nil {
+			return fmt.Errorf("failed to discover %s: %w", u, err)
+		}
+	}
+
+	result, err := compareCards(cards[urlA], cards[urlB])
+	if err != nil {
+		return err
+	}
+
+	if output == "json" {
+		data, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal comparison to JSON: %w", err)
+		}
+		fmt.Fprintln(w, string(data))
+		return nil
+	}
+	writeComparisonTable(w, result)
+	return nil
+}
+
+// compareCards compares two AgentCards attribute by attribute. The full
+// structural differences are computed with jsondiff.
+func compareCards(a, b *types.AgentCard) (*comparison, error) {
+	result := &comparison{A: a.Name, B: b.Name}
+	add := func(field, valueA, valueB string) {
+		result.Rows = append(result.Rows, comparisonRow{Field: field, A: valueA, B: valueB, Differs: valueA != valueB})
+	}
+
+	add("name", a.Name, b.Name)
+	add("version", a.Version, b.Version)
+	add("url", a.URL, b.URL)
+	for _, capability := range []string{"streaming", "pushNotifications", "stateTransitionHistory"} {
+		add("capability."+capability, yesNo(a.Capabilities.Has(capability)), yesNo(b.Capabilities.Has(capability)))
+	}
+	add("inputModes", strings.Join(a.DefaultInputModes, ","), strings.Join(b.DefaultInputModes, ","))
+	add("outputModes", strings.Join(a.DefaultOutputModes, ","), strings.Join(b.DefaultOutputModes, ","))
+	add("auth", authType(a), authType(b))
+
+	skillsA, skillsB := skillNames(a), skillNames(b)
+	for _, id := range unionSorted(skillsA, skillsB) {
+		add("skill."+id, presence(skillsA, id), presence(skillsB, id))
+	}
+
+	methodsA, methodsB := supportedMethods(a), supportedMethods(b)
+	for _, method := range unionSorted(methodsA, methodsB) {
+		add("method."+method, yesNo(methodsA[method] != ""), yesNo(methodsB[method] != ""))
+	}
+
+	docA, err := toJSONValue(a)
+	if err != nil {
+		return nil, err
+	}
+	docB, err := toJSONValue(b)
+	if err != nil {
+		return nil, err
+	}
+	result.Differences = jsondiff.CompareValues(docA, docB, nil)
+	if result.Differences == nil {
+		result.Differences = []jsondiff.Difference{}
+	}
+	return result, nil
+}
+
+// writeComparisonTable renders a comparison, marking differing rows with *
+func writeComparisonTable(w io.Writer, result *comparison) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, " \tFIELD\t%s\t%s\n", dash(result.A), dash(result.B))
+	differing := 0
+	for _, row := range result.Rows {
+		marker := " "
+		if row.Differs {
+			marker = "*"
+			differing++
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", marker, row.Field, dash(row.A), dash(row.B))
+	}
+	tw.Flush()
+	fmt.Fprintf(w, "\n%d of %d fields differ\n", differing, len(result.Rows))
+}
+
+// skillNames maps skill IDs (or names, when unset) to display names
+func skillNames(card *types.AgentCard) map[string]string {
+	skills := make(map[string]string, len(card.Skills))
+	for _, skill := range card.Skills {
+		id := skill.ID
+		if id == "" {
+			id = skill.Name
+		}
+		skills[id] = skill.Name
+	}
+	return skills
+}
+
+// supportedMethods maps the implemented A2A methods usable with card to
+// their client functions; streaming methods need the streaming capability
+func supportedMethods(card *types.AgentCard) map[string]string {
+	methods := make(map[string]string)
+	for _, m := range client.Methods() {
+		if m.Implemented && (!m.Streaming || card.Capabilities.Streaming) {
+			methods[m.Method] = m.Function
+		}
+	}
+	return methods
+}
+
+// authType returns the authentication type a card advertises
+func authType(card *types.AgentCard) string {
+	if card.Authentication == nil {
+		return ""
+	}
+	return card.Authentication.Type
+}
+
+// unionSorted returns the sorted keys present in either map
+func unionSorted(a, b map[string]string) []string {
+	keys := make([]string, 0, len(a)+len(b))
+	for k := range a {
+		keys = append(keys, k)
+	}
+	for k := range b {
+		if _, ok := a[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// presence renders whether key is in m, using its value when non-empty
+func presence(m map[string]string, key string) string {
+	value, ok := m[key]
+	if !ok {
+		return ""
+	}
+	if value == "" {
+		return "yes"
+	}
+	return value
+}
+
+// dash renders empty table cells as "-"
+func dash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+// toJSONValue converts v to its generic JSON representation
+func toJSONValue(v interface{}) (interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal AgentCard: %w", err)
+	}
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil, fmt.Errorf("failed to decode AgentCard: %w", err)
+	}
+	return value, nil
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/craine-io/openribcage/internal/config"
+	"github.com/craine-io/openribcage/pkg/a2a/types"
+)
+
+// rowsByField indexes comparison rows by field name
+func rowsByField(rows []comparisonRow) map[string]comparisonRow {
+	byField := make(map[string]comparisonRow, len(rows))
+	for _, row := range rows {
+		byField[row.Field] = row
+	}
+	return byField
+}
+
+// TestCompareCards tests the attribute rows and structural differences of
+// a comparison
+func TestCompareCards(t *testing.T) {
+	a := &types.AgentCard{
+		Name:              "agent-a",
+		Version:           "1.0.0",
+		URL:               "http://a",
+		Capabilities:      types.AgentCapabilities{Streaming: true},
+		DefaultInputModes: []string{"text"},
+		Authentication:    &types.AgentAuthentication{Type: "bearer"},
+		Skills:            []types.AgentSkill{{ID: "deploy", Name: "Deploy"}, {Name: "triage"}},
+	}
+	b := &types.AgentCard{
+		Name:              "agent-b",
+		Version:           "1.0.0",
+		URL:               "http://b",
+		DefaultInputModes: []string{"text"},
+		Skills:            []types.AgentSkill{{ID: "deploy", Name: "Deploy"}, {ID: "rollback"}},
+	}
+
+	result, err := compareCards(a, b)
+	require.NoError(t, err)
+	assert.Equal(t, "agent-a", result.A)
+	assert.Equal(t, "agent-b", result.B)
+
+	rows := rowsByField(result.Rows)
+	assert.Equal(t, comparisonRow{Field: "name", A: "agent-a", B: "agent-b", Differs: true}, rows["name"])
+	assert.Equal(t, comparisonRow{Field: "version", A: "1.0.0", B: "1.0.0"}, rows["version"])
+	assert.Equal(t, comparisonRow{Field: "capability.streaming", A: "yes", B: "no", Differs: true}, rows["capability.streaming"])
+	assert.False(t, rows["inputModes"].Differs)
+	assert.Equal(t, comparisonRow{Field: "auth", A: "bearer", B: "", Differs: true}, rows["auth"])
+	assert.Equal(t, comparisonRow{Field: "skill.deploy", A: "Deploy", B: "Deploy"}, rows["skill.deploy"])
+	assert.Equal(t, comparisonRow{Field: "skill.triage", A: "triage", B: "", Differs: true}, rows["skill.triage"])
+	assert.Equal(t, comparisonRow{Field: "skill.rollback", A: "", B: "yes", Differs: true}, rows["skill.rollback"])
+	assert.Equal(t, comparisonRow{Field: "method." + types.A2AMethods.TasksSend, A: "yes", B: "yes"}, rows["method."+types.A2AMethods.TasksSend])
+	assert.Equal(t, comparisonRow{Field: "method." + types.A2AMethods.TasksStream, A: "yes", B: "no", Differs: true}, rows["method."+types.A2AMethods.TasksStream])
+	assert.NotEmpty(t, result.Differences)
+
+	same, err := compareCards(b, b)
+	require.NoError(t, err)
+	assert.Empty(t, same.Differences)
+	assert.NotNil(t, same.Differences)
+	for _, row := range same.Rows {
+		assert.False(t, row.Differs, row.Field)
+	}
+}
+
+// TestWriteComparisonTable tests the table rendering of a comparison
+func TestWriteComparisonTable(t *testing.T) {
+	var out bytes.Buffer
+	writeComparisonTable(&out, &comparison{
+		A: "agent-a",
+		B: "",
+		Rows: []comparisonRow{
+			{Field: "name", A: "agent-a", B: "", Differs: true},
+			{Field: "version", A: "1.0.0", B: "1.0.0"},
+		},
+	})
+	assert.Equal(t, "   FIELD    agent-a  -\n*  name     agent-a  -\n   version  1.0.0    1.0.0\n\n1 of 2 fields differ\n", out.String())
+}
+
+// TestRunCompare tests comparing two discovered agents as JSON
+func TestRunCompare(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	require.NoError(t, config.Init(""))
+	compareTimeout = 5 * time.Second
+	t.Cleanup(func() { compareTimeout = 0 })
+
+	streaming := newCapabilityAgent(t, true)
+	plain := newCapabilityAgent(t, false)
+
+	var out bytes.Buffer
+	require.NoError(t, runCompare(&out, streaming.URL, plain.URL, "json"))
+	var result comparison
+	require.NoError(t, json.Unmarshal(out.Bytes(), &result))
+	rows := rowsByField(result.Rows)
+	assert.Equal(t, comparisonRow{Field: "capability.streaming", A: "yes", B: "no", Differs: true}, rows["capability.streaming"])
+	assert.True(t, rows["url"].Differs)
+
+	assert.ErrorContains(t, runCompare(&out, streaming.URL, plain.URL, "yaml"), "unsupported output format: yaml")
+	missing := httptest.NewServer(http.NotFoundHandler())
+	defer missing.Close()
+	assert.ErrorContains(t, runCompare(&out, streaming.URL, missing.URL, "table"), "failed to discover "+missing.URL)
+}
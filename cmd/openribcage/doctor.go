@@ -0,0 +1,328 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/craine-io/openribcage/internal/auth"
+	"github.com/craine-io/openribcage/internal/config"
+	"github.com/craine-io/openribcage/pkg/a2a/client"
+	"github.com/craine-io/openribcage/pkg/a2a/types"
+	"github.com/craine-io/openribcage/pkg/agentcard"
+	"github.com/craine-io/openribcage/pkg/netguard"
+	"github.com/craine-io/openribcage/pkg/registry"
+)
+
+// checkStatus is the outcome of a single doctor check
+type checkStatus string
+
+const (
+	checkPass checkStatus = "PASS"
+	checkWarn checkStatus = "WARN"
+	checkFail checkStatus = "FAIL"
+)
+
+// checkResult is one line of the doctor report
+type checkResult struct {
+	Category string
+	Name     string
+	Status   checkStatus
+	Detail   string
+	Hint     string
+}
+
+// doctorReport collects check results in the order they ran
+type doctorReport struct {
+	results []checkResult
+}
+
+// add records a check result
+func (r *doctorReport) add(category, name string, status checkStatus, detail, hint string) {
+	r.results = append(r.results, checkResult{
+		Category: category,
+		Name:     name,
+		Status:   status,
+		Detail:   detail,
+		Hint:     hint,
+	})
+}
+
+// failed reports whether any check failed
+func (r *doctorReport) failed() bool {
+	for _, result := range r.results {
+		if result.Status == checkFail {
+			return true
+		}
+	}
+	return false
+}
+
+// write prints the report grouped by category, followed by a summary
+func (r *doctorReport) write(w io.Writer) {
+	counts := map[checkStatus]int{}
+	category := ""
+	for _, result := range r.results {
+		if result.Category != category {
+			if category != "" {
+				fmt.Fprintln(w)
+			}
+			category = result.Category
+			fmt.Fprintln(w, category)
+		}
+		counts[result.Status]++
+
+		line := fmt.Sprintf("  [%s] %s", result.Status, result.Name)
+		if result.Detail != "" {
+			line += ": " + result.Detail
+		}
+		fmt.Fprintln(w, line)
+		if result.Hint != "" && result.Status != checkPass {
+			fmt.Fprintf(w, "         hint: %s\n", result.Hint)
+		}
+	}
+	fmt.Fprintf(w, "\n%d passed, %d warnings, %d failed\n", counts[checkPass], counts[checkWarn], counts[checkFail])
+}
+
+// runDoctor runs every check, writes the report to w and returns an
+// error if any check failed
+func runDoctor(w io.Writer, agentURL string) error {
+	report := &doctorReport{}
+	cfg := config.Get()
+
+	checkConfig(report, cfg)
+
+	ctx, cancel := context.WithTimeout(context.Background(), doctorTimeout)
+	defer cancel()
+
+	discoverer, err := newDiscoverer(doctorTimeout)
+	if err != nil {
+		report.add("Discovery hosts", "discoverer", checkFail, err.Error(), "fix the a2a.proxy_url and a2a.tls settings")
+	} else {
+		checkDiscoveryHosts(ctx, report, discoverer, cfg.A2A.DiscoveryHosts)
+	}
+
+	checkRegistryStore(report)
+
+	if agentURL != "" && discoverer != nil {
+		checkAgentRoundTrip(ctx, report, discoverer, cfg, agentURL)
+	}
+
+	report.write(w)
+	if report.failed() {
+		return errors.New("one or more checks failed")
+	}
+	return nil
+}
+
+// checkConfig checks the loaded configuration for values that cannot work
+func checkConfig(report *doctorReport, cfg *config.Config) {
+	const category = "Configuration"
+
+	if port := cfg.Server.Port; port < 1 || port > 65535 {
+		report.add(category, "server port", checkFail, fmt.Sprintf("%d is outside 1-65535", port), "set server.port to a valid port")
+	} else {
+		report.add(category, "server port", checkPass, fmt.Sprint(port), "")
+	}
+
+	if cfg.Server.ReadTimeout <= 0 || cfg.Server.WriteTimeout <= 0 {
+		report.add(category, "server timeouts", checkWarn, "read or write timeout is not positive", "set server.read_timeout and server.write_timeout, e.g. 30s")
+	} else {
+		report.add(category, "server timeouts", checkPass, fmt.Sprintf("read %s, write %s", cfg.Server.ReadTimeout, cfg.Server.WriteTimeout), "")
+	}
+
+	if cfg.Server.TLS.Enabled {
+		if err := checkFiles(cfg.Server.TLS.CertFile, cfg.Server.TLS.KeyFile); err != nil {
+			report.add(category, "server TLS", checkFail, err.Error(), "set server.tls.cert_file and server.tls.key_file to readable files")
+		} else {
+			report.add(category, "server TLS", checkPass, "certificate and key found", "")
+		}
+	}
+
+	if cfg.A2A.Timeout <= 0 {
+		report.add(category, "a2a timeout", checkFail, fmt.Sprintf("%s is not positive", cfg.A2A.Timeout), "set a2a.timeout, e.g. 30s")
+	} else {
+		report.add(category, "a2a timeout", checkPass, cfg.A2A.Timeout.String(), "")
+	}
+
+	if files := cfg.A2A.TLS.ClientTLS(); files != nil {
+		if _, err := files.TLSConfig(); err != nil {
+			report.add(category, "a2a TLS", checkFail, err.Error(), "check a2a.tls.cert_file, key_file and ca_file")
+		} else {
+			report.add(category, "a2a TLS", checkPass, "client TLS files loaded", "")
+		}
+	}
+
+	if cfg.A2A.ProxyURL != "" {
+		if _, err := netguard.Proxy(cfg.A2A.ProxyURL); err != nil {
+			report.add(category, "a2a proxy", checkFail, err.Error(), "use an http, https or socks5 URL for a2a.proxy_url")
+		} else {
+			report.add(category, "a2a proxy", checkPass, cfg.A2A.ProxyURL, "")
+		}
+	}
+
+	if _, err := logrus.ParseLevel(cfg.Logging.Level); err != nil {
+		report.add(category, "log level", checkWarn, fmt.Sprintf("unknown level %q, using info", cfg.Logging.Level), "set logging.level to debug, info, warn or error")
+	} else {
+		report.add(category, "log level", checkPass, cfg.Logging.Level, "")
+	}
+}
+
+// checkFiles returns an error for the first path that is empty or unreadable
+func checkFiles(paths ...string) error {
+	for _, path := range paths {
+		if path == "" {
+			return errors.New("file path not set")
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		f.Close()
+	}
+	return nil
+}
+
+// checkDiscoveryHosts checks that each configured discovery host is
+// reachable and serves a valid AgentCard
+func checkDiscoveryHosts(ctx context.Context, report *doctorReport, discoverer *agentcard.Discoverer, hosts []string) {
+	const category = "Discovery hosts"
+
+	if len(hosts) == 0 {
+		report.add(category, "a2a.discovery_hosts", checkWarn, "no hosts configured", "list your agents under a2a.discovery_hosts")
+		return
+	}
+
+	for _, result := range discoverer.CheckHosts(ctx, hosts) {
+		if result.OK() {
+			report.add(category, result.Host, checkPass, "agent: "+result.AgentName, "")
+			continue
+		}
+		report.add(category, result.Host, checkFail, result.Error, discoveryHint(result.Err, result.Reachable))
+	}
+}
+
+// discoveryHint suggests a fix for a failed discovery
+func discoveryHint(err error, reachable bool) string {
+	var netErr net.Error
+	switch {
+	case errors.Is(err, netguard.ErrHostNotAllowed):
+		return "add the host to a2a.allowed_hosts, or set a2a.allow_private_networks for local agents"
+	case !reachable, errors.As(err, &netErr), errors.Is(err, context.DeadlineExceeded):
+		return "check that the agent is running and the host and port are correct"
+	case errors.Is(err, agentcard.ErrInvalidSignature):
+		return "the AgentCard signature did not verify; check the agent's signing keys"
+	case errors.Is(err, types.ErrEmptyResponse):
+		return "the agent returned an empty AgentCard"
+	default:
+		return "check that the agent serves an AgentCard at " + agentcard.WellKnownPath
+	}
+}
+
+// checkRegistryStore checks that the registry file can be read and written
+func checkRegistryStore(report *doctorReport) {
+	const category = "Registry"
+
+	path, err := registry.DefaultStorePath()
+	if err != nil {
+		report.add(category, "store", checkFail, err.Error(), "set the HOME environment variable")
+		return
+	}
+
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		if err := checkWritable(filepath.Dir(path)); err != nil {
+			report.add(category, path, checkFail, err.Error(), "make the directory writable")
+			return
+		}
+		report.add(category, path, checkPass, "not created yet, will be created on first use", "")
+		return
+	}
+
+	agents, err := registry.NewFileStore(path).LoadAll()
+	if err != nil {
+		report.add(category, path, checkFail, err.Error(), "fix or remove the file; it is recreated on the next discovery")
+		return
+	}
+	if err := checkWritable(filepath.Dir(path)); err != nil {
+		report.add(category, path, checkFail, err.Error(), "make the directory writable")
+		return
+	}
+	report.add(category, path, checkPass, fmt.Sprintf("%d agents", len(agents)), "")
+}
+
+// checkWritable checks that files can be created in dir, or in its nearest
+// existing ancestor if dir does not exist yet
+func checkWritable(dir string) error {
+	for {
+		if _, err := os.Stat(dir); err == nil {
+			break
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
+	}
+
+	f, err := os.CreateTemp(dir, ".openribcage-doctor-*")
+	if err != nil {
+		return fmt.Errorf("%s is not writable: %w", dir, err)
+	}
+	f.Close()
+	return os.Remove(f.Name())
+}
+
+// checkAgentRoundTrip discovers a sample agent and queries a task that
+// does not exist, which exercises a full JSON-RPC exchange without
+// creating any work on the agent
+func checkAgentRoundTrip(ctx context.Context, report *doctorReport, discoverer *agentcard.Discoverer, cfg *config.Config, agentURL string) {
+	const category = "Agent round-trip"
+
+	creds, err := auth.NewAuthenticator().LoadCredentialsFromEnv(envPrefix)
+	if err != nil {
+		report.add(category, "credentials", checkFail, err.Error(), "check the "+envPrefix+"_* credential variables")
+		return
+	}
+	discoverer.SetCredentials(creds)
+
+	card, err := discoverer.Discover(ctx, agentURL)
+	if err != nil {
+		report.add(category, "agent card", checkFail, err.Error(), discoveryHint(err, true))
+		return
+	}
+	report.add(category, "agent card", checkPass, fmt.Sprintf("%s (version %s)", card.Name, card.Version), "")
+
+	a2aClient := client.New(client.Config{
+		BaseURL:     agentURL,
+		Timeout:     doctorTimeout,
+		Headers:     cfg.A2A.DefaultHeaders,
+		Credentials: creds,
+		HostPolicy:  cfg.A2A.HostPolicy(),
+		TLS:         cfg.A2A.TLS.ClientTLS(),
+		ProxyURL:    cfg.A2A.ProxyURL,
+
+		RequestIDPrefix: requestIDPrefix,
+	})
+	defer a2aClient.Close()
+
+	start := time.Now()
+	_, err = a2aClient.GetTaskStatus(ctx, "", "openribcage-doctor-"+types.NewID())
+	latency := time.Since(start).Round(time.Millisecond)
+	switch {
+	case err == nil, errors.Is(err, client.ErrTaskNotFound):
+		report.add(category, "JSON-RPC", checkPass, fmt.Sprintf("agent answered in %s", latency), "")
+	case errors.As(err, new(*types.JSONRPCErrorError)):
+		report.add(category, "JSON-RPC", checkWarn, err.Error(), "the agent answered but rejected tasks/get; check its A2A version")
+	case errors.Is(err, netguard.ErrHostNotAllowed):
+		report.add(category, "JSON-RPC", checkFail, err.Error(), "add the host to a2a.allowed_hosts")
+	default:
+		report.add(category, "JSON-RPC", checkFail, err.Error(), "check the agent URL and credentials")
+	}
+}
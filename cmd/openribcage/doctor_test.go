@@ -0,0 +1,145 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/craine-io/openribcage/internal/config"
+	"github.com/craine-io/openribcage/pkg/a2a/types"
+	"github.com/craine-io/openribcage/pkg/agentcard"
+	"github.com/craine-io/openribcage/pkg/registry"
+)
+
+// newDoctorAgent starts an agent that serves an AgentCard and answers
+// every JSON-RPC call with a task not found error
+func newDoctorAgent(t *testing.T) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method != http.MethodPost {
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"name":         "doctor-agent",
+				"version":      "1.0.0",
+				"url":          "http://" + r.Host,
+				"capabilities": map[string]bool{"streaming": true},
+			})
+			return
+		}
+		var req types.JSONRPCRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		json.NewEncoder(w).Encode(types.JSONRPCResponse{
+			JSONRPC: "2.0",
+			ID:      req.ID,
+			Error:   &types.JSONRPCError{Code: types.A2AErrorCodes.TaskNotFound, Message: "task not found"},
+		})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// resultsByName indexes a report's results by check name
+func resultsByName(report *doctorReport) map[string]checkResult {
+	byName := make(map[string]checkResult, len(report.results))
+	for _, result := range report.results {
+		byName[result.Name] = result
+	}
+	return byName
+}
+
+// TestCheckConfig tests that unusable configuration values fail or warn
+// with a hint
+func TestCheckConfig(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	require.NoError(t, config.Init(""))
+
+	report := &doctorReport{}
+	checkConfig(report, config.Get())
+	assert.False(t, report.failed())
+	for _, result := range report.results {
+		assert.Equal(t, checkPass, result.Status, result.Name)
+	}
+
+	cfg := *config.Get()
+	cfg.Server.Port = 0
+	cfg.Server.ReadTimeout = 0
+	cfg.Server.TLS.Enabled = true
+	cfg.Server.TLS.CertFile = filepath.Join(t.TempDir(), "missing.crt")
+	cfg.A2A.Timeout = 0
+	cfg.A2A.ProxyURL = "ftp://proxy"
+	cfg.Logging.Level = "loud"
+
+	report = &doctorReport{}
+	checkConfig(report, &cfg)
+	assert.True(t, report.failed())
+	byName := resultsByName(report)
+	assert.Equal(t, checkFail, byName["server port"].Status)
+	assert.Equal(t, checkWarn, byName["server timeouts"].Status)
+	assert.Equal(t, checkFail, byName["server TLS"].Status)
+	assert.Equal(t, checkFail, byName["a2a timeout"].Status)
+	assert.Equal(t, checkFail, byName["a2a proxy"].Status)
+	assert.Equal(t, checkWarn, byName["log level"].Status)
+	assert.Equal(t, "set server.port to a valid port", byName["server port"].Hint)
+}
+
+// TestCheckRegistryStore tests the registry check for a missing, a valid
+// and a corrupt store file
+func TestCheckRegistryStore(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	path, err := registry.DefaultStorePath()
+	require.NoError(t, err)
+
+	report := &doctorReport{}
+	checkRegistryStore(report)
+	require.Len(t, report.results, 1)
+	assert.Equal(t, checkPass, report.results[0].Status)
+	assert.Contains(t, report.results[0].Detail, "not created yet")
+
+	require.NoError(t, registry.NewFileStore(path).Save(&types.Agent{ID: "k8s", Name: "k8s", URL: "http://k8s:8083"}))
+	report = &doctorReport{}
+	checkRegistryStore(report)
+	assert.Equal(t, checkPass, report.results[0].Status)
+	assert.Equal(t, "1 agents", report.results[0].Detail)
+
+	require.NoError(t, os.WriteFile(path, []byte("{not json"), 0o600))
+	report = &doctorReport{}
+	checkRegistryStore(report)
+	assert.Equal(t, checkFail, report.results[0].Status)
+	assert.Equal(t, path, report.results[0].Name)
+}
+
+// TestRunDoctor tests a full report against a healthy discovery host and
+// agent, and that a host without an AgentCard fails the run
+func TestRunDoctor(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	agent := newDoctorAgent(t)
+	missing := httptest.NewServer(http.NotFoundHandler())
+	defer missing.Close()
+
+	configPath := filepath.Join(t.TempDir(), "openribcage.yaml")
+	require.NoError(t, os.WriteFile(configPath, []byte("a2a:\n  discovery_hosts:\n    - "+agent.URL+"\n"), 0o644))
+	require.NoError(t, config.Init(configPath))
+	t.Cleanup(func() { require.NoError(t, config.Init("")) })
+
+	var out bytes.Buffer
+	require.NoError(t, runDoctor(&out, agent.URL))
+	report := out.String()
+	assert.Contains(t, report, "[PASS] "+agent.URL+": agent: doctor-agent")
+	assert.Contains(t, report, "[PASS] agent card: doctor-agent (version 1.0.0)")
+	assert.Contains(t, report, "[PASS] JSON-RPC: agent answered in")
+	assert.Contains(t, report, "0 failed")
+
+	require.NoError(t, os.WriteFile(configPath, []byte("a2a:\n  discovery_hosts:\n    - "+missing.URL+"\n"), 0o644))
+	require.NoError(t, config.Init(configPath))
+	out.Reset()
+	assert.ErrorContains(t, runDoctor(&out, ""), "one or more checks failed")
+	assert.Contains(t, out.String(), "[FAIL] "+missing.URL)
+	assert.Contains(t, out.String(), "hint: check that the agent serves an AgentCard at "+agentcard.WellKnownPath)
+	assert.NotContains(t, out.String(), "Agent round-trip")
+}
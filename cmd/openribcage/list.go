@@ -0,0 +1,54 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/craine-io/openribcage/internal/agentlist"
+	"github.com/craine-io/openribcage/internal/config"
+	"github.com/craine-io/openribcage/pkg/a2a/types"
+)
+
+// recordDiscovery saves a discovered agent to the local registry so that
+// later invocations can list it
+func recordDiscovery(agentURL string, card *types.AgentCard) error {
+	reg, err := agentlist.Open(config.Get().Registry.CleanupInterval)
+	if err != nil {
+		return err
+	}
+	return agentlist.Record(reg, agentURL, card)
+}
+
+// runList prints the agents in the local registry in the given output
+// format, hiding agents not seen within stale when it is positive. When
+// exportPath is set, the whole registry is also written to that file as a
+// JSON snapshot.
+func runList(w io.Writer, output string, stale time.Duration, exportPath string) error {
+	if output != "table" && output != "json" {
+		return fmt.Errorf("unsupported output format: %s (supported: table, json)", output)
+	}
+
+	reg, err := agentlist.Open(config.Get().Registry.CleanupInterval)
+	if err != nil {
+		return err
+	}
+
+	if exportPath != "" {
+		if err := agentlist.Export(reg, exportPath); err != nil {
+			return err
+		}
+	}
+
+	summaries := agentlist.Summaries(reg, stale)
+	if output == "json" {
+		data, err := json.MarshalIndent(summaries, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal agents to JSON: %w", err)
+		}
+		_, err = fmt.Fprintln(w, string(data))
+		return err
+	}
+	return agentlist.WriteTable(w, summaries)
+}
@@ -9,30 +9,76 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
+	"text/tabwriter"
 	"time"
 
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 
+	"github.com/craine-io/openribcage/internal/auth"
 	"github.com/craine-io/openribcage/internal/config"
 	"github.com/craine-io/openribcage/pkg/a2a/client"
+	"github.com/craine-io/openribcage/pkg/a2a/transcript"
 	"github.com/craine-io/openribcage/pkg/agentcard"
 )
 
+// envPrefix is the prefix of environment variables read by openribcage
+const envPrefix = "OPENRIBCAGE"
+
 var (
 	// Version information (set by build)
 	version = "dev"
 	commit  = "unknown"
 	date    = "unknown"
 
+	// configErr holds the result of validating the configuration
+	configErr error
+
 	// Global flags
-	configFile string
-	verbose    bool
+	configFile      string
+	verbose         bool
+	requestIDPrefix string
 
 	// Discovery flags
 	discoveryTimeout time.Duration
+	checkHosts       bool
+
+	// Discover list flags
+	listOutput string
+	listStale  time.Duration
+	listExport string
+
+	// Communicate flags
+	communicateTimeout time.Duration
+	communicateStream  bool
+	communicateForce   bool
+	communicateDryRun  bool
+
+	// Replay flags
+	replayIgnore  []string
+	replayTimeout time.Duration
+
+	// Methods flags
+	methodsOutput string
+
+	// Compare flags
+	compareOutput  string
+	compareTimeout time.Duration
+
+	// Doctor flags
+	doctorAgent   string
+	doctorTimeout time.Duration
+
+	// Config init flags
+	configInitForce bool
+
+	// Status flags
+	statusServer  string
+	statusOutput  string
+	statusTimeout time.Duration
 )
 
 // rootCmd represents the base command when called without any subcommands
@@ -47,6 +93,13 @@ capabilities through the proven A2A protocol standard, enabling natural
 conversation with AI agents through avatar interfaces.`,
 	Version: fmt.Sprintf("%s (commit: %s, built: %s)", version, commit, date),
 	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		// Fail fast on invalid configuration; doctor reports it instead
+		// and config init can write a fresh file
+		if configErr != nil && cmd != doctorCmd && cmd != configInitCmd {
+			fmt.Fprintln(os.Stderr, configErr)
+			os.Exit(1)
+		}
+
 		// Set up logging
 		if verbose {
 			logrus.SetLevel(logrus.DebugLevel)
@@ -63,21 +116,47 @@ var discoverCmd = &cobra.Command{
 	Short: "Discover A2A agents and their capabilities",
 	Long: `Discover A2A-compliant agents by scanning for AgentCard endpoints
 and parsing their capabilities. Returns agent information in JSON format.
+Discovered agents are saved to the local registry; see discover list.
 
 Examples:
   # Discover a single agent
   openribcage discover http://localhost:8083/api/a2a/kagent/k8s-agent
   
   # Discover with verbose logging
-  openribcage discover -v http://localhost:8083/api/a2a/kagent/k8s-agent`,
-	Args: cobra.ExactArgs(1),
+  openribcage discover -v http://localhost:8083/api/a2a/kagent/k8s-agent
+
+  # Check every host listed in a2a.discovery_hosts
+  openribcage discover --check-hosts`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if checkHosts {
+			return cobra.NoArgs(cmd, args)
+		}
+		return cobra.ExactArgs(1)(cmd, args)
+	},
 	Run: func(cmd *cobra.Command, args []string) {
+		if checkHosts {
+			runCheckHosts()
+			return
+		}
+
 		agentURL := args[0]
 
 		logrus.Infof("Discovering A2A agent at: %s", agentURL)
 
 		// Create AgentCard discoverer
-		discoverer := agentcard.NewDiscoverer(discoveryTimeout)
+		discoverer, err := newDiscoverer(discoveryTimeout)
+		if err != nil {
+			logrus.Errorf("%v", err)
+			os.Exit(1)
+		}
+
+		// Apply credentials from OPENRIBCAGE_* environment variables
+		creds, err := auth.NewAuthenticator().LoadCredentialsFromEnv(envPrefix)
+		if err != nil {
+			logrus.Errorf("Failed to load credentials: %v", err)
+			os.Exit(1)
+		}
+		discoverer.SetCredentials(creds)
 
 		// Create context with timeout
 		ctx, cancel := context.WithTimeout(context.Background(), discoveryTimeout)
@@ -99,26 +178,328 @@ Examples:
 
 		fmt.Println(string(output))
 		logrus.Infof("Successfully discovered agent: %s (version: %s)", card.Name, card.Version)
+
+		// Remember the agent for discover list
+		if err := recordDiscovery(agentURL, card); err != nil {
+			logrus.Warnf("Failed to save agent to the local registry: %v", err)
+		}
+	},
+}
+
+// discoverListCmd represents the discover list command
+var discoverListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List agents in the local registry",
+	Long: `List the agents recorded in the local registry file
+(~/.openribcage/agents.json), which every successful discover adds to.
+Shows each agent's name, URL, status, capabilities and when it was last
+seen; --stale hides agents not seen within the given duration.
+
+With --export, the full registry, including AgentCards and status, is
+also written to a JSON snapshot that can be loaded with Registry.Import.
+
+Examples:
+  openribcage discover list
+  openribcage discover list --stale 24h -o json
+  openribcage discover list --export agents-snapshot.json`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := runList(os.Stdout, listOutput, listStale, listExport); err != nil {
+			logrus.Errorf("%v", err)
+			os.Exit(1)
+		}
 	},
 }
 
+// newDiscoverer creates a Discoverer with the configured retry, host and
+// TLS settings
+func newDiscoverer(timeout time.Duration) (*agentcard.Discoverer, error) {
+	a2aConfig := config.Get().A2A
+
+	discoverer := agentcard.NewDiscoverer(timeout)
+	discoverer.SetRetryPolicy(a2aConfig.RetryPolicy())
+	discoverer.SetHostPolicy(a2aConfig.HostPolicy())
+	if err := discoverer.SetProxyURL(a2aConfig.ProxyURL); err != nil {
+		return nil, fmt.Errorf("invalid a2a.proxy_url: %w", err)
+	}
+
+	if files := a2aConfig.TLS.ClientTLS(); files != nil {
+		tlsConfig, err := files.TLSConfig()
+		if err != nil {
+			return nil, fmt.Errorf("invalid a2a.tls configuration: %w", err)
+		}
+		if err := discoverer.SetTLSConfig(tlsConfig); err != nil {
+			return nil, fmt.Errorf("invalid a2a.tls configuration: %w", err)
+		}
+	}
+	return discoverer, nil
+}
+
+// newClientConfig returns the client configuration for talking to the
+// agent at agentURL with the configured headers, retry, host and TLS
+// settings and the given credentials
+func newClientConfig(agentURL string, timeout time.Duration, creds *auth.Credentials) client.Config {
+	a2aConfig := config.Get().A2A
+	return client.Config{
+		BaseURL:     agentURL,
+		Timeout:     timeout,
+		Headers:     a2aConfig.DefaultHeaders,
+		Credentials: creds,
+		Retry:       a2aConfig.RetryPolicy(),
+		HostPolicy:  a2aConfig.HostPolicy(),
+		TLS:         a2aConfig.TLS.ClientTLS(),
+		ProxyURL:    a2aConfig.ProxyURL,
+
+		StreamTimeout:   a2aConfig.StreamTimeout,
+		MethodTimeouts:  a2aConfig.MethodTimeouts,
+		RequestIDPrefix: requestIDPrefix,
+	}
+}
+
+// runCheckHosts checks every configured discovery host and prints a summary
+func runCheckHosts() {
+	hosts := config.Get().A2A.DiscoveryHosts
+	if len(hosts) == 0 {
+		logrus.Errorf("No discovery hosts configured (a2a.discovery_hosts)")
+		os.Exit(1)
+	}
+
+	discoverer, err := newDiscoverer(discoveryTimeout)
+	if err != nil {
+		logrus.Errorf("%v", err)
+		os.Exit(1)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), discoveryTimeout)
+	defer cancel()
+
+	results := discoverer.CheckHosts(ctx, hosts)
+
+	reachable, valid := 0, 0
+	for _, result := range results {
+		status := "FAIL"
+		if result.OK() {
+			status = "OK"
+		}
+		if result.Reachable {
+			reachable++
+		}
+		if result.ValidCard {
+			valid++
+			fmt.Printf("[%s] %s (agent: %s)\n", status, result.Host, result.AgentName)
+		} else {
+			fmt.Printf("[%s] %s: %s\n", status, result.Host, result.Error)
+		}
+	}
+
+	fmt.Printf("\n%d/%d hosts reachable, %d serving a valid AgentCard\n", reachable, len(results), valid)
+	if valid < len(results) {
+		os.Exit(1)
+	}
+}
+
 // communicateCmd represents the communicate command
 var communicateCmd = &cobra.Command{
 	Use:   "communicate [agent-url] [message]",
 	Short: "Communicate with A2A agents",
 	Long: `Send messages to A2A agents using the JSON-RPC 2.0 protocol.
-Supports both single messages and streaming communication patterns.`,
+Supports both single messages and streaming communication patterns.
+
+With --stream, the agent's AgentCard is checked first and the command
+fails fast if the agent does not advertise streaming; --force skips
+the check.
+
+With --dry-run, the request is printed instead of sent, with sensitive
+headers redacted; no capability check is made.`,
 	Args: cobra.ExactArgs(2),
 	Run: func(cmd *cobra.Command, args []string) {
 		agentURL := args[0]
 		message := args[1]
 
 		logrus.Infof("Communicating with agent: %s", agentURL)
-		logrus.Infof("Message: %s", message)
+		logrus.Debugf("Message: %s", message)
+
+		if err := runCommunicate(agentURL, message); err != nil {
+			logrus.Errorf("%v", err)
+			os.Exit(1)
+		}
+	},
+}
+
+// replayCmd represents the replay command
+var replayCmd = &cobra.Command{
+	Use:   "replay [transcript.json] [agent-url]",
+	Short: "Replay a recorded transcript against a live agent",
+	Long: `Re-send the JSON-RPC requests recorded in a transcript to a live agent,
+in order, and diff each live response against the recorded one.
+Volatile fields can be excluded from the comparison with --ignore,
+either by key name (matched at any depth) or by JSON Pointer.
 
-		// TODO: Implement A2A communication
-		// This will be implemented in Issue #4 and #10
-		fmt.Println("A2A communication functionality coming in Issue #10!")
+Examples:
+  # Replay a transcript, ignoring the default volatile fields
+  openribcage replay transcript.json http://localhost:8083/api/a2a/kagent/k8s-agent
+
+  # Also ignore the message text
+  openribcage replay transcript.json http://localhost:8083/api/a2a/kagent/k8s-agent --ignore id,timestamp,text`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		transcriptPath, agentURL := args[0], args[1]
+
+		diverged, err := runReplay(os.Stdout, transcriptPath, agentURL)
+		if err != nil {
+			logrus.Errorf("%v", err)
+			os.Exit(1)
+		}
+		if diverged > 0 {
+			os.Exit(1)
+		}
+	},
+}
+
+// methodsCmd represents the methods command
+var methodsCmd = &cobra.Command{
+	Use:   "methods",
+	Short: "List the A2A methods supported by this client",
+	Long: `List every A2A protocol method known to openribcage, the client
+function that calls it, and whether streaming and authentication are
+supported for it.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		methods := client.Methods()
+
+		switch methodsOutput {
+		case "json":
+			output, err := json.MarshalIndent(methods, "", "  ")
+			if err != nil {
+				logrus.Errorf("Failed to marshal methods to JSON: %v", err)
+				os.Exit(1)
+			}
+			fmt.Println(string(output))
+		case "table":
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "METHOD\tFUNCTION\tSTREAMING\tIMPLEMENTED\tAUTH")
+			for _, m := range methods {
+				function := m.Function
+				if function == "" {
+					function = "-"
+				}
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", m.Method, function, yesNo(m.Streaming), yesNo(m.Implemented), yesNo(m.Auth))
+			}
+			w.Flush()
+		default:
+			logrus.Errorf("Unsupported output format: %s (supported: table, json)", methodsOutput)
+			os.Exit(1)
+		}
+	},
+}
+
+// yesNo renders a boolean for table output
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
+
+// compareCmd represents the compare command
+var compareCmd = &cobra.Command{
+	Use:   "compare [url-a] [url-b]",
+	Short: "Compare the capabilities of two A2A agents",
+	Long: `Discover two agents and print their capabilities, skills, supported
+methods and versions side by side. Rows that differ are marked with *.
+
+Examples:
+  # Check that a canary matches production
+  openribcage compare http://prod:8083/api/a2a/agent http://canary:8083/api/a2a/agent
+
+  # Emit the comparison, including every structural difference, as JSON
+  openribcage compare -o json http://a:8080 http://b:8080`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := runCompare(os.Stdout, args[0], args[1], compareOutput); err != nil {
+			logrus.Errorf("Compare failed: %v", err)
+			os.Exit(1)
+		}
+	},
+}
+
+// doctorCmd represents the doctor command
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check configuration, discovery hosts and registry health",
+	Long: `Run first-run sanity checks: configuration values, reachability of
+the configured discovery hosts, access to the registry file and,
+optionally, a JSON-RPC round-trip with a sample agent. Each check is
+reported as PASS, WARN or FAIL with a hint for fixing failures.
+
+Examples:
+  openribcage doctor
+  openribcage doctor --agent http://localhost:8083/api/a2a/kagent/k8s-agent`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := runDoctor(os.Stdout, doctorAgent); err != nil {
+			os.Exit(1)
+		}
+	},
+}
+
+// configCmd groups the configuration commands
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Manage the openribcage configuration file",
+}
+
+// configInitCmd represents the config init command
+var configInitCmd = &cobra.Command{
+	Use:   "init [path]",
+	Short: "Write the default configuration to a file",
+	Long: `Write the default configuration as commented YAML, as a starting
+point for your own settings. Without a path the file is written to
+./openribcage.yaml, the first location searched for configuration.
+An existing file is only replaced with --force.
+
+Examples:
+  openribcage config init
+  openribcage config init ~/.openribcage.yaml --force`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		path := ""
+		if len(args) == 1 {
+			path = args[0]
+		}
+		written, err := config.WriteDefault(path, configInitForce)
+		if err != nil {
+			logrus.Errorf("%v", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Wrote default configuration to %s\n", written)
+	},
+}
+
+// statusCmd represents the status command
+var statusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show the circuit breaker state of each agent",
+	Long: `Show the circuit breaker state of each agent called through a
+running gateway (openribcage serve): closed, open or half-open, the
+number of consecutive failures and the last error. The command only
+reads state and is safe to run frequently.
+
+Examples:
+  openribcage status
+  openribcage status --server http://gateway:8080 -o json`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		serverURL := statusServer
+		if serverURL == "" {
+			serverURL = defaultServerURL()
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), statusTimeout)
+		defer cancel()
+		if err := runStatus(ctx, os.Stdout, serverURL, statusOutput); err != nil {
+			logrus.Errorf("%v", err)
+			os.Exit(1)
+		}
 	},
 }
 
@@ -132,32 +513,85 @@ for real-time agent communication.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		logrus.Info("Starting openribcage A2A client server...")
 
-		// TODO: Implement server mode
-		// This will provide API for avatar interfaces
-		fmt.Println("Server mode functionality coming in Phase 2!")
+		if err := runServe(); err != nil {
+			logrus.Errorf("Server failed: %v", err)
+			os.Exit(1)
+		}
 	},
 }
 
 func init() {
+	cobra.OnInitialize(initConfig)
+
 	// Global flags
 	rootCmd.PersistentFlags().StringVar(&configFile, "config", "", "config file (default is $HOME/.openribcage.yaml)")
 	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "verbose output")
+	rootCmd.PersistentFlags().StringVar(&requestIDPrefix, "json-rpc-id-prefix", "", "prefix for generated JSON-RPC request IDs (e.g. openribcage-)")
 
 	// Discovery command flags
 	discoverCmd.Flags().DurationVar(&discoveryTimeout, "timeout", 30*time.Second, "discovery timeout duration")
+	discoverCmd.Flags().BoolVar(&checkHosts, "check-hosts", false, "check reachability of all configured discovery hosts")
+	discoverListCmd.Flags().StringVarP(&listOutput, "output", "o", "table", "output format (table, json)")
+	discoverListCmd.Flags().DurationVar(&listStale, "stale", 0, "hide agents not seen within this duration (0 shows all)")
+	discoverListCmd.Flags().StringVar(&listExport, "export", "", "also write the registry to this file as a JSON snapshot")
+
+	// Communicate command flags
+	communicateCmd.Flags().DurationVar(&communicateTimeout, "timeout", 30*time.Second, "request timeout duration")
+	communicateCmd.Flags().BoolVar(&communicateStream, "stream", false, "stream the agent's response")
+	communicateCmd.Flags().BoolVar(&communicateForce, "force", false, "skip the agent capability check")
+	communicateCmd.Flags().BoolVar(&communicateDryRun, "dry-run", false, "print the request instead of sending it")
+
+	// Replay command flags
+	replayCmd.Flags().StringSliceVar(&replayIgnore, "ignore", transcript.DefaultIgnore, "fields to ignore when diffing (key names or JSON Pointers)")
+	replayCmd.Flags().DurationVar(&replayTimeout, "timeout", 30*time.Second, "per-request timeout duration")
+
+	// Methods command flags
+	methodsCmd.Flags().StringVarP(&methodsOutput, "output", "o", "table", "output format (table, json)")
+
+	// Compare command flags
+	compareCmd.Flags().StringVarP(&compareOutput, "output", "o", "table", "output format (table, json)")
+	compareCmd.Flags().DurationVar(&compareTimeout, "timeout", 30*time.Second, "discovery timeout duration")
+
+	// Doctor command flags
+	doctorCmd.Flags().StringVar(&doctorAgent, "agent", "", "agent URL for a sample JSON-RPC round-trip")
+	doctorCmd.Flags().DurationVar(&doctorTimeout, "timeout", 10*time.Second, "timeout for network checks")
+
+	// Config init command flags
+	configInitCmd.Flags().BoolVar(&configInitForce, "force", false, "overwrite an existing file")
+
+	// Status command flags
+	statusCmd.Flags().StringVar(&statusServer, "server", "", "gateway URL (default from server.host and server.port)")
+	statusCmd.Flags().StringVarP(&statusOutput, "output", "o", "table", "output format (table, json)")
+	statusCmd.Flags().DurationVar(&statusTimeout, "timeout", 10*time.Second, "request timeout duration")
 
 	// Add subcommands
+	configCmd.AddCommand(configInitCmd)
+	discoverCmd.AddCommand(discoverListCmd)
 	rootCmd.AddCommand(discoverCmd)
 	rootCmd.AddCommand(communicateCmd)
+	rootCmd.AddCommand(replayCmd)
+	rootCmd.AddCommand(methodsCmd)
+	rootCmd.AddCommand(compareCmd)
+	rootCmd.AddCommand(doctorCmd)
+	rootCmd.AddCommand(configCmd)
+	rootCmd.AddCommand(statusCmd)
 	rootCmd.AddCommand(serveCmd)
 }
 
-func main() {
-	// Initialize configuration
+// initConfig loads the configuration once flags are parsed, so that
+// --config is honoured. Invalid values are reported once the command is
+// known, so that doctor can still diagnose them.
+func initConfig() {
+	configErr = nil
 	if err := config.Init(configFile); err != nil {
-		logrus.Fatalf("Failed to initialize configuration: %v", err)
+		if !errors.As(err, new(*config.ValidationError)) {
+			logrus.Fatalf("Failed to initialize configuration: %v", err)
+		}
+		configErr = err
 	}
+}
 
+func main() {
 	// Initialize A2A client
 	if err := client.Init(); err != nil {
 		logrus.Fatalf("Failed to initialize A2A client: %v", err)
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/craine-io/openribcage/internal/config"
+)
+
+// executeRoot runs the root command with args and restores the global
+// flags afterwards
+func executeRoot(t *testing.T, args ...string) {
+	t.Cleanup(func() {
+		configFile = ""
+		configInitForce = false
+		configErr = nil
+		requestIDPrefix = ""
+	})
+	rootCmd.SetArgs(args)
+	require.NoError(t, rootCmd.Execute())
+}
+
+// TestConfigFlag tests that --config is loaded after flags are parsed and
+// that invalid values in it are recorded for the fail-fast check
+func TestConfigFlag(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "openribcage.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("server:\n  port: 9191\n"), 0o644))
+
+	executeRoot(t, "--config", path, "config", "init", filepath.Join(dir, "out.yaml"))
+	assert.Equal(t, path, config.Path())
+	assert.Equal(t, 9191, config.Get().Server.Port)
+	assert.NoError(t, configErr)
+
+	invalid := filepath.Join(dir, "invalid.yaml")
+	require.NoError(t, os.WriteFile(invalid, []byte("server:\n  port: 70000\n"), 0o644))
+	executeRoot(t, "--config", invalid, "config", "init", filepath.Join(dir, "out.yaml"), "--force")
+	var validationErr *config.ValidationError
+	assert.ErrorAs(t, configErr, &validationErr)
+	assert.Equal(t, invalid, config.Path())
+}
+
+// TestRequestIDPrefixFlag tests that --json-rpc-id-prefix reaches the
+// client configuration
+func TestRequestIDPrefixFlag(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	dir := t.TempDir()
+	executeRoot(t, "--json-rpc-id-prefix", "openribcage-", "config", "init", filepath.Join(dir, "out.yaml"))
+	assert.Equal(t, "openribcage-", newClientConfig("http://agent.example.com", time.Second, nil).RequestIDPrefix)
+}
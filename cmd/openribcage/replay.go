@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/craine-io/openribcage/internal/auth"
+	"github.com/craine-io/openribcage/pkg/a2a/client"
+	"github.com/craine-io/openribcage/pkg/a2a/transcript"
+)
+
+// runReplay replays the transcript at transcriptPath against the agent at
+// agentURL, prints the divergences to w and returns how many entries
+// diverged. The agent is called with the same credentials, host policy
+// and transport settings as communicate.
+func runReplay(w io.Writer, transcriptPath, agentURL string) (int, error) {
+	t, err := transcript.Load(transcriptPath)
+	if err != nil {
+		return 0, err
+	}
+
+	creds, err := auth.NewAuthenticator().LoadCredentialsFromEnv(envPrefix)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load credentials: %w", err)
+	}
+
+	logrus.Infof("Replaying %d recorded requests against: %s", len(t.Entries), agentURL)
+
+	a2aClient := client.New(newClientConfig(agentURL, replayTimeout, creds))
+	defer a2aClient.Close()
+
+	divergences, err := transcript.Replay(context.Background(), a2aClient, "", t, replayIgnore)
+	if err != nil {
+		return 0, fmt.Errorf("replay failed: %w", err)
+	}
+
+	for _, d := range divergences {
+		fmt.Fprintf(w, "#%d %s:\n", d.Index, d.Method)
+		if d.Error != "" {
+			fmt.Fprintf(w, "  error: %s\n", d.Error)
+		}
+		for _, diff := range d.Differences {
+			fmt.Fprintf(w, "  %s\n", diff)
+		}
+	}
+
+	fmt.Fprintf(w, "%d of %d requests diverged\n", len(divergences), len(t.Entries))
+	return len(divergences), nil
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/craine-io/openribcage/internal/config"
+	"github.com/craine-io/openribcage/pkg/a2a/types"
+)
+
+// writeReplayFixtures writes a one-entry transcript and a config file
+// with the given YAML, loads the config and returns the transcript path
+func writeReplayFixtures(t *testing.T, configYAML string) string {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "openribcage.yaml")
+	require.NoError(t, os.WriteFile(configPath, []byte(configYAML), 0o644))
+	require.NoError(t, config.Init(configPath))
+	t.Cleanup(func() { require.NoError(t, config.Init("")) })
+
+	transcriptPath := filepath.Join(dir, "transcript.json")
+	require.NoError(t, os.WriteFile(transcriptPath, []byte(`{"entries":[
+		{"method":"tasks/status","params":{"id":"task-1"},"result":{"id":"task-1","status":"completed"}}
+	]}`), 0o644))
+	return transcriptPath
+}
+
+// TestReplayUsesClientSettings tests that replay sends the configured
+// headers and the credentials from the environment
+func TestReplayUsesClientSettings(t *testing.T) {
+	var mu sync.Mutex
+	var header http.Header
+	agent := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		header = r.Header.Clone()
+		mu.Unlock()
+		var req types.JSONRPCRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(types.JSONRPCResponse{JSONRPC: "2.0", ID: req.ID, Result: json.RawMessage(`{"id":"task-1","status":"completed"}`)})
+	}))
+	defer agent.Close()
+
+	transcriptPath := writeReplayFixtures(t, "a2a:\n  default_headers:\n    X-Tenant: acme\n")
+	t.Setenv(envPrefix+"_AUTH_TYPE", "bearer")
+	t.Setenv(envPrefix+"_TOKEN", "secret-token")
+	replayTimeout = 5 * time.Second
+
+	var out bytes.Buffer
+	diverged, err := runReplay(&out, transcriptPath, agent.URL)
+	require.NoError(t, err)
+	assert.Equal(t, 0, diverged)
+	assert.Equal(t, "0 of 1 requests diverged\n", out.String())
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, "Bearer secret-token", header.Get("Authorization"))
+	assert.Equal(t, "acme", header.Get("X-Tenant"))
+}
+
+// TestReplayHostPolicy tests that replay honours the configured host policy
+func TestReplayHostPolicy(t *testing.T) {
+	hit := false
+	agent := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { hit = true }))
+	defer agent.Close()
+
+	transcriptPath := writeReplayFixtures(t, "a2a:\n  denied_hosts:\n    - 127.0.0.1\n")
+	replayTimeout = 5 * time.Second
+
+	var out bytes.Buffer
+	diverged, err := runReplay(&out, transcriptPath, agent.URL)
+	require.NoError(t, err)
+	assert.Equal(t, 1, diverged)
+	assert.Contains(t, out.String(), "not allowed")
+	assert.False(t, hit, "a denied agent is never contacted")
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/sirupsen/logrus"
+
+	"github.com/craine-io/openribcage/internal/auth"
+	"github.com/craine-io/openribcage/internal/config"
+	"github.com/craine-io/openribcage/internal/server"
+	"github.com/craine-io/openribcage/pkg/a2a/client"
+	"github.com/craine-io/openribcage/pkg/metrics"
+	"github.com/craine-io/openribcage/pkg/registry"
+)
+
+// runServe runs the REST and WebSocket gateway until SIGINT or SIGTERM
+func runServe() error {
+	cfg := config.Get()
+
+	creds, err := auth.NewAuthenticator().LoadCredentialsFromEnv(envPrefix)
+	if err != nil {
+		return fmt.Errorf("failed to load credentials: %w", err)
+	}
+
+	promRegistry := prometheus.NewRegistry()
+	promRegistry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+	a2aMetrics, err := metrics.New(promRegistry)
+	if err != nil {
+		return err
+	}
+
+	discoverer, err := newDiscoverer(cfg.A2A.Timeout)
+	if err != nil {
+		return err
+	}
+	discoverer.SetCredentials(creds)
+	discoverer.SetMetrics(a2aMetrics)
+
+	reg := registry.NewRegistry(cfg.Registry.CleanupInterval)
+	reg.SetMaxAgents(cfg.Registry.MaxAgents)
+	reg.SetEvictOldest(cfg.Registry.EvictOldest)
+	clientBase := client.Config{
+		Timeout:     cfg.A2A.Timeout,
+		Headers:     cfg.A2A.DefaultHeaders,
+		Credentials: creds,
+		Retry:       cfg.A2A.RetryPolicy(),
+		Breaker:     cfg.A2A.BreakerPolicy(),
+		HostPolicy:  cfg.A2A.HostPolicy(),
+		TLS:         cfg.A2A.TLS.ClientTLS(),
+		ProxyURL:    cfg.A2A.ProxyURL,
+		Metrics:     a2aMetrics,
+
+		StreamTimeout:   cfg.A2A.StreamTimeout,
+		MethodTimeouts:  cfg.A2A.MethodTimeouts,
+		RequestIDPrefix: requestIDPrefix,
+		UploadThreshold: cfg.A2A.UploadThreshold,
+		UploadChunkSize: cfg.A2A.UploadChunkSize,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Apply log level changes without a restart; other settings are read
+	// once at startup
+	if config.Path() != "" {
+		config.OnReload(func(cfg *config.Config) {
+			if level, err := logrus.ParseLevel(cfg.Logging.Level); err == nil {
+				logrus.SetLevel(level)
+			}
+		})
+		go func() {
+			if err := config.Watch(ctx); err != nil {
+				logrus.Warnf("Config hot-reload disabled: %v", err)
+			}
+		}()
+	}
+
+	if interval := cfg.Registry.HealthCheckInterval; interval > 0 {
+		pinger := client.New(clientBase)
+		defer pinger.Close()
+		go reg.StartHealthChecks(ctx, pinger, interval, cfg.Registry.HealthCheckConcurrency)
+	}
+
+	srv := server.New(cfg.Server, clientBase, reg, discoverer)
+	srv.SetMetricsHandler(promhttp.HandlerFor(promRegistry, promhttp.HandlerOpts{}))
+	return srv.Run(ctx)
+}
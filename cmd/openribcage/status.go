@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/craine-io/openribcage/internal/config"
+	"github.com/craine-io/openribcage/pkg/a2a/client"
+)
+
+// defaultServerURL returns the gateway URL from the server configuration
+func defaultServerURL() string {
+	cfg := config.Get().Server
+	scheme := "http"
+	if cfg.TLS.Enabled {
+		scheme = "https"
+	}
+	host := cfg.Host
+	if host == "" || host == "0.0.0.0" || host == "::" {
+		host = "localhost"
+	}
+	return scheme + "://" + net.JoinHostPort(host, strconv.Itoa(cfg.Port))
+}
+
+// fetchBreakerStatuses reads the circuit breaker state of each agent from
+// a running gateway's /api/v1/breakers endpoint
+func fetchBreakerStatuses(ctx context.Context, serverURL string) ([]client.BreakerStatus, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(serverURL, "/")+"/api/v1/breakers", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach server: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status: %s", resp.Status)
+	}
+	var statuses []client.BreakerStatus
+	if err := json.NewDecoder(resp.Body).Decode(&statuses); err != nil {
+		return nil, fmt.Errorf("failed to decode breaker status: %w", err)
+	}
+	return statuses, nil
+}
+
+// runStatus prints the circuit breaker state of each agent known to the
+// gateway at serverURL in the given output format
+func runStatus(ctx context.Context, w io.Writer, serverURL, output string) error {
+	if output != "table" && output != "json" {
+		return fmt.Errorf("unsupported output format: %s (supported: table, json)", output)
+	}
+
+	statuses, err := fetchBreakerStatuses(ctx, serverURL)
+	if err != nil {
+		return err
+	}
+
+	if output == "json" {
+		data, err := json.MarshalIndent(statuses, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal breaker status to JSON: %w", err)
+		}
+		_, err = fmt.Fprintln(w, string(data))
+		return err
+	}
+
+	if len(statuses) == 0 {
+		_, err := fmt.Fprintln(w, "No agents have been called yet")
+		return err
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "AGENT\tSTATE\tFAILURES\tOPENED\tLAST ERROR")
+	for _, status := range statuses {
+		lastError := status.LastError
+		if lastError == "" {
+			lastError = "-"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%s\t%s\n", status.Agent, status.State, status.Failures, openedAt(status.OpenedAt), lastError)
+	}
+	return tw.Flush()
+}
+
+// openedAt renders when a breaker opened relative to now for table output
+func openedAt(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return time.Since(t).Round(time.Second).String() + " ago"
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/craine-io/openribcage/internal/config"
+	"github.com/craine-io/openribcage/internal/server"
+	"github.com/craine-io/openribcage/pkg/a2a/client"
+	"github.com/craine-io/openribcage/pkg/a2a/types"
+	"github.com/craine-io/openribcage/pkg/agentcard"
+	"github.com/craine-io/openribcage/pkg/registry"
+)
+
+// statusAgent is a fake agent that fails until told otherwise and can hold
+// a request to keep a breaker probe in flight
+type statusAgent struct {
+	*httptest.Server
+
+	mu      sync.Mutex
+	failing bool
+	hold    chan struct{}
+	held    chan struct{}
+}
+
+// newStatusAgent starts a failing statusAgent that is closed when the test ends
+func newStatusAgent(t *testing.T) *statusAgent {
+	a := &statusAgent{failing: true}
+	a.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		a.mu.Lock()
+		failing, hold, held := a.failing, a.hold, a.held
+		a.mu.Unlock()
+		if hold != nil {
+			held <- struct{}{}
+			<-hold
+		}
+		if failing {
+			http.Error(w, "agent down", http.StatusInternalServerError)
+			return
+		}
+
+		var req types.JSONRPCRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(types.JSONRPCResponse{JSONRPC: "2.0", ID: req.ID, Result: json.RawMessage(`{"id":"task-1","status":"completed"}`)})
+	}))
+	t.Cleanup(a.Close)
+	return a
+}
+
+// statusOf runs the status command against gateway and decodes its JSON output
+func statusOf(t *testing.T, gateway string) client.BreakerStatus {
+	var out bytes.Buffer
+	require.NoError(t, runStatus(context.Background(), &out, gateway, "json"))
+	var statuses []client.BreakerStatus
+	require.NoError(t, json.Unmarshal(out.Bytes(), &statuses))
+	require.Len(t, statuses, 1)
+	return statuses[0]
+}
+
+// TestStatus tests that the status command reports closed, open and
+// half-open breakers with their failure count and last error
+func TestStatus(t *testing.T) {
+	agent := newStatusAgent(t)
+	reg := registry.NewRegistry(time.Minute)
+	require.NoError(t, reg.Register(&types.Agent{ID: "agent", Name: "agent", URL: agent.URL, Status: types.AgentStatusOnline}))
+	s := server.New(config.ServerConfig{}, client.Config{
+		Timeout: 5 * time.Second,
+		Breaker: client.BreakerPolicy{Threshold: 2, CoolDown: 50 * time.Millisecond},
+	}, reg, agentcard.NewDiscoverer(5*time.Second))
+	gateway := httptest.NewServer(s.Handler())
+	defer gateway.Close()
+
+	sendTask := func() int {
+		resp, err := http.Post(gateway.URL+"/api/v1/agents/agent/tasks", "application/json", bytes.NewBufferString(`{"text":"hello"}`))
+		require.NoError(t, err)
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	var out bytes.Buffer
+	require.NoError(t, runStatus(context.Background(), &out, gateway.URL, "table"))
+	assert.Equal(t, "No agents have been called yet\n", out.String())
+
+	// Closed with one failure
+	sendTask()
+	status := statusOf(t, gateway.URL)
+	assert.Equal(t, agent.URL, status.Agent)
+	assert.Equal(t, client.BreakerClosed, status.State)
+	assert.Equal(t, 1, status.Failures)
+	assert.Equal(t, "unexpected status: 500 Internal Server Error", status.LastError)
+
+	// Open at the threshold
+	sendTask()
+	status = statusOf(t, gateway.URL)
+	assert.Equal(t, client.BreakerOpen, status.State)
+	assert.Equal(t, 2, status.Failures)
+	assert.Equal(t, "unexpected status: 500 Internal Server Error", status.LastError)
+
+	out.Reset()
+	require.NoError(t, runStatus(context.Background(), &out, gateway.URL, "table"))
+	assert.Contains(t, out.String(), "AGENT")
+	assert.Contains(t, out.String(), agent.URL)
+	assert.Contains(t, out.String(), "open")
+	assert.Contains(t, out.String(), "unexpected status: 500 Internal Server Error")
+
+	// Half-open while the probe is in flight
+	time.Sleep(60 * time.Millisecond)
+	agent.mu.Lock()
+	agent.failing = false
+	agent.hold = make(chan struct{})
+	agent.held = make(chan struct{}, 1)
+	hold, held := agent.hold, agent.held
+	agent.mu.Unlock()
+
+	probe := make(chan int, 1)
+	go func() { probe <- sendTask() }()
+	<-held
+	status = statusOf(t, gateway.URL)
+	assert.Equal(t, client.BreakerHalfOpen, status.State)
+	assert.Equal(t, 2, status.Failures)
+	assert.Equal(t, "unexpected status: 500 Internal Server Error", status.LastError)
+
+	agent.mu.Lock()
+	agent.hold = nil
+	agent.mu.Unlock()
+	close(hold)
+	assert.Equal(t, http.StatusOK, <-probe)
+
+	// Closed again after a successful probe
+	status = statusOf(t, gateway.URL)
+	assert.Equal(t, client.BreakerClosed, status.State)
+	assert.Equal(t, 0, status.Failures)
+}
+
+// TestStatusErrors tests the status command's error reporting
+func TestStatusErrors(t *testing.T) {
+	err := runStatus(context.Background(), &bytes.Buffer{}, "http://localhost:1", "yaml")
+	assert.ErrorContains(t, err, "unsupported output format")
+
+	notFound := httptest.NewServer(http.NotFoundHandler())
+	defer notFound.Close()
+	_, err = fetchBreakerStatuses(context.Background(), notFound.URL)
+	assert.ErrorContains(t, err, "404")
+}
+
+// TestDefaultServerURL tests deriving the gateway URL from the server config
+func TestDefaultServerURL(t *testing.T) {
+	require.NoError(t, config.Init(""))
+	assert.Equal(t, "http://localhost:8080", defaultServerURL())
+}
@@ -0,0 +1,107 @@
+// Package agentlist implements the local agent registry shared by the
+// openribcage and discovery CLIs.
+//
+// Agents found by discover, scan and validate are recorded in a registry
+// file in the user's home directory, so that later invocations can list
+// them.
+package agentlist
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/craine-io/openribcage/pkg/a2a/types"
+	"github.com/craine-io/openribcage/pkg/registry"
+)
+
+// Open loads the registry shared between CLI invocations
+func Open(cleanupInterval time.Duration) (*registry.Registry, error) {
+	path, err := registry.DefaultStorePath()
+	if err != nil {
+		return nil, err
+	}
+	return registry.NewRegistryWithStore(cleanupInterval, registry.NewFileStore(path))
+}
+
+// Record saves a discovered agent to reg, keeping the ID of an agent
+// already recorded at agentURL
+func Record(reg *registry.Registry, agentURL string, card *types.AgentCard) error {
+	now := time.Now()
+	return reg.Register(&types.Agent{
+		ID:            reg.AgentID(card.Name, agentURL),
+		Name:          card.Name,
+		URL:           agentURL,
+		Card:          card,
+		Status:        types.AgentStatusOnline,
+		LastSeen:      now,
+		DiscoveredAt:  now,
+		CardFetchedAt: now,
+	})
+}
+
+// Export writes a JSON snapshot of every agent in reg to path, in the
+// format read by Registry.Import
+func Export(reg *registry.Registry, path string) error {
+	data, err := reg.Export()
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write export: %w", err)
+	}
+	return nil
+}
+
+// Summaries returns a summary of each agent in reg, hiding agents not
+// seen within stale when it is positive
+func Summaries(reg *registry.Registry, stale time.Duration) []types.AgentSummary {
+	summaries := []types.AgentSummary{}
+	for _, summary := range reg.Summaries() {
+		if stale > 0 && time.Since(summary.LastSeen) > stale {
+			continue
+		}
+		summaries = append(summaries, summary)
+	}
+	return summaries
+}
+
+// WriteTable renders summaries as a table of name, URL, status,
+// capabilities and last-seen time
+func WriteTable(w io.Writer, summaries []types.AgentSummary) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tURL\tSTATUS\tCAPABILITIES\tLAST SEEN")
+	for _, summary := range summaries {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", summary.Name, summary.URL, summary.Status, capabilities(summary), lastSeen(summary.LastSeen))
+	}
+	return tw.Flush()
+}
+
+// capabilities lists an agent's enabled capabilities for table output
+func capabilities(summary types.AgentSummary) string {
+	var caps []string
+	if summary.Streaming {
+		caps = append(caps, "streaming")
+	}
+	if summary.PushNotifications {
+		caps = append(caps, "pushNotifications")
+	}
+	if summary.StateTransitionHistory {
+		caps = append(caps, "stateTransitionHistory")
+	}
+	if len(caps) == 0 {
+		return "-"
+	}
+	return strings.Join(caps, ",")
+}
+
+// lastSeen renders a last-seen time relative to now for table output
+func lastSeen(t time.Time) string {
+	if t.IsZero() {
+		return "never"
+	}
+	return time.Since(t).Round(time.Second).String() + " ago"
+}
@@ -0,0 +1,127 @@
+package agentlist
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/craine-io/openribcage/pkg/a2a/types"
+	"github.com/craine-io/openribcage/pkg/registry"
+)
+
+// TestRecord tests that agents are persisted to the registry file and that
+// rediscovering an agent keeps its ID
+func TestRecord(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	reg, err := Open(time.Minute)
+	require.NoError(t, err)
+	card := &types.AgentCard{Name: "K8s Agent", Version: "1.0.0"}
+	require.NoError(t, Record(reg, "http://localhost:8083/k8s", card))
+	require.NoError(t, Record(reg, "http://localhost:8083/k8s/", card))
+	require.NoError(t, Record(reg, "http://localhost:8084/k8s", card))
+
+	reopened, err := Open(time.Minute)
+	require.NoError(t, err)
+	agents := reopened.List()
+	require.Len(t, agents, 2)
+	ids := []string{agents[0].ID, agents[1].ID}
+	assert.ElementsMatch(t, []string{"k8s-agent", "k8s-agent-2"}, ids)
+	for _, agent := range agents {
+		assert.Equal(t, types.AgentStatusOnline, agent.Status)
+		assert.Equal(t, "K8s Agent", agent.Card.Name)
+	}
+}
+
+// TestExport tests the snapshot format written by --export: a JSON array
+// of full agent records sorted by ID that Registry.Import reads back
+func TestExport(t *testing.T) {
+	seen := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	reg := registry.NewRegistry(time.Minute)
+	require.NoError(t, reg.Register(&types.Agent{
+		ID:       "zeta",
+		Name:     "zeta",
+		URL:      "http://zeta:8080",
+		Status:   types.AgentStatusOffline,
+		LastSeen: seen,
+	}))
+	require.NoError(t, reg.Register(&types.Agent{
+		ID:       "alpha",
+		Name:     "alpha",
+		URL:      "http://alpha:8080",
+		Card:     &types.AgentCard{Name: "alpha", Version: "2.0.0"},
+		Status:   types.AgentStatusOnline,
+		LastSeen: seen,
+	}))
+
+	path := filepath.Join(t.TempDir(), "agents-snapshot.json")
+	require.NoError(t, Export(reg, path))
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.True(t, bytes.HasSuffix(data, []byte("]\n")), "the snapshot ends with a newline")
+
+	var records []map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &records))
+	require.Len(t, records, 2)
+	assert.Equal(t, "alpha", records[0]["id"], "agents are sorted by ID")
+	assert.Equal(t, "zeta", records[1]["id"])
+	assert.Equal(t, "http://alpha:8080", records[0]["url"])
+	assert.Equal(t, "online", records[0]["status"])
+	assert.Equal(t, "2025-06-01T12:00:00Z", records[0]["last_seen"])
+	assert.Equal(t, "2.0.0", records[0]["card"].(map[string]interface{})["version"])
+	assert.Nil(t, records[1]["card"])
+	assert.Contains(t, records[0], "discovered_at")
+
+	imported := registry.NewRegistry(time.Minute)
+	require.NoError(t, imported.Import(data))
+	agent, err := imported.Get("alpha")
+	require.NoError(t, err)
+	assert.Equal(t, "2.0.0", agent.Card.Version)
+	assert.True(t, seen.Equal(agent.LastSeen))
+}
+
+// TestExportError tests that a failed write is reported
+func TestExportError(t *testing.T) {
+	err := Export(registry.NewRegistry(time.Minute), filepath.Join(t.TempDir(), "missing", "agents.json"))
+	assert.ErrorContains(t, err, "failed to write export")
+}
+
+// TestSummaries tests hiding agents not seen within the stale threshold
+func TestSummaries(t *testing.T) {
+	reg := registry.NewRegistry(time.Minute)
+	require.NoError(t, reg.Register(&types.Agent{ID: "fresh", URL: "http://fresh", LastSeen: time.Now()}))
+	require.NoError(t, reg.Register(&types.Agent{ID: "old", URL: "http://old", LastSeen: time.Now().Add(-2 * time.Hour)}))
+
+	ids := func(summaries []types.AgentSummary) []string {
+		var ids []string
+		for _, summary := range summaries {
+			ids = append(ids, summary.ID)
+		}
+		return ids
+	}
+	assert.ElementsMatch(t, []string{"fresh", "old"}, ids(Summaries(reg, 0)))
+	assert.Equal(t, []string{"fresh"}, ids(Summaries(reg, time.Hour)))
+	assert.NotNil(t, Summaries(registry.NewRegistry(time.Minute), 0), "an empty registry lists as []")
+}
+
+// TestWriteTable tests the table columns
+func TestWriteTable(t *testing.T) {
+	var out bytes.Buffer
+	require.NoError(t, WriteTable(&out, []types.AgentSummary{
+		{Name: "k8s", URL: "http://k8s", Status: types.AgentStatusOnline, Streaming: true, PushNotifications: true, LastSeen: time.Now()},
+		{Name: "idle", URL: "http://idle", Status: types.AgentStatusOffline},
+	}))
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	require.Len(t, lines, 3)
+	assert.Equal(t, []string{"NAME", "URL", "STATUS", "CAPABILITIES", "LAST", "SEEN"}, strings.Fields(lines[0]))
+	assert.Equal(t, []string{"k8s", "http://k8s", "online", "streaming,pushNotifications", "0s", "ago"}, strings.Fields(lines[1]))
+	assert.Equal(t, []string{"idle", "http://idle", "offline", "-", "never"}, strings.Fields(lines[2]))
+}
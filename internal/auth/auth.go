@@ -6,9 +6,14 @@ package auth
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
+	"os"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/sirupsen/logrus"
 )
@@ -17,21 +22,38 @@ import (
 type AuthType string
 
 const (
-	AuthTypeNone   AuthType = "none"
-	AuthTypeBearer AuthType = "bearer"
-	AuthTypeAPIKey AuthType = "apikey"
-	AuthTypeOAuth2 AuthType = "oauth2"
+	AuthTypeNone        AuthType = "none"
+	AuthTypeBearer      AuthType = "bearer"
+	AuthTypeAPIKey      AuthType = "apikey"
+	AuthTypeAPIKeyQuery AuthType = "apikey-query"
+	AuthTypeOAuth2      AuthType = "oauth2"
+	AuthTypeMTLS        AuthType = "mtls"
 )
 
-// Authenticator handles A2A authentication
+// DefaultAPIKeyQueryParam is the query parameter used by AuthTypeAPIKeyQuery
+// when Credentials.QueryParam is unset
+const DefaultAPIKeyQueryParam = "api_key"
+
+// Authenticator handles A2A authentication.
+// OAuth2 access tokens are cached per Authenticator until close to expiry.
 type Authenticator struct {
-	logger *logrus.Logger
+	logger     *logrus.Logger
+	httpClient *http.Client
+
+	mu        sync.Mutex
+	tokens    map[string]*cachedToken
+	tokenSkew time.Duration
 }
 
 // NewAuthenticator creates a new A2A authenticator
 func NewAuthenticator() *Authenticator {
 	return &Authenticator{
 		logger: logrus.New(),
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+		tokens:    make(map[string]*cachedToken),
+		tokenSkew: 30 * time.Second,
 	}
 }
 
@@ -43,9 +65,27 @@ type Credentials struct {
 	Username string            `json:"username,omitempty"`
 	Password string            `json:"password,omitempty"`
 	Headers  map[string]string `json:"headers,omitempty"`
+
+	// QueryParam names the query parameter carrying the key for AuthTypeAPIKeyQuery
+	QueryParam string `json:"query_param,omitempty"`
+
+	// Config holds scheme-specific settings, such as the OAuth2
+	// token_url, client_id, client_secret and scopes, or the mTLS
+	// cert_file, key_file and ca_file
+	Config map[string]string `json:"config,omitempty"`
 }
 
-// AddAuthHeaders adds authentication headers to an HTTP request
+// queryParam returns the API key query parameter name
+func (c *Credentials) queryParam() string {
+	if c.QueryParam != "" {
+		return c.QueryParam
+	}
+	return DefaultAPIKeyQueryParam
+}
+
+// AddAuthHeaders adds authentication headers to an HTTP request.
+// For AuthTypeAPIKeyQuery the key is added to the request URL's query
+// string instead; use RedactURL before logging such URLs.
 func (a *Authenticator) AddAuthHeaders(req *http.Request, creds *Credentials) error {
 	if creds == nil {
 		return nil
@@ -72,10 +112,23 @@ func (a *Authenticator) AddAuthHeaders(req *http.Request, creds *Credentials) er
 		req.Header.Set("X-API-Key", creds.APIKey)
 		req.Header.Set("Authorization", fmt.Sprintf("ApiKey %s", creds.APIKey))
 
+	case AuthTypeAPIKeyQuery:
+		if creds.APIKey == "" {
+			return fmt.Errorf("API key is required")
+		}
+		query := req.URL.Query()
+		query.Set(creds.queryParam(), creds.APIKey)
+		req.URL.RawQuery = query.Encode()
+
 	case AuthTypeOAuth2:
-		// TODO: Implement OAuth2 flow
-		// This will be implemented when needed
-		return fmt.Errorf("OAuth2 authentication not yet implemented")
+		token, err := a.oauth2Token(req.Context(), creds)
+		if err != nil {
+			return fmt.Errorf("failed to obtain OAuth2 token: %w", err)
+		}
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+
+	case AuthTypeMTLS:
+		// The client certificate is presented by the transport
 
 	default:
 		return fmt.Errorf("unsupported authentication type: %s", creds.Type)
@@ -104,13 +157,16 @@ func (a *Authenticator) ValidateCredentials(creds *Credentials) error {
 			return fmt.Errorf("bearer token cannot be empty")
 		}
 
-	case AuthTypeAPIKey:
+	case AuthTypeAPIKey, AuthTypeAPIKeyQuery:
 		if strings.TrimSpace(creds.APIKey) == "" {
 			return fmt.Errorf("API key cannot be empty")
 		}
 
 	case AuthTypeOAuth2:
-		return fmt.Errorf("OAuth2 validation not yet implemented")
+		return validateOAuth2(creds)
+
+	case AuthTypeMTLS:
+		return validateMTLS(creds)
 
 	default:
 		return fmt.Errorf("unsupported authentication type: %s", creds.Type)
@@ -119,24 +175,106 @@ func (a *Authenticator) ValidateCredentials(creds *Credentials) error {
 	return nil
 }
 
-// LoadCredentialsFromEnv loads credentials from environment variables
-func (a *Authenticator) LoadCredentialsFromEnv(prefix string) *Credentials {
-	// TODO: Implement environment variable credential loading
-	// This will be implemented when needed
-	// Look for variables like:
-	// - {PREFIX}_AUTH_TYPE
-	// - {PREFIX}_TOKEN
-	// - {PREFIX}_API_KEY
-	// etc.
+// LoadCredentialsFromEnv loads credentials from environment variables:
+//   - {PREFIX}_AUTH_TYPE (defaults to none)
+//   - {PREFIX}_TOKEN
+//   - {PREFIX}_API_KEY
+//   - {PREFIX}_USERNAME
+//   - {PREFIX}_PASSWORD
+//   - {PREFIX}_HEADER_* for custom headers, e.g. {PREFIX}_HEADER_X_TENANT
+//     sets the X-Tenant header
+//
+// The resulting credentials are validated before being returned.
+func (a *Authenticator) LoadCredentialsFromEnv(prefix string) (*Credentials, error) {
+	prefix = strings.ToUpper(strings.TrimSuffix(prefix, "_")) + "_"
+
+	creds := &Credentials{
+		Type:     AuthType(strings.ToLower(strings.TrimSpace(os.Getenv(prefix + "AUTH_TYPE")))),
+		Token:    os.Getenv(prefix + "TOKEN"),
+		APIKey:   os.Getenv(prefix + "API_KEY"),
+		Username: os.Getenv(prefix + "USERNAME"),
+		Password: os.Getenv(prefix + "PASSWORD"),
+	}
+	if creds.Type == "" {
+		creds.Type = AuthTypeNone
+	}
+
+	headerPrefix := prefix + "HEADER_"
+	for _, env := range os.Environ() {
+		key, value, ok := strings.Cut(env, "=")
+		if !ok || !strings.HasPrefix(key, headerPrefix) || key == headerPrefix {
+			continue
+		}
+		if creds.Headers == nil {
+			creds.Headers = make(map[string]string)
+		}
+		name := strings.ReplaceAll(strings.TrimPrefix(key, headerPrefix), "_", "-")
+		creds.Headers[http.CanonicalHeaderKey(name)] = value
+	}
 
-	return &Credentials{
-		Type: AuthTypeNone,
+	if err := a.ValidateCredentials(creds); err != nil {
+		return nil, fmt.Errorf("invalid credentials from %s* environment variables: %w", prefix, err)
 	}
+
+	a.logger.Debugf("Loaded %s credentials from %s* environment variables", creds.Type, prefix)
+	return creds, nil
 }
 
-// RefreshToken refreshes an authentication token if needed
+// RefreshToken refreshes an OAuth2 access token if the cached token is
+// missing or within the token skew of expiry. Other credential types
+// carry static secrets and need no refresh.
 func (a *Authenticator) RefreshToken(ctx context.Context, creds *Credentials) error {
-	// TODO: Implement token refresh logic
-	// This will be implemented when needed for OAuth2 flows
-	return fmt.Errorf("token refresh not yet implemented")
+	if creds == nil || creds.Type != AuthTypeOAuth2 {
+		return nil
+	}
+
+	_, err := a.oauth2Token(ctx, creds)
+	return err
+}
+
+// sensitiveQueryParams are query parameters whose values are always redacted
+var sensitiveQueryParams = []string{DefaultAPIKeyQueryParam, "apikey", "api-key", "key", "token", "access_token"}
+
+// RedactURL masks the values of sensitive query parameters in rawURL so it
+// can be logged safely. Additional parameter names may be supplied.
+func RedactURL(rawURL string, params ...string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.RawQuery == "" {
+		return rawURL
+	}
+
+	query := u.Query()
+	redacted := false
+	for _, name := range append(params, sensitiveQueryParams...) {
+		if name != "" && query.Has(name) {
+			query.Set(name, "REDACTED")
+			redacted = true
+		}
+	}
+	if !redacted {
+		return rawURL
+	}
+
+	u.RawQuery = query.Encode()
+	return u.String()
+}
+
+// RedactError masks sensitive query parameters in the URL carried by a
+// *url.Error, as returned by http.Client. Other errors are returned unchanged.
+func RedactError(err error, params ...string) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		redacted := *urlErr
+		redacted.URL = RedactURL(urlErr.URL, params...)
+		return &redacted
+	}
+	return err
+}
+
+// RedactParam returns the query parameter that carries a secret for creds, if any
+func RedactParam(creds *Credentials) string {
+	if creds == nil || creds.Type != AuthTypeAPIKeyQuery {
+		return ""
+	}
+	return creds.queryParam()
 }
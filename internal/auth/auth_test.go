@@ -0,0 +1,74 @@
+package auth
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestAPIKeyQuery tests that query-parameter API keys are added to the
+// request URL, keeping its existing query, and not sent as headers
+func TestAPIKeyQuery(t *testing.T) {
+	a := NewAuthenticator()
+
+	req, err := http.NewRequest("GET", "http://agent.example.com/card?lang=en", nil)
+	require.NoError(t, err)
+	require.NoError(t, a.AddAuthHeaders(req, &Credentials{Type: AuthTypeAPIKeyQuery, APIKey: "s3cret"}))
+	assert.Equal(t, "s3cret", req.URL.Query().Get(DefaultAPIKeyQueryParam))
+	assert.Equal(t, "en", req.URL.Query().Get("lang"))
+	assert.Empty(t, req.Header.Get("Authorization"))
+	assert.Empty(t, req.Header.Get("X-API-Key"))
+
+	req, err = http.NewRequest("GET", "http://agent.example.com/card", nil)
+	require.NoError(t, err)
+	require.NoError(t, a.AddAuthHeaders(req, &Credentials{Type: AuthTypeAPIKeyQuery, APIKey: "s3cret", QueryParam: "code"}))
+	assert.Equal(t, "code=s3cret", req.URL.RawQuery)
+
+	assert.Error(t, a.AddAuthHeaders(req, &Credentials{Type: AuthTypeAPIKeyQuery}))
+	assert.ErrorContains(t, a.ValidateCredentials(&Credentials{Type: AuthTypeAPIKeyQuery, APIKey: " "}), "API key cannot be empty")
+}
+
+// TestRedactURL tests masking of sensitive query parameters
+func TestRedactURL(t *testing.T) {
+	tests := []struct {
+		name   string
+		url    string
+		params []string
+		want   string
+	}{
+		{"default parameter", "http://a.example.com/x?api_key=s3cret&lang=en", nil, "http://a.example.com/x?api_key=REDACTED&lang=en"},
+		{"well-known name", "http://a.example.com/x?token=s3cret", nil, "http://a.example.com/x?token=REDACTED"},
+		{"custom parameter", "http://a.example.com/x?code=s3cret", []string{"code"}, "http://a.example.com/x?code=REDACTED"},
+		{"nothing sensitive", "http://a.example.com/x?lang=en&b=1", nil, "http://a.example.com/x?lang=en&b=1"},
+		{"no query", "http://a.example.com/x", []string{"code"}, "http://a.example.com/x"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, RedactURL(tt.url, tt.params...))
+		})
+	}
+}
+
+// TestRedactError tests that URLs inside *url.Error are redacted and that
+// other errors pass through
+func TestRedactError(t *testing.T) {
+	creds := &Credentials{Type: AuthTypeAPIKeyQuery, APIKey: "s3cret", QueryParam: "code"}
+	err := &url.Error{Op: "Get", URL: "http://a.example.com/x?code=s3cret", Err: errors.New("connection refused")}
+
+	redacted := RedactError(err, RedactParam(creds))
+	assert.NotContains(t, redacted.Error(), "s3cret")
+	assert.Contains(t, redacted.Error(), "connection refused")
+	assert.Contains(t, err.Error(), "s3cret")
+
+	plain := errors.New("boom")
+	assert.Equal(t, plain, RedactError(plain, "code"))
+
+	assert.Empty(t, RedactParam(nil))
+	assert.Empty(t, RedactParam(&Credentials{Type: AuthTypeBearer, Token: "t"}))
+	assert.Equal(t, DefaultAPIKeyQueryParam, RedactParam(&Credentials{Type: AuthTypeAPIKeyQuery}))
+}
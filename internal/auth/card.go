@@ -0,0 +1,86 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/craine-io/openribcage/pkg/a2a/types"
+)
+
+// ErrUnsupportedScheme is returned when an AgentCard requires an
+// authentication scheme the client does not implement
+var ErrUnsupportedScheme = errors.New("unsupported authentication scheme")
+
+// cardConfigAliases maps camelCase AgentCard config keys onto Credentials.Config keys
+var cardConfigAliases = map[string]string{
+	"tokenUrl":     OAuth2TokenURL,
+	"clientId":     OAuth2ClientID,
+	"clientSecret": OAuth2ClientSecret,
+}
+
+// CredentialsFromCard maps an AgentCard's authentication requirements onto
+// a Credentials template. Secrets such as tokens and API keys are left
+// empty for the caller to fill in; the template's Type tells the user what
+// the agent needs. Cards without authentication yield AuthTypeNone.
+//
+// API keys are sent as a query parameter when the card's config has
+// "in": "query", using its "name" as the parameter.
+func CredentialsFromCard(card *types.AgentCard) (*Credentials, error) {
+	if card == nil {
+		return nil, fmt.Errorf("agent card cannot be nil")
+	}
+	if card.Authentication == nil {
+		return &Credentials{Type: AuthTypeNone}, nil
+	}
+
+	config := make(map[string]string, len(card.Authentication.Config))
+	for key, value := range card.Authentication.Config {
+		if alias, ok := cardConfigAliases[key]; ok {
+			key = alias
+		}
+		config[key] = configString(value)
+	}
+
+	creds := &Credentials{}
+	switch scheme := strings.ToLower(strings.TrimSpace(card.Authentication.Type)); scheme {
+	case "", "none":
+		creds.Type = AuthTypeNone
+
+	case "bearer":
+		creds.Type = AuthTypeBearer
+
+	case "apikey", "api_key", "api-key":
+		creds.Type = AuthTypeAPIKey
+		if strings.EqualFold(config["in"], "query") {
+			creds.Type = AuthTypeAPIKeyQuery
+			creds.QueryParam = config["name"]
+		}
+
+	case "oauth2":
+		creds.Type = AuthTypeOAuth2
+		creds.Config = config
+
+	default:
+		return nil, fmt.Errorf("%w: agent %q requires %q", ErrUnsupportedScheme, card.Name, card.Authentication.Type)
+	}
+
+	return creds, nil
+}
+
+// configString renders an AgentCard config value as a string.
+// Lists, such as OAuth2 scopes, are joined with spaces.
+func configString(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case []interface{}:
+		parts := make([]string, 0, len(v))
+		for _, item := range v {
+			parts = append(parts, fmt.Sprint(item))
+		}
+		return strings.Join(parts, " ")
+	default:
+		return fmt.Sprint(v)
+	}
+}
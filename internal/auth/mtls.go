@@ -0,0 +1,98 @@
+package auth
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// Credentials.Config keys used by AuthTypeMTLS
+const (
+	MTLSCertFile = "cert_file"
+	MTLSKeyFile  = "key_file"
+	MTLSCAFile   = "ca_file"
+)
+
+// ClientTLS locates the files for mutual TLS: a client certificate and
+// key, and optionally a CA bundle used instead of the system roots to
+// verify agents
+type ClientTLS struct {
+	CertFile string `json:"cert_file,omitempty"`
+	KeyFile  string `json:"key_file,omitempty"`
+	CAFile   string `json:"ca_file,omitempty"`
+}
+
+// TLSConfig loads the certificate, key and CA bundle into a tls.Config
+func (c *ClientTLS) TLSConfig() (*tls.Config, error) {
+	cfg := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if c.CertFile != "" || c.KeyFile != "" {
+		if c.CertFile == "" || c.KeyFile == "" {
+			return nil, fmt.Errorf("client certificate and key must be set together")
+		}
+		cert, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+
+	if c.CAFile != "" {
+		pem, err := os.ReadFile(c.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA bundle: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in CA bundle %s", c.CAFile)
+		}
+		cfg.RootCAs = pool
+	}
+
+	return cfg, nil
+}
+
+// MTLSFromCredentials returns the ClientTLS described by AuthTypeMTLS
+// credentials, or nil for other credential types
+func MTLSFromCredentials(creds *Credentials) *ClientTLS {
+	if creds == nil || creds.Type != AuthTypeMTLS {
+		return nil
+	}
+	return &ClientTLS{
+		CertFile: creds.Config[MTLSCertFile],
+		KeyFile:  creds.Config[MTLSKeyFile],
+		CAFile:   creds.Config[MTLSCAFile],
+	}
+}
+
+// validateMTLS checks that the certificate and key are configured. It runs
+// on every request, so the files themselves are only loaded once, by
+// ClientTLS.TLSConfig when the transport is built.
+func validateMTLS(creds *Credentials) error {
+	for _, key := range []string{MTLSCertFile, MTLSKeyFile} {
+		if strings.TrimSpace(creds.Config[key]) == "" {
+			return fmt.Errorf("mTLS %s is required", key)
+		}
+	}
+	return nil
+}
+
+// TLSTransport returns a copy of base, or of http.DefaultTransport when
+// base is nil, that uses cfg for TLS. Any other http.RoundTripper cannot
+// be given a TLS config and is an error rather than silently replaced.
+func TLSTransport(base http.RoundTripper, cfg *tls.Config) (*http.Transport, error) {
+	var transport *http.Transport
+	switch base := base.(type) {
+	case nil:
+		transport = http.DefaultTransport.(*http.Transport).Clone()
+	case *http.Transport:
+		transport = base.Clone()
+	default:
+		return nil, fmt.Errorf("cannot apply TLS config to transport of type %T", base)
+	}
+	transport.TLSClientConfig = cfg
+	return transport, nil
+}
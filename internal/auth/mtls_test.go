@@ -0,0 +1,182 @@
+package auth
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writePEM writes a single PEM block to a file in dir and returns its path
+func writePEM(t *testing.T, dir, name, blockType string, der []byte) string {
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600))
+	return path
+}
+
+// newClientCert creates a CA and a client certificate signed by it, and
+// returns the CA pool and the client's ClientTLS files
+func newClientCert(t *testing.T) (*x509.CertPool, *ClientTLS) {
+	dir := t.TempDir()
+
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	require.NoError(t, err)
+	caCert, err := x509.ParseCertificate(caDER)
+	require.NoError(t, err)
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "openribcage"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, caCert, &key.PublicKey, caKey)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	pool := x509.NewCertPool()
+	pool.AddCert(caCert)
+	return pool, &ClientTLS{
+		CertFile: writePEM(t, dir, "client.crt", "CERTIFICATE", der),
+		KeyFile:  writePEM(t, dir, "client.key", "EC PRIVATE KEY", keyDER),
+	}
+}
+
+// TestMutualTLS tests that the client certificate is presented to a
+// server requiring one and that the CA bundle verifies the server
+func TestMutualTLS(t *testing.T) {
+	clientCAs, files := newClientCert(t)
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.TLS.PeerCertificates[0].Subject.CommonName))
+	}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs}
+	server.StartTLS()
+	defer server.Close()
+	files.CAFile = writePEM(t, t.TempDir(), "ca.crt", "CERTIFICATE", server.Certificate().Raw)
+
+	cfg, err := files.TLSConfig()
+	require.NoError(t, err)
+	assert.Len(t, cfg.Certificates, 1)
+	assert.NotNil(t, cfg.RootCAs)
+
+	transport, err := TLSTransport(nil, cfg)
+	require.NoError(t, err)
+	client := &http.Client{Transport: transport}
+	resp, err := client.Get(server.URL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, "openribcage", string(body))
+
+	// Without the client certificate the handshake is rejected
+	transport, err = TLSTransport(transport, &tls.Config{RootCAs: cfg.RootCAs})
+	require.NoError(t, err)
+	client = &http.Client{Transport: transport}
+	_, err = client.Get(server.URL)
+	assert.Error(t, err)
+}
+
+// TestTLSTransportCustomRoundTripper tests that a transport that cannot be
+// given a TLS config is an error instead of being replaced
+func TestTLSTransportCustomRoundTripper(t *testing.T) {
+	var custom http.RoundTripper = roundTripFunc(func(*http.Request) (*http.Response, error) { return nil, nil })
+	_, err := TLSTransport(custom, &tls.Config{})
+	assert.ErrorContains(t, err, "cannot apply TLS config to transport of type auth.roundTripFunc")
+
+	base := &http.Transport{MaxIdleConns: 7}
+	transport, err := TLSTransport(base, &tls.Config{ServerName: "agent"})
+	require.NoError(t, err)
+	assert.NotSame(t, base, transport)
+	assert.Equal(t, 7, transport.MaxIdleConns)
+	assert.Equal(t, "agent", transport.TLSClientConfig.ServerName)
+}
+
+// roundTripFunc adapts a function to http.RoundTripper
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+// RoundTrip implements http.RoundTripper
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// TestClientTLSErrors tests invalid certificate, key and CA settings
+func TestClientTLSErrors(t *testing.T) {
+	_, files := newClientCert(t)
+	notPEM := filepath.Join(t.TempDir(), "ca.crt")
+	require.NoError(t, os.WriteFile(notPEM, []byte("not a certificate"), 0o600))
+
+	tests := []struct {
+		name  string
+		files ClientTLS
+		want  string
+	}{
+		{"cert without key", ClientTLS{CertFile: files.CertFile}, "client certificate and key must be set together"},
+		{"missing cert", ClientTLS{CertFile: "/nonexistent.crt", KeyFile: files.KeyFile}, "failed to load client certificate"},
+		{"mismatched pair", ClientTLS{CertFile: files.KeyFile, KeyFile: files.CertFile}, "failed to load client certificate"},
+		{"missing CA", ClientTLS{CAFile: "/nonexistent.crt"}, "failed to read CA bundle"},
+		{"empty CA", ClientTLS{CAFile: notPEM}, "no certificates found in CA bundle"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.files.TLSConfig()
+			assert.ErrorContains(t, err, tt.want)
+		})
+	}
+}
+
+// TestMTLSCredentials tests building and validating mTLS credentials
+func TestMTLSCredentials(t *testing.T) {
+	_, files := newClientCert(t)
+	creds := &Credentials{Type: AuthTypeMTLS, Config: map[string]string{MTLSCertFile: files.CertFile, MTLSKeyFile: files.KeyFile}}
+
+	assert.Equal(t, files, MTLSFromCredentials(creds))
+	assert.Nil(t, MTLSFromCredentials(&Credentials{Type: AuthTypeBearer, Token: "t"}))
+	assert.Nil(t, MTLSFromCredentials(nil))
+
+	a := NewAuthenticator()
+	assert.NoError(t, a.ValidateCredentials(creds))
+	assert.ErrorContains(t, a.ValidateCredentials(&Credentials{Type: AuthTypeMTLS, Config: map[string]string{MTLSCertFile: files.CertFile}}), "mTLS key_file is required")
+
+	// Validation runs per request and must not touch the files; they are
+	// loaded by TLSConfig when the transport is built
+	missing := &Credentials{Type: AuthTypeMTLS, Config: map[string]string{MTLSCertFile: "/nonexistent.crt", MTLSKeyFile: "/nonexistent.key"}}
+	assert.NoError(t, a.ValidateCredentials(missing))
+	_, err := MTLSFromCredentials(missing).TLSConfig()
+	assert.ErrorContains(t, err, "failed to load client certificate")
+
+	req, err := http.NewRequest("GET", "https://agent.example.com", nil)
+	require.NoError(t, err)
+	require.NoError(t, a.AddAuthHeaders(req, creds))
+	assert.Empty(t, req.Header.Get("Authorization"))
+}
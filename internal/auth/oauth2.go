@@ -0,0 +1,159 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Credentials.Config keys used by AuthTypeOAuth2
+const (
+	OAuth2TokenURL     = "token_url"
+	OAuth2ClientID     = "client_id"
+	OAuth2ClientSecret = "client_secret"
+	OAuth2Scopes       = "scopes"
+)
+
+// defaultTokenLifetime applies to tokens issued without expires_in
+const defaultTokenLifetime = 5 * time.Minute
+
+// cachedToken is an access token with its expiry
+type cachedToken struct {
+	accessToken string
+	expiresAt   time.Time
+	lifetime    time.Duration
+}
+
+// refreshSkew returns how long before expiry the token is refreshed. The
+// skew is capped at half the token's lifetime, so short-lived tokens are
+// still reused instead of being fetched again for every request.
+func (t *cachedToken) refreshSkew(skew time.Duration) time.Duration {
+	if max := t.lifetime / 2; skew > max {
+		return max
+	}
+	return skew
+}
+
+// tokenResponse is an OAuth2 token endpoint response (RFC 6749 section 5)
+type tokenResponse struct {
+	AccessToken      string `json:"access_token"`
+	TokenType        string `json:"token_type"`
+	ExpiresIn        int64  `json:"expires_in"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// SetTokenSkew sets how long before expiry a cached OAuth2 token is
+// refreshed; it is capped at half of each token's lifetime
+func (a *Authenticator) SetTokenSkew(skew time.Duration) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.tokenSkew = skew
+}
+
+// validateOAuth2 checks that the client-credentials settings are present
+func validateOAuth2(creds *Credentials) error {
+	for _, key := range []string{OAuth2TokenURL, OAuth2ClientID, OAuth2ClientSecret} {
+		if strings.TrimSpace(creds.Config[key]) == "" {
+			return fmt.Errorf("OAuth2 %s is required", key)
+		}
+	}
+
+	u, err := url.Parse(creds.Config[OAuth2TokenURL])
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return fmt.Errorf("OAuth2 token_url must be an http or https URL")
+	}
+	return nil
+}
+
+// oauth2Token returns a cached access token, fetching a new one when the
+// cached token is missing or within the refresh skew of expiry
+func (a *Authenticator) oauth2Token(ctx context.Context, creds *Credentials) (string, error) {
+	if err := validateOAuth2(creds); err != nil {
+		return "", err
+	}
+
+	key := tokenCacheKey(creds)
+
+	a.mu.Lock()
+	token, ok := a.tokens[key]
+	skew := a.tokenSkew
+	a.mu.Unlock()
+
+	if ok && time.Until(token.expiresAt) > token.refreshSkew(skew) {
+		return token.accessToken, nil
+	}
+
+	token, err := a.fetchToken(ctx, creds)
+	if err != nil {
+		return "", err
+	}
+
+	a.mu.Lock()
+	a.tokens[key] = token
+	a.mu.Unlock()
+
+	return token.accessToken, nil
+}
+
+// fetchToken performs the client-credentials grant against the token URL
+func (a *Authenticator) fetchToken(ctx context.Context, creds *Credentials) (*cachedToken, error) {
+	tokenURL := creds.Config[OAuth2TokenURL]
+	a.logger.Debugf("Fetching OAuth2 token from: %s", tokenURL)
+
+	form := url.Values{"grant_type": {"client_credentials"}}
+	if scopes := strings.Fields(strings.ReplaceAll(creds.Config[OAuth2Scopes], ",", " ")); len(scopes) > 0 {
+		form.Set("scope", strings.Join(scopes, " "))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(url.QueryEscape(creds.Config[OAuth2ClientID]), url.QueryEscape(creds.Config[OAuth2ClientSecret]))
+
+	issuedAt := time.Now()
+	resp, err := a.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("token request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read token response: %w", err)
+	}
+
+	var tr tokenResponse
+	if err := json.Unmarshal(body, &tr); err != nil {
+		return nil, fmt.Errorf("failed to parse token response (HTTP %d): %w", resp.StatusCode, err)
+	}
+	if resp.StatusCode != http.StatusOK || tr.Error != "" {
+		if tr.Error != "" {
+			return nil, fmt.Errorf("token request rejected: %s %s", tr.Error, tr.ErrorDescription)
+		}
+		return nil, fmt.Errorf("token request failed: HTTP %d", resp.StatusCode)
+	}
+	if tr.AccessToken == "" {
+		return nil, fmt.Errorf("token response missing access_token")
+	}
+
+	lifetime := defaultTokenLifetime
+	if tr.ExpiresIn > 0 {
+		lifetime = time.Duration(tr.ExpiresIn) * time.Second
+	}
+
+	return &cachedToken{accessToken: tr.AccessToken, expiresAt: issuedAt.Add(lifetime), lifetime: lifetime}, nil
+}
+
+// tokenCacheKey identifies the token issued for a set of credentials
+func tokenCacheKey(creds *Credentials) string {
+	return strings.Join([]string{creds.Config[OAuth2TokenURL], creds.Config[OAuth2ClientID], creds.Config[OAuth2Scopes]}, "|")
+}
@@ -0,0 +1,127 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// tokenServer is a fake OAuth2 token endpoint that issues numbered tokens
+type tokenServer struct {
+	*httptest.Server
+
+	mu     sync.Mutex
+	issued int
+	scope  string
+}
+
+// newTokenServer starts a tokenServer that issues tokens valid for
+// expiresIn seconds and is closed when the test ends
+func newTokenServer(t *testing.T, expiresIn int64) *tokenServer {
+	s := &tokenServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, secret, ok := r.BasicAuth()
+		if !ok || id != "client" || secret != "s3cret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			json.NewEncoder(w).Encode(tokenResponse{Error: "invalid_client", ErrorDescription: "bad credentials"})
+			return
+		}
+		if r.FormValue("grant_type") != "client_credentials" {
+			http.Error(w, "unsupported grant", http.StatusBadRequest)
+			return
+		}
+
+		s.mu.Lock()
+		s.issued++
+		s.scope = r.FormValue("scope")
+		token := fmt.Sprintf("token-%d", s.issued)
+		s.mu.Unlock()
+		json.NewEncoder(w).Encode(tokenResponse{AccessToken: token, TokenType: "Bearer", ExpiresIn: expiresIn})
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// oauth2Creds returns client-credentials settings for tokenURL
+func oauth2Creds(tokenURL, secret string) *Credentials {
+	return &Credentials{Type: AuthTypeOAuth2, Config: map[string]string{
+		OAuth2TokenURL:     tokenURL,
+		OAuth2ClientID:     "client",
+		OAuth2ClientSecret: secret,
+		OAuth2Scopes:       "agents:read, agents:write",
+	}}
+}
+
+// authorization applies creds to a new request and returns its Authorization header
+func authorization(t *testing.T, a *Authenticator, creds *Credentials) string {
+	req, err := http.NewRequest("POST", "http://agent.example.com", nil)
+	require.NoError(t, err)
+	require.NoError(t, a.AddAuthHeaders(req, creds))
+	return req.Header.Get("Authorization")
+}
+
+// TestOAuth2 tests that client-credentials tokens are fetched with the
+// configured scopes and reused until they near expiry
+func TestOAuth2(t *testing.T) {
+	server := newTokenServer(t, 3600)
+	a := NewAuthenticator()
+	creds := oauth2Creds(server.URL, "s3cret")
+
+	assert.Equal(t, "Bearer token-1", authorization(t, a, creds))
+	assert.Equal(t, "Bearer token-1", authorization(t, a, creds))
+	server.mu.Lock()
+	assert.Equal(t, 1, server.issued)
+	assert.Equal(t, "agents:read agents:write", server.scope)
+	server.mu.Unlock()
+
+	// A skew longer than the lifetime is capped at half of it
+	a.SetTokenSkew(2 * time.Hour)
+	require.NoError(t, a.RefreshToken(context.Background(), creds))
+	assert.Equal(t, "Bearer token-1", authorization(t, a, creds))
+}
+
+// TestOAuth2ShortLivedToken tests that tokens living shorter than the
+// refresh skew are still reused
+func TestOAuth2ShortLivedToken(t *testing.T) {
+	server := newTokenServer(t, 20)
+	a := NewAuthenticator()
+	creds := oauth2Creds(server.URL, "s3cret")
+
+	for i := 0; i < 3; i++ {
+		assert.Equal(t, "Bearer token-1", authorization(t, a, creds))
+	}
+
+	token := &cachedToken{lifetime: 20 * time.Second}
+	assert.Equal(t, 10*time.Second, token.refreshSkew(30*time.Second))
+	assert.Equal(t, 5*time.Second, token.refreshSkew(5*time.Second))
+}
+
+// TestOAuth2Errors tests token endpoint failures and invalid settings
+func TestOAuth2Errors(t *testing.T) {
+	server := newTokenServer(t, 0)
+	a := NewAuthenticator()
+
+	req, err := http.NewRequest("POST", "http://agent.example.com", nil)
+	require.NoError(t, err)
+	err = a.AddAuthHeaders(req, oauth2Creds(server.URL, "wrong"))
+	assert.ErrorContains(t, err, "token request rejected: invalid_client bad credentials")
+	assert.Empty(t, req.Header.Get("Authorization"))
+
+	notFound := httptest.NewServer(http.NotFoundHandler())
+	defer notFound.Close()
+	assert.ErrorContains(t, a.RefreshToken(context.Background(), oauth2Creds(notFound.URL, "s3cret")), "failed to parse token response (HTTP 404)")
+
+	creds := oauth2Creds("ftp://tokens.example.com", "s3cret")
+	assert.ErrorContains(t, a.ValidateCredentials(creds), "must be an http or https URL")
+	creds = oauth2Creds(server.URL, "")
+	assert.ErrorContains(t, a.ValidateCredentials(creds), "OAuth2 client_secret is required")
+	assert.NoError(t, a.RefreshToken(context.Background(), &Credentials{Type: AuthTypeBearer, Token: "t"}))
+}
@@ -5,25 +5,36 @@
 package config
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"strconv"
+	"sync/atomic"
 	"time"
 
 	"github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+
+	"github.com/craine-io/openribcage/internal/auth"
+	"github.com/craine-io/openribcage/pkg/a2a/client"
+	"github.com/craine-io/openribcage/pkg/a2a/retry"
+	"github.com/craine-io/openribcage/pkg/netguard"
 )
 
 // Config holds the application configuration
 type Config struct {
 	// Server configuration
 	Server ServerConfig `yaml:"server" json:"server"`
-	
+
 	// A2A client configuration
 	A2A A2AConfig `yaml:"a2a" json:"a2a"`
-	
+
 	// Logging configuration
 	Logging LoggingConfig `yaml:"logging" json:"logging"`
-	
+
 	// Registry configuration
 	Registry RegistryConfig `yaml:"registry" json:"registry"`
 }
@@ -39,12 +50,74 @@ type ServerConfig struct {
 
 // A2AConfig holds A2A client configuration
 type A2AConfig struct {
-	Timeout         time.Duration     `yaml:"timeout" json:"timeout"`
-	RetryAttempts   int               `yaml:"retry_attempts" json:"retry_attempts"`
-	RetryDelay      time.Duration     `yaml:"retry_delay" json:"retry_delay"`
-	DefaultHeaders  map[string]string `yaml:"default_headers" json:"default_headers"`
-	StreamTimeout   time.Duration     `yaml:"stream_timeout" json:"stream_timeout"`
-	DiscoveryHosts  []string          `yaml:"discovery_hosts" json:"discovery_hosts"`
+	Timeout        time.Duration     `yaml:"timeout" json:"timeout"`
+	RetryAttempts  int               `yaml:"retry_attempts" json:"retry_attempts"`
+	RetryDelay     time.Duration     `yaml:"retry_delay" json:"retry_delay"`
+	RetryMaxDelay  time.Duration     `yaml:"retry_max_delay" json:"retry_max_delay"`
+	RetryJitter    float64           `yaml:"retry_jitter" json:"retry_jitter"`
+	DefaultHeaders map[string]string `yaml:"default_headers" json:"default_headers"`
+	StreamTimeout  time.Duration     `yaml:"stream_timeout" json:"stream_timeout"`
+	DiscoveryHosts []string          `yaml:"discovery_hosts" json:"discovery_hosts"`
+
+	// MethodTimeouts overrides timeout and stream_timeout for individual
+	// JSON-RPC methods, e.g. tasks/status: 5s
+	MethodTimeouts map[string]time.Duration `yaml:"method_timeouts" json:"method_timeouts"`
+
+	// AllowedHosts and DeniedHosts restrict the agent hosts that may be
+	// contacted; see netguard.Policy for the pattern syntax. DeniedHosts
+	// extends netguard.DefaultDeny.
+	AllowedHosts []string `yaml:"allowed_hosts" json:"allowed_hosts"`
+	DeniedHosts  []string `yaml:"denied_hosts" json:"denied_hosts"`
+
+	// AllowPrivateNetworks lets agent-advertised URLs point at private addresses
+	AllowPrivateNetworks bool `yaml:"allow_private_networks" json:"allow_private_networks"`
+
+	// ProxyURL routes agent traffic through an http, https or socks5 proxy;
+	// when empty the HTTP_PROXY/HTTPS_PROXY environment variables apply
+	ProxyURL string `yaml:"proxy_url" json:"proxy_url"`
+
+	// TLS holds the client certificate, key and CA bundle for agents
+	// behind mutual TLS; Enabled is ignored
+	TLS TLSConfig `yaml:"tls" json:"tls"`
+
+	// BreakerThreshold opens an agent's circuit breaker after this many
+	// consecutive failures (0 disables it); BreakerCoolDown is how long it
+	// stays open before probing the agent again
+	BreakerThreshold int           `yaml:"breaker_threshold" json:"breaker_threshold"`
+	BreakerCoolDown  time.Duration `yaml:"breaker_cool_down" json:"breaker_cool_down"`
+
+	// UploadThreshold is the size in bytes above which file parts are
+	// uploaded to an agent's upload endpoint in chunks of UploadChunkSize
+	// and sent by URL (0 sends every file inline)
+	UploadThreshold int64 `yaml:"upload_threshold" json:"upload_threshold"`
+	UploadChunkSize int64 `yaml:"upload_chunk_size" json:"upload_chunk_size"`
+}
+
+// RetryPolicy returns the retry policy shared by the A2A client and discoverer
+func (c A2AConfig) RetryPolicy() retry.Policy {
+	return retry.Policy{
+		Attempts:  c.RetryAttempts,
+		BaseDelay: c.RetryDelay,
+		MaxDelay:  c.RetryMaxDelay,
+		Jitter:    c.RetryJitter,
+	}
+}
+
+// BreakerPolicy returns the circuit breaker policy for the A2A client
+func (c A2AConfig) BreakerPolicy() client.BreakerPolicy {
+	return client.BreakerPolicy{
+		Threshold: c.BreakerThreshold,
+		CoolDown:  c.BreakerCoolDown,
+	}
+}
+
+// HostPolicy returns the host access policy shared by the A2A client and discoverer
+func (c A2AConfig) HostPolicy() *netguard.Policy {
+	policy := netguard.DefaultPolicy()
+	policy.Allow = append(policy.Allow, c.AllowedHosts...)
+	policy.Deny = append(policy.Deny, c.DeniedHosts...)
+	policy.AllowPrivate = c.AllowPrivateNetworks
+	return policy
 }
 
 // LoggingConfig holds logging configuration
@@ -52,6 +125,16 @@ type LoggingConfig struct {
 	Level  string `yaml:"level" json:"level"`
 	Format string `yaml:"format" json:"format"`
 	Output string `yaml:"output" json:"output"`
+
+	// Rotation of file output; ignored for stdout and stderr
+	MaxSizeMB  int  `yaml:"max_size_mb" json:"max_size_mb"`
+	MaxBackups int  `yaml:"max_backups" json:"max_backups"`
+	MaxAgeDays int  `yaml:"max_age_days" json:"max_age_days"`
+	Compress   bool `yaml:"compress" json:"compress"`
+
+	// RedactFields lists header and field names masked in log output;
+	// empty uses the logging package defaults
+	RedactFields []string `yaml:"redact_fields" json:"redact_fields"`
 }
 
 // RegistryConfig holds agent registry configuration
@@ -59,6 +142,17 @@ type RegistryConfig struct {
 	CleanupInterval time.Duration `yaml:"cleanup_interval" json:"cleanup_interval"`
 	StaleThreshold  time.Duration `yaml:"stale_threshold" json:"stale_threshold"`
 	MaxAgents       int           `yaml:"max_agents" json:"max_agents"`
+
+	// EvictOldest makes a full registry drop its least recently seen agent
+	// instead of rejecting new ones
+	EvictOldest bool `yaml:"evict_oldest" json:"evict_oldest"`
+
+	// HealthCheckInterval is how often the server pings registered agents
+	// to update their status; zero disables health checks.
+	// HealthCheckConcurrency bounds the pings in flight (default
+	// registry.DefaultHealthCheckConcurrency).
+	HealthCheckInterval    time.Duration `yaml:"health_check_interval" json:"health_check_interval"`
+	HealthCheckConcurrency int           `yaml:"health_check_concurrency" json:"health_check_concurrency"`
 }
 
 // TLSConfig holds TLS configuration
@@ -66,18 +160,22 @@ type TLSConfig struct {
 	Enabled  bool   `yaml:"enabled" json:"enabled"`
 	CertFile string `yaml:"cert_file" json:"cert_file"`
 	KeyFile  string `yaml:"key_file" json:"key_file"`
+	CAFile   string `yaml:"ca_file" json:"ca_file"`
 }
 
-var (
-	// Global configuration instance
-	globalConfig *Config
-	logger       = logrus.New()
-)
+// ClientTLS returns the certificate files for use as a TLS client, or nil
+// when none are set
+func (c TLSConfig) ClientTLS() *auth.ClientTLS {
+	if c.CertFile == "" && c.KeyFile == "" && c.CAFile == "" {
+		return nil
+	}
+	return &auth.ClientTLS{CertFile: c.CertFile, KeyFile: c.KeyFile, CAFile: c.CAFile}
+}
 
-// Init initializes the configuration system
-func Init(configFile string) error {
-	// Set default configuration
-	globalConfig = &Config{
+// Default returns the built-in configuration that files and environment
+// variables are layered over
+func Default() *Config {
+	return &Config{
 		Server: ServerConfig{
 			Host:         "localhost",
 			Port:         8080,
@@ -91,14 +189,19 @@ func Init(configFile string) error {
 			Timeout:        30 * time.Second,
 			RetryAttempts:  3,
 			RetryDelay:     1 * time.Second,
+			RetryMaxDelay:  30 * time.Second,
+			RetryJitter:    0.1,
 			StreamTimeout:  5 * time.Minute,
 			DefaultHeaders: make(map[string]string),
 			DiscoveryHosts: []string{},
 		},
 		Logging: LoggingConfig{
-			Level:  "info",
-			Format: "text",
-			Output: "stdout",
+			Level:      "info",
+			Format:     "text",
+			Output:     "stdout",
+			MaxSizeMB:  100,
+			MaxBackups: 3,
+			MaxAgeDays: 28,
 		},
 		Registry: RegistryConfig{
 			CleanupInterval: 5 * time.Minute,
@@ -106,27 +209,52 @@ func Init(configFile string) error {
 			MaxAgents:       100,
 		},
 	}
+}
+
+// DefaultPaths returns the locations searched, in order, for a config file
+// when none is given
+func DefaultPaths() []string {
+	return []string{
+		"./openribcage.yaml",
+		"./config/openribcage.yaml",
+		filepath.Join(os.Getenv("HOME"), ".openribcage.yaml"),
+		"/etc/openribcage/config.yaml",
+	}
+}
+
+var (
+	// Global configuration instance, replaced wholesale on reload
+	globalConfig atomic.Pointer[Config]
+	logger       = logrus.New()
+
+	// configPath is the file the configuration was loaded from, if any
+	configPath atomic.Value
+)
+
+// Init initializes the configuration system. If the loaded values fail
+// Validate, the *ValidationError is returned but the configuration stays
+// loaded, so that Get still works for diagnostics.
+func Init(configFile string) error {
+	cfg := Default()
+	loaded := ""
 
 	// Load configuration file if specified
 	if configFile != "" {
-		if err := loadConfigFile(configFile); err != nil {
+		var err error
+		if cfg, err = loadConfigFile(cfg, configFile); err != nil {
 			return fmt.Errorf("failed to load config file: %w", err)
 		}
+		loaded = configFile
 	} else {
 		// Try to load from default locations
-		defaultPaths := []string{
-			"./openribcage.yaml",
-			"./config/openribcage.yaml",
-			filepath.Join(os.Getenv("HOME"), ".openribcage.yaml"),
-			"/etc/openribcage/config.yaml",
-		}
-
-		for _, path := range defaultPaths {
+		for _, path := range DefaultPaths() {
 			if _, err := os.Stat(path); err == nil {
-				if err := loadConfigFile(path); err != nil {
+				fileCfg, err := loadConfigFile(cfg, path)
+				if err != nil {
 					logger.Warnf("Failed to load config from %s: %v", path, err)
 				} else {
 					logger.Infof("Loaded configuration from: %s", path)
+					cfg, loaded = fileCfg, path
 					break
 				}
 			}
@@ -134,42 +262,172 @@ func Init(configFile string) error {
 	}
 
 	// Override with environment variables
-	loadEnvironmentVariables()
+	loadEnvironmentVariables(cfg)
 
-	return nil
+	globalConfig.Store(cfg)
+	configPath.Store(loaded)
+	return cfg.Validate()
 }
 
-// Get returns the global configuration
+// Get returns the global configuration. The returned value is replaced,
+// never modified, when the configuration is reloaded, so callers that
+// need consistent settings should call Get once and keep the result.
 func Get() *Config {
-	return globalConfig
+	return globalConfig.Load()
 }
 
-// loadConfigFile loads configuration from a YAML file
-func loadConfigFile(filename string) error {
-	// TODO: Implement YAML configuration loading
-	// This will be implemented when needed
-	// Use gopkg.in/yaml.v3 for YAML parsing
-	return fmt.Errorf("YAML config loading not yet implemented")
+// Path returns the config file that was loaded, or "" if the defaults and
+// environment variables are in use
+func Path() string {
+	path, _ := configPath.Load().(string)
+	return path
 }
 
-// loadEnvironmentVariables loads configuration from environment variables
-func loadEnvironmentVariables() {
-	// Server configuration
-	if host := os.Getenv("OPENRIBCAGE_HOST"); host != "" {
-		globalConfig.Server.Host = host
+// loadConfigFile loads configuration from a YAML file, merging it over a
+// copy of base. Keys absent from the file keep their values from base;
+// unknown keys are logged as warnings rather than rejected.
+func loadConfigFile(base *Config, filename string) (*Config, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
 	}
 
-	// A2A configuration
-	if timeout := os.Getenv("OPENRIBCAGE_A2A_TIMEOUT"); timeout != "" {
-		if duration, err := time.ParseDuration(timeout); err == nil {
-			globalConfig.A2A.Timeout = duration
-		}
+	// Decode into a copy so a malformed file leaves base intact
+	cfg := base.clone()
+	if err := decodeYAML(data, cfg, false); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", filename, err)
+	}
+
+	// Strict decoding into a scratch value only reports unknown keys
+	if err := decodeYAML(data, &Config{}, true); err != nil {
+		logger.Warnf("Config file %s contains unrecognized settings: %v", filename, err)
+	}
+
+	return cfg, nil
+}
+
+// decodeYAML decodes YAML data into cfg. An empty document is not an error.
+func decodeYAML(data []byte, cfg *Config, strict bool) error {
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(strict)
+	if err := decoder.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+	return nil
+}
+
+// clone returns a deep copy of the configuration
+func (c *Config) clone() *Config {
+	cp := *c
+	cp.A2A.DefaultHeaders = make(map[string]string, len(c.A2A.DefaultHeaders))
+	for k, v := range c.A2A.DefaultHeaders {
+		cp.A2A.DefaultHeaders[k] = v
+	}
+	cp.A2A.MethodTimeouts = make(map[string]time.Duration, len(c.A2A.MethodTimeouts))
+	for k, v := range c.A2A.MethodTimeouts {
+		cp.A2A.MethodTimeouts[k] = v
 	}
+	cp.A2A.DiscoveryHosts = append([]string(nil), c.A2A.DiscoveryHosts...)
+	cp.A2A.AllowedHosts = append([]string(nil), c.A2A.AllowedHosts...)
+	cp.A2A.DeniedHosts = append([]string(nil), c.A2A.DeniedHosts...)
+	cp.Logging.RedactFields = append([]string(nil), c.Logging.RedactFields...)
+	return &cp
+}
+
+// loadEnvironmentVariables overrides cfg with environment
+// variables. Every variable is named OPENRIBCAGE_<SECTION>_<SETTING>:
+//
+//   - OPENRIBCAGE_SERVER_HOST (or the older OPENRIBCAGE_HOST)
+//   - OPENRIBCAGE_SERVER_PORT
+//   - OPENRIBCAGE_SERVER_READ_TIMEOUT, OPENRIBCAGE_SERVER_WRITE_TIMEOUT
+//   - OPENRIBCAGE_SERVER_TLS_ENABLED, OPENRIBCAGE_SERVER_TLS_CERT_FILE,
+//     OPENRIBCAGE_SERVER_TLS_KEY_FILE
+//   - OPENRIBCAGE_A2A_TIMEOUT, OPENRIBCAGE_A2A_STREAM_TIMEOUT
+//   - OPENRIBCAGE_A2A_RETRY_ATTEMPTS, OPENRIBCAGE_A2A_RETRY_DELAY
+//   - OPENRIBCAGE_LOGGING_LEVEL, OPENRIBCAGE_LOGGING_FORMAT,
+//     OPENRIBCAGE_LOGGING_OUTPUT (or the older OPENRIBCAGE_LOG_*)
+//   - OPENRIBCAGE_REGISTRY_CLEANUP_INTERVAL,
+//     OPENRIBCAGE_REGISTRY_STALE_THRESHOLD, OPENRIBCAGE_REGISTRY_MAX_AGENTS
+//
+// Durations use Go syntax (e.g. 30s) and booleans strconv.ParseBool
+// syntax. Malformed values are logged and ignored. When both a variable
+// and its older name are set, the variable wins.
+func loadEnvironmentVariables(cfg *Config) {
+	// Server configuration
+	envString("OPENRIBCAGE_HOST", &cfg.Server.Host)
+	envString("OPENRIBCAGE_SERVER_HOST", &cfg.Server.Host)
+	envInt("OPENRIBCAGE_SERVER_PORT", &cfg.Server.Port)
+	envDuration("OPENRIBCAGE_SERVER_READ_TIMEOUT", &cfg.Server.ReadTimeout)
+	envDuration("OPENRIBCAGE_SERVER_WRITE_TIMEOUT", &cfg.Server.WriteTimeout)
+	envBool("OPENRIBCAGE_SERVER_TLS_ENABLED", &cfg.Server.TLS.Enabled)
+	envString("OPENRIBCAGE_SERVER_TLS_CERT_FILE", &cfg.Server.TLS.CertFile)
+	envString("OPENRIBCAGE_SERVER_TLS_KEY_FILE", &cfg.Server.TLS.KeyFile)
+
+	// A2A configuration
+	envDuration("OPENRIBCAGE_A2A_TIMEOUT", &cfg.A2A.Timeout)
+	envDuration("OPENRIBCAGE_A2A_STREAM_TIMEOUT", &cfg.A2A.StreamTimeout)
+	envInt("OPENRIBCAGE_A2A_RETRY_ATTEMPTS", &cfg.A2A.RetryAttempts)
+	envDuration("OPENRIBCAGE_A2A_RETRY_DELAY", &cfg.A2A.RetryDelay)
 
 	// Logging configuration
-	if level := os.Getenv("OPENRIBCAGE_LOG_LEVEL"); level != "" {
-		globalConfig.Logging.Level = level
+	envString("OPENRIBCAGE_LOG_LEVEL", &cfg.Logging.Level)
+	envString("OPENRIBCAGE_LOG_FORMAT", &cfg.Logging.Format)
+	envString("OPENRIBCAGE_LOG_OUTPUT", &cfg.Logging.Output)
+	envString("OPENRIBCAGE_LOGGING_LEVEL", &cfg.Logging.Level)
+	envString("OPENRIBCAGE_LOGGING_FORMAT", &cfg.Logging.Format)
+	envString("OPENRIBCAGE_LOGGING_OUTPUT", &cfg.Logging.Output)
+
+	// Registry configuration
+	envDuration("OPENRIBCAGE_REGISTRY_CLEANUP_INTERVAL", &cfg.Registry.CleanupInterval)
+	envDuration("OPENRIBCAGE_REGISTRY_STALE_THRESHOLD", &cfg.Registry.StaleThreshold)
+	envInt("OPENRIBCAGE_REGISTRY_MAX_AGENTS", &cfg.Registry.MaxAgents)
+}
+
+// envString sets *dst to the named variable when it is set and not empty
+func envString(name string, dst *string) {
+	if value := os.Getenv(name); value != "" {
+		*dst = value
 	}
+}
 
-	// Add more environment variable mappings as needed
+// envDuration sets *dst to the named variable parsed as a duration
+func envDuration(name string, dst *time.Duration) {
+	value := os.Getenv(name)
+	if value == "" {
+		return
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		logger.Warnf("Ignoring %s: %q is not a duration (e.g. 30s)", name, value)
+		return
+	}
+	*dst = duration
+}
+
+// envInt sets *dst to the named variable parsed as an integer
+func envInt(name string, dst *int) {
+	value := os.Getenv(name)
+	if value == "" {
+		return
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		logger.Warnf("Ignoring %s: %q is not an integer", name, value)
+		return
+	}
+	*dst = n
+}
+
+// envBool sets *dst to the named variable parsed as a boolean
+func envBool(name string, dst *bool) {
+	value := os.Getenv(name)
+	if value == "" {
+		return
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		logger.Warnf("Ignoring %s: %q is not a boolean (use true or false)", name, value)
+		return
+	}
+	*dst = b
 }
@@ -0,0 +1,135 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/craine-io/openribcage/pkg/a2a/retry"
+	"github.com/craine-io/openribcage/pkg/netguard"
+)
+
+// TestRetryPolicy tests that the default A2A settings yield the default
+// retry policy and that each setting maps onto the policy
+func TestRetryPolicy(t *testing.T) {
+	assert.Equal(t, retry.DefaultPolicy(), Default().A2A.RetryPolicy())
+
+	a2a := A2AConfig{RetryAttempts: 5, RetryDelay: time.Second, RetryMaxDelay: time.Minute, RetryJitter: 0.25}
+	assert.Equal(t, retry.Policy{Attempts: 5, BaseDelay: time.Second, MaxDelay: time.Minute, Jitter: 0.25}, a2a.RetryPolicy())
+}
+
+// writeConfig writes a YAML config file into a temporary directory
+func writeConfig(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "openribcage.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	return path
+}
+
+// TestLoadConfigFile tests that YAML settings are merged over the defaults
+// and that unknown keys do not prevent loading
+func TestLoadConfigFile(t *testing.T) {
+	path := writeConfig(t, `
+server:
+  port: 9090
+a2a:
+  timeout: 45s
+  default_headers:
+    X-Tenant: acme
+  discovery_hosts:
+    - http://agents.example.com
+unknown_section:
+  enabled: true
+`)
+
+	cfg, err := loadConfigFile(Default(), path)
+	require.NoError(t, err)
+	assert.Equal(t, 9090, cfg.Server.Port)
+	assert.Equal(t, "localhost", cfg.Server.Host)
+	assert.Equal(t, 45*time.Second, cfg.A2A.Timeout)
+	assert.Equal(t, 3, cfg.A2A.RetryAttempts)
+	assert.Equal(t, map[string]string{"X-Tenant": "acme"}, cfg.A2A.DefaultHeaders)
+	assert.Equal(t, []string{"http://agents.example.com"}, cfg.A2A.DiscoveryHosts)
+	assert.Equal(t, "info", cfg.Logging.Level)
+}
+
+// TestLoadConfigFileKeepsBase tests that loading never modifies the base
+// configuration, even when the file is malformed
+func TestLoadConfigFileKeepsBase(t *testing.T) {
+	base := Default()
+	base.A2A.DefaultHeaders["X-Base"] = "1"
+
+	cfg, err := loadConfigFile(base, writeConfig(t, "a2a:\n  default_headers:\n    X-Tenant: acme\n"))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"X-Base": "1", "X-Tenant": "acme"}, cfg.A2A.DefaultHeaders)
+	assert.Equal(t, map[string]string{"X-Base": "1"}, base.A2A.DefaultHeaders)
+
+	_, err = loadConfigFile(base, writeConfig(t, "server: [port"))
+	assert.ErrorContains(t, err, "failed to parse")
+	assert.Equal(t, Default().Server, base.Server)
+
+	cfg, err = loadConfigFile(base, writeConfig(t, ""))
+	require.NoError(t, err)
+	assert.Equal(t, base.Server, cfg.Server)
+	assert.Equal(t, base.A2A.DefaultHeaders, cfg.A2A.DefaultHeaders)
+	assert.Equal(t, base.Registry, cfg.Registry)
+
+	_, err = loadConfigFile(base, filepath.Join(t.TempDir(), "missing.yaml"))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+// TestInitConfigFile tests that Init loads the given file and records its
+// path, and fails when the file cannot be loaded
+func TestInitConfigFile(t *testing.T) {
+	t.Cleanup(func() { Init("") })
+
+	path := writeConfig(t, "logging:\n  level: debug\n")
+	require.NoError(t, Init(path))
+	assert.Equal(t, path, Path())
+	assert.Equal(t, "debug", Get().Logging.Level)
+
+	err := Init(filepath.Join(t.TempDir(), "missing.yaml"))
+	assert.ErrorContains(t, err, "failed to load config file")
+}
+
+// TestHostPolicy tests that configured hosts extend the default policy
+func TestHostPolicy(t *testing.T) {
+	a2a := A2AConfig{AllowedHosts: []string{"*.example.com"}, DeniedHosts: []string{"bad.example.com"}, AllowPrivateNetworks: true}
+	policy := a2a.HostPolicy()
+
+	assert.Equal(t, []string{"*.example.com"}, policy.Allow)
+	assert.Equal(t, append(append([]string(nil), netguard.DefaultDeny...), "bad.example.com"), policy.Deny)
+	assert.True(t, policy.AllowPrivate)
+	assert.NoError(t, policy.CheckURL("https://agents.example.com"))
+	assert.ErrorIs(t, policy.CheckURL("https://bad.example.com"), netguard.ErrHostNotAllowed)
+	assert.ErrorIs(t, policy.CheckURL("https://other.org"), netguard.ErrHostNotAllowed)
+}
+
+// TestEnvironmentVariables tests that OPENRIBCAGE_<SECTION>_<SETTING>
+// variables override the config and win over their older aliases
+func TestEnvironmentVariables(t *testing.T) {
+	t.Setenv("OPENRIBCAGE_SERVER_PORT", "9191")
+	t.Setenv("OPENRIBCAGE_HOST", "old.example.com")
+	t.Setenv("OPENRIBCAGE_LOG_LEVEL", "warn")
+	t.Setenv("OPENRIBCAGE_LOG_FORMAT", "text")
+	t.Setenv("OPENRIBCAGE_LOGGING_FORMAT", "json")
+	t.Setenv("OPENRIBCAGE_A2A_TIMEOUT", "not a duration")
+
+	cfg := Default()
+	loadEnvironmentVariables(cfg)
+	assert.Equal(t, 9191, cfg.Server.Port)
+	assert.Equal(t, "old.example.com", cfg.Server.Host)
+	assert.Equal(t, "warn", cfg.Logging.Level)
+	assert.Equal(t, "json", cfg.Logging.Format)
+	assert.Equal(t, Default().A2A.Timeout, cfg.A2A.Timeout)
+
+	t.Setenv("OPENRIBCAGE_SERVER_HOST", "agents.example.com")
+	t.Setenv("OPENRIBCAGE_LOGGING_LEVEL", "debug")
+	cfg = Default()
+	loadEnvironmentVariables(cfg)
+	assert.Equal(t, "agents.example.com", cfg.Server.Host)
+	assert.Equal(t, "debug", cfg.Logging.Level)
+}
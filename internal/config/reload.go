@@ -0,0 +1,120 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// reloadDebounce collapses the burst of events an editor produces when
+// saving a file into a single reload
+const reloadDebounce = 200 * time.Millisecond
+
+var (
+	listenersMu sync.Mutex
+	listeners   []func(*Config)
+
+	// reloadMu serializes reloads
+	reloadMu sync.Mutex
+)
+
+// OnReload registers fn to be called with the new configuration after each
+// successful reload. Listeners run in registration order on the reloading
+// goroutine and must not block.
+func OnReload(fn func(*Config)) {
+	listenersMu.Lock()
+	defer listenersMu.Unlock()
+	listeners = append(listeners, fn)
+}
+
+// Reload re-reads the config file that Init loaded, applies environment
+// variables and validates the result. A file that cannot be read, parsed
+// or validated is rejected and the current configuration is kept;
+// otherwise it replaces the current configuration and every OnReload
+// listener is notified.
+func Reload() error {
+	path := Path()
+	if path == "" {
+		return errors.New("no config file was loaded")
+	}
+
+	reloadMu.Lock()
+	defer reloadMu.Unlock()
+
+	cfg, err := loadConfigFile(Default(), path)
+	if err != nil {
+		return fmt.Errorf("failed to reload config file: %w", err)
+	}
+	loadEnvironmentVariables(cfg)
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+
+	globalConfig.Store(cfg)
+	logger.Infof("Reloaded configuration from: %s", path)
+
+	listenersMu.Lock()
+	fns := append([]func(*Config){}, listeners...)
+	listenersMu.Unlock()
+	for _, fn := range fns {
+		fn(cfg)
+	}
+	return nil
+}
+
+// Watch reloads the configuration whenever the config file that Init
+// loaded changes, until ctx is done. Rejected reloads are logged and the
+// previous configuration stays in effect. The file's directory is watched
+// so that editors replacing the file on save are handled.
+func Watch(ctx context.Context) error {
+	path := Path()
+	if path == "" {
+		return errors.New("no config file was loaded")
+	}
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return fmt.Errorf("failed to resolve config file path: %w", err)
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to watch config file: %w", err)
+	}
+	defer watcher.Close()
+	if err := watcher.Add(filepath.Dir(path)); err != nil {
+		return fmt.Errorf("failed to watch config file: %w", err)
+	}
+
+	timer := time.NewTimer(reloadDebounce)
+	timer.Stop()
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if filepath.Clean(event.Name) != path || event.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Rename) == 0 {
+				continue
+			}
+			timer.Reset(reloadDebounce)
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			logger.Warnf("Config file watcher error: %v", err)
+		case <-timer.C:
+			if err := Reload(); err != nil {
+				logger.Warnf("Keeping previous configuration: %v", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package config
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestConfigHotReload tests that file changes are applied and that
+// malformed changes keep the previous configuration
+func TestConfigHotReload(t *testing.T) {
+	path := writeConfig(t, "logging:\n  level: info\n")
+	require.NoError(t, Init(path))
+
+	reloaded := make(chan *Config, 4)
+	OnReload(func(cfg *Config) { reloaded <- cfg })
+	t.Cleanup(func() {
+		listenersMu.Lock()
+		listeners = nil
+		listenersMu.Unlock()
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	watching := make(chan struct{})
+	go func() {
+		_ = Watch(ctx)
+		close(watching)
+	}()
+	defer func() {
+		cancel()
+		<-watching
+	}()
+	time.Sleep(100 * time.Millisecond)
+
+	require.NoError(t, os.WriteFile(path, []byte("logging:\n  level: debug\n"), 0o644))
+	select {
+	case cfg := <-reloaded:
+		assert.Equal(t, "debug", cfg.Logging.Level)
+		assert.Equal(t, "debug", Get().Logging.Level)
+	case <-time.After(5 * time.Second):
+		t.Fatal("configuration was not reloaded")
+	}
+
+	for _, bad := range []string{"logging: [not a map\n", "server:\n  port: 0\n"} {
+		require.NoError(t, os.WriteFile(path, []byte(bad), 0o644))
+		select {
+		case <-reloaded:
+			t.Fatalf("malformed configuration %q was applied", bad)
+		case <-time.After(time.Second):
+		}
+		assert.Equal(t, "debug", Get().Logging.Level)
+		assert.Equal(t, 8080, Get().Server.Port)
+	}
+}
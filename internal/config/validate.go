@@ -0,0 +1,123 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ValidationError lists every problem Config.Validate found, each naming
+// the offending setting
+type ValidationError struct {
+	Problems []string
+}
+
+// Error implements the error interface
+func (e *ValidationError) Error() string {
+	return "invalid configuration:\n  - " + strings.Join(e.Problems, "\n  - ")
+}
+
+// Validate checks the configuration for values that cannot work and
+// returns a *ValidationError listing all of them, or nil
+func (c *Config) Validate() error {
+	var problems []string
+	add := func(format string, args ...interface{}) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+	positive := func(name string, d time.Duration) {
+		if d <= 0 {
+			add("%s: must be positive, got %s (e.g. 30s)", name, d)
+		}
+	}
+	notNegative := func(name string, d time.Duration) {
+		if d < 0 {
+			add("%s: must not be negative, got %s", name, d)
+		}
+	}
+
+	if c.Server.Port < 1 || c.Server.Port > 65535 {
+		add("server.port: %d is outside 1-65535", c.Server.Port)
+	}
+	positive("server.read_timeout", c.Server.ReadTimeout)
+	positive("server.write_timeout", c.Server.WriteTimeout)
+	if c.Server.TLS.Enabled {
+		checkFile := func(name, path string) {
+			if path == "" {
+				add("%s: required when server.tls.enabled is set", name)
+			} else if _, err := os.Stat(path); err != nil {
+				add("%s: %v", name, err)
+			}
+		}
+		checkFile("server.tls.cert_file", c.Server.TLS.CertFile)
+		checkFile("server.tls.key_file", c.Server.TLS.KeyFile)
+	}
+
+	positive("a2a.timeout", c.A2A.Timeout)
+	positive("a2a.stream_timeout", c.A2A.StreamTimeout)
+	if c.A2A.RetryAttempts < 0 {
+		add("a2a.retry_attempts: must not be negative, got %d", c.A2A.RetryAttempts)
+	}
+	notNegative("a2a.retry_delay", c.A2A.RetryDelay)
+	notNegative("a2a.retry_max_delay", c.A2A.RetryMaxDelay)
+	if c.A2A.RetryJitter < 0 || c.A2A.RetryJitter > 1 {
+		add("a2a.retry_jitter: %g is outside 0-1", c.A2A.RetryJitter)
+	}
+	if c.A2A.BreakerThreshold < 0 {
+		add("a2a.breaker_threshold: must not be negative, got %d", c.A2A.BreakerThreshold)
+	}
+	notNegative("a2a.breaker_cool_down", c.A2A.BreakerCoolDown)
+	if c.A2A.UploadThreshold < 0 {
+		add("a2a.upload_threshold: must not be negative, got %d", c.A2A.UploadThreshold)
+	}
+	if c.A2A.UploadChunkSize < 0 {
+		add("a2a.upload_chunk_size: must not be negative, got %d", c.A2A.UploadChunkSize)
+	}
+	for _, method := range sortedKeys(c.A2A.MethodTimeouts) {
+		positive("a2a.method_timeouts."+method, c.A2A.MethodTimeouts[method])
+	}
+
+	if _, err := logrus.ParseLevel(c.Logging.Level); err != nil {
+		add("logging.level: unknown level %q (use debug, info, warn or error)", c.Logging.Level)
+	}
+	switch strings.ToLower(c.Logging.Format) {
+	case "text", "json", "":
+	default:
+		add("logging.format: unknown format %q (use text or json)", c.Logging.Format)
+	}
+	if c.Logging.MaxSizeMB < 0 {
+		add("logging.max_size_mb: must not be negative, got %d", c.Logging.MaxSizeMB)
+	}
+	if c.Logging.MaxBackups < 0 {
+		add("logging.max_backups: must not be negative, got %d", c.Logging.MaxBackups)
+	}
+	if c.Logging.MaxAgeDays < 0 {
+		add("logging.max_age_days: must not be negative, got %d", c.Logging.MaxAgeDays)
+	}
+
+	positive("registry.cleanup_interval", c.Registry.CleanupInterval)
+	notNegative("registry.stale_threshold", c.Registry.StaleThreshold)
+	if c.Registry.MaxAgents < 0 {
+		add("registry.max_agents: must not be negative, got %d (0 means unlimited)", c.Registry.MaxAgents)
+	}
+	notNegative("registry.health_check_interval", c.Registry.HealthCheckInterval)
+
+	if len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+	return nil
+}
+
+// sortedKeys returns the keys of m in order, so problems are reported
+// deterministically
+func sortedKeys(m map[string]time.Duration) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,68 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestConfigValidate tests each configuration validation rule
+func TestConfigValidate(t *testing.T) {
+	defaults := *Default()
+	require.NoError(t, defaults.Validate())
+	certFile := filepath.Join(t.TempDir(), "server.crt")
+	require.NoError(t, os.WriteFile(certFile, []byte("certificate"), 0o600))
+
+	tests := []struct {
+		name    string
+		mutate  func(c *Config)
+		problem string
+	}{
+		{"zero port", func(c *Config) { c.Server.Port = 0 }, "server.port"},
+		{"port out of range", func(c *Config) { c.Server.Port = 70000 }, "server.port"},
+		{"zero read timeout", func(c *Config) { c.Server.ReadTimeout = 0 }, "server.read_timeout"},
+		{"negative write timeout", func(c *Config) { c.Server.WriteTimeout = -time.Second }, "server.write_timeout"},
+		{"TLS without cert", func(c *Config) {
+			c.Server.TLS = TLSConfig{Enabled: true, KeyFile: certFile}
+		}, "server.tls.cert_file"},
+		{"TLS with missing key", func(c *Config) {
+			c.Server.TLS = TLSConfig{Enabled: true, CertFile: certFile, KeyFile: filepath.Join(t.TempDir(), "missing-key.pem")}
+		}, "server.tls.key_file"},
+		{"zero a2a timeout", func(c *Config) { c.A2A.Timeout = 0 }, "a2a.timeout"},
+		{"zero stream timeout", func(c *Config) { c.A2A.StreamTimeout = 0 }, "a2a.stream_timeout"},
+		{"negative retries", func(c *Config) { c.A2A.RetryAttempts = -1 }, "a2a.retry_attempts"},
+		{"negative retry delay", func(c *Config) { c.A2A.RetryDelay = -time.Second }, "a2a.retry_delay"},
+		{"jitter above one", func(c *Config) { c.A2A.RetryJitter = 1.5 }, "a2a.retry_jitter"},
+		{"negative breaker threshold", func(c *Config) { c.A2A.BreakerThreshold = -1 }, "a2a.breaker_threshold"},
+		{"unknown log level", func(c *Config) { c.Logging.Level = "loud" }, "logging.level"},
+		{"unknown log format", func(c *Config) { c.Logging.Format = "xml" }, "logging.format"},
+		{"zero cleanup interval", func(c *Config) { c.Registry.CleanupInterval = 0 }, "registry.cleanup_interval"},
+		{"negative max agents", func(c *Config) { c.Registry.MaxAgents = -1 }, "registry.max_agents"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := defaults
+			tt.mutate(&cfg)
+
+			var validationErr *ValidationError
+			require.ErrorAs(t, cfg.Validate(), &validationErr)
+			require.Len(t, validationErr.Problems, 1)
+			assert.Contains(t, validationErr.Problems[0], tt.problem)
+		})
+	}
+
+	t.Run("problems are combined", func(t *testing.T) {
+		cfg := defaults
+		cfg.Server.Port = 0
+		cfg.Logging.Level = "loud"
+
+		var validationErr *ValidationError
+		require.ErrorAs(t, cfg.Validate(), &validationErr)
+		assert.Len(t, validationErr.Problems, 2)
+	})
+}
@@ -0,0 +1,179 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// settingComments documents each setting in files written by EncodeYAML,
+// keyed by YAML path. Settings shared by several sections, such as tls,
+// are also looked up without their section.
+var settingComments = map[string]string{
+	"server":               "REST and WebSocket API served by openribcage serve",
+	"server.host":          "Interface to listen on",
+	"server.port":          "Port to listen on (1-65535)",
+	"server.read_timeout":  "Maximum duration for reading a request",
+	"server.write_timeout": "Maximum duration for writing a response",
+	"server.tls":           "Serve HTTPS with this certificate and key when enabled",
+
+	"tls.enabled":   "Serve HTTPS; requires cert_file and key_file",
+	"tls.cert_file": "PEM certificate file",
+	"tls.key_file":  "PEM private key file",
+	"tls.ca_file":   "PEM CA bundle used to verify peers",
+
+	"a2a":                        "Client settings for talking to A2A agents",
+	"a2a.timeout":                "Timeout for a single request",
+	"a2a.retry_attempts":         "Retries of failed requests (0 disables retries)",
+	"a2a.retry_delay":            "Delay before the first retry, doubled on each attempt",
+	"a2a.retry_max_delay":        "Upper bound on the delay between retries",
+	"a2a.retry_jitter":           "Random fraction (0-1) added to retry delays",
+	"a2a.default_headers":        "Headers sent with every request",
+	"a2a.stream_timeout":         "Maximum duration of a streamed task",
+	"a2a.method_timeouts":        "Per-method timeouts overriding timeout and stream_timeout, e.g. tasks/status: 5s",
+	"a2a.discovery_hosts":        "Agent URLs checked by discover --check-hosts and doctor",
+	"a2a.allowed_hosts":          "Only contact these hosts when set (e.g. *.example.com)",
+	"a2a.denied_hosts":           "Never contact these hosts",
+	"a2a.allow_private_networks": "Let agent-advertised URLs point at private addresses",
+	"a2a.proxy_url":              "http, https or socks5 proxy for agent traffic",
+	"a2a.tls":                    "Client certificate and CA bundle for agents behind mutual TLS",
+	"a2a.tls.enabled":            "Ignored for the client; set cert_file and key_file instead",
+	"a2a.breaker_threshold":      "Consecutive failures that open an agent's circuit breaker (0 disables it)",
+	"a2a.breaker_cool_down":      "How long a breaker stays open before probing the agent",
+	"a2a.upload_threshold":       "File size in bytes above which files are uploaded to the agent's upload endpoint (0 sends them inline)",
+	"a2a.upload_chunk_size":      "Size in bytes of each upload chunk (0 uses the client default)",
+
+	"logging":               "Log output",
+	"logging.level":         "debug, info, warn or error",
+	"logging.format":        "text or json",
+	"logging.output":        "stdout, stderr or a file path",
+	"logging.max_size_mb":   "Rotate the log file at this size in megabytes",
+	"logging.max_backups":   "Rotated log files to keep (0 keeps all)",
+	"logging.max_age_days":  "Days to keep rotated log files (0 keeps them forever)",
+	"logging.compress":      "Gzip rotated log files",
+	"logging.redact_fields": "Header and field names masked in logs (default: Authorization, X-API-Key, token, password)",
+
+	"registry":                          "Registry of discovered agents",
+	"registry.cleanup_interval":         "How often stale agents are removed",
+	"registry.stale_threshold":          "Age after which an agent counts as stale",
+	"registry.max_agents":               "Maximum number of agents (0 means unlimited)",
+	"registry.evict_oldest":             "Evict the least recently seen agent when full instead of rejecting new ones",
+	"registry.health_check_interval":    "How often openribcage serve pings agents (0 disables it)",
+	"registry.health_check_concurrency": "Pings in flight at once (0 uses the default)",
+}
+
+// EncodeYAML renders the configuration as YAML with a comment above each
+// setting. Durations are written in Go syntax, e.g. 30s, and the result
+// loads back through the config file loader.
+func (c *Config) EncodeYAML() ([]byte, error) {
+	node, err := yamlNode(reflect.ValueOf(*c), "")
+	if err != nil {
+		return nil, err
+	}
+	node.HeadComment = "openribcage configuration"
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(node); err != nil {
+		return nil, fmt.Errorf("failed to encode configuration: %w", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, fmt.Errorf("failed to encode configuration: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// yamlNode converts v to a YAML node, commenting struct fields from
+// settingComments
+func yamlNode(v reflect.Value, path string) (*yaml.Node, error) {
+	if d, ok := v.Interface().(time.Duration); ok {
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: d.String()}, nil
+	}
+
+	if v.Kind() != reflect.Struct {
+		node := &yaml.Node{}
+		if err := node.Encode(v.Interface()); err != nil {
+			return nil, fmt.Errorf("failed to encode %s: %w", path, err)
+		}
+		return node, nil
+	}
+
+	node := &yaml.Node{Kind: yaml.MappingNode}
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+		if name == "" || name == "-" || !field.IsExported() {
+			continue
+		}
+
+		key := name
+		if path != "" {
+			key = path + "." + name
+		}
+		value, err := yamlNode(v.Field(i), key)
+		if err != nil {
+			return nil, err
+		}
+		node.Content = append(node.Content,
+			&yaml.Node{Kind: yaml.ScalarNode, Value: name, HeadComment: settingComment(key)},
+			value,
+		)
+	}
+	return node, nil
+}
+
+// settingComment returns the comment for the setting at path
+func settingComment(path string) string {
+	if comment, ok := settingComments[path]; ok {
+		return comment
+	}
+	if _, rest, ok := strings.Cut(path, "."); ok {
+		return settingComments[rest]
+	}
+	return ""
+}
+
+// WriteDefault writes the default configuration as commented YAML to
+// path, or to the first of DefaultPaths when path is empty, and returns
+// the path written. An existing file is only replaced when force is set.
+func WriteDefault(path string, force bool) (string, error) {
+	if path == "" {
+		path = DefaultPaths()[0]
+	}
+
+	data, err := Default().EncodeYAML()
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return "", fmt.Errorf("failed to create config directory: %w", err)
+	}
+	flags := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	if !force {
+		flags |= os.O_EXCL
+	}
+	f, err := os.OpenFile(path, flags, 0o644)
+	if errors.Is(err, os.ErrExist) {
+		return "", fmt.Errorf("%s already exists (use --force to overwrite)", path)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to write config file: %w", err)
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return "", fmt.Errorf("failed to write config file: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return "", fmt.Errorf("failed to write config file: %w", err)
+	}
+	return path, nil
+}
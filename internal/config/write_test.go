@@ -0,0 +1,31 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestConfigWriteDefault tests that the generated default config file
+// loads back to the same configuration
+func TestConfigWriteDefault(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "openribcage.yaml")
+
+	written, err := WriteDefault(path, false)
+	require.NoError(t, err)
+	assert.Equal(t, path, written)
+
+	_, err = WriteDefault(path, false)
+	assert.ErrorContains(t, err, "already exists")
+	_, err = WriteDefault(path, true)
+	require.NoError(t, err)
+
+	require.NoError(t, Init(path))
+	loaded, err := Get().EncodeYAML()
+	require.NoError(t, err)
+	defaults, err := Default().EncodeYAML()
+	require.NoError(t, err)
+	assert.Equal(t, string(defaults), string(loaded))
+}
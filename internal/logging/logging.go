@@ -7,19 +7,49 @@ package logging
 import (
 	"fmt"
 	"io"
+	"net/http"
 	"os"
 	"strings"
+	"sync/atomic"
 
 	"github.com/sirupsen/logrus"
+	"gopkg.in/natefinch/lumberjack.v2"
 )
 
 // Logger wraps logrus with additional A2A-specific functionality
 type Logger struct {
 	*logrus.Logger
+
+	// closer closes the log file when output is a file
+	closer io.Closer
+
+	// redactor masks sensitive fields in every entry
+	redactor atomic.Pointer[Redactor]
+}
+
+// Rotation controls how a log file is rotated. Zero values use the
+// defaults: rotate at 100 MB and keep every backup uncompressed.
+type Rotation struct {
+	// MaxSizeMB is the size in megabytes at which the file is rotated
+	MaxSizeMB int
+	// MaxBackups is the number of rotated files to keep
+	MaxBackups int
+	// MaxAgeDays is the number of days to keep rotated files
+	MaxAgeDays int
+	// Compress gzips rotated files
+	Compress bool
 }
 
-// NewLogger creates a new structured logger
+// NewLogger creates a new structured logger, rotating file output with
+// the default Rotation
 func NewLogger(level, format, output string) (*Logger, error) {
+	return NewLoggerWithRotation(level, format, output, Rotation{})
+}
+
+// NewLoggerWithRotation creates a new structured logger. When output is a
+// file it is rotated according to rotation; rotation is ignored for
+// stdout and stderr.
+func NewLoggerWithRotation(level, format, output string, rotation Rotation) (*Logger, error) {
 	logger := logrus.New()
 
 	// Set log level
@@ -52,22 +82,44 @@ func NewLogger(level, format, output string) (*Logger, error) {
 
 	// Set output destination
 	var writer io.Writer
+	var closer io.Closer
 	switch strings.ToLower(output) {
 	case "stdout", "":
 		writer = os.Stdout
 	case "stderr":
 		writer = os.Stderr
 	default:
-		// Try to open as file
+		// Check the file can be opened now rather than on the first write
 		file, err := os.OpenFile(output, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0666)
 		if err != nil {
 			return nil, err
 		}
-		writer = file
+		file.Close()
+
+		rotator := &lumberjack.Logger{
+			Filename:   output,
+			MaxSize:    rotation.MaxSizeMB,
+			MaxBackups: rotation.MaxBackups,
+			MaxAge:     rotation.MaxAgeDays,
+			Compress:   rotation.Compress,
+		}
+		writer = rotator
+		closer = rotator
 	}
 	logger.SetOutput(writer)
 
-	return &Logger{Logger: logger}, nil
+	l := &Logger{Logger: logger, closer: closer}
+	l.redactor.Store(NewRedactor())
+	logger.AddHook(redactHook{logger: l})
+	return l, nil
+}
+
+// Close closes the log file, if any. Logging after Close reopens it.
+func (l *Logger) Close() error {
+	if l.closer == nil {
+		return nil
+	}
+	return l.closer.Close()
 }
 
 // WithA2AContext adds A2A-specific context to log entries
@@ -108,14 +160,16 @@ func (l *Logger) WithAvatarContext(avatarID, personaID string) *logrus.Entry {
 	})
 }
 
-// LogA2ARequest logs an A2A protocol request
-func (l *Logger) LogA2ARequest(method, agentURL, taskID string) {
-	l.WithA2AContext("", taskID, method).Infof("A2A request: %s -> %s", method, agentURL)
+// LogA2ARequest logs an A2A protocol request with its headers, redacting
+// sensitive ones
+func (l *Logger) LogA2ARequest(method, agentURL, taskID string, headers http.Header) {
+	l.WithA2AContext("", taskID, method).WithField("headers", headers).Infof("A2A request: %s -> %s", method, agentURL)
 }
 
-// LogA2AResponse logs an A2A protocol response
-func (l *Logger) LogA2AResponse(method, agentURL, taskID string, success bool, duration string) {
-	entry := l.WithA2AContext("", taskID, method).WithField("duration", duration)
+// LogA2AResponse logs an A2A protocol response with its headers,
+// redacting sensitive ones
+func (l *Logger) LogA2AResponse(method, agentURL, taskID string, headers http.Header, success bool, duration string) {
+	entry := l.WithA2AContext("", taskID, method).WithField("duration", duration).WithField("headers", headers)
 	if success {
 		entry.Infof("A2A response success: %s <- %s", method, agentURL)
 	} else {
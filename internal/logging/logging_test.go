@@ -0,0 +1,36 @@
+package logging
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestLogRotation tests that file output rolls over once it reaches the
+// configured size
+func TestLogRotation(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "openribcage.log")
+
+	logger, err := NewLoggerWithRotation("info", "text", path, Rotation{MaxSizeMB: 1, MaxBackups: 2})
+	require.NoError(t, err)
+	defer logger.Close()
+
+	line := strings.Repeat("x", 1024)
+	for i := 0; i < 1500; i++ {
+		logger.Info(line)
+	}
+	require.NoError(t, logger.Close())
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 2, "expected the active file and one backup")
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.LessOrEqual(t, info.Size(), int64(1024*1024))
+}
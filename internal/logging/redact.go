@@ -0,0 +1,112 @@
+package logging
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// RedactedValue replaces the value of a sensitive header or field
+const RedactedValue = "[REDACTED]"
+
+// DefaultSensitiveFields are the header and field names redacted when no
+// list is configured
+var DefaultSensitiveFields = []string{"Authorization", "X-API-Key", "token", "password"}
+
+// Redactor masks the values of sensitive header and field names. Names
+// are matched case-insensitively.
+type Redactor struct {
+	names map[string]struct{}
+}
+
+// NewRedactor creates a redactor for the given names, or for
+// DefaultSensitiveFields when none are given
+func NewRedactor(names ...string) *Redactor {
+	if len(names) == 0 {
+		names = DefaultSensitiveFields
+	}
+	r := &Redactor{names: make(map[string]struct{}, len(names))}
+	for _, name := range names {
+		r.names[strings.ToLower(name)] = struct{}{}
+	}
+	return r
+}
+
+// IsSensitive reports whether values named name are redacted
+func (r *Redactor) IsSensitive(name string) bool {
+	_, ok := r.names[strings.ToLower(name)]
+	return ok
+}
+
+// Headers returns a copy of h with sensitive header values redacted
+func (r *Redactor) Headers(h http.Header) http.Header {
+	out := make(http.Header, len(h))
+	for name, values := range h {
+		if r.IsSensitive(name) {
+			out[name] = []string{RedactedValue}
+			continue
+		}
+		out[name] = append([]string(nil), values...)
+	}
+	return out
+}
+
+// Fields returns a copy of fields with sensitive values redacted. Header
+// and string map values are redacted by key as well.
+func (r *Redactor) Fields(fields logrus.Fields) logrus.Fields {
+	out := make(logrus.Fields, len(fields))
+	for name, value := range fields {
+		out[name] = r.value(name, value)
+	}
+	return out
+}
+
+// value redacts a single field value
+func (r *Redactor) value(name string, value interface{}) interface{} {
+	if r.IsSensitive(name) {
+		return RedactedValue
+	}
+	switch v := value.(type) {
+	case http.Header:
+		return r.Headers(v)
+	case map[string]string:
+		out := make(map[string]string, len(v))
+		for k, s := range v {
+			if r.IsSensitive(k) {
+				s = RedactedValue
+			}
+			out[k] = s
+		}
+		return out
+	}
+	return value
+}
+
+// redactHook redacts every entry's fields before it is formatted
+type redactHook struct {
+	logger *Logger
+}
+
+// Levels implements logrus.Hook
+func (h redactHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire implements logrus.Hook
+func (h redactHook) Fire(entry *logrus.Entry) error {
+	entry.Data = h.logger.redactor.Load().Fields(entry.Data)
+	return nil
+}
+
+// SetSensitiveFields replaces the header and field names this logger
+// redacts. No names restores DefaultSensitiveFields.
+func (l *Logger) SetSensitiveFields(names ...string) {
+	l.redactor.Store(NewRedactor(names...))
+}
+
+// WithHeaders adds HTTP headers to log entries with sensitive values
+// redacted
+func (l *Logger) WithHeaders(h http.Header) *logrus.Entry {
+	return l.WithField("headers", l.redactor.Load().Headers(h))
+}
@@ -0,0 +1,53 @@
+package logging
+
+import (
+	"bytes"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestLogRedaction tests that sensitive headers and fields never reach
+// formatted log output
+func TestLogRedaction(t *testing.T) {
+	const token = "Bearer s3cr3t-token"
+
+	for _, format := range []string{"text", "json"} {
+		t.Run(format, func(t *testing.T) {
+			logger, err := NewLogger("debug", format, "stdout")
+			require.NoError(t, err)
+			var buf bytes.Buffer
+			logger.SetOutput(&buf)
+
+			headers := http.Header{}
+			headers.Set("Authorization", token)
+			headers.Set("X-Api-Key", token)
+			headers.Set("Content-Type", "application/json")
+
+			logger.LogA2ARequest("tasks/send", "http://agent.example", "task-1", headers)
+			logger.LogA2AResponse("tasks/send", "http://agent.example", "task-1", headers, true, "5ms")
+			logger.WithHeaders(headers).Info("headers")
+			logger.WithField("token", token).WithField("Password", token).Info("fields")
+			logger.WithField("default_headers", map[string]string{"authorization": token}).Info("config")
+
+			out := buf.String()
+			assert.NotContains(t, out, "s3cr3t")
+			assert.Contains(t, out, RedactedValue)
+			assert.Contains(t, out, "application/json")
+		})
+	}
+
+	t.Run("custom fields", func(t *testing.T) {
+		logger, err := NewLogger("info", "text", "stdout")
+		require.NoError(t, err)
+		var buf bytes.Buffer
+		logger.SetOutput(&buf)
+		logger.SetSensitiveFields("X-Session")
+
+		logger.WithField("x-session", token).WithField("token", "visible").Info("custom")
+		assert.NotContains(t, buf.String(), "s3cr3t")
+		assert.Contains(t, buf.String(), "visible")
+	})
+}
@@ -0,0 +1,273 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/craine-io/openribcage/pkg/a2a/client"
+	"github.com/craine-io/openribcage/pkg/a2a/types"
+	"github.com/craine-io/openribcage/pkg/netguard"
+	"github.com/craine-io/openribcage/pkg/registry"
+)
+
+// apiPrefix is the path under which the REST API is served
+const apiPrefix = "/api/v1"
+
+// maxRequestBody limits the size of REST request bodies
+const maxRequestBody = 1 << 20
+
+// discoverRequest is the body of POST /api/v1/discover
+type discoverRequest struct {
+	URL string `json:"url"`
+}
+
+// taskRequest is the body of POST /api/v1/agents/{id}/tasks. Either
+// Message or Text must be set.
+type taskRequest struct {
+	TaskID  string         `json:"taskId,omitempty"`
+	Message *types.Message `json:"message,omitempty"`
+	Text    string         `json:"text,omitempty"`
+}
+
+// toTaskRequest builds the A2A request, generating a task ID when none is given
+func (r *taskRequest) toTaskRequest() (*types.TaskRequest, error) {
+	msg := r.Message
+	if msg == nil {
+		if r.Text == "" {
+			return nil, errors.New("message or text is required")
+		}
+		msg = &types.Message{
+			Role:  "user",
+			Parts: []types.Part{{Type: "text", Text: r.Text}},
+		}
+	}
+
+	if err := msg.Validate(); err != nil {
+		return nil, err
+	}
+
+	req := types.NewTaskRequest(msg)
+	if r.TaskID != "" {
+		req.ID = r.TaskID
+	}
+	return req, nil
+}
+
+// errorResponse is the body of every non-2xx API response
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// Handler returns the gateway's HTTP handler
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", s.handleHealth)
+	if s.metrics != nil {
+		mux.Handle("/metrics", s.metrics)
+	}
+	mux.HandleFunc(apiPrefix+"/discover", s.handleDiscover)
+	mux.HandleFunc(apiPrefix+"/stream", s.handleStreamRoot)
+	mux.HandleFunc(apiPrefix+"/agents", s.handleAgents)
+	mux.HandleFunc(apiPrefix+"/breakers", s.handleBreakers)
+	mux.HandleFunc(apiPrefix+"/agents/", s.handleAgent)
+	return mux
+}
+
+// handleHealth reports that the gateway is up
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// handleDiscover fetches an agent's card and registers the agent
+func (s *Server) handleDiscover(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodPost) {
+		return
+	}
+
+	var body discoverRequest
+	if err := decodeRequest(w, r, &body); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if body.URL == "" {
+		writeError(w, http.StatusBadRequest, errors.New("url is required"))
+		return
+	}
+
+	card, err := s.discoverer.Discover(r.Context(), body.URL)
+	if err != nil {
+		status := http.StatusBadGateway
+		if errors.Is(err, netguard.ErrHostNotAllowed) {
+			status = http.StatusForbidden
+		}
+		writeError(w, status, fmt.Errorf("discovery failed: %w", err))
+		return
+	}
+
+	now := time.Now()
+	agent := &types.Agent{
+		ID:            s.registry.AgentID(card.Name, body.URL),
+		Name:          card.Name,
+		URL:           body.URL,
+		Card:          card,
+		Status:        types.AgentStatusOnline,
+		LastSeen:      now,
+		DiscoveredAt:  now,
+		CardFetchedAt: now,
+	}
+	if err := s.registry.Register(agent); err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, registry.ErrRegistryFull) {
+			status = http.StatusInsufficientStorage
+		}
+		writeError(w, status, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, types.NewAgentSummary(agent))
+}
+
+// handleAgents lists the registered agents
+func (s *Server) handleAgents(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodGet) {
+		return
+	}
+	writeJSON(w, http.StatusOK, s.registry.Summaries())
+}
+
+// handleBreakers reports the circuit breaker state of each agent
+func (s *Server) handleBreakers(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodGet) {
+		return
+	}
+	writeJSON(w, http.StatusOK, s.breakerStatuses())
+}
+
+// handleAgent routes the per-agent endpoints:
+//
+//	GET  /api/v1/agents/{id}
+//	POST /api/v1/agents/{id}/tasks
+//	GET  /api/v1/agents/{id}/tasks/{taskId}
+//	POST /api/v1/agents/{id}/tasks/{taskId}/cancel
+//	GET  /api/v1/agents/{id}/stream (WebSocket)
+func (s *Server) handleAgent(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, apiPrefix+"/agents/"), "/"), "/")
+
+	agent, err := s.registry.Get(parts[0])
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+
+	switch {
+	case len(parts) == 1:
+		if allowMethod(w, r, http.MethodGet) {
+			writeJSON(w, http.StatusOK, agent)
+		}
+	case len(parts) == 2 && parts[1] == "tasks":
+		if allowMethod(w, r, http.MethodPost) {
+			s.handleSendTask(w, r, agent)
+		}
+	case len(parts) == 2 && parts[1] == "stream":
+		if allowMethod(w, r, http.MethodGet) {
+			s.handleStream(w, r, agent)
+		}
+	case len(parts) == 3 && parts[1] == "tasks":
+		if allowMethod(w, r, http.MethodGet) {
+			s.handleTaskStatus(w, r, agent, parts[2])
+		}
+	case len(parts) == 4 && parts[1] == "tasks" && parts[3] == "cancel":
+		if allowMethod(w, r, http.MethodPost) {
+			s.handleCancelTask(w, r, agent, parts[2])
+		}
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// handleSendTask sends a task to the agent and returns its response
+func (s *Server) handleSendTask(w http.ResponseWriter, r *http.Request, agent *types.Agent) {
+	var body taskRequest
+	if err := decodeRequest(w, r, &body); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	req, err := body.toTaskRequest()
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	resp, err := s.clientFor(agent.URL).SendTask(r.Context(), "", req)
+	if err != nil {
+		writeError(w, upstreamStatus(err), fmt.Errorf("failed to send task: %w", err))
+		return
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// handleTaskStatus returns the current status of a task
+func (s *Server) handleTaskStatus(w http.ResponseWriter, r *http.Request, agent *types.Agent, taskID string) {
+	status, err := s.clientFor(agent.URL).GetTaskStatus(r.Context(), "", taskID)
+	if err != nil {
+		writeError(w, upstreamStatus(err), fmt.Errorf("failed to get task status: %w", err))
+		return
+	}
+	writeJSON(w, http.StatusOK, status)
+}
+
+// handleCancelTask cancels a task
+func (s *Server) handleCancelTask(w http.ResponseWriter, r *http.Request, agent *types.Agent, taskID string) {
+	if err := s.clientFor(agent.URL).CancelTask(r.Context(), "", taskID); err != nil {
+		writeError(w, upstreamStatus(err), fmt.Errorf("failed to cancel task: %w", err))
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// upstreamStatus maps an error from an outbound call to a response status
+func upstreamStatus(err error) int {
+	switch {
+	case errors.Is(err, client.ErrTaskNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, netguard.ErrHostNotAllowed):
+		return http.StatusForbidden
+	case errors.Is(err, client.ErrCircuitOpen), errors.Is(err, client.ErrClientClosed):
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusBadGateway
+	}
+}
+
+// allowMethod replies 405 unless r uses method
+func allowMethod(w http.ResponseWriter, r *http.Request, method string) bool {
+	if r.Method == method {
+		return true
+	}
+	w.Header().Set("Allow", method)
+	writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", r.Method))
+	return false
+}
+
+// decodeRequest decodes a JSON request body of bounded size into v
+func decodeRequest(w http.ResponseWriter, r *http.Request, v interface{}) error {
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBody)).Decode(v); err != nil {
+		return fmt.Errorf("invalid request body: %w", err)
+	}
+	return nil
+}
+
+// writeJSON writes v as a JSON response with the given status
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes err as a JSON error response
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, errorResponse{Error: err.Error()})
+}
@@ -0,0 +1,191 @@
+// Package server implements the openribcage gateway.
+//
+// The gateway exposes the agents in a registry to avatar interfaces over a
+// small REST API and a WebSocket endpoint, forwarding each call to the
+// agent with an A2A client.
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/craine-io/openribcage/internal/config"
+	"github.com/craine-io/openribcage/pkg/a2a/client"
+	"github.com/craine-io/openribcage/pkg/a2a/types"
+	"github.com/craine-io/openribcage/pkg/agentcard"
+	"github.com/craine-io/openribcage/pkg/registry"
+)
+
+// DefaultShutdownTimeout bounds how long Run waits for in-flight requests
+// to finish once its context is canceled
+const DefaultShutdownTimeout = 10 * time.Second
+
+// Server is an HTTP gateway between avatar clients and A2A agents
+type Server struct {
+	config     config.ServerConfig
+	clientBase client.Config
+	registry   *registry.Registry
+	discoverer *agentcard.Discoverer
+	logger     *logrus.Logger
+
+	// metrics serves /metrics when set
+	metrics http.Handler
+
+	mu      sync.Mutex
+	clients map[string]*client.Client
+}
+
+// New creates a gateway serving the agents in reg. clientBase is the
+// template for outbound clients; its BaseURL is replaced by each agent's URL.
+func New(cfg config.ServerConfig, clientBase client.Config, reg *registry.Registry, discoverer *agentcard.Discoverer) *Server {
+	return &Server{
+		config:     cfg,
+		clientBase: clientBase,
+		registry:   reg,
+		discoverer: discoverer,
+		logger:     logrus.StandardLogger(),
+		clients:    make(map[string]*client.Client),
+	}
+}
+
+// SetLogger sets the logger used for request and lifecycle messages
+func (s *Server) SetLogger(logger *logrus.Logger) {
+	if logger != nil {
+		s.logger = logger
+	}
+}
+
+// SetMetricsHandler mounts h, typically a promhttp handler, at /metrics
+func (s *Server) SetMetricsHandler(h http.Handler) {
+	s.metrics = h
+}
+
+// Addr returns the host:port the server listens on
+func (s *Server) Addr() string {
+	return net.JoinHostPort(s.config.Host, strconv.Itoa(s.config.Port))
+}
+
+// Run serves until ctx is canceled, then shuts down gracefully, waiting up
+// to DefaultShutdownTimeout for in-flight requests, and closes the
+// outbound clients
+func (s *Server) Run(ctx context.Context) error {
+	httpServer := &http.Server{
+		Addr:         s.Addr(),
+		Handler:      s.Handler(),
+		ReadTimeout:  s.config.ReadTimeout,
+		WriteTimeout: s.config.WriteTimeout,
+	}
+	defer s.closeClients()
+
+	errCh := make(chan error, 1)
+	go func() {
+		s.logger.Infof("Gateway listening on %s", httpServer.Addr)
+		if s.config.TLS.Enabled {
+			errCh <- httpServer.ListenAndServeTLS(s.config.TLS.CertFile, s.config.TLS.KeyFile)
+		} else {
+			errCh <- httpServer.ListenAndServe()
+		}
+	}()
+
+	select {
+	case err := <-errCh:
+		return fmt.Errorf("server failed: %w", err)
+	case <-ctx.Done():
+	}
+
+	s.logger.Info("Shutting down gateway...")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), DefaultShutdownTimeout)
+	defer cancel()
+	if err := httpServer.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("graceful shutdown failed: %w", err)
+	}
+	if err := <-errCh; err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("server failed: %w", err)
+	}
+	return nil
+}
+
+// clientFor returns the cached outbound client for an agent URL
+func (s *Server) clientFor(agentURL string) *client.Client {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if c, ok := s.clients[agentURL]; ok {
+		return c
+	}
+	cfg := s.clientBase
+	cfg.BaseURL = agentURL
+	cfg.OnBreakerChange = s.breakerChanged
+	cfg.UploadEndpoint = func(string) string { return s.uploadEndpoint(agentURL) }
+	c := client.New(cfg)
+	s.clients[agentURL] = c
+	return c
+}
+
+// uploadEndpoint returns the upload endpoint advertised by the card of the
+// agent at agentURL, if any
+func (s *Server) uploadEndpoint(agentURL string) string {
+	for _, agent := range s.registry.List() {
+		if agent.URL == agentURL && agent.Card != nil {
+			return agent.Card.UploadEndpoint()
+		}
+	}
+	return ""
+}
+
+// breakerChanged marks the agents at agentURL offline while their circuit
+// breaker is open and online again once it closes
+func (s *Server) breakerChanged(agentURL string, from, to client.BreakerState) {
+	status := types.AgentStatusOnline
+	switch to {
+	case client.BreakerOpen:
+		status = types.AgentStatusOffline
+	case client.BreakerHalfOpen:
+		return
+	}
+
+	for _, agent := range s.registry.List() {
+		if agent.URL != agentURL {
+			continue
+		}
+		if err := s.registry.UpdateStatus(agent.ID, status); err != nil {
+			s.logger.Warnf("Failed to update status of agent %s: %v", agent.ID, err)
+		}
+	}
+}
+
+// breakerStatuses returns the circuit breaker state of every agent
+// contacted through the gateway
+func (s *Server) breakerStatuses() []client.BreakerStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	statuses := []client.BreakerStatus{}
+	for _, c := range s.clients {
+		statuses = append(statuses, c.BreakerStatuses()...)
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Agent < statuses[j].Agent })
+	return statuses
+}
+
+// closeClients closes every outbound client, canceling open streams
+func (s *Server) closeClients() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for agentURL, c := range s.clients {
+		if err := c.Close(); err != nil {
+			s.logger.Warnf("Failed to close client for %s: %v", agentURL, err)
+		}
+		delete(s.clients, agentURL)
+	}
+}
@@ -0,0 +1,169 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/craine-io/openribcage/internal/config"
+	"github.com/craine-io/openribcage/pkg/a2a/client"
+	"github.com/craine-io/openribcage/pkg/a2a/types"
+	"github.com/craine-io/openribcage/pkg/agentcard"
+	"github.com/craine-io/openribcage/pkg/registry"
+)
+
+// testAgent is a fake A2A agent that fails with 500 until told otherwise
+// and can hold a request to keep a breaker probe in flight
+type testAgent struct {
+	*httptest.Server
+
+	mu      sync.Mutex
+	failing bool
+	hold    chan struct{}
+	held    chan struct{}
+}
+
+// newTestAgent starts a failing testAgent that is closed when the test ends
+func newTestAgent(t *testing.T) *testAgent {
+	a := &testAgent{failing: true}
+	a.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		a.mu.Lock()
+		failing, hold, held := a.failing, a.hold, a.held
+		a.mu.Unlock()
+		if hold != nil {
+			held <- struct{}{}
+			<-hold
+		}
+		if failing {
+			http.Error(w, "agent down", http.StatusInternalServerError)
+			return
+		}
+
+		var req types.JSONRPCRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(types.JSONRPCResponse{JSONRPC: "2.0", ID: req.ID, Result: json.RawMessage(`{"id":"task-1","status":"completed"}`)})
+	}))
+	t.Cleanup(a.Close)
+	return a
+}
+
+// newTestGateway starts a gateway serving agent under the ID "agent"
+func newTestGateway(t *testing.T, agent *testAgent, breaker client.BreakerPolicy) (*httptest.Server, *registry.Registry) {
+	reg := registry.NewRegistry(time.Minute)
+	require.NoError(t, reg.Register(&types.Agent{
+		ID:     "agent",
+		Name:   "agent",
+		URL:    agent.URL,
+		Status: types.AgentStatusOnline,
+	}))
+
+	s := New(config.ServerConfig{}, client.Config{Timeout: 5 * time.Second, Breaker: breaker}, reg, agentcard.NewDiscoverer(5*time.Second))
+	gateway := httptest.NewServer(s.Handler())
+	t.Cleanup(func() {
+		gateway.Close()
+		s.closeClients()
+	})
+	return gateway, reg
+}
+
+// sendTask posts a task to the agent through the gateway and returns the
+// response status
+func sendTask(t *testing.T, gateway *httptest.Server) int {
+	resp, err := http.Post(gateway.URL+"/api/v1/agents/agent/tasks", "application/json", bytes.NewBufferString(`{"text":"hello"}`))
+	require.NoError(t, err)
+	resp.Body.Close()
+	return resp.StatusCode
+}
+
+// getBreakers fetches /api/v1/breakers
+func getBreakers(t *testing.T, gateway *httptest.Server) []client.BreakerStatus {
+	resp, err := http.Get(gateway.URL + "/api/v1/breakers")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var statuses []client.BreakerStatus
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&statuses))
+	return statuses
+}
+
+// TestBreakersEndpoint tests that /api/v1/breakers reports each breaker
+// state and that an open breaker marks its agent offline
+func TestBreakersEndpoint(t *testing.T) {
+	agent := newTestAgent(t)
+	gateway, reg := newTestGateway(t, agent, client.BreakerPolicy{Threshold: 2, CoolDown: 50 * time.Millisecond})
+
+	assert.Empty(t, getBreakers(t, gateway), "no agent has been called yet")
+
+	// Closed, counting the first failure
+	assert.Equal(t, http.StatusBadGateway, sendTask(t, gateway))
+	statuses := getBreakers(t, gateway)
+	require.Len(t, statuses, 1)
+	assert.Equal(t, agent.URL, statuses[0].Agent)
+	assert.Equal(t, client.BreakerClosed, statuses[0].State)
+	assert.Equal(t, 1, statuses[0].Failures)
+	assert.Equal(t, "unexpected status: 500 Internal Server Error", statuses[0].LastError)
+
+	// Open after the threshold; the agent is marked offline and calls
+	// fail fast with 503
+	assert.Equal(t, http.StatusBadGateway, sendTask(t, gateway))
+	statuses = getBreakers(t, gateway)
+	require.Len(t, statuses, 1)
+	assert.Equal(t, client.BreakerOpen, statuses[0].State)
+	assert.Equal(t, 2, statuses[0].Failures)
+	assert.False(t, statuses[0].OpenedAt.IsZero())
+	registered, err := reg.Get("agent")
+	require.NoError(t, err)
+	assert.Equal(t, types.AgentStatusOffline, registered.Status)
+	assert.Equal(t, http.StatusServiceUnavailable, sendTask(t, gateway))
+
+	// Half-open while the probe is in flight
+	time.Sleep(60 * time.Millisecond)
+	agent.mu.Lock()
+	agent.failing = false
+	agent.hold = make(chan struct{})
+	agent.held = make(chan struct{}, 1)
+	hold, held := agent.hold, agent.held
+	agent.mu.Unlock()
+
+	probe := make(chan int, 1)
+	go func() { probe <- sendTask(t, gateway) }()
+	<-held
+	statuses = getBreakers(t, gateway)
+	require.Len(t, statuses, 1)
+	assert.Equal(t, client.BreakerHalfOpen, statuses[0].State)
+	assert.Equal(t, 2, statuses[0].Failures)
+
+	// A successful probe closes the breaker and brings the agent back online
+	agent.mu.Lock()
+	agent.hold = nil
+	agent.mu.Unlock()
+	close(hold)
+	assert.Equal(t, http.StatusOK, <-probe)
+	statuses = getBreakers(t, gateway)
+	require.Len(t, statuses, 1)
+	assert.Equal(t, client.BreakerClosed, statuses[0].State)
+	assert.Equal(t, 0, statuses[0].Failures)
+	registered, err = reg.Get("agent")
+	require.NoError(t, err)
+	assert.Equal(t, types.AgentStatusOnline, registered.Status)
+}
+
+// TestBreakersEndpointMethod tests that /api/v1/breakers is read-only
+func TestBreakersEndpointMethod(t *testing.T) {
+	gateway, _ := newTestGateway(t, newTestAgent(t), client.BreakerPolicy{Threshold: 1})
+
+	resp, err := http.Post(gateway.URL+"/api/v1/breakers", "application/json", nil)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+	assert.Equal(t, http.MethodGet, resp.Header.Get("Allow"))
+}
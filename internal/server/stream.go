@@ -0,0 +1,181 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/craine-io/openribcage/pkg/a2a/types"
+)
+
+const (
+	// writeWait bounds how long a single WebSocket write may take
+	writeWait = 10 * time.Second
+
+	// pongWait is how long the peer may stay silent, i.e. not answer a
+	// ping, before the connection is considered dead
+	pongWait = 60 * time.Second
+
+	// pingPeriod is how often pings are sent; it must be less than pongWait
+	pingPeriod = pongWait * 9 / 10
+)
+
+var upgrader = websocket.Upgrader{
+	ReadBufferSize:  4096,
+	WriteBufferSize: 4096,
+}
+
+// streamRequest is the first frame sent on a stream. AgentID is required
+// on /api/v1/stream and ignored when the path names the agent.
+type streamRequest struct {
+	AgentID string `json:"agentId,omitempty"`
+	taskRequest
+}
+
+// streamError is the frame sent when a stream fails
+type streamError struct {
+	Type  string `json:"type"`
+	Error string `json:"error"`
+}
+
+// handleStream upgrades to a WebSocket, reads a streamRequest from the
+// first frame and bridges the agent's task stream to the socket. agent is
+// nil when the request names the agent instead of the path.
+func (s *Server) handleStream(w http.ResponseWriter, r *http.Request, agent *types.Agent) {
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// Upgrade has already replied to the client
+		s.logger.Debugf("WebSocket upgrade failed: %v", err)
+		return
+	}
+	defer conn.Close()
+
+	var body streamRequest
+	_ = conn.SetReadDeadline(time.Now().Add(pongWait))
+	if err := conn.ReadJSON(&body); err != nil {
+		s.closeStream(conn, websocket.CloseUnsupportedData, "invalid task request")
+		return
+	}
+	if agent == nil {
+		if agent, err = s.registry.Get(body.AgentID); err != nil {
+			s.closeStream(conn, websocket.ClosePolicyViolation, err.Error())
+			return
+		}
+	}
+	req, err := body.toTaskRequest()
+	if err != nil {
+		s.closeStream(conn, websocket.CloseUnsupportedData, err.Error())
+		return
+	}
+
+	s.bridge(r.Context(), conn, agent, req)
+}
+
+// handleStreamRoot serves /api/v1/stream, where the first frame names the agent
+func (s *Server) handleStreamRoot(w http.ResponseWriter, r *http.Request) {
+	if allowMethod(w, r, http.MethodGet) {
+		s.handleStream(w, r, nil)
+	}
+}
+
+// bridge forwards every event of the agent's task stream to conn as a JSON
+// frame, pinging the peer every pingPeriod. The socket is closed once a
+// Done event is sent or the stream ends; if the peer disconnects or stops
+// answering pings, the upstream stream is canceled.
+func (s *Server) bridge(ctx context.Context, conn *websocket.Conn, agent *types.Agent, req *types.TaskRequest) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	readDone := make(chan struct{})
+	defer func() {
+		conn.Close()
+		<-readDone
+	}()
+	go func() {
+		defer close(readDone)
+		defer cancel()
+		s.readPump(conn)
+	}()
+
+	s.logger.Debugf("Streaming task %s from %s", req.ID, agent.ID)
+	events, errs := s.clientFor(agent.URL).StreamTask(ctx, "", req)
+
+	ticker := time.NewTicker(pingPeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				s.finishStream(ctx, conn, <-errs)
+				return
+			}
+			if err := s.writeFrame(conn, event); err != nil {
+				s.logger.Debugf("WebSocket write failed: %v", err)
+				return
+			}
+			if event.Done {
+				s.closeStream(conn, websocket.CloseNormalClosure, "")
+				return
+			}
+		case <-ticker.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(writeWait)); err != nil {
+				s.logger.Debugf("WebSocket ping failed: %v", err)
+				return
+			}
+		case <-ctx.Done():
+			s.logger.Debugf("WebSocket client for task %s went away", req.ID)
+			return
+		}
+	}
+}
+
+// readPump reads and discards frames from the peer so that pongs and close
+// frames are processed, returning once the connection fails or closes or
+// the peer misses pongWait
+func (s *Server) readPump(conn *websocket.Conn) {
+	_ = conn.SetReadDeadline(time.Now().Add(pongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(pongWait))
+	})
+	for {
+		if _, _, err := conn.ReadMessage(); err != nil {
+			return
+		}
+	}
+}
+
+// finishStream closes the socket after the upstream stream ended, sending
+// an error frame first if it failed. Nothing is sent once the peer is gone.
+func (s *Server) finishStream(ctx context.Context, conn *websocket.Conn, err error) {
+	if ctx.Err() != nil {
+		return
+	}
+	if err == nil {
+		s.closeStream(conn, websocket.CloseNormalClosure, "")
+		return
+	}
+	_ = s.writeFrame(conn, streamError{Type: "error", Error: err.Error()})
+	code := websocket.CloseInternalServerErr
+	if errors.Is(err, context.Canceled) {
+		code = websocket.CloseGoingAway
+	}
+	s.closeStream(conn, code, "stream failed")
+}
+
+// writeFrame writes v to conn as a JSON text frame
+func (s *Server) writeFrame(conn *websocket.Conn, v interface{}) error {
+	_ = conn.SetWriteDeadline(time.Now().Add(writeWait))
+	return conn.WriteJSON(v)
+}
+
+// closeStream sends a close frame with the given code and reason
+func (s *Server) closeStream(conn *websocket.Conn, code int, reason string) {
+	msg := websocket.FormatCloseMessage(code, reason)
+	if err := conn.WriteControl(websocket.CloseMessage, msg, time.Now().Add(writeWait)); err != nil {
+		s.logger.Debugf("Failed to close WebSocket: %v", err)
+	}
+}
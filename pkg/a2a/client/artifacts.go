@@ -0,0 +1,93 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/craine-io/openribcage/pkg/a2a/types"
+)
+
+// ErrArtifactNotFound is returned by StreamArtifactTo when the task ends
+// without producing the requested artifact
+var ErrArtifactNotFound = errors.New("artifact not found")
+
+// StreamArtifactTo streams a task and writes the chunks of the named
+// artifact to w in arrival order, without buffering the whole artifact.
+// It returns once the artifact's last chunk has been written or the task
+// stream ends, reporting the number of bytes written.
+//
+// Text parts are written as-is, file parts as their content, and data
+// parts as JSON.
+func (c *Client) StreamArtifactTo(ctx context.Context, agentID string, req *types.TaskRequest, artifactName string, w io.Writer) (int64, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	events, errs := c.StreamTask(ctx, agentID, req)
+
+	var written int64
+	seen := false
+	for event := range events {
+		artifact, ok := event.Artifact()
+		if ok && artifact.Name == artifactName {
+			seen = true
+			n, err := writeArtifactChunk(w, artifact)
+			written += n
+			if err != nil {
+				return written, fmt.Errorf("failed to write artifact %s: %w", artifactName, err)
+			}
+			if artifact.LastChunk {
+				return written, nil
+			}
+		}
+		if event.Done {
+			return written, artifactResult(seen, artifactName)
+		}
+	}
+
+	if err := <-errs; err != nil {
+		return written, err
+	}
+	return written, artifactResult(seen, artifactName)
+}
+
+// artifactResult reports ErrArtifactNotFound if the artifact was never seen
+func artifactResult(seen bool, artifactName string) error {
+	if !seen {
+		return fmt.Errorf("%w: %s", ErrArtifactNotFound, artifactName)
+	}
+	return nil
+}
+
+// writeArtifactChunk writes the parts of one artifact chunk to w
+func writeArtifactChunk(w io.Writer, artifact *types.Artifact) (int64, error) {
+	var written int64
+	for _, part := range artifact.Parts {
+		var data []byte
+		switch {
+		case part.File != nil:
+			data = part.File.Content
+		case part.Image != nil:
+			data = part.Image.Content
+		case part.Audio != nil:
+			data = part.Audio.Content
+		case part.Type == "text":
+			data = []byte(part.Text)
+		case part.Data != nil:
+			encoded, err := json.Marshal(part.Data)
+			if err != nil {
+				return written, err
+			}
+			data = encoded
+		}
+
+		n, err := w.Write(data)
+		written += int64(n)
+		if err != nil {
+			return written, err
+		}
+	}
+	return written, nil
+}
@@ -0,0 +1,50 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/craine-io/openribcage/pkg/a2a/types"
+)
+
+// TestStreamArtifactTo tests that the chunks of the named artifact are
+// written in order, skipping other artifacts, until its last chunk
+func TestStreamArtifactTo(t *testing.T) {
+	server := newSSEServer(t,
+		`{"type":"artifact","data":{"artifact":{"name":"report","index":0,"parts":[{"type":"text","text":"line 1\n"}]}}}`,
+		`{"type":"artifact","data":{"name":"log","index":1,"parts":[{"type":"text","text":"ignored"}]}}`,
+		`{"type":"artifact","data":{"artifact":{"name":"report","index":0,"append":true,"parts":[{"type":"data","data":{"ok":true}}]}}}`,
+		`{"type":"artifact","data":{"artifact":{"name":"report","index":0,"append":true,"lastChunk":true,"parts":[{"type":"file","file":{"name":"tail.txt","mime_type":"text/plain","size":4,"content":"dGFpbA=="}}]}}}`,
+		`{"type":"artifact","data":{"artifact":{"name":"report","index":0,"append":true,"parts":[{"type":"text","text":"after last chunk"}]}}}`,
+	)
+	c := newTestClient(t, Config{BaseURL: server.URL, Timeout: 5 * time.Second})
+
+	var out bytes.Buffer
+	req := &types.TaskRequest{ID: "task-1", Message: &types.Message{Role: "user", Parts: []types.Part{{Type: "text", Text: "report"}}}}
+	n, err := c.StreamArtifactTo(context.Background(), "", req, "report", &out)
+	require.NoError(t, err)
+	assert.Equal(t, "line 1\n{\"ok\":true}tail", out.String())
+	assert.Equal(t, int64(out.Len()), n)
+}
+
+// TestStreamArtifactToNotFound tests that a task ending without the named
+// artifact reports ErrArtifactNotFound
+func TestStreamArtifactToNotFound(t *testing.T) {
+	server := newSSEServer(t,
+		`{"type":"artifact","data":{"name":"log","parts":[{"type":"text","text":"ignored"}]}}`,
+		`{"type":"status","data":"completed","done":true}`,
+	)
+	c := newTestClient(t, Config{BaseURL: server.URL, Timeout: 5 * time.Second})
+
+	var out bytes.Buffer
+	req := &types.TaskRequest{ID: "task-1", Message: &types.Message{Role: "user", Parts: []types.Part{{Type: "text", Text: "report"}}}}
+	n, err := c.StreamArtifactTo(context.Background(), "", req, "report", &out)
+	assert.ErrorIs(t, err, ErrArtifactNotFound)
+	assert.Zero(t, n)
+	assert.Empty(t, out.String())
+}
@@ -0,0 +1,193 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/craine-io/openribcage/internal/auth"
+	"github.com/craine-io/openribcage/pkg/a2a/types"
+	"github.com/craine-io/openribcage/pkg/httpcompress"
+)
+
+// BatchError reports the elements of a batch that failed. Errors is
+// indexed like the batch requests and holds nil for elements that
+// succeeded.
+type BatchError struct {
+	Errors []error
+}
+
+// Error implements the error interface
+func (e *BatchError) Error() string {
+	var failed []string
+	for i, err := range e.Errors {
+		if err != nil {
+			failed = append(failed, fmt.Sprintf("[%d] %v", i, err))
+		}
+	}
+	return fmt.Sprintf("%d of %d batch requests failed: %s", len(failed), len(e.Errors), strings.Join(failed, "; "))
+}
+
+// Unwrap returns the per-element errors
+func (e *BatchError) Unwrap() []error {
+	var errs []error
+	for _, err := range e.Errors {
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+// SendBatch sends several tasks to an agent as a single JSON-RPC 2.0
+// batch. Responses are matched to requests by id and returned in request
+// order. If some elements fail, the successful responses are still
+// returned alongside a *BatchError whose failed indices have nil
+// responses; transport failures fail the whole batch.
+func (c *Client) SendBatch(ctx context.Context, agentID string, reqs []*types.TaskRequest) ([]*types.TaskResponse, error) {
+	if len(reqs) == 0 {
+		return nil, nil
+	}
+	for i, req := range reqs {
+		if err := req.Message.Validate(); err != nil {
+			return nil, fmt.Errorf("request %d: %w", i, err)
+		}
+	}
+	ctx, cancel := c.WithDefaultTimeout(ctx)
+	defer cancel()
+
+	method := types.A2AMethods.TasksSend
+	batch := make([]*types.JSONRPCRequest, len(reqs))
+	index := make(map[string]int, len(reqs))
+	for i, req := range reqs {
+		params, err := c.encodeParams(method, map[string]interface{}{
+			"id":      req.ID,
+			"message": req.Message,
+		})
+		if err != nil {
+			return nil, err
+		}
+		id := c.requestID()
+		batch[i] = &types.JSONRPCRequest{
+			JSONRPC: jsonRPCVersion,
+			Method:  method,
+			Params:  params,
+			ID:      id,
+		}
+		index[id] = i
+	}
+
+	reqBody, err := json.Marshal(batch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal batch: %w", err)
+	}
+
+	rpcResps, err := c.doBatch(ctx, agentID, reqBody)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]*types.TaskResponse, len(reqs))
+	errs := make([]error, len(reqs))
+	seen := make([]bool, len(reqs))
+	for _, rpcResp := range rpcResps {
+		i, ok := index[fmt.Sprint(rpcResp.ID)]
+		if !ok || seen[i] {
+			c.logger.Debugf("Ignoring batch response with unexpected id %v", rpcResp.ID)
+			continue
+		}
+		seen[i] = true
+
+		if err := c.runEnvelopeHooks(method, rpcResp); err != nil {
+			errs[i] = err
+			continue
+		}
+		if err := checkResponse(method, batch[i].ID.(string), rpcResp, c.config.StrictJSONRPC); err != nil {
+			errs[i] = err
+			continue
+		}
+		var resp types.TaskResponse
+		if err := decodeResult(method, rpcResp, &resp); err != nil {
+			errs[i] = err
+			continue
+		}
+		resp.Artifacts = types.MergeArtifacts(resp.Artifacts)
+		results[i] = &resp
+	}
+
+	failed := false
+	for i := range reqs {
+		if !seen[i] {
+			errs[i] = errors.New("no response for request in batch")
+		}
+		failed = failed || errs[i] != nil
+	}
+	if failed {
+		return results, &BatchError{Errors: errs}
+	}
+	return results, nil
+}
+
+// doBatch performs the HTTP exchange for a batch. An agent that rejects
+// the whole batch may answer with a single error object instead of an
+// array, which is returned as an error.
+func (c *Client) doBatch(ctx context.Context, agentID string, reqBody []byte) ([]*types.JSONRPCResponse, error) {
+	httpReq, err := c.newRequest(ctx, types.A2AMethods.TasksSend, c.agentURL(agentID), reqBody)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.dryRun(httpReq, reqBody); err != nil {
+		return nil, err
+	}
+
+	if err := c.waitRateLimit(ctx, agentID); err != nil {
+		return nil, err
+	}
+
+	redactParam := auth.RedactParam(c.config.Credentials)
+	c.logger.Debugf("A2A batch request -> %s", auth.RedactURL(httpReq.URL.String(), redactParam))
+
+	if err := c.runRequestHooks(httpReq); err != nil {
+		return nil, err
+	}
+	if err := c.allowRequest(agentID); err != nil {
+		return nil, err
+	}
+	httpResp, err := c.httpClient.Do(httpReq)
+	c.recordRequest(ctx, agentID, httpResp, err)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", auth.RedactError(err, redactParam))
+	}
+	defer httpResp.Body.Close()
+	if err := httpcompress.Decode(httpResp); err != nil {
+		return nil, err
+	}
+	if err := c.runResponseHooks(httpResp); err != nil {
+		return nil, err
+	}
+
+	if httpResp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status: %s", httpResp.Status)
+	}
+
+	var raw json.RawMessage
+	if err := decodeBody(httpResp.Body, &raw); err != nil {
+		return nil, err
+	}
+
+	var resps []*types.JSONRPCResponse
+	if err := json.Unmarshal(raw, &resps); err == nil {
+		return resps, nil
+	}
+	var single types.JSONRPCResponse
+	if err := json.Unmarshal(raw, &single); err != nil {
+		return nil, fmt.Errorf("failed to decode batch response: %w", err)
+	}
+	if single.Error != nil {
+		return nil, fmt.Errorf("batch rejected: %w", types.NewJSONRPCErrorError("", single.Error))
+	}
+	return nil, errors.New("failed to decode batch response: expected an array")
+}
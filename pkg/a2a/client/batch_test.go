@@ -0,0 +1,117 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/craine-io/openribcage/pkg/a2a/types"
+)
+
+// newBatchServer starts an agent that answers a batch in reverse order,
+// failing tasks named "fail" and leaving out tasks named "drop"
+func newBatchServer(t *testing.T) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var batch []struct {
+			ID     string `json:"id"`
+			Params struct {
+				ID string `json:"id"`
+			} `json:"params"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&batch); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		var resps []types.JSONRPCResponse
+		for i := len(batch) - 1; i >= 0; i-- {
+			req := batch[i]
+			resp := types.JSONRPCResponse{JSONRPC: "2.0", ID: req.ID}
+			switch req.Params.ID {
+			case "drop":
+				continue
+			case "fail":
+				resp.Error = &types.JSONRPCError{Code: -32603, Message: "task failed"}
+			default:
+				resp.Result = json.RawMessage(`{"id":"` + req.Params.ID + `","status":"completed"}`)
+			}
+			resps = append(resps, resp)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(resps)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// batchRequest returns a task request with a text message
+func batchRequest(id string) *types.TaskRequest {
+	return &types.TaskRequest{ID: id, Message: &types.Message{Role: "user", Parts: []types.Part{{Type: "text", Text: id}}}}
+}
+
+// TestSendBatch tests that batch responses are matched to requests by id
+// and returned in request order
+func TestSendBatch(t *testing.T) {
+	c := newTestClient(t, Config{BaseURL: newBatchServer(t).URL, Timeout: 5 * time.Second})
+
+	resps, err := c.SendBatch(context.Background(), "", []*types.TaskRequest{batchRequest("a"), batchRequest("b"), batchRequest("c")})
+	require.NoError(t, err)
+	require.Len(t, resps, 3)
+	for i, id := range []string{"a", "b", "c"} {
+		assert.Equal(t, id, resps[i].ID)
+		assert.Equal(t, types.TaskStateCompleted, resps[i].Status)
+	}
+
+	resps, err = c.SendBatch(context.Background(), "", nil)
+	assert.NoError(t, err)
+	assert.Nil(t, resps)
+}
+
+// TestSendBatchPartialFailure tests that failed and missing elements are
+// reported per index alongside the successful responses
+func TestSendBatchPartialFailure(t *testing.T) {
+	c := newTestClient(t, Config{BaseURL: newBatchServer(t).URL, Timeout: 5 * time.Second})
+
+	resps, err := c.SendBatch(context.Background(), "", []*types.TaskRequest{batchRequest("a"), batchRequest("fail"), batchRequest("drop")})
+	var batchErr *BatchError
+	require.ErrorAs(t, err, &batchErr)
+	require.Len(t, batchErr.Errors, 3)
+	assert.NoError(t, batchErr.Errors[0])
+	assert.ErrorContains(t, batchErr.Errors[1], "task failed")
+	assert.ErrorContains(t, batchErr.Errors[2], "no response for request in batch")
+	assert.ErrorContains(t, err, "2 of 3 batch requests failed")
+
+	require.Len(t, resps, 3)
+	assert.Equal(t, "a", resps[0].ID)
+	assert.Nil(t, resps[1])
+	assert.Nil(t, resps[2])
+}
+
+// TestSendBatchRejected tests that a single error object fails the whole batch
+func TestSendBatchRejected(t *testing.T) {
+	agent := newAgentServer(t, taskResult)
+	c := newTestClient(t, Config{BaseURL: agent.URL, Timeout: 5 * time.Second})
+
+	// agentServer cannot decode a batch and answers 400
+	_, err := c.SendBatch(context.Background(), "", []*types.TaskRequest{batchRequest("a")})
+	assert.ErrorContains(t, err, "400")
+
+	rejecting := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"jsonrpc":"2.0","id":null,"error":{"code":-32600,"message":"batches not supported"}}`))
+	}))
+	defer rejecting.Close()
+	c = newTestClient(t, Config{BaseURL: rejecting.URL, Timeout: 5 * time.Second})
+	_, err = c.SendBatch(context.Background(), "", []*types.TaskRequest{batchRequest("a")})
+	assert.ErrorContains(t, err, "batch rejected")
+	assert.ErrorContains(t, err, "batches not supported")
+
+	_, err = c.SendBatch(context.Background(), "", []*types.TaskRequest{{ID: "empty", Message: &types.Message{Role: "user"}}})
+	assert.ErrorContains(t, err, "request 0")
+}
@@ -0,0 +1,225 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/craine-io/openribcage/internal/auth"
+)
+
+// ErrCircuitOpen is returned without contacting an agent while its circuit
+// breaker is open
+var ErrCircuitOpen = errors.New("circuit breaker open")
+
+// DefaultBreakerCoolDown is how long a breaker stays open before a probe
+// request is let through, when BreakerPolicy.CoolDown is unset
+const DefaultBreakerCoolDown = 30 * time.Second
+
+// BreakerState is the state of an agent's circuit breaker
+type BreakerState string
+
+const (
+	// BreakerClosed lets every request through
+	BreakerClosed BreakerState = "closed"
+	// BreakerOpen fails requests fast with ErrCircuitOpen
+	BreakerOpen BreakerState = "open"
+	// BreakerHalfOpen lets a single probe request through after the
+	// cool-down; its outcome closes or re-opens the breaker
+	BreakerHalfOpen BreakerState = "half-open"
+)
+
+// BreakerPolicy configures the per-agent circuit breakers. Only failures
+// that suggest the agent is unavailable count: transport errors, timeouts
+// and 5xx responses. JSON-RPC errors show the agent is up and do not.
+type BreakerPolicy struct {
+	// Threshold is the number of consecutive failures that opens the
+	// breaker; zero disables circuit breaking
+	Threshold int `json:"threshold"`
+	// CoolDown is how long the breaker stays open before probing
+	// (default DefaultBreakerCoolDown)
+	CoolDown time.Duration `json:"cool_down"`
+}
+
+// BreakerStatus is a snapshot of an agent's circuit breaker
+type BreakerStatus struct {
+	Agent     string       `json:"agent"`
+	State     BreakerState `json:"state"`
+	Failures  int          `json:"failures"`
+	LastError string       `json:"last_error,omitempty"`
+	OpenedAt  time.Time    `json:"opened_at,omitempty"`
+}
+
+// breakerOutcome classifies a request for the breaker
+type breakerOutcome int
+
+const (
+	// outcomeIgnored neither counts as a failure nor closes the breaker,
+	// e.g. a request canceled by the caller
+	outcomeIgnored breakerOutcome = iota
+	outcomeSuccess
+	outcomeFailure
+)
+
+// breakers holds a circuit breaker per agent
+type breakers struct {
+	mu    sync.Mutex
+	state map[string]*breaker
+}
+
+// breaker is the state of a single agent's circuit
+type breaker struct {
+	state     BreakerState
+	failures  int
+	lastError string
+	openedAt  time.Time
+	probing   bool
+}
+
+// get returns the breaker for key, creating a closed one if needed
+func (b *breakers) get(key string) *breaker {
+	if b.state == nil {
+		b.state = make(map[string]*breaker)
+	}
+	br, ok := b.state[key]
+	if !ok {
+		br = &breaker{state: BreakerClosed}
+		b.state[key] = br
+	}
+	return br
+}
+
+// allow reports whether a request to key may proceed, moving an open
+// breaker whose cool-down has elapsed to half-open. It returns the state
+// transition, if any, for the change callback.
+func (b *breakers) allow(key string, policy BreakerPolicy) (from, to BreakerState, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	br := b.get(key)
+	switch br.state {
+	case BreakerOpen:
+		coolDown := policy.CoolDown
+		if coolDown <= 0 {
+			coolDown = DefaultBreakerCoolDown
+		}
+		if remaining := coolDown - time.Since(br.openedAt); remaining > 0 {
+			return "", "", fmt.Errorf("%w for agent %q: %d consecutive failures, retrying in %s (last error: %s)",
+				ErrCircuitOpen, key, br.failures, remaining.Round(time.Millisecond), br.lastError)
+		}
+		br.state = BreakerHalfOpen
+		br.probing = true
+		return BreakerOpen, BreakerHalfOpen, nil
+	case BreakerHalfOpen:
+		if br.probing {
+			return "", "", fmt.Errorf("%w for agent %q: waiting for a probe request", ErrCircuitOpen, key)
+		}
+		br.probing = true
+	}
+	return "", "", nil
+}
+
+// record applies the outcome of a request to key's breaker and returns
+// the state transition, if any
+func (b *breakers) record(key string, policy BreakerPolicy, outcome breakerOutcome, err error) (from, to BreakerState) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	br := b.get(key)
+	from = br.state
+	br.probing = false
+
+	switch outcome {
+	case outcomeSuccess:
+		br.state = BreakerClosed
+		br.failures = 0
+	case outcomeFailure:
+		br.failures++
+		br.lastError = err.Error()
+		if br.state == BreakerHalfOpen || br.failures >= policy.Threshold {
+			br.state = BreakerOpen
+			br.openedAt = time.Now()
+		}
+	}
+	return from, br.state
+}
+
+// statuses returns a snapshot of every breaker, sorted by agent
+func (b *breakers) statuses() []BreakerStatus {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	list := make([]BreakerStatus, 0, len(b.state))
+	for key, br := range b.state {
+		status := BreakerStatus{
+			Agent:     key,
+			State:     br.state,
+			Failures:  br.failures,
+			LastError: br.lastError,
+		}
+		if br.state != BreakerClosed {
+			status.OpenedAt = br.openedAt
+		}
+		list = append(list, status)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Agent < list[j].Agent })
+	return list
+}
+
+// BreakerStatuses returns the circuit breaker state of every agent the
+// client has called, sorted by agent. Agents addressed through BaseURL are
+// reported under that URL. It is cheap and safe to call concurrently.
+func (c *Client) BreakerStatuses() []BreakerStatus {
+	return c.breakers.statuses()
+}
+
+// allowRequest checks the agent's circuit breaker before a request
+func (c *Client) allowRequest(agentID string) error {
+	if c.config.Breaker.Threshold <= 0 {
+		return nil
+	}
+	key := c.agentLabel(agentID)
+	from, to, err := c.breakers.allow(key, c.config.Breaker)
+	c.breakerChanged(key, from, to)
+	return err
+}
+
+// recordRequest feeds the outcome of an HTTP exchange to the agent's
+// circuit breaker: transport errors and 5xx responses are failures unless
+// the caller canceled the request
+func (c *Client) recordRequest(ctx context.Context, agentID string, resp *http.Response, err error) {
+	if c.config.Breaker.Threshold <= 0 {
+		return
+	}
+	outcome := outcomeSuccess
+	switch {
+	case err != nil && errors.Is(ctx.Err(), context.Canceled):
+		outcome = outcomeIgnored
+	case err != nil:
+		outcome = outcomeFailure
+		err = auth.RedactError(err, auth.RedactParam(c.config.Credentials))
+	case resp.StatusCode >= 500:
+		outcome = outcomeFailure
+		err = fmt.Errorf("unexpected status: %s", resp.Status)
+	}
+
+	key := c.agentLabel(agentID)
+	from, to := c.breakers.record(key, c.config.Breaker, outcome, err)
+	c.breakerChanged(key, from, to)
+}
+
+// breakerChanged logs a breaker transition and reports it to
+// Config.OnBreakerChange
+func (c *Client) breakerChanged(agent string, from, to BreakerState) {
+	if from == to {
+		return
+	}
+	c.logger.Infof("Circuit breaker for agent %s: %s -> %s", agent, from, to)
+	if c.config.OnBreakerChange != nil {
+		c.config.OnBreakerChange(agent, from, to)
+	}
+}
@@ -0,0 +1,190 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// flakyAgent is a fake agent that can be switched between answering,
+// failing with 500 and holding requests until released
+type flakyAgent struct {
+	*httptest.Server
+
+	mu      sync.Mutex
+	failing bool
+	hold    chan struct{}
+	held    chan struct{}
+	hits    int
+}
+
+// newFlakyAgent starts a healthy flakyAgent that is closed when the test ends
+func newFlakyAgent(t *testing.T) *flakyAgent {
+	a := &flakyAgent{}
+	ok := newAgentServer(t, taskResult)
+	a.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		a.mu.Lock()
+		a.hits++
+		failing, hold, held := a.failing, a.hold, a.held
+		a.mu.Unlock()
+
+		if hold != nil {
+			held <- struct{}{}
+			<-hold
+		}
+		if failing {
+			http.Error(w, "agent down", http.StatusInternalServerError)
+			return
+		}
+		ok.Config.Handler.ServeHTTP(w, r)
+	}))
+	t.Cleanup(a.Close)
+	return a
+}
+
+// setFailing switches the agent between failing and answering
+func (a *flakyAgent) setFailing(failing bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.failing = failing
+}
+
+// holdNext makes the agent hold requests until the returned release
+// function is called; held receives each request as it arrives
+func (a *flakyAgent) holdNext() (held <-chan struct{}, release func()) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.hold = make(chan struct{})
+	a.held = make(chan struct{}, 1)
+	hold := a.hold
+	return a.held, func() {
+		a.mu.Lock()
+		a.hold = nil
+		a.mu.Unlock()
+		close(hold)
+	}
+}
+
+// hitCount returns how many requests reached the agent
+func (a *flakyAgent) hitCount() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.hits
+}
+
+// TestBreakerTransitions tests that a breaker opens after consecutive
+// failures, fails fast while open, half-opens for a single probe after
+// the cool-down and closes or re-opens on its outcome
+func TestBreakerTransitions(t *testing.T) {
+	agent := newFlakyAgent(t)
+	var mu sync.Mutex
+	var transitions []string
+	c := newTestClient(t, Config{
+		BaseURL: agent.URL,
+		Timeout: 5 * time.Second,
+		Breaker: BreakerPolicy{Threshold: 2, CoolDown: 50 * time.Millisecond},
+		OnBreakerChange: func(agentKey string, from, to BreakerState) {
+			mu.Lock()
+			defer mu.Unlock()
+			transitions = append(transitions, string(from)+"->"+string(to))
+		},
+	})
+	ctx := context.Background()
+	status := func() BreakerStatus {
+		statuses := c.BreakerStatuses()
+		require.Len(t, statuses, 1)
+		assert.Equal(t, agent.URL, statuses[0].Agent)
+		return statuses[0]
+	}
+
+	// Closed: calls go through
+	_, err := c.GetTaskStatus(ctx, "", "task-1")
+	require.NoError(t, err)
+	assert.Equal(t, BreakerStatus{Agent: agent.URL, State: BreakerClosed}, status())
+
+	// Failures below the threshold keep the breaker closed
+	agent.setFailing(true)
+	_, err = c.GetTaskStatus(ctx, "", "task-1")
+	require.Error(t, err)
+	assert.Equal(t, BreakerClosed, status().State)
+	assert.Equal(t, 1, status().Failures)
+
+	// The threshold opens it
+	_, err = c.GetTaskStatus(ctx, "", "task-1")
+	require.Error(t, err)
+	open := status()
+	assert.Equal(t, BreakerOpen, open.State)
+	assert.Equal(t, 2, open.Failures)
+	assert.Equal(t, "unexpected status: 500 Internal Server Error", open.LastError)
+	assert.False(t, open.OpenedAt.IsZero())
+
+	// Open: calls fail fast without reaching the agent
+	hits := agent.hitCount()
+	_, err = c.GetTaskStatus(ctx, "", "task-1")
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+	assert.Equal(t, hits, agent.hitCount())
+
+	// After the cool-down a single probe is let through
+	time.Sleep(60 * time.Millisecond)
+	agent.setFailing(false)
+	held, release := agent.holdNext()
+	probe := make(chan error, 1)
+	go func() {
+		_, err := c.GetTaskStatus(ctx, "", "task-1")
+		probe <- err
+	}()
+	<-held
+	halfOpen := status()
+	assert.Equal(t, BreakerHalfOpen, halfOpen.State)
+	assert.Equal(t, 2, halfOpen.Failures)
+	assert.Equal(t, open.LastError, halfOpen.LastError)
+
+	_, err = c.GetTaskStatus(ctx, "", "task-1")
+	assert.ErrorIs(t, err, ErrCircuitOpen, "only one probe may be in flight")
+
+	// A successful probe closes the breaker
+	release()
+	require.NoError(t, <-probe)
+	assert.Equal(t, BreakerClosed, status().State)
+	assert.Equal(t, 0, status().Failures)
+
+	// A failed probe re-opens it at once
+	agent.setFailing(true)
+	for i := 0; i < 2; i++ {
+		c.GetTaskStatus(ctx, "", "task-1")
+	}
+	require.Equal(t, BreakerOpen, status().State)
+	time.Sleep(60 * time.Millisecond)
+	_, err = c.GetTaskStatus(ctx, "", "task-1")
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, ErrCircuitOpen)
+	assert.Equal(t, BreakerOpen, status().State)
+	assert.Equal(t, 3, status().Failures)
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []string{
+		"closed->open", "open->half-open", "half-open->closed",
+		"closed->open", "open->half-open", "half-open->open",
+	}, transitions)
+}
+
+// TestBreakerDisabled tests that a zero threshold never opens a breaker
+func TestBreakerDisabled(t *testing.T) {
+	agent := newFlakyAgent(t)
+	agent.setFailing(true)
+	c := newTestClient(t, Config{BaseURL: agent.URL, Timeout: 5 * time.Second})
+
+	for i := 0; i < 5; i++ {
+		_, err := c.GetTaskStatus(context.Background(), "", "task-1")
+		assert.NotErrorIs(t, err, ErrCircuitOpen)
+	}
+	assert.Equal(t, 5, agent.hitCount())
+	assert.Empty(t, c.BreakerStatuses())
+}
@@ -7,19 +7,26 @@
 package client
 
 import (
-	"bufio"
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
-	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
 
+	"github.com/craine-io/openribcage/internal/auth"
+	"github.com/craine-io/openribcage/pkg/a2a/retry"
+	"github.com/craine-io/openribcage/pkg/a2a/streaming"
 	"github.com/craine-io/openribcage/pkg/a2a/types"
+	"github.com/craine-io/openribcage/pkg/httpcompress"
+	"github.com/craine-io/openribcage/pkg/metrics"
+	"github.com/craine-io/openribcage/pkg/netguard"
 )
 
 // Config holds A2A client configuration
@@ -27,6 +34,117 @@ type Config struct {
 	BaseURL string            `json:"base_url"`
 	Timeout time.Duration     `json:"timeout"`
 	Headers map[string]string `json:"headers"`
+
+	// StreamTimeout bounds each streaming call; zero leaves streams
+	// running until the caller's context is done
+	StreamTimeout time.Duration `json:"stream_timeout,omitempty"`
+
+	// MethodTimeouts overrides Timeout or StreamTimeout for individual
+	// JSON-RPC methods, e.g. a short budget for tasks/status. Timeout only
+	// applies when the caller's context has no deadline; StreamTimeout and
+	// method timeouts always apply, and the earlier of them and the
+	// caller's deadline wins.
+	MethodTimeouts map[string]time.Duration `json:"method_timeouts,omitempty"`
+
+	// Credentials are applied to every outgoing request when set
+	Credentials *auth.Credentials `json:"credentials,omitempty"`
+
+	// Retry controls retries of failed unary calls; the zero value disables retries
+	Retry retry.Policy `json:"retry"`
+
+	// MaxFileSize limits attachments sent with SendTaskWithFiles (default DefaultMaxFileSize)
+	MaxFileSize int64 `json:"max_file_size,omitempty"`
+
+	// UploadThreshold is the size above which inline file parts are
+	// uploaded with UploadFile and sent by URL instead; zero disables
+	// uploads. Parts are sent inline when the agent has no upload endpoint.
+	UploadThreshold int64 `json:"upload_threshold,omitempty"`
+
+	// UploadChunkSize is the size of the chunks UploadFile sends, in bytes
+	// (default DefaultUploadChunkSize)
+	UploadChunkSize int64 `json:"upload_chunk_size,omitempty"`
+
+	// UploadEndpoint returns the upload endpoint an agent advertises in its
+	// card, or "" if it has none. Uploads are disabled when it is nil.
+	UploadEndpoint func(agentID string) string `json:"-"`
+
+	// StrictJSONRPC also rejects responses and JSON-RPC stream events that
+	// omit the jsonrpc field; a version other than "2.0" is always
+	// rejected. It is off by default for interoperability with lenient
+	// agents.
+	StrictJSONRPC bool `json:"strict_jsonrpc,omitempty"`
+
+	// PositionalParams lists methods whose params are sent as a positional
+	// array, ordered by PositionalOrder, instead of a named object
+	PositionalParams []string `json:"positional_params,omitempty"`
+
+	// MaxClockSkew enables clock skew detection: responses whose Date header
+	// differs from local time by more than this are logged. Zero disables it.
+	MaxClockSkew time.Duration `json:"max_clock_skew,omitempty"`
+
+	// HostPolicy restricts which agent hosts may be contacted. When nil,
+	// netguard.DefaultPolicy is used; set an empty Policy to allow all hosts.
+	HostPolicy *netguard.Policy `json:"host_policy,omitempty"`
+
+	// SerializeTasks queues turns on the same task (SendTask and StreamTask
+	// with a task ID already in flight) instead of letting them race, for
+	// agents that do not tolerate concurrent turns. Cancellation is never
+	// queued.
+	SerializeTasks bool `json:"serialize_tasks,omitempty"`
+
+	// RequestIDPrefix is prepended to generated JSON-RPC request IDs, e.g.
+	// "openribcage-", so requests can be found in agent-side logs
+	RequestIDPrefix string `json:"request_id_prefix,omitempty"`
+
+	// TLS configures mutual TLS and a custom CA bundle. When unset,
+	// AuthTypeMTLS Credentials supply the files instead.
+	TLS *auth.ClientTLS `json:"tls,omitempty"`
+
+	// MaxStreamLineSize limits a single SSE line read by StreamTask, in
+	// bytes (default streaming.DefaultMaxLineSize)
+	MaxStreamLineSize int `json:"max_stream_line_size,omitempty"`
+
+	// UnknownEvents decides how StreamTask handles events of types not in
+	// streaming.KnownEventTypes (default streaming.PassUnknown)
+	UnknownEvents streaming.UnknownEventPolicy `json:"unknown_events,omitempty"`
+
+	// DryRun makes every call build its request, validating the message
+	// and credentials and assembling headers, and return it in a
+	// *DryRunError instead of sending it. Large files are not uploaded.
+	DryRun bool `json:"dry_run,omitempty"`
+
+	// ProxyURL routes requests through an http, https or socks5 proxy.
+	// When unset, the HTTP_PROXY/HTTPS_PROXY environment variables apply.
+	ProxyURL string `json:"proxy_url,omitempty"`
+
+	// RateLimit caps requests per second to each agent; zero disables
+	// limiting. RateBurst allows short bursts above it (default 1).
+	RateLimit float64 `json:"rate_limit,omitempty"`
+	RateBurst int     `json:"rate_burst,omitempty"`
+
+	// Breaker opens a per-agent circuit breaker after consecutive
+	// failures; the zero value disables circuit breaking
+	Breaker BreakerPolicy `json:"breaker"`
+
+	// OnBreakerChange, when set, is called after an agent's circuit
+	// breaker changes state, e.g. to mark the agent offline in a registry.
+	// agent is the agent ID, or BaseURL for agents addressed directly.
+	OnBreakerChange func(agent string, from, to BreakerState) `json:"-"`
+
+	// Metrics records request counts, errors and latency when set
+	Metrics *metrics.Metrics `json:"-"`
+
+	// OutputMode is the MIME type the agent should answer in, typically
+	// chosen with NegotiateModes. When set, unary requests advertise it in
+	// their Accept header after application/json, which carries the
+	// JSON-RPC envelope.
+	OutputMode string `json:"output_mode,omitempty"`
+
+	// HTTPClient, when set, is used for every request instead of the
+	// default client, e.g. to supply a custom transport or test double.
+	// Timeouts are applied through request contexts, so it should not set
+	// its own Timeout, which would also cut streams short.
+	HTTPClient *http.Client `json:"-"`
 }
 
 // Client represents an A2A protocol client
@@ -34,18 +152,79 @@ type Client struct {
 	config     Config
 	logger     *logrus.Logger
 	httpClient *http.Client
-	// TODO: Add HTTP client, connection pool, etc. in Issue #10
+	auth       *auth.Authenticator
+	hosts      *netguard.Policy
+	tasks      taskLocks
+	limits     rateLimiters
+	breakers   breakers
+	uploads    uploads
+
+	middlewareMu sync.RWMutex
+	middleware   []Middleware
+
+	// ownsTransport is set when httpClient was built by New rather than
+	// supplied through Config.HTTPClient
+	ownsTransport bool
+
+	// initErr is returned by every call when New could not configure TLS
+	initErr error
+
+	// done is canceled by Close to stop in-flight streams
+	done      context.Context
+	cancel    context.CancelFunc
+	closed    atomic.Bool
+	closeOnce sync.Once
+	streamMu  sync.Mutex
+	streams   sync.WaitGroup
 }
 
 // New creates a new A2A protocol client
 func New(config Config) *Client {
+	hosts := config.HostPolicy
+	if hosts == nil {
+		hosts = netguard.DefaultPolicy()
+	}
+
+	httpClient, ownsTransport := config.HTTPClient, false
+	initErr := config.UnknownEvents.Validate()
+	if httpClient == nil {
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		if files := clientTLS(config); files != nil {
+			tlsConfig, err := files.TLSConfig()
+			if err != nil {
+				initErr = fmt.Errorf("invalid TLS configuration: %w", err)
+			}
+			transport.TLSClientConfig = tlsConfig
+		}
+		proxy, err := netguard.Proxy(config.ProxyURL)
+		if err != nil && initErr == nil {
+			initErr = err
+		}
+		transport.Proxy = proxy
+		httpClient = &http.Client{Transport: transport}
+		ownsTransport = true
+	}
+
+	done, cancel := context.WithCancel(context.Background())
 	return &Client{
-		config: config,
-		logger: logrus.New(),
-		httpClient: &http.Client{
-			Timeout: config.Timeout,
-		},
+		config:        config,
+		logger:        logrus.New(),
+		httpClient:    httpClient,
+		ownsTransport: ownsTransport,
+		auth:          auth.NewAuthenticator(),
+		hosts:         hosts,
+		initErr:       initErr,
+		done:          done,
+		cancel:        cancel,
+	}
+}
+
+// clientTLS returns the mutual TLS files configured for the client, if any
+func clientTLS(config Config) *auth.ClientTLS {
+	if config.TLS != nil {
+		return config.TLS
 	}
+	return auth.MTLSFromCredentials(config.Credentials)
 }
 
 // Init initializes the A2A client package
@@ -61,107 +240,487 @@ func Init() error {
 }
 
 // SendTask sends a task to an A2A agent
-// TODO: Implement in Issue #10
 func (c *Client) SendTask(ctx context.Context, agentID string, req *types.TaskRequest) (*types.TaskResponse, error) {
-	return nil, fmt.Errorf("SendTask not yet implemented - see Issue #10")
+	if err := req.Message.Validate(); err != nil {
+		return nil, err
+	}
+
+	msg, err := c.uploadLargeFiles(ctx, agentID, req.Message)
+	if err != nil {
+		return nil, err
+	}
+
+	unlock, err := c.lockTask(ctx, agentID, req.ID)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	params := map[string]interface{}{
+		"id":      req.ID,
+		"message": msg,
+	}
+
+	var resp types.TaskResponse
+	if err := c.call(ctx, agentID, types.A2AMethods.TasksSend, params, &resp); err != nil {
+		return nil, err
+	}
+	resp.Artifacts = types.MergeArtifacts(resp.Artifacts)
+	return &resp, nil
+}
+
+// SendMessage sends a message to an A2A agent using the message/send method
+func (c *Client) SendMessage(ctx context.Context, agentID string, msg *types.Message) (*types.TaskResponse, error) {
+	if err := msg.Validate(); err != nil {
+		return nil, err
+	}
+	msg, err := c.uploadLargeFiles(ctx, agentID, msg)
+	if err != nil {
+		return nil, err
+	}
+
+	params := map[string]interface{}{
+		"message": msg,
+	}
+
+	var resp types.TaskResponse
+	if err := c.call(ctx, agentID, types.A2AMethods.MessageSend, params, &resp); err != nil {
+		return nil, err
+	}
+	resp.Artifacts = types.MergeArtifacts(resp.Artifacts)
+	return &resp, nil
 }
 
-// StreamTask sends a task with streaming response
+// StreamTask sends a task with streaming response. The stream is
+// canceled if the client is closed.
 func (c *Client) StreamTask(ctx context.Context, agentID string, req *types.TaskRequest) (<-chan *types.StreamResponse, <-chan error) {
+	if err := req.Message.Validate(); err != nil {
+		return failedStream(err)
+	}
+	return c.startStream(ctx, agentID, types.A2AMethods.TasksStream, func(ctx context.Context, out chan<- *types.StreamResponse) error {
+		msg, err := c.uploadLargeFiles(ctx, agentID, req.Message)
+		if err != nil {
+			return err
+		}
+
+		unlock, err := c.lockTask(ctx, agentID, req.ID)
+		if err != nil {
+			return err
+		}
+		defer unlock()
+
+		return c.stream(ctx, agentID, types.A2AMethods.TasksStream, map[string]interface{}{
+			"id":      req.ID,
+			"message": msg,
+		}, out)
+	})
+}
+
+// StreamMessage sends a message to an A2A agent using the message/stream
+// method and delivers the agent's events as they arrive. The stream is
+// canceled if the client is closed.
+func (c *Client) StreamMessage(ctx context.Context, agentID string, msg *types.Message) (<-chan *types.StreamResponse, <-chan error) {
+	if err := msg.Validate(); err != nil {
+		return failedStream(err)
+	}
+	return c.startStream(ctx, agentID, types.A2AMethods.MessageStream, func(ctx context.Context, out chan<- *types.StreamResponse) error {
+		msg, err := c.uploadLargeFiles(ctx, agentID, msg)
+		if err != nil {
+			return err
+		}
+		return c.stream(ctx, agentID, types.A2AMethods.MessageStream, map[string]interface{}{
+			"message": msg,
+		}, out)
+	})
+}
+
+// ResubscribeTask reopens the event stream of an existing task, e.g. after
+// a disconnect, using the tasks/resubscribe method. The agent replays the
+// task's current state before sending new events. Unlike StreamTask it
+// sends no turn, so it is not queued behind one when SerializeTasks is
+// set. The stream is canceled if the client is closed.
+func (c *Client) ResubscribeTask(ctx context.Context, agentID, taskID string) (<-chan *types.StreamResponse, <-chan error) {
+	return c.startStream(ctx, agentID, types.A2AMethods.TasksResubscribe, func(ctx context.Context, out chan<- *types.StreamResponse) error {
+		return c.stream(ctx, agentID, types.A2AMethods.TasksResubscribe, map[string]interface{}{
+			"id": taskID,
+		}, out)
+	})
+}
+
+// startStream runs a streaming call in the background, tracking it so that
+// Close cancels it, and records its outcome under method
+func (c *Client) startStream(ctx context.Context, agentID, method string, run func(context.Context, chan<- *types.StreamResponse) error) (<-chan *types.StreamResponse, <-chan error) {
+	ctx, done, err := c.trackStream(ctx)
+	if err != nil {
+		return failedStream(err)
+	}
+
 	out := make(chan *types.StreamResponse)
 	errs := make(chan error, 1)
 
 	go func() {
+		defer done()
 		defer close(out)
 		defer close(errs)
 
-		// Construct the request URL
-		url := fmt.Sprintf("%s/%s", c.config.BaseURL, agentID)
-
-		// Create the JSON-RPC request
-		jsonReq := &types.JSONRPCRequest{
-			JSONRPC: "2.0",
-			Method:  types.A2AMethods.TasksStream,
-			Params: map[string]interface{}{
-				"id":      req.ID,
-				"message": req.Message,
-			},
-			ID: uuid.New().String(),
-		}
+		ctx, cancel := c.withMethodTimeout(ctx, method)
+		defer cancel()
 
-		reqBody, err := json.Marshal(jsonReq)
-		if err != nil {
-			errs <- fmt.Errorf("failed to marshal request: %w", err)
+		start := time.Now()
+		err := run(ctx, out)
+		if errors.Is(err, ErrDryRun) {
+			errs <- err
 			return
 		}
-
-		httpReq, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(reqBody))
+		c.config.Metrics.ObserveRequest(method, c.agentLabel(agentID), time.Since(start), err != nil)
 		if err != nil {
-			errs <- fmt.Errorf("failed to create request: %w", err)
-			return
+			errs <- err
 		}
+	}()
 
-		httpReq.Header.Set("Content-Type", "application/json")
-		httpReq.Header.Set("Accept", "text/event-stream")
-		for k, v := range c.config.Headers {
-			httpReq.Header.Set(k, v)
-		}
+	return out, errs
+}
+
+// failedStream returns a closed event channel and an error channel holding
+// only err, for streams that fail before they start
+func failedStream(err error) (<-chan *types.StreamResponse, <-chan error) {
+	out := make(chan *types.StreamResponse)
+	errs := make(chan error, 1)
+	close(out)
+	errs <- err
+	close(errs)
+	return out, errs
+}
+
+// stream sends a streaming JSON-RPC request and delivers its events on out
+// until the stream ends, fails or ctx is canceled
+func (c *Client) stream(ctx context.Context, agentID, method string, rawParams map[string]interface{}, out chan<- *types.StreamResponse) error {
+	httpReq, reqBody, err := c.BuildRequest(ctx, agentID, method, rawParams)
+	if err != nil {
+		return err
+	}
+	if err := c.dryRun(httpReq, reqBody); err != nil {
+		return err
+	}
+
+	if err := c.waitRateLimit(ctx, agentID); err != nil {
+		return err
+	}
+
+	if err := c.runRequestHooks(httpReq); err != nil {
+		return err
+	}
+	if err := c.allowRequest(agentID); err != nil {
+		return err
+	}
+	resp, err := c.httpClient.Do(httpReq)
+	c.recordRequest(ctx, agentID, resp, err)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", auth.RedactError(err, auth.RedactParam(c.config.Credentials)))
+	}
+	defer resp.Body.Close()
+	if err := httpcompress.Decode(resp); err != nil {
+		return err
+	}
+	if err := c.runResponseHooks(resp); err != nil {
+		return err
+	}
 
-		resp, err := c.httpClient.Do(httpReq)
+	if resp.StatusCode != http.StatusOK {
+		return streamStatusError(resp)
+	}
+
+	// Some agents answer with a single JSON-RPC response, typically an error
+	if isJSONResponse(resp) {
+		event, err := decodeJSONStream(resp, method, c.config.MaxStreamLineSize, c.config.StrictJSONRPC)
 		if err != nil {
-			errs <- fmt.Errorf("request failed: %w", err)
-			return
+			return err
 		}
-		defer resp.Body.Close()
-
-		if resp.StatusCode != http.StatusOK {
-			errs <- fmt.Errorf("unexpected status: %s", resp.Status)
-			return
+		select {
+		case out <- event:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
 		}
+	}
 
-		scanner := bufio.NewScanner(resp.Body)
-		for scanner.Scan() {
-			line := scanner.Text()
-			if strings.HasPrefix(line, "data:") {
-				data := strings.TrimSpace(strings.TrimPrefix(line, "data:"))
-				if data == "" {
-					continue
-				}
-				var streamResp types.StreamResponse
-				if err := json.Unmarshal([]byte(data), &streamResp); err != nil {
-					errs <- fmt.Errorf("failed to unmarshal stream response: %w", err)
-					return
-				}
-				select {
-				case out <- &streamResp:
-				case <-ctx.Done():
-					errs <- ctx.Err()
-					return
-				}
+	scanner := streaming.NewLineScanner(resp.Body, c.config.MaxStreamLineSize)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "data:") {
+			data := strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+			if data == "" {
+				continue
+			}
+			if err := checkEventVersion(method, []byte(data), c.config.StrictJSONRPC); err != nil {
+				return err
+			}
+			streamResp, err := streaming.DecodeEvent("", []byte(data))
+			if err != nil {
+				return err
+			}
+			deliver, err := c.config.UnknownEvents.Apply(streamResp)
+			if err != nil {
+				return err
+			}
+			if !deliver {
+				continue
+			}
+			select {
+			case out <- streamResp:
+			case <-ctx.Done():
+				return ctx.Err()
 			}
 		}
-		if err := scanner.Err(); err != nil {
-			errs <- fmt.Errorf("scanner error: %w", err)
-		}
-	}()
-
-	return out, errs
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read stream: %w", streaming.ScanError(err, c.config.MaxStreamLineSize))
+	}
+	return nil
 }
 
-// GetTaskStatus retrieves the status of a task
-// TODO: Implement in Issue #10
+// GetTaskStatus retrieves the status of a task.
+//
+// If the agent reports that the task does not exist, the returned error
+// wraps ErrTaskNotFound so pollers can stop gracefully; transport and
+// other protocol failures are returned as-is.
 func (c *Client) GetTaskStatus(ctx context.Context, agentID, taskID string) (*types.TaskStatus, error) {
-	return nil, fmt.Errorf("GetTaskStatus not yet implemented - see Issue #10")
+	params := map[string]interface{}{
+		"id": taskID,
+	}
+
+	resp, err := c.roundTrip(ctx, agentID, types.A2AMethods.TasksStatus, params)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Error != nil && resp.Error.Code == types.A2AErrorCodes.TaskNotFound {
+		return nil, fmt.Errorf("%w: %s: %w", ErrTaskNotFound, taskID, types.NewJSONRPCErrorError(types.A2AMethods.TasksStatus, resp.Error))
+	}
+
+	var status types.TaskStatus
+	if err := decodeResult(types.A2AMethods.TasksStatus, resp, &status); err != nil {
+		return nil, err
+	}
+	if status.ID == "" {
+		status.ID = taskID
+	}
+	status.Artifacts = types.MergeArtifacts(status.Artifacts)
+	return &status, nil
 }
 
 // CancelTask cancels a running task
-// TODO: Implement in Issue #10
 func (c *Client) CancelTask(ctx context.Context, agentID, taskID string) error {
-	return fmt.Errorf("CancelTask not yet implemented - see Issue #10")
+	params := map[string]interface{}{
+		"id": taskID,
+	}
+
+	return c.call(ctx, agentID, types.A2AMethods.TasksCancel, params, nil)
+}
+
+// Call sends an arbitrary JSON-RPC method to an agent and returns the raw
+// response envelope. JSON-RPC errors are returned in the envelope, not as
+// a Go error, which makes Call suitable for replay and diagnostics.
+func (c *Client) Call(ctx context.Context, agentID, method string, params interface{}) (*types.JSONRPCResponse, error) {
+	return c.roundTrip(ctx, agentID, method, params)
+}
+
+// call performs a unary JSON-RPC call and decodes the result into out
+func (c *Client) call(ctx context.Context, agentID, method string, params, out interface{}) error {
+	resp, err := c.roundTrip(ctx, agentID, method, params)
+	if err != nil {
+		return err
+	}
+	return decodeResult(method, resp, out)
+}
+
+// decodeResult converts a JSON-RPC error into a *types.JSONRPCErrorError,
+// or decodes the result into out
+func decodeResult(method string, resp *types.JSONRPCResponse, out interface{}) error {
+	if resp.Error != nil {
+		return types.NewJSONRPCErrorError(method, resp.Error)
+	}
+
+	if out == nil || len(resp.Result) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(resp.Result, out); err != nil {
+		return fmt.Errorf("failed to unmarshal %s result: %w", method, err)
+	}
+	return nil
+}
+
+// roundTrip sends a JSON-RPC request to an agent and returns the raw
+// response envelope, recording it in the configured metrics
+func (c *Client) roundTrip(ctx context.Context, agentID, method string, params interface{}) (*types.JSONRPCResponse, error) {
+	start := time.Now()
+	resp, err := c.retryRoundTrip(ctx, agentID, method, params)
+	if errors.Is(err, ErrDryRun) {
+		return nil, err
+	}
+	failed := err != nil || resp.Error != nil
+	c.config.Metrics.ObserveRequest(method, c.agentLabel(agentID), time.Since(start), failed)
+	return resp, err
+}
+
+// retryRoundTrip sends a JSON-RPC request, retrying failures as allowed
+// by the retry policy
+func (c *Client) retryRoundTrip(ctx context.Context, agentID, method string, params interface{}) (*types.JSONRPCResponse, error) {
+	ctx, cancel := c.withMethodTimeout(ctx, method)
+	defer cancel()
+
+	reqID, reqBody, err := c.encodeRequest(method, params)
+	if err != nil {
+		return nil, err
+	}
+
+	var lastErr error
+	for attempt := 0; attempt <= c.config.Retry.Attempts; attempt++ {
+		if attempt > 0 {
+			c.logger.Debugf("Retry attempt %d/%d for %s", attempt, c.config.Retry.Attempts, method)
+			if err := c.config.Retry.Wait(ctx, attempt); err != nil {
+				return nil, err
+			}
+		}
+
+		resp, retryable, err := c.doRoundTrip(ctx, agentID, method, reqID, reqBody)
+		if err == nil {
+			return resp, nil
+		}
+		if !retryable || ctx.Err() != nil {
+			return nil, err
+		}
+		lastErr = err
+	}
+
+	return nil, fmt.Errorf("failed after %d attempts: %w", c.config.Retry.Attempts+1, lastErr)
 }
 
-// Ping tests connectivity to an A2A agent
-// TODO: Implement in Issue #10
-func (c *Client) Ping(ctx context.Context, agentURL string) error {
-	return fmt.Errorf("Ping not yet implemented - see Issue #10")
+// doRoundTrip performs a single HTTP exchange, reporting whether a failure
+// is worth retrying (transport errors and 5xx responses)
+func (c *Client) doRoundTrip(ctx context.Context, agentID, method, reqID string, reqBody []byte) (*types.JSONRPCResponse, bool, error) {
+	httpReq, err := c.newRequest(ctx, method, c.agentURL(agentID), reqBody)
+	if err != nil {
+		return nil, false, err
+	}
+	if err := c.dryRun(httpReq, reqBody); err != nil {
+		return nil, false, err
+	}
+
+	if err := c.waitRateLimit(ctx, agentID); err != nil {
+		return nil, false, err
+	}
+
+	redactParam := auth.RedactParam(c.config.Credentials)
+	c.logger.Debugf("A2A request: %s -> %s", method, auth.RedactURL(httpReq.URL.String(), redactParam))
+
+	if err := c.runRequestHooks(httpReq); err != nil {
+		return nil, false, err
+	}
+	if err := c.allowRequest(agentID); err != nil {
+		return nil, false, err
+	}
+	start := time.Now()
+	httpResp, err := c.httpClient.Do(httpReq)
+	c.recordRequest(ctx, agentID, httpResp, err)
+	if err != nil {
+		return nil, true, fmt.Errorf("request failed: %w", auth.RedactError(err, redactParam))
+	}
+	defer httpResp.Body.Close()
+	if err := httpcompress.Decode(httpResp); err != nil {
+		return nil, false, err
+	}
+	if err := c.runResponseHooks(httpResp); err != nil {
+		return nil, false, err
+	}
+
+	if skew, _, ok := clockSkew(httpResp, start, time.Since(start)); ok {
+		c.checkSkew(httpReq.URL.String(), skew)
+	}
+
+	if httpResp.StatusCode != http.StatusOK {
+		return nil, httpResp.StatusCode >= 500, fmt.Errorf("unexpected status: %s", httpResp.Status)
+	}
+
+	var resp types.JSONRPCResponse
+	if err := decodeBody(httpResp.Body, &resp); err != nil {
+		return nil, false, err
+	}
+	if err := c.runEnvelopeHooks(method, &resp); err != nil {
+		return nil, false, err
+	}
+	if err := checkResponse(method, reqID, &resp, c.config.StrictJSONRPC); err != nil {
+		return nil, false, err
+	}
+	return &resp, false, nil
+}
+
+// newRequest builds an authenticated JSON-RPC POST request for method.
+// Credentials are validated before the request is built so that
+// misconfigured auth never reaches the network. An Accept header from
+// Config.Headers or the context wins over the default for method.
+func (c *Client) newRequest(ctx context.Context, method, url string, body []byte) (*http.Request, error) {
+	if err := c.checkOpen(); err != nil {
+		return nil, err
+	}
+	if err := c.hosts.CheckURL(url); err != nil {
+		return nil, err
+	}
+	if c.config.Credentials != nil {
+		if err := c.auth.ValidateCredentials(c.config.Credentials); err != nil {
+			return nil, fmt.Errorf("invalid credentials: %w", err)
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	httpcompress.SetAcceptEncoding(req)
+	c.setHeaders(req)
+	if req.Header.Get("Accept") == "" {
+		req.Header.Set("Accept", c.accept(method))
+	}
+
+	if err := c.auth.AddAuthHeaders(req, c.config.Credentials); err != nil {
+		return nil, fmt.Errorf("failed to add auth headers: %w", err)
+	}
+
+	return req, nil
+}
+
+// accept returns the default Accept header for method: SSE for streaming
+// methods, otherwise JSON followed by the configured output mode
+func (c *Client) accept(method string) string {
+	switch {
+	case streamingMethods[method]:
+		return "text/event-stream"
+	case c.config.OutputMode != "":
+		return "application/json, " + c.config.OutputMode
+	}
+	return "application/json"
+}
+
+// requestID generates a JSON-RPC request ID with the configured prefix
+func (c *Client) requestID() string {
+	return c.config.RequestIDPrefix + types.NewID()
+}
+
+// agentLabel identifies an agent in metrics: its ID, or BaseURL when the
+// agent is addressed directly
+func (c *Client) agentLabel(agentID string) string {
+	if agentID == "" {
+		return c.config.BaseURL
+	}
+	return agentID
+}
+
+// agentURL returns the JSON-RPC endpoint for an agent.
+// An empty agentID addresses BaseURL directly.
+func (c *Client) agentURL(agentID string) string {
+	if agentID == "" {
+		return c.config.BaseURL
+	}
+	return fmt.Sprintf("%s/%s", strings.TrimSuffix(c.config.BaseURL, "/"), agentID)
 }
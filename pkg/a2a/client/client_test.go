@@ -0,0 +1,389 @@
+package client
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/craine-io/openribcage/internal/auth"
+	"github.com/craine-io/openribcage/pkg/a2a/retry"
+	"github.com/craine-io/openribcage/pkg/a2a/types"
+	"github.com/craine-io/openribcage/pkg/httpcompress"
+	"github.com/craine-io/openribcage/pkg/netguard"
+)
+
+// countingTransport counts the requests it forwards to
+// http.DefaultTransport
+type countingTransport struct {
+	mu       sync.Mutex
+	requests int
+}
+
+// RoundTrip implements http.RoundTripper
+func (c *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	c.mu.Lock()
+	c.requests++
+	c.mu.Unlock()
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+// count returns how many requests have been forwarded
+func (c *countingTransport) count() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.requests
+}
+
+// failingAgent answers the first failures requests with status and then
+// behaves like a healthy agent
+func failingAgent(t *testing.T, failures, status int) (*httptest.Server, func() int) {
+	ok := newAgentServer(t, taskResult)
+	var mu sync.Mutex
+	hits := 0
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		hits++
+		fail := hits <= failures
+		mu.Unlock()
+		if fail {
+			http.Error(w, "failing", status)
+			return
+		}
+		ok.Config.Handler.ServeHTTP(w, r)
+	}))
+	t.Cleanup(s.Close)
+	return s, func() int {
+		mu.Lock()
+		defer mu.Unlock()
+		return hits
+	}
+}
+
+// TestRetry tests which failures the client retries under its policy
+func TestRetry(t *testing.T) {
+	policy := retry.Policy{Attempts: 2, BaseDelay: time.Millisecond}
+
+	tests := []struct {
+		name     string
+		failures int
+		status   int
+		wantHits int
+		wantErr  string
+	}{
+		{"recovers after server errors", 2, http.StatusServiceUnavailable, 3, ""},
+		{"gives up after the last attempt", 5, http.StatusInternalServerError, 3, "failed after 3 attempts"},
+		{"client errors are not retried", 5, http.StatusBadRequest, 1, "400 Bad Request"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			agent, hits := failingAgent(t, tt.failures, tt.status)
+			c := newTestClient(t, Config{BaseURL: agent.URL, Timeout: 5 * time.Second, Retry: policy})
+
+			_, err := c.GetTaskStatus(context.Background(), "", "task-1")
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.wantErr)
+			}
+			assert.Equal(t, tt.wantHits, hits())
+		})
+	}
+}
+
+// TestRetryDisabled tests that the zero policy makes a single attempt
+func TestRetryDisabled(t *testing.T) {
+	agent, hits := failingAgent(t, 1, http.StatusInternalServerError)
+	c := newTestClient(t, Config{BaseURL: agent.URL, Timeout: 5 * time.Second})
+
+	_, err := c.GetTaskStatus(context.Background(), "", "task-1")
+	assert.Error(t, err)
+	assert.Equal(t, 1, hits())
+}
+
+// TestGetTaskStatusNotFound tests that a missing task is reported as
+// ErrTaskNotFound and that other JSON-RPC errors are not
+func TestGetTaskStatusNotFound(t *testing.T) {
+	code := types.A2AErrorCodes.TaskNotFound
+	agent := newAgentServer(t, func(req *types.JSONRPCRequest) (interface{}, *types.JSONRPCError) {
+		if code == 0 {
+			return map[string]string{"status": "working"}, nil
+		}
+		return nil, &types.JSONRPCError{Code: code, Message: "no such task"}
+	})
+	c := newTestClient(t, Config{BaseURL: agent.URL, Timeout: 5 * time.Second})
+	ctx := context.Background()
+
+	_, err := c.GetTaskStatus(ctx, "", "task-1")
+	assert.ErrorIs(t, err, ErrTaskNotFound)
+	assert.ErrorContains(t, err, "task-1")
+
+	code = types.A2AErrorCodes.TaskNotCancelable
+	_, err = c.GetTaskStatus(ctx, "", "task-1")
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, ErrTaskNotFound)
+
+	// A status without an ID takes the requested one
+	code = 0
+	status, err := c.GetTaskStatus(ctx, "", "task-1")
+	require.NoError(t, err)
+	assert.Equal(t, "task-1", status.ID)
+	assert.Equal(t, types.TaskState("working"), status.Status)
+}
+
+// TestAPIKeyQueryRedacted tests that a query-parameter API key reaches the
+// agent but is masked in transport errors
+func TestAPIKeyQueryRedacted(t *testing.T) {
+	var mu sync.Mutex
+	var key string
+	ok := newAgentServer(t, taskResult)
+	agent := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		key = r.URL.Query().Get("code")
+		mu.Unlock()
+		ok.Config.Handler.ServeHTTP(w, r)
+	}))
+	defer agent.Close()
+	creds := &auth.Credentials{Type: auth.AuthTypeAPIKeyQuery, APIKey: "s3cret", QueryParam: "code"}
+
+	c := newTestClient(t, Config{BaseURL: agent.URL, Timeout: 5 * time.Second, Credentials: creds})
+	_, err := c.GetTaskStatus(context.Background(), "", "task-1")
+	require.NoError(t, err)
+	mu.Lock()
+	assert.Equal(t, "s3cret", key)
+	mu.Unlock()
+
+	down := httptest.NewServer(http.NotFoundHandler())
+	down.Close()
+	c = newTestClient(t, Config{BaseURL: down.URL, Timeout: 5 * time.Second, Credentials: creds})
+	_, err = c.GetTaskStatus(context.Background(), "", "task-1")
+	require.Error(t, err)
+	assert.NotContains(t, err.Error(), "s3cret")
+	assert.Contains(t, err.Error(), "code=REDACTED")
+}
+
+// TestHostPolicy tests that requests to denied hosts fail before anything
+// is sent
+func TestHostPolicy(t *testing.T) {
+	agent := newAgentServer(t, taskResult)
+	c := newTestClient(t, Config{
+		BaseURL:    agent.URL,
+		Timeout:    5 * time.Second,
+		HostPolicy: &netguard.Policy{Deny: []string{"127.0.0.1"}},
+	})
+
+	_, err := c.GetTaskStatus(context.Background(), "", "task-1")
+	assert.ErrorIs(t, err, netguard.ErrHostNotAllowed)
+	_, err = c.Ping(context.Background(), agent.URL)
+	assert.ErrorIs(t, err, netguard.ErrHostNotAllowed)
+	assert.Equal(t, 0, agent.requestCount())
+
+	c = newTestClient(t, Config{BaseURL: agent.URL, Timeout: 5 * time.Second, HostPolicy: &netguard.Policy{Allow: []string{"127.0.0.0/8"}}})
+	_, err = c.GetTaskStatus(context.Background(), "", "task-1")
+	assert.NoError(t, err)
+}
+
+// TestRequestIDPrefix tests that generated JSON-RPC request IDs carry the
+// configured prefix
+func TestRequestIDPrefix(t *testing.T) {
+	types.SetIDGenerator(func() string { return "1234" })
+	defer types.SetIDGenerator(nil)
+
+	var mu sync.Mutex
+	var ids []interface{}
+	agent := newAgentServer(t, func(req *types.JSONRPCRequest) (interface{}, *types.JSONRPCError) {
+		mu.Lock()
+		ids = append(ids, req.ID)
+		mu.Unlock()
+		return taskResult(req)
+	})
+
+	c := newTestClient(t, Config{BaseURL: agent.URL, Timeout: 5 * time.Second, RequestIDPrefix: "openribcage-"})
+	_, err := c.GetTaskStatus(context.Background(), "", "task-1")
+	require.NoError(t, err)
+
+	c = newTestClient(t, Config{BaseURL: agent.URL, Timeout: 5 * time.Second})
+	_, err = c.GetTaskStatus(context.Background(), "", "task-1")
+	require.NoError(t, err)
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []interface{}{"openribcage-1234", "1234"}, ids)
+}
+
+// TestTLS tests that a configured CA bundle verifies the agent and that
+// invalid TLS files fail every call
+func TestTLS(t *testing.T) {
+	agent := newAgentServer(t, taskResult)
+	secure := httptest.NewTLSServer(agent.Config.Handler)
+	defer secure.Close()
+	caFile := filepath.Join(t.TempDir(), "ca.crt")
+	require.NoError(t, os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: secure.Certificate().Raw}), 0o600))
+
+	c := newTestClient(t, Config{BaseURL: secure.URL, Timeout: 5 * time.Second, TLS: &auth.ClientTLS{CAFile: caFile}})
+	_, err := c.GetTaskStatus(context.Background(), "", "task-1")
+	assert.NoError(t, err)
+
+	c = newTestClient(t, Config{BaseURL: secure.URL, Timeout: 5 * time.Second})
+	_, err = c.GetTaskStatus(context.Background(), "", "task-1")
+	assert.Error(t, err)
+
+	c = newTestClient(t, Config{BaseURL: secure.URL, Timeout: 5 * time.Second, TLS: &auth.ClientTLS{CAFile: "/nonexistent.crt"}})
+	_, err = c.GetTaskStatus(context.Background(), "", "task-1")
+	assert.ErrorContains(t, err, "invalid TLS configuration")
+
+	creds := &auth.Credentials{Type: auth.AuthTypeMTLS, Config: map[string]string{auth.MTLSCertFile: "/nonexistent.crt", auth.MTLSKeyFile: "/nonexistent.key"}}
+	c = newTestClient(t, Config{BaseURL: secure.URL, Timeout: 5 * time.Second, Credentials: creds})
+	_, err = c.GetTaskStatus(context.Background(), "", "task-1")
+	assert.ErrorContains(t, err, "failed to load client certificate")
+	assert.Equal(t, 1, agent.requestCount())
+}
+
+// TestProxyURL tests that requests go through the configured proxy and
+// that an invalid proxy URL fails every call
+func TestProxyURL(t *testing.T) {
+	agent := newAgentServer(t, taskResult)
+	var mu sync.Mutex
+	var proxied []string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		proxied = append(proxied, r.URL.Host)
+		mu.Unlock()
+		agent.Config.Handler.ServeHTTP(w, r)
+	}))
+	defer proxy.Close()
+
+	c := newTestClient(t, Config{BaseURL: agent.URL, Timeout: 5 * time.Second, ProxyURL: proxy.URL})
+	_, err := c.GetTaskStatus(context.Background(), "", "task-1")
+	require.NoError(t, err)
+	mu.Lock()
+	assert.Equal(t, []string{agent.Listener.Addr().String()}, proxied)
+	mu.Unlock()
+
+	c = newTestClient(t, Config{BaseURL: agent.URL, Timeout: 5 * time.Second, ProxyURL: "ftp://proxy"})
+	_, err = c.GetTaskStatus(context.Background(), "", "task-1")
+	assert.ErrorContains(t, err, "unsupported proxy scheme")
+}
+
+// TestHTTPClient tests that an injected http.Client carries every request,
+// and that Close leaves its connections to the owner
+func TestHTTPClient(t *testing.T) {
+	agent := newAgentServer(t, taskResult)
+	transport := &countingTransport{}
+
+	c := New(Config{BaseURL: agent.URL, Timeout: 5 * time.Second, HTTPClient: &http.Client{Transport: transport}})
+	_, err := c.GetTaskStatus(context.Background(), "", "task-1")
+	require.NoError(t, err)
+	_, err = c.SendTask(context.Background(), "", batchRequest("task-1"))
+	require.NoError(t, err)
+	assert.Equal(t, 2, transport.count())
+	assert.Equal(t, 2, agent.requestCount())
+	assert.False(t, c.ownsTransport)
+	require.NoError(t, c.Close())
+}
+
+// TestMessageValidation tests that malformed messages are rejected before
+// any request is sent
+func TestMessageValidation(t *testing.T) {
+	text := types.Part{Type: "text", Text: "Hello"}
+	tests := []struct {
+		name string
+		msg  *types.Message
+		want string
+	}{
+		{"nil message", nil, "message is required"},
+		{"empty role", &types.Message{Parts: []types.Part{text}}, "role is required"},
+		{"unknown role", &types.Message{Role: "robot", Parts: []types.Part{text}}, "unknown role"},
+		{"no parts", &types.Message{Role: "user"}, "at least one part"},
+		{"empty parts", &types.Message{Role: "user", Parts: []types.Part{}}, "at least one part"},
+		{"empty text", &types.Message{Role: "user", Parts: []types.Part{{Type: "text"}}}, "part 0: text part has no text"},
+		{"data without data", &types.Message{Role: "user", Parts: []types.Part{text, {Type: "data"}}}, "part 1: data part has no data"},
+		{"file without source", &types.Message{Role: "user", Parts: []types.Part{{Type: "file", File: &types.FilePart{Name: "a.txt"}}}}, "neither content nor URL"},
+		{"image without image", &types.Message{Role: "user", Parts: []types.Part{{Type: "image"}}}, "image part has no image"},
+		{"audio without source", &types.Message{Role: "user", Parts: []types.Part{types.NewAudioPart(&types.AudioPart{MimeType: "audio/wav"})}}, "neither content nor URL"},
+	}
+
+	agent := newAgentServer(t, taskResult)
+	c := newTestClient(t, Config{BaseURL: agent.URL, Timeout: 5 * time.Second})
+	ctx := context.Background()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.msg.Validate()
+			require.ErrorIs(t, err, types.ErrInvalidMessage)
+			assert.Contains(t, err.Error(), tt.want)
+
+			_, err = c.SendTask(ctx, "", &types.TaskRequest{ID: "task-1", Message: tt.msg})
+			assert.ErrorIs(t, err, types.ErrInvalidMessage)
+			_, err = c.SendMessage(ctx, "", tt.msg)
+			assert.ErrorIs(t, err, types.ErrInvalidMessage)
+			events, errs := c.StreamTask(ctx, "", &types.TaskRequest{ID: "task-1", Message: tt.msg})
+			for range events {
+			}
+			assert.ErrorIs(t, <-errs, types.ErrInvalidMessage)
+		})
+	}
+	assert.Zero(t, agent.requestCount(), "invalid messages must not reach the agent")
+
+	valid := &types.Message{Role: "agent", Parts: []types.Part{
+		text,
+		types.NewJSONPart(map[string]int{"n": 1}, ""),
+		types.NewImagePart(&types.ImagePart{MimeType: "image/png", URL: "https://example.com/a.png"}),
+		{Type: "video", Extra: map[string]json.RawMessage{"video": json.RawMessage(`{}`)}},
+	}}
+	assert.NoError(t, valid.Validate())
+}
+
+// TestCompressedResponses tests decoding of deflate encoded JSON-RPC
+// responses and gzip encoded SSE streams
+func TestCompressedResponses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, httpcompress.AcceptEncoding, r.Header.Get("Accept-Encoding"))
+
+		var req types.JSONRPCRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		if r.Header.Get("Accept") == "text/event-stream" {
+			w.Header().Set("Content-Type", "text/event-stream")
+			w.Header().Set("Content-Encoding", "gzip")
+			gz := gzip.NewWriter(w)
+			for _, state := range []string{"working", "completed"} {
+				fmt.Fprintf(gz, "data: {\"id\":\"task-1\",\"type\":\"status\",\"data\":{\"state\":%q}}\n\n", state)
+				gz.Flush()
+				w.(http.Flusher).Flush()
+			}
+			gz.Close()
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Encoding", "deflate")
+		zw := zlib.NewWriter(w)
+		json.NewEncoder(zw).Encode(types.JSONRPCResponse{JSONRPC: "2.0", ID: req.ID, Result: json.RawMessage(`{"id":"task-1","status":"completed"}`)})
+		zw.Close()
+	}))
+	defer server.Close()
+
+	c := newTestClient(t, Config{BaseURL: server.URL, Timeout: 5 * time.Second})
+	status, err := c.GetTaskStatus(context.Background(), "", "task-1")
+	require.NoError(t, err)
+	assert.Equal(t, "task-1", status.ID)
+
+	received, err := drainStream(c.ResubscribeTask(context.Background(), "", "task-1"))
+	require.NoError(t, err)
+	require.Len(t, received, 2)
+	for _, event := range received {
+		assert.Equal(t, "task-1", event.ID)
+	}
+}
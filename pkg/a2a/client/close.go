@@ -0,0 +1,58 @@
+package client
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrClientClosed is returned by methods called after Close
+var ErrClientClosed = errors.New("client is closed")
+
+// Close releases the client's idle connections and cancels in-flight
+// streams, waiting for their goroutines to exit. The client is unusable
+// afterwards: every method returns ErrClientClosed. Close is idempotent.
+// Connections of an http.Client supplied through Config.HTTPClient are
+// left to its owner.
+func (c *Client) Close() error {
+	c.closeOnce.Do(func() {
+		c.streamMu.Lock()
+		c.closed.Store(true)
+		c.streamMu.Unlock()
+
+		c.cancel()
+		c.streams.Wait()
+		if c.ownsTransport {
+			c.httpClient.CloseIdleConnections()
+		}
+	})
+	return nil
+}
+
+// checkOpen returns ErrClientClosed once Close has been called, or the
+// error that kept New from configuring the transport
+func (c *Client) checkOpen() error {
+	if c.closed.Load() {
+		return ErrClientClosed
+	}
+	return c.initErr
+}
+
+// trackStream registers a streaming goroutine and returns a context that
+// is also canceled by Close. The returned function must be called when
+// the goroutine exits.
+func (c *Client) trackStream(ctx context.Context) (context.Context, func(), error) {
+	c.streamMu.Lock()
+	defer c.streamMu.Unlock()
+	if err := c.checkOpen(); err != nil {
+		return nil, nil, err
+	}
+	c.streams.Add(1)
+
+	ctx, cancel := context.WithCancel(ctx)
+	stop := context.AfterFunc(c.done, cancel)
+	return ctx, func() {
+		stop()
+		cancel()
+		c.streams.Done()
+	}, nil
+}
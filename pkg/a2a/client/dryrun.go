@@ -0,0 +1,89 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/craine-io/openribcage/pkg/a2a/types"
+)
+
+// ErrDryRun matches the *DryRunError returned by every call when
+// Config.DryRun is set
+var ErrDryRun = errors.New("dry run: request not sent")
+
+// DryRunError carries the request a call would have sent had
+// Config.DryRun not been set
+type DryRunError struct {
+	// Request is the fully-formed request, headers and credentials
+	// included. Its body has not been read.
+	Request *http.Request
+	// Body is the marshalled JSON-RPC request
+	Body []byte
+}
+
+// Error implements the error interface
+func (e *DryRunError) Error() string {
+	return fmt.Sprintf("dry run: %s %s not sent", e.Request.Method, e.Request.URL)
+}
+
+// Is matches ErrDryRun
+func (e *DryRunError) Is(target error) bool {
+	return target == ErrDryRun
+}
+
+// BuildRequest builds the request that calling method with params would
+// send to an agent, without sending it. Params are encoded, credentials
+// validated and headers assembled exactly as for a real call; the
+// returned body is the marshalled JSON-RPC request, which the request
+// also carries. Middleware request hooks are not run.
+func (c *Client) BuildRequest(ctx context.Context, agentID, method string, params interface{}) (*http.Request, []byte, error) {
+	_, body, err := c.encodeRequest(method, params)
+	if err != nil {
+		return nil, nil, err
+	}
+	req, err := c.newRequest(ctx, method, c.agentURL(agentID), body)
+	if err != nil {
+		return nil, nil, err
+	}
+	return req, body, nil
+}
+
+// encodeRequest builds and marshals a JSON-RPC request for method with a
+// new request ID
+func (c *Client) encodeRequest(method string, rawParams interface{}) (string, []byte, error) {
+	params, err := c.encodeParams(method, rawParams)
+	if err != nil {
+		return "", nil, err
+	}
+
+	reqID := c.requestID()
+	jsonReq := &types.JSONRPCRequest{
+		JSONRPC: jsonRPCVersion,
+		Method:  method,
+		Params:  params,
+		ID:      reqID,
+	}
+	if c.config.StrictJSONRPC {
+		if err := checkVersion(method, "request", jsonReq.JSONRPC); err != nil {
+			return "", nil, err
+		}
+	}
+
+	body, err := json.Marshal(jsonReq)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+	return reqID, body, nil
+}
+
+// dryRun returns a *DryRunError for req when Config.DryRun is set
+func (c *Client) dryRun(req *http.Request, body []byte) error {
+	if !c.config.DryRun {
+		return nil
+	}
+	c.logger.Debugf("Dry run: not sending %s %s", req.Method, req.URL)
+	return &DryRunError{Request: req, Body: body}
+}
@@ -0,0 +1,94 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/craine-io/openribcage/internal/auth"
+	"github.com/craine-io/openribcage/pkg/a2a/types"
+)
+
+// TestDryRun tests that dry runs build fully-formed requests without
+// sending them
+func TestDryRun(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("dry run sent %s %s", r.Method, r.URL)
+	}))
+	defer server.Close()
+
+	c := newTestClient(t, Config{
+		BaseURL:         server.URL,
+		Timeout:         5 * time.Second,
+		Headers:         map[string]string{"X-Tenant": "acme"},
+		Credentials:     &auth.Credentials{Type: auth.AuthTypeBearer, Token: "secret-token"},
+		RequestIDPrefix: "dry-",
+		DryRun:          true,
+	})
+	ctx := context.Background()
+
+	checkRequest := func(t *testing.T, dryRun *DryRunError, method, accept string) {
+		req := dryRun.Request
+		assert.Equal(t, http.MethodPost, req.Method)
+		assert.Equal(t, server.URL, req.URL.String())
+		assert.Equal(t, "Bearer secret-token", req.Header.Get("Authorization"))
+		assert.Equal(t, "acme", req.Header.Get("X-Tenant"))
+		assert.Equal(t, "application/json", req.Header.Get("Content-Type"))
+		assert.Equal(t, accept, req.Header.Get("Accept"))
+
+		var body struct {
+			JSONRPC string `json:"jsonrpc"`
+			Method  string `json:"method"`
+			ID      string `json:"id"`
+			Params  struct {
+				ID      string        `json:"id"`
+				Message types.Message `json:"message"`
+			} `json:"params"`
+		}
+		require.NoError(t, json.Unmarshal(dryRun.Body, &body))
+		assert.Equal(t, "2.0", body.JSONRPC)
+		assert.Equal(t, method, body.Method)
+		assert.True(t, strings.HasPrefix(body.ID, "dry-"))
+		assert.Equal(t, "task-1", body.Params.ID)
+		assert.Equal(t, "hello", body.Params.Message.Parts[0].Text)
+
+		sent, err := io.ReadAll(req.Body)
+		require.NoError(t, err)
+		assert.Equal(t, dryRun.Body, sent, "the request must carry the returned body")
+	}
+
+	req := &types.TaskRequest{ID: "task-1", Message: &types.Message{Role: "user", Parts: []types.Part{{Type: "text", Text: "hello"}}}}
+
+	_, err := c.SendTask(ctx, "", req)
+	require.ErrorIs(t, err, ErrDryRun)
+	var dryRun *DryRunError
+	require.ErrorAs(t, err, &dryRun)
+	checkRequest(t, dryRun, types.A2AMethods.TasksSend, "application/json")
+
+	events, errs := c.StreamTask(ctx, "", req)
+	for range events {
+		t.Error("dry run streamed an event")
+	}
+	require.ErrorAs(t, <-errs, &dryRun)
+	checkRequest(t, dryRun, types.A2AMethods.TasksStream, "text/event-stream")
+
+	// Validation still runs before anything is built
+	_, err = c.SendTask(ctx, "", &types.TaskRequest{ID: "task-1", Message: &types.Message{Role: "user"}})
+	assert.ErrorIs(t, err, types.ErrInvalidMessage)
+	assert.NotErrorIs(t, err, ErrDryRun)
+
+	// BuildRequest works without DryRun too
+	live := newTestClient(t, Config{BaseURL: server.URL, Timeout: 5 * time.Second})
+	httpReq, body, err := live.BuildRequest(ctx, "", types.A2AMethods.TasksStatus, map[string]interface{}{"id": "task-1"})
+	require.NoError(t, err)
+	assert.Equal(t, "application/json", httpReq.Header.Get("Accept"))
+	assert.Contains(t, string(body), `"method":"`+types.A2AMethods.TasksStatus+`"`)
+}
@@ -0,0 +1,109 @@
+package client
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/craine-io/openribcage/pkg/a2a/types"
+)
+
+// ErrTaskNotFound is returned when an agent reports that a task does not exist
+var ErrTaskNotFound = errors.New("task not found")
+
+// ErrEmptyResponse is returned when an agent answers with an empty or
+// whitespace-only body
+var ErrEmptyResponse = types.ErrEmptyResponse
+
+// jsonRPCVersion is the only JSON-RPC version spoken by A2A agents
+const jsonRPCVersion = "2.0"
+
+// ProtocolError reports a JSON-RPC protocol violation in an A2A exchange
+type ProtocolError struct {
+	Method string
+	Reason string
+}
+
+// Error implements the error interface
+func (e *ProtocolError) Error() string {
+	return fmt.Sprintf("A2A protocol error in %s: %s", e.Method, e.Reason)
+}
+
+// checkVersion returns a *ProtocolError if version is not "2.0"
+func checkVersion(method, kind, version string) error {
+	if version == jsonRPCVersion {
+		return nil
+	}
+	if version == "" {
+		return &ProtocolError{Method: method, Reason: fmt.Sprintf("%s is missing the jsonrpc version", kind)}
+	}
+	return &ProtocolError{Method: method, Reason: fmt.Sprintf("%s has jsonrpc version %q, expected %q", kind, version, jsonRPCVersion)}
+}
+
+// checkEventVersion checks the jsonrpc version of a stream event sent as
+// a JSON-RPC response, as checkResponse does for unary calls. Events that
+// are not JSON-RPC responses carry no version and are not checked.
+func checkEventVersion(method string, data []byte, strict bool) error {
+	var frame struct {
+		JSONRPC *string         `json:"jsonrpc"`
+		Result  json.RawMessage `json:"result"`
+		Error   json.RawMessage `json:"error"`
+	}
+	if err := json.Unmarshal(data, &frame); err != nil {
+		return nil
+	}
+	if frame.JSONRPC != nil {
+		return checkVersion(method, "stream event", *frame.JSONRPC)
+	}
+	if strict && (frame.Result != nil || frame.Error != nil) {
+		return checkVersion(method, "stream event", "")
+	}
+	return nil
+}
+
+// checkResponse verifies that a response answers the request with ID
+// reqID. A null ID is accepted on error responses, as JSON-RPC 2.0 allows
+// when the request could not be read. The jsonrpc version must be "2.0"
+// if present; a missing version is only rejected when strict is set.
+func checkResponse(method, reqID string, resp *types.JSONRPCResponse, strict bool) error {
+	if resp.JSONRPC != "" || strict {
+		if err := checkVersion(method, "response", resp.JSONRPC); err != nil {
+			return err
+		}
+	}
+
+	if resp.ID == nil && resp.Error != nil {
+		return nil
+	}
+	if id, ok := resp.ID.(string); !ok || id != reqID {
+		return &ProtocolError{Method: method, Reason: fmt.Sprintf("response id %v does not match request id %q", resp.ID, reqID)}
+	}
+	return nil
+}
+
+// decodeBody decodes a JSON response body into v, returning
+// ErrEmptyResponse instead of io.EOF when the body is empty or only
+// whitespace
+func decodeBody(body io.Reader, v interface{}) error {
+	r := bufio.NewReader(body)
+	for {
+		b, err := r.ReadByte()
+		if err == io.EOF {
+			return ErrEmptyResponse
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read response: %w", err)
+		}
+		if b != ' ' && b != '\t' && b != '\r' && b != '\n' {
+			r.UnreadByte()
+			break
+		}
+	}
+
+	if err := json.NewDecoder(r).Decode(v); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/craine-io/openribcage/pkg/a2a/types"
+)
+
+// TestDecodeBody tests that empty bodies are told apart from malformed ones
+func TestDecodeBody(t *testing.T) {
+	var v map[string]int
+	assert.ErrorIs(t, decodeBody(strings.NewReader(""), &v), ErrEmptyResponse)
+	assert.ErrorIs(t, decodeBody(strings.NewReader(" \r\n\t"), &v), ErrEmptyResponse)
+
+	err := decodeBody(strings.NewReader("  {"), &v)
+	assert.ErrorContains(t, err, "failed to decode response")
+	assert.NotErrorIs(t, err, ErrEmptyResponse)
+
+	require.NoError(t, decodeBody(strings.NewReader("\n {\"a\": 1}"), &v))
+	assert.Equal(t, map[string]int{"a": 1}, v)
+}
+
+// TestEmptyResponse tests that an agent answering with an empty body
+// fails the call with ErrEmptyResponse
+func TestEmptyResponse(t *testing.T) {
+	agent := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte("\n"))
+	}))
+	defer agent.Close()
+
+	c := newTestClient(t, Config{BaseURL: agent.URL, Timeout: 5 * time.Second})
+	_, err := c.GetTaskStatus(context.Background(), "", "task-1")
+	assert.ErrorIs(t, err, ErrEmptyResponse)
+}
+
+// TestCheckResponse tests matching response IDs and versions to requests
+func TestCheckResponse(t *testing.T) {
+	rpcErr := &types.JSONRPCError{Code: -32700, Message: "parse error"}
+	tests := []struct {
+		name   string
+		resp   types.JSONRPCResponse
+		strict bool
+		want   string
+	}{
+		{"match", types.JSONRPCResponse{JSONRPC: "2.0", ID: "req-1"}, true, ""},
+		{"missing version", types.JSONRPCResponse{ID: "req-1"}, false, ""},
+		{"missing version strict", types.JSONRPCResponse{ID: "req-1"}, true, "missing the jsonrpc version"},
+		{"wrong version", types.JSONRPCResponse{JSONRPC: "1.0", ID: "req-1"}, false, `jsonrpc version "1.0"`},
+		{"wrong id", types.JSONRPCResponse{JSONRPC: "2.0", ID: "req-2"}, false, `response id req-2 does not match request id "req-1"`},
+		{"numeric id", types.JSONRPCResponse{JSONRPC: "2.0", ID: float64(1)}, false, "does not match"},
+		{"null id", types.JSONRPCResponse{JSONRPC: "2.0"}, false, "does not match"},
+		{"null id on error", types.JSONRPCResponse{JSONRPC: "2.0", Error: rpcErr}, false, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkResponse("tasks/get", "req-1", &tt.resp, tt.strict)
+			if tt.want == "" {
+				assert.NoError(t, err)
+				return
+			}
+			var protoErr *ProtocolError
+			require.ErrorAs(t, err, &protoErr)
+			assert.Equal(t, "tasks/get", protoErr.Method)
+			assert.ErrorContains(t, err, tt.want)
+		})
+	}
+}
+
+// TestResponseIDMismatch tests that a response answering another request
+// fails the call
+func TestResponseIDMismatch(t *testing.T) {
+	agent := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"jsonrpc":"2.0","id":"someone-else","result":{"id":"task-1","status":"working"}}`))
+	}))
+	defer agent.Close()
+
+	c := newTestClient(t, Config{BaseURL: agent.URL, Timeout: 5 * time.Second})
+	_, err := c.GetTaskStatus(context.Background(), "", "task-1")
+	var protoErr *ProtocolError
+	require.ErrorAs(t, err, &protoErr)
+	assert.ErrorContains(t, err, "does not match request id")
+}
+
+// TestJSONRPCErrors tests that agent JSON-RPC errors are returned as
+// *types.JSONRPCErrorError and match the code sentinels
+func TestJSONRPCErrors(t *testing.T) {
+	codes := map[string]int{
+		types.A2AMethods.TasksSend:   types.JSONRPCErrorCodes.InvalidParams,
+		types.A2AMethods.TasksStatus: types.A2AErrorCodes.TaskNotFound,
+		types.A2AMethods.TasksCancel: types.A2AErrorCodes.TaskNotCancelable,
+	}
+	agent := newAgentServer(t, func(req *types.JSONRPCRequest) (interface{}, *types.JSONRPCError) {
+		code, ok := codes[req.Method]
+		if !ok {
+			code = types.JSONRPCErrorCodes.MethodNotFound
+		}
+		return nil, &types.JSONRPCError{Code: code, Message: "rejected", Data: map[string]interface{}{"method": req.Method}}
+	})
+
+	c := newTestClient(t, Config{BaseURL: agent.URL, Timeout: 5 * time.Second})
+	ctx := context.Background()
+
+	_, err := c.SendTask(ctx, "agent", types.NewTaskRequest(&types.Message{
+		Role:  "user",
+		Parts: []types.Part{{Type: "text", Text: "Hello"}},
+	}))
+	var rpcErr *types.JSONRPCErrorError
+	require.True(t, errors.As(err, &rpcErr), "got %v", err)
+	assert.Equal(t, types.JSONRPCErrorCodes.InvalidParams, rpcErr.Code())
+	assert.Equal(t, "rejected", rpcErr.Message())
+	assert.Equal(t, types.A2AMethods.TasksSend, rpcErr.Method)
+	assert.Equal(t, map[string]interface{}{"method": types.A2AMethods.TasksSend}, rpcErr.Data())
+	assert.True(t, errors.Is(err, types.ErrInvalidParams))
+	assert.False(t, errors.Is(err, types.ErrInternalError))
+
+	_, err = c.GetTaskStatus(ctx, "agent", "missing")
+	require.True(t, errors.As(err, &rpcErr), "got %v", err)
+	assert.True(t, errors.Is(err, ErrTaskNotFound))
+	assert.True(t, errors.Is(err, types.ErrTaskNotFound))
+
+	err = c.CancelTask(ctx, "agent", "done")
+	require.True(t, errors.As(err, &rpcErr), "got %v", err)
+	assert.True(t, errors.Is(err, types.ErrTaskNotCancelable))
+
+	_, err = c.Call(ctx, "agent", "tasks/unknown", nil)
+	require.NoError(t, err, "Call returns JSON-RPC errors in the envelope")
+
+	code, ok := types.JSONRPCErrorCode(fmt.Errorf("wrapped: %w", rpcErr))
+	assert.True(t, ok)
+	assert.Equal(t, types.A2AErrorCodes.TaskNotCancelable, code)
+	_, ok = types.JSONRPCErrorCode(errors.New("plain"))
+	assert.False(t, ok)
+}
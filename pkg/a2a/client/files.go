@@ -0,0 +1,139 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"mime"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/craine-io/openribcage/pkg/a2a/types"
+)
+
+// DefaultMaxFileSize is the largest attachment accepted when Config.MaxFileSize is unset
+const DefaultMaxFileSize = 10 << 20
+
+// MultiError collects independent errors so callers can see every failure at once
+type MultiError struct {
+	Errors []error
+}
+
+// Error implements the error interface
+func (m *MultiError) Error() string {
+	msgs := make([]string, len(m.Errors))
+	for i, err := range m.Errors {
+		msgs[i] = err.Error()
+	}
+	return fmt.Sprintf("%d errors occurred: %s", len(m.Errors), strings.Join(msgs, "; "))
+}
+
+// Unwrap returns the collected errors for use with errors.Is and errors.As
+func (m *MultiError) Unwrap() []error {
+	return m.Errors
+}
+
+// FileError reports why a single attachment was rejected
+type FileError struct {
+	Path string
+	Err  error
+}
+
+// Error implements the error interface
+func (e *FileError) Error() string {
+	return fmt.Sprintf("file %s: %v", e.Path, e.Err)
+}
+
+// Unwrap returns the underlying error
+func (e *FileError) Unwrap() error {
+	return e.Err
+}
+
+var (
+	// ErrFileTooLarge is returned for attachments exceeding the configured
+	// maximum size. It is types.ErrFileTooLarge, so one check covers both
+	// uploads and downloads.
+	ErrFileTooLarge = types.ErrFileTooLarge
+	// ErrMissingMimeType is returned when an attachment's MIME type cannot be determined
+	ErrMissingMimeType = errors.New("missing MIME type")
+)
+
+// SendTaskWithFiles sends a task with the given files attached as file parts.
+//
+// Every file is validated before anything is sent. If any file is too
+// large, unreadable, or has no detectable MIME type, a *MultiError holding
+// one *FileError per rejected file is returned and no request is made.
+func (c *Client) SendTaskWithFiles(ctx context.Context, agentID string, req *types.TaskRequest, paths []string) (*types.TaskResponse, error) {
+	parts, err := c.loadFileParts(paths)
+	if err != nil {
+		return nil, err
+	}
+
+	msg := &types.Message{Role: "user"}
+	if req.Message != nil {
+		msg.Role = req.Message.Role
+		msg.Parts = append(msg.Parts, req.Message.Parts...)
+	}
+	msg.Parts = append(msg.Parts, parts...)
+
+	return c.SendTask(ctx, agentID, &types.TaskRequest{ID: req.ID, Message: msg})
+}
+
+// loadFileParts validates and reads every file, collecting per-file errors
+func (c *Client) loadFileParts(paths []string) ([]types.Part, error) {
+	maxSize := c.config.MaxFileSize
+	if maxSize <= 0 {
+		maxSize = DefaultMaxFileSize
+	}
+
+	var errs []error
+	parts := make([]types.Part, 0, len(paths))
+	for _, path := range paths {
+		part, err := loadFilePart(path, maxSize)
+		if err != nil {
+			errs = append(errs, &FileError{Path: path, Err: err})
+			continue
+		}
+		parts = append(parts, part)
+	}
+
+	if len(errs) > 0 {
+		return nil, &MultiError{Errors: errs}
+	}
+	return parts, nil
+}
+
+// loadFilePart validates a single file and reads it into a file part
+func loadFilePart(path string, maxSize int64) (types.Part, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return types.Part{}, fmt.Errorf("unreadable: %w", err)
+	}
+	if info.IsDir() {
+		return types.Part{}, fmt.Errorf("unreadable: is a directory")
+	}
+	if info.Size() > maxSize {
+		return types.Part{}, fmt.Errorf("%w: %d bytes exceeds limit of %d", ErrFileTooLarge, info.Size(), maxSize)
+	}
+
+	mimeType, _, _ := mime.ParseMediaType(mime.TypeByExtension(filepath.Ext(path)))
+	if mimeType == "" {
+		return types.Part{}, ErrMissingMimeType
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return types.Part{}, fmt.Errorf("unreadable: %w", err)
+	}
+
+	return types.Part{
+		Type: "file",
+		File: &types.FilePart{
+			Name:     filepath.Base(path),
+			MimeType: mimeType,
+			Size:     int64(len(content)),
+			Content:  content,
+		},
+	}, nil
+}
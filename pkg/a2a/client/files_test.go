@@ -0,0 +1,89 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/craine-io/openribcage/pkg/a2a/types"
+)
+
+// writeFile creates a file of size bytes named name in dir
+func writeFile(t *testing.T, dir, name string, size int) string {
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(path, make([]byte, size), 0o644))
+	return path
+}
+
+// TestSendTaskWithFiles tests that valid files are appended to the message
+// as file parts after its own parts
+func TestSendTaskWithFiles(t *testing.T) {
+	var mu sync.Mutex
+	var sent types.TaskRequest
+	agent := newAgentServer(t, func(req *types.JSONRPCRequest) (interface{}, *types.JSONRPCError) {
+		data, _ := json.Marshal(req.Params)
+		mu.Lock()
+		defer mu.Unlock()
+		json.Unmarshal(data, &sent)
+		return taskResult(req)
+	})
+	c := newTestClient(t, Config{BaseURL: agent.URL, Timeout: 5 * time.Second})
+
+	dir := t.TempDir()
+	paths := []string{writeFile(t, dir, "report.json", 16), writeFile(t, dir, "notes.txt", 8)}
+	req := &types.TaskRequest{ID: "task-1", Message: &types.Message{Role: "user", Parts: []types.Part{{Type: "text", Text: "summarize"}}}}
+	_, err := c.SendTaskWithFiles(context.Background(), "", req, paths)
+	require.NoError(t, err)
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, "task-1", sent.ID)
+	require.Len(t, sent.Message.Parts, 3)
+	assert.Equal(t, "summarize", sent.Message.Parts[0].Text)
+	assert.Equal(t, &types.FilePart{Name: "report.json", MimeType: "application/json", Size: 16, Content: make([]byte, 16)}, sent.Message.Parts[1].File)
+	assert.Equal(t, "notes.txt", sent.Message.Parts[2].File.Name)
+	assert.Equal(t, "text/plain", sent.Message.Parts[2].File.MimeType)
+}
+
+// TestSendTaskWithFilesRejected tests that every invalid file is reported
+// together and that nothing is sent
+func TestSendTaskWithFilesRejected(t *testing.T) {
+	agent := newAgentServer(t, taskResult)
+	c := newTestClient(t, Config{BaseURL: agent.URL, Timeout: 5 * time.Second, MaxFileSize: 32})
+
+	dir := t.TempDir()
+	paths := []string{
+		writeFile(t, dir, "ok.txt", 8),
+		writeFile(t, dir, "large.txt", 64),
+		writeFile(t, dir, "unknown.nomime", 8),
+		filepath.Join(dir, "missing.txt"),
+		dir,
+	}
+	_, err := c.SendTaskWithFiles(context.Background(), "", &types.TaskRequest{ID: "task-1"}, paths)
+
+	var multi *MultiError
+	require.ErrorAs(t, err, &multi)
+	require.Len(t, multi.Errors, 4)
+	assert.ErrorIs(t, err, ErrFileTooLarge)
+	assert.ErrorIs(t, err, types.ErrFileTooLarge)
+	assert.ErrorIs(t, err, ErrMissingMimeType)
+
+	var fileErrs []string
+	for _, err := range multi.Errors {
+		var fileErr *FileError
+		require.True(t, errors.As(err, &fileErr))
+		fileErrs = append(fileErrs, fileErr.Path)
+	}
+	assert.Equal(t, paths[1:], fileErrs)
+	assert.ErrorContains(t, multi.Errors[2], "unreadable")
+	assert.ErrorContains(t, multi.Errors[3], "is a directory")
+	assert.Equal(t, 0, agent.requestCount())
+}
@@ -0,0 +1,43 @@
+package client
+
+import (
+	"context"
+	"net/http"
+)
+
+// headersKey is the context key for per-call headers
+type headersKey struct{}
+
+// WithHeaders returns a context carrying HTTP headers for the calls made
+// with it, e.g. correlation or tenant IDs. They are merged over
+// Config.Headers, winning on conflicts, without touching the shared
+// config. Nested calls merge too, with the innermost value winning.
+// Credentials are applied after these headers and cannot be overridden.
+func WithHeaders(ctx context.Context, headers map[string]string) context.Context {
+	merged := make(map[string]string, len(headers))
+	for k, v := range HeadersFromContext(ctx) {
+		merged[http.CanonicalHeaderKey(k)] = v
+	}
+	for k, v := range headers {
+		merged[http.CanonicalHeaderKey(k)] = v
+	}
+	return context.WithValue(ctx, headersKey{}, merged)
+}
+
+// HeadersFromContext returns the headers set with WithHeaders, or nil.
+// The returned map must not be modified.
+func HeadersFromContext(ctx context.Context) map[string]string {
+	headers, _ := ctx.Value(headersKey{}).(map[string]string)
+	return headers
+}
+
+// setHeaders applies Config.Headers and then any headers from the
+// request's context
+func (c *Client) setHeaders(req *http.Request) {
+	for k, v := range c.config.Headers {
+		req.Header.Set(k, v)
+	}
+	for k, v := range HeadersFromContext(req.Context()) {
+		req.Header.Set(k, v)
+	}
+}
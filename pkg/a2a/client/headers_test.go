@@ -0,0 +1,48 @@
+package client
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/craine-io/openribcage/pkg/a2a/types"
+)
+
+// TestWithHeaders tests merging per-call headers over the configured ones
+func TestWithHeaders(t *testing.T) {
+	ctx := WithHeaders(context.Background(), map[string]string{"x-tenant": "acme", "X-Trace": "outer"})
+	ctx = WithHeaders(ctx, map[string]string{"X-Trace": "inner"})
+
+	assert.Equal(t, map[string]string{"X-Tenant": "acme", "X-Trace": "inner"}, HeadersFromContext(ctx))
+	assert.Nil(t, HeadersFromContext(context.Background()))
+}
+
+// TestPerCallHeadersReachAgent tests that per-call headers, Accept
+// included, win over the configured and default headers on the wire
+func TestPerCallHeadersReachAgent(t *testing.T) {
+	server := newAgentServer(t, taskResult)
+	c := newTestClient(t, Config{
+		BaseURL:    server.URL,
+		Timeout:    5 * time.Second,
+		Headers:    map[string]string{"X-Tenant": "default", "X-Client": "openribcage"},
+		OutputMode: "text/markdown",
+	})
+
+	ctx := WithHeaders(context.Background(), map[string]string{"X-Tenant": "acme", "Accept": "application/json"})
+	_, err := c.GetTaskStatus(ctx, "", "task-1")
+	require.NoError(t, err)
+
+	header := server.lastHeader()
+	assert.Equal(t, "acme", header.Get("X-Tenant"))
+	assert.Equal(t, "openribcage", header.Get("X-Client"))
+	assert.Equal(t, "application/json", header.Get("Accept"), "a per-call Accept replaces the negotiated one")
+
+	// Streams keep a per-call Accept too
+	req, _, err := c.BuildRequest(WithHeaders(context.Background(), map[string]string{"Accept": "text/event-stream, application/json"}),
+		"", types.A2AMethods.TasksStream, map[string]interface{}{"id": "task-1"})
+	require.NoError(t, err)
+	assert.Equal(t, "text/event-stream, application/json", req.Header.Get("Accept"))
+}
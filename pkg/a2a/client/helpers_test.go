@@ -0,0 +1,87 @@
+package client
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/craine-io/openribcage/pkg/a2a/types"
+)
+
+// rpcHandler answers a decoded JSON-RPC request with a result or an error
+type rpcHandler func(req *types.JSONRPCRequest) (interface{}, *types.JSONRPCError)
+
+// agentServer is a fake A2A agent that answers unary JSON-RPC calls and
+// records the headers of every request it receives
+type agentServer struct {
+	*httptest.Server
+
+	mu      sync.Mutex
+	headers []http.Header
+	methods []string
+}
+
+// newAgentServer starts an agentServer that is closed when the test ends
+func newAgentServer(t *testing.T, handle rpcHandler) *agentServer {
+	t.Helper()
+	s := &agentServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req types.JSONRPCRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		s.mu.Lock()
+		s.headers = append(s.headers, r.Header.Clone())
+		s.methods = append(s.methods, req.Method)
+		s.mu.Unlock()
+
+		resp := types.JSONRPCResponse{JSONRPC: "2.0", ID: req.ID}
+		result, rpcErr := handle(&req)
+		if rpcErr != nil {
+			resp.Error = rpcErr
+		} else {
+			data, err := json.Marshal(result)
+			require.NoError(t, err)
+			resp.Result = data
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(resp)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// lastHeader returns the headers of the most recent request
+func (s *agentServer) lastHeader() http.Header {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.headers) == 0 {
+		return nil
+	}
+	return s.headers[len(s.headers)-1]
+}
+
+// requestCount returns how many requests the server has received
+func (s *agentServer) requestCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.methods)
+}
+
+// taskResult answers every call with a completed task
+func taskResult(req *types.JSONRPCRequest) (interface{}, *types.JSONRPCError) {
+	return map[string]string{"id": "task-1", "status": "completed"}, nil
+}
+
+// newTestClient creates a client for server that is closed when the test ends
+func newTestClient(t *testing.T, config Config) *Client {
+	t.Helper()
+	c := New(config)
+	t.Cleanup(func() { c.Close() })
+	return c
+}
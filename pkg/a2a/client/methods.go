@@ -0,0 +1,52 @@
+package client
+
+import "github.com/craine-io/openribcage/pkg/a2a/types"
+
+// MethodInfo describes how the client supports an A2A protocol method
+type MethodInfo struct {
+	Method      string `json:"method"`
+	Function    string `json:"function,omitempty"`
+	Streaming   bool   `json:"streaming"`
+	Implemented bool   `json:"implemented"`
+	Auth        bool   `json:"auth"`
+}
+
+// methodFunctions maps A2A methods to the Client functions that call them
+var methodFunctions = map[string]string{
+	types.A2AMethods.TasksSend:                "SendTask",
+	types.A2AMethods.TasksStream:              "StreamTask",
+	types.A2AMethods.TasksStatus:              "GetTaskStatus",
+	types.A2AMethods.TasksCancel:              "CancelTask",
+	types.A2AMethods.MessageSend:              "SendMessage",
+	types.A2AMethods.MessageStream:            "StreamMessage",
+	types.A2AMethods.TasksPushNotificationSet: "SetTaskPushNotification",
+	types.A2AMethods.TasksPushNotificationGet: "GetTaskPushNotification",
+	types.A2AMethods.TasksResubscribe:         "ResubscribeTask",
+}
+
+// streamingMethods are the A2A methods answered with an SSE stream
+var streamingMethods = map[string]bool{
+	types.A2AMethods.TasksStream:      true,
+	types.A2AMethods.MessageStream:    true,
+	types.A2AMethods.TasksResubscribe: true,
+}
+
+// Methods describes every method declared in types.A2AMethods and whether
+// this client implements it. All implemented methods send configured
+// credentials.
+func Methods() []MethodInfo {
+	all := types.AllA2AMethods()
+
+	infos := make([]MethodInfo, 0, len(all))
+	for _, method := range all {
+		function, implemented := methodFunctions[method]
+		infos = append(infos, MethodInfo{
+			Method:      method,
+			Function:    function,
+			Streaming:   streamingMethods[method],
+			Implemented: implemented,
+			Auth:        implemented,
+		})
+	}
+	return infos
+}
@@ -0,0 +1,38 @@
+package client
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/craine-io/openribcage/pkg/a2a/types"
+)
+
+// TestMethods tests that every declared A2A method is listed in order and
+// that each implemented method names a real Client function
+func TestMethods(t *testing.T) {
+	infos := Methods()
+
+	var methods []string
+	for _, info := range infos {
+		methods = append(methods, info.Method)
+		if !info.Implemented {
+			assert.Empty(t, info.Function, info.Method)
+			assert.False(t, info.Auth, info.Method)
+			continue
+		}
+		_, ok := reflect.TypeOf(&Client{}).MethodByName(info.Function)
+		assert.True(t, ok, "%s: Client has no function %s", info.Method, info.Function)
+		assert.True(t, info.Auth, info.Method)
+	}
+	assert.Equal(t, types.AllA2AMethods(), methods)
+
+	streaming := map[string]bool{}
+	for _, info := range infos {
+		streaming[info.Method] = info.Streaming
+	}
+	assert.True(t, streaming[types.A2AMethods.TasksStream])
+	assert.True(t, streaming[types.A2AMethods.TasksResubscribe])
+	assert.False(t, streaming[types.A2AMethods.TasksSend])
+}
@@ -0,0 +1,91 @@
+package client
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/craine-io/openribcage/pkg/a2a/types"
+)
+
+// Middleware observes and mutates the client's A2A traffic, e.g. for
+// custom auth, tracing or payload redaction. Any hook may be nil.
+//
+// Hooks of every registered Middleware run in registration order:
+//
+//   - Request runs just before a request is sent, after the client has set
+//     its headers and credentials, so it sees and may change the final
+//     request.
+//   - Response runs when the response headers arrive, before the body is
+//     read. A hook that reads the body must replace it.
+//   - Envelope runs on each decoded JSON-RPC response envelope of unary
+//     and batch calls, before the client checks it. Streamed events are
+//     not envelopes and do not reach it.
+//
+// A hook returning an error short-circuits the chain: later hooks are
+// skipped, no request is sent (or the response is discarded) and the call
+// fails with that error. Request and Response hooks apply to every method
+// that contacts an agent, including Ping and Warmup.
+type Middleware struct {
+	Request  func(req *http.Request) error
+	Response func(resp *http.Response) error
+	Envelope func(method string, resp *types.JSONRPCResponse) error
+}
+
+// Use appends m to the client's middleware chain. It is safe to call
+// concurrently with requests; in-flight requests keep the chain they
+// started with.
+func (c *Client) Use(m Middleware) {
+	c.middlewareMu.Lock()
+	defer c.middlewareMu.Unlock()
+
+	chain := make([]Middleware, len(c.middleware), len(c.middleware)+1)
+	copy(chain, c.middleware)
+	c.middleware = append(chain, m)
+}
+
+// chain returns the current middleware chain
+func (c *Client) chain() []Middleware {
+	c.middlewareMu.RLock()
+	defer c.middlewareMu.RUnlock()
+	return c.middleware
+}
+
+// runRequestHooks passes req through every Request hook
+func (c *Client) runRequestHooks(req *http.Request) error {
+	for _, m := range c.chain() {
+		if m.Request == nil {
+			continue
+		}
+		if err := m.Request(req); err != nil {
+			return fmt.Errorf("request middleware: %w", err)
+		}
+	}
+	return nil
+}
+
+// runResponseHooks passes resp through every Response hook
+func (c *Client) runResponseHooks(resp *http.Response) error {
+	for _, m := range c.chain() {
+		if m.Response == nil {
+			continue
+		}
+		if err := m.Response(resp); err != nil {
+			return fmt.Errorf("response middleware: %w", err)
+		}
+	}
+	return nil
+}
+
+// runEnvelopeHooks passes a decoded JSON-RPC response through every
+// Envelope hook
+func (c *Client) runEnvelopeHooks(method string, resp *types.JSONRPCResponse) error {
+	for _, m := range c.chain() {
+		if m.Envelope == nil {
+			continue
+		}
+		if err := m.Envelope(method, resp); err != nil {
+			return fmt.Errorf("envelope middleware: %w", err)
+		}
+	}
+	return nil
+}
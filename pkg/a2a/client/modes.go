@@ -0,0 +1,44 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/craine-io/openribcage/pkg/a2a/types"
+)
+
+// ErrModeUnsupported is returned by NegotiateModes when the agent does not
+// support a desired mode. The agent's default mode is returned alongside
+// it, so callers may treat it as a warning.
+var ErrModeUnsupported = errors.New("mode not supported by agent")
+
+// NegotiateModes picks the input and output MIME types to use with the
+// agent described by card. Each desired mode is kept when one of the
+// card's default modes accepts it, wildcards such as "text/*" included.
+// Otherwise the agent's first default mode is returned together with an
+// error wrapping ErrModeUnsupported. An empty desired mode selects the
+// agent's default, and a card that lists no modes accepts any.
+func NegotiateModes(card *types.AgentCard, desiredInput, desiredOutput string) (in, out string, err error) {
+	in, inErr := negotiateMode("input", card.DefaultInputModes, desiredInput)
+	out, outErr := negotiateMode("output", card.DefaultOutputModes, desiredOutput)
+	return in, out, errors.Join(inErr, outErr)
+}
+
+// negotiateMode picks desired if supported lists it, or else the first
+// supported mode
+func negotiateMode(kind string, supported []string, desired string) (string, error) {
+	if len(supported) == 0 {
+		return desired, nil
+	}
+	if desired == "" {
+		return supported[0], nil
+	}
+	for _, mode := range supported {
+		if types.MatchMode(mode, desired) {
+			return desired, nil
+		}
+	}
+	return supported[0], fmt.Errorf("%w: %s mode %q not in %s, using %q",
+		ErrModeUnsupported, kind, desired, strings.Join(supported, ", "), supported[0])
+}
@@ -0,0 +1,65 @@
+package client
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/craine-io/openribcage/pkg/a2a/types"
+)
+
+// TestNegotiateModes tests picking input and output modes from a card
+func TestNegotiateModes(t *testing.T) {
+	card := &types.AgentCard{
+		DefaultInputModes:  []string{"text/plain", "application/json"},
+		DefaultOutputModes: []string{"text/*"},
+	}
+
+	in, out, err := NegotiateModes(card, "application/json", "text/markdown")
+	require.NoError(t, err)
+	assert.Equal(t, "application/json", in)
+	assert.Equal(t, "text/markdown", out)
+
+	in, out, err = NegotiateModes(card, "", "")
+	require.NoError(t, err)
+	assert.Equal(t, "text/plain", in)
+	assert.Equal(t, "text/*", out)
+
+	in, _, err = NegotiateModes(card, "image/png", "")
+	assert.ErrorIs(t, err, ErrModeUnsupported)
+	assert.Equal(t, "text/plain", in, "the agent's default is returned with the error")
+
+	in, out, err = NegotiateModes(&types.AgentCard{}, "image/png", "audio/wav")
+	require.NoError(t, err, "a card without modes accepts any")
+	assert.Equal(t, "image/png", in)
+	assert.Equal(t, "audio/wav", out)
+}
+
+// TestOutputModeAccept tests that the negotiated output mode reaches the
+// agent in the Accept header of unary calls, and that dry runs show the
+// same header
+func TestOutputModeAccept(t *testing.T) {
+	server := newAgentServer(t, taskResult)
+	ctx := context.Background()
+
+	c := newTestClient(t, Config{BaseURL: server.URL, Timeout: 5 * time.Second, OutputMode: "text/markdown"})
+	_, err := c.GetTaskStatus(ctx, "", "task-1")
+	require.NoError(t, err)
+	assert.Equal(t, "application/json, text/markdown", server.lastHeader().Get("Accept"))
+
+	req, _, err := c.BuildRequest(ctx, "", types.A2AMethods.TasksStatus, map[string]interface{}{"id": "task-1"})
+	require.NoError(t, err)
+	assert.Equal(t, "application/json, text/markdown", req.Header.Get("Accept"))
+
+	req, _, err = c.BuildRequest(ctx, "", types.A2AMethods.TasksStream, map[string]interface{}{"id": "task-1"})
+	require.NoError(t, err)
+	assert.Equal(t, "text/event-stream", req.Header.Get("Accept"), "streams always ask for SSE")
+
+	plain := newTestClient(t, Config{BaseURL: server.URL, Timeout: 5 * time.Second})
+	_, err = plain.GetTaskStatus(ctx, "", "task-1")
+	require.NoError(t, err)
+	assert.Equal(t, "application/json", server.lastHeader().Get("Accept"))
+}
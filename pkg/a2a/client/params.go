@@ -0,0 +1,56 @@
+package client
+
+import (
+	"fmt"
+
+	"github.com/craine-io/openribcage/pkg/a2a/types"
+)
+
+// PositionalOrder gives the order of named parameters when an A2A method's
+// params are sent as a JSON-RPC positional array
+var PositionalOrder = map[string][]string{
+	types.A2AMethods.TasksSend:                {"id", "message"},
+	types.A2AMethods.TasksStream:              {"id", "message"},
+	types.A2AMethods.TasksStatus:              {"id"},
+	types.A2AMethods.TasksCancel:              {"id"},
+	types.A2AMethods.MessageSend:              {"message"},
+	types.A2AMethods.MessageStream:            {"message"},
+	types.A2AMethods.TasksPushNotificationSet: {"id", "pushNotificationConfig"},
+	types.A2AMethods.TasksPushNotificationGet: {"id"},
+	types.A2AMethods.TasksResubscribe:         {"id"},
+}
+
+// positional reports whether params for method are sent as an array
+func (c *Client) positional(method string) bool {
+	for _, m := range c.config.PositionalParams {
+		if m == method {
+			return true
+		}
+	}
+	return false
+}
+
+// encodeParams converts named params to a positional array when the client
+// is configured to do so for method. Params that are not a name/value map
+// are passed through unchanged.
+func (c *Client) encodeParams(method string, params interface{}) (interface{}, error) {
+	if !c.positional(method) {
+		return params, nil
+	}
+
+	named, ok := params.(map[string]interface{})
+	if !ok {
+		return params, nil
+	}
+
+	order, ok := PositionalOrder[method]
+	if !ok {
+		return nil, fmt.Errorf("no positional parameter order known for method %s", method)
+	}
+
+	args := make([]interface{}, len(order))
+	for i, name := range order {
+		args[i] = named[name]
+	}
+	return args, nil
+}
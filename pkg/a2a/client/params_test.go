@@ -0,0 +1,66 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/craine-io/openribcage/pkg/a2a/types"
+)
+
+// TestPositionalParams tests that only the configured methods send their
+// params as an array in PositionalOrder
+func TestPositionalParams(t *testing.T) {
+	var mu sync.Mutex
+	params := map[string]string{}
+	agent := newAgentServer(t, func(req *types.JSONRPCRequest) (interface{}, *types.JSONRPCError) {
+		data, _ := json.Marshal(req.Params)
+		mu.Lock()
+		params[req.Method] = string(data)
+		mu.Unlock()
+		return taskResult(req)
+	})
+	c := newTestClient(t, Config{
+		BaseURL:          agent.URL,
+		Timeout:          5 * time.Second,
+		PositionalParams: []string{types.A2AMethods.TasksStatus},
+	})
+	ctx := context.Background()
+
+	_, err := c.GetTaskStatus(ctx, "", "task-1")
+	require.NoError(t, err)
+	require.NoError(t, c.CancelTask(ctx, "", "task-1"))
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.JSONEq(t, `["task-1"]`, params[types.A2AMethods.TasksStatus])
+	assert.JSONEq(t, `{"id":"task-1"}`, params[types.A2AMethods.TasksCancel])
+}
+
+// TestEncodeParams tests the ordering of positional params and the
+// handling of params that cannot be converted
+func TestEncodeParams(t *testing.T) {
+	c := &Client{config: Config{PositionalParams: []string{types.A2AMethods.TasksSend, "custom/method"}}}
+
+	args, err := c.encodeParams(types.A2AMethods.TasksSend, map[string]interface{}{"message": "hi", "id": "task-1"})
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{"task-1", "hi"}, args)
+
+	// Missing names are sent as null to keep positions stable
+	args, err = c.encodeParams(types.A2AMethods.TasksSend, map[string]interface{}{"message": "hi"})
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{nil, "hi"}, args)
+
+	raw := []string{"already", "positional"}
+	args, err = c.encodeParams(types.A2AMethods.TasksSend, raw)
+	require.NoError(t, err)
+	assert.Equal(t, raw, args)
+
+	_, err = c.encodeParams("custom/method", map[string]interface{}{"id": "task-1"})
+	assert.ErrorContains(t, err, "no positional parameter order known for method custom/method")
+}
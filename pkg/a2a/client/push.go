@@ -0,0 +1,45 @@
+package client
+
+import (
+	"context"
+	"errors"
+
+	"github.com/craine-io/openribcage/pkg/a2a/types"
+)
+
+// SetTaskPushNotification registers a webhook the agent calls with updates
+// to a task and returns the config the agent stored. The config is
+// validated before it is sent; agents without push notification support
+// answer with an error matching types.ErrPushNotificationNotSupported.
+func (c *Client) SetTaskPushNotification(ctx context.Context, agentID, taskID string, cfg *types.PushNotificationConfig) (*types.TaskPushNotificationConfig, error) {
+	if cfg == nil {
+		return nil, errors.New("push notification config is required")
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	params := map[string]interface{}{
+		"id":                     taskID,
+		"pushNotificationConfig": cfg,
+	}
+
+	var resp types.TaskPushNotificationConfig
+	if err := c.call(ctx, agentID, types.A2AMethods.TasksPushNotificationSet, params, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetTaskPushNotification returns the webhook registered for a task
+func (c *Client) GetTaskPushNotification(ctx context.Context, agentID, taskID string) (*types.TaskPushNotificationConfig, error) {
+	params := map[string]interface{}{
+		"id": taskID,
+	}
+
+	var resp types.TaskPushNotificationConfig
+	if err := c.call(ctx, agentID, types.A2AMethods.TasksPushNotificationGet, params, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
@@ -0,0 +1,69 @@
+package client
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/craine-io/openribcage/pkg/a2a/types"
+)
+
+// TestPushNotificationConfig tests registering and reading back a task's
+// webhook
+func TestPushNotificationConfig(t *testing.T) {
+	var mu sync.Mutex
+	stored := map[string]interface{}{}
+	agent := newAgentServer(t, func(req *types.JSONRPCRequest) (interface{}, *types.JSONRPCError) {
+		id, _ := req.Params.(map[string]interface{})["id"].(string)
+		mu.Lock()
+		defer mu.Unlock()
+		switch req.Method {
+		case types.A2AMethods.TasksPushNotificationSet:
+			stored[id] = req.Params
+			return req.Params, nil
+		case types.A2AMethods.TasksPushNotificationGet:
+			if result, ok := stored[id]; ok {
+				return result, nil
+			}
+			return nil, &types.JSONRPCError{Code: types.A2AErrorCodes.TaskNotFound, Message: "no config"}
+		default:
+			return nil, &types.JSONRPCError{Code: types.JSONRPCErrorCodes.MethodNotFound, Message: "method not found"}
+		}
+	})
+	c := newTestClient(t, Config{BaseURL: agent.URL, Timeout: 5 * time.Second})
+	ctx := context.Background()
+
+	webhook := &types.PushNotificationConfig{
+		URL:            "https://hooks.example.com/a2a",
+		Token:          "task-token",
+		Authentication: &types.PushNotificationAuthentication{Schemes: []string{"Bearer"}},
+	}
+	set, err := c.SetTaskPushNotification(ctx, "", "task-1", webhook)
+	require.NoError(t, err)
+	assert.Equal(t, "task-1", set.ID)
+	assert.Equal(t, *webhook, set.PushNotificationConfig)
+
+	got, err := c.GetTaskPushNotification(ctx, "", "task-1")
+	require.NoError(t, err)
+	assert.Equal(t, *webhook, got.PushNotificationConfig)
+
+	_, err = c.GetTaskPushNotification(ctx, "", "task-2")
+	assert.ErrorIs(t, err, types.ErrTaskNotFound)
+
+	for _, bad := range []*types.PushNotificationConfig{
+		nil,
+		{},
+		{URL: "ftp://hooks.example.com/a2a"},
+		{URL: "hooks.example.com/a2a"},
+		{URL: "https:///a2a"},
+		{URL: "https://hooks.example.com/a2a", Authentication: &types.PushNotificationAuthentication{}},
+	} {
+		_, err := c.SetTaskPushNotification(ctx, "", "task-3", bad)
+		assert.Error(t, err, "config %+v", bad)
+	}
+	assert.Equal(t, 3, agent.requestCount(), "invalid configs must not be sent")
+}
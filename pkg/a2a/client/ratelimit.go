@@ -0,0 +1,94 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrRateLimited is returned when a request cannot acquire a rate limit
+// token before its context is done
+var ErrRateLimited = errors.New("rate limit exceeded")
+
+// rateLimiters holds a token bucket per agent
+type rateLimiters struct {
+	mu      sync.Mutex
+	buckets map[string]*tokenBucket
+}
+
+// tokenBucket refills at rate tokens per second up to burst. Tokens may go
+// negative: each waiter reserves its token up front and sleeps until the
+// bucket has refilled past it.
+type tokenBucket struct {
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// bucket returns the token bucket for key, creating a full one if needed
+func (l *rateLimiters) bucket(key string, rate float64, burst int) *tokenBucket {
+	if l.buckets == nil {
+		l.buckets = make(map[string]*tokenBucket)
+	}
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &tokenBucket{rate: rate, burst: float64(burst), tokens: float64(burst), last: time.Now()}
+		l.buckets[key] = b
+	}
+	return b
+}
+
+// wait blocks until a token for key is available. It fails fast with
+// ErrRateLimited when ctx would expire before then, and releases the
+// reserved token if ctx is done while waiting.
+func (l *rateLimiters) wait(ctx context.Context, key string, rate float64, burst int) error {
+	l.mu.Lock()
+	b := l.bucket(key, rate, burst)
+	now := time.Now()
+	b.tokens += now.Sub(b.last).Seconds() * b.rate
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+	b.last = now
+
+	var delay time.Duration
+	if b.tokens < 1 {
+		delay = time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+	}
+	if deadline, ok := ctx.Deadline(); ok && now.Add(delay).After(deadline) {
+		l.mu.Unlock()
+		return fmt.Errorf("%w for agent %q: next request allowed in %s, after the context deadline", ErrRateLimited, key, delay.Round(time.Millisecond))
+	}
+	b.tokens--
+	l.mu.Unlock()
+
+	if delay == 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		l.mu.Lock()
+		b.tokens++
+		l.mu.Unlock()
+		return fmt.Errorf("%w for agent %q: %w", ErrRateLimited, key, ctx.Err())
+	}
+}
+
+// waitRateLimit waits for the agent's rate limit when Config.RateLimit is set
+func (c *Client) waitRateLimit(ctx context.Context, agentID string) error {
+	if c.config.RateLimit <= 0 {
+		return nil
+	}
+	burst := c.config.RateBurst
+	if burst < 1 {
+		burst = 1
+	}
+	return c.limits.wait(ctx, c.agentLabel(agentID), c.config.RateLimit, burst)
+}
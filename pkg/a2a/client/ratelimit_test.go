@@ -0,0 +1,69 @@
+package client
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRateLimiters tests the per-agent token buckets
+func TestRateLimiters(t *testing.T) {
+	var l rateLimiters
+	ctx := context.Background()
+
+	start := time.Now()
+	require.NoError(t, l.wait(ctx, "k8s", 10, 2))
+	require.NoError(t, l.wait(ctx, "k8s", 10, 2))
+	require.NoError(t, l.wait(ctx, "helm", 10, 2))
+	assert.Less(t, time.Since(start), 50*time.Millisecond)
+
+	// The burst is spent, so the next k8s token takes about 100ms
+	require.NoError(t, l.wait(ctx, "k8s", 10, 2))
+	assert.GreaterOrEqual(t, time.Since(start), 80*time.Millisecond)
+}
+
+// TestRateLimitersContext tests failing fast on short deadlines and
+// releasing the reserved token on cancellation
+func TestRateLimitersContext(t *testing.T) {
+	var l rateLimiters
+	require.NoError(t, l.wait(context.Background(), "k8s", 1, 1))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	err := l.wait(ctx, "k8s", 1, 1)
+	assert.ErrorIs(t, err, ErrRateLimited)
+	assert.ErrorContains(t, err, "after the context deadline")
+	assert.NoError(t, ctx.Err())
+
+	ctx, cancel = context.WithCancel(context.Background())
+	time.AfterFunc(10*time.Millisecond, cancel)
+	err = l.wait(ctx, "k8s", 1, 1)
+	assert.ErrorIs(t, err, ErrRateLimited)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	assert.Greater(t, l.buckets["k8s"].tokens, -0.5)
+}
+
+// TestRateLimit tests that the client spaces requests to an agent
+func TestRateLimit(t *testing.T) {
+	agent := newAgentServer(t, taskResult)
+	c := newTestClient(t, Config{BaseURL: agent.URL, Timeout: 5 * time.Second, RateLimit: 20})
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		_, err := c.GetTaskStatus(context.Background(), "", "task-1")
+		require.NoError(t, err)
+	}
+	assert.GreaterOrEqual(t, time.Since(start), 90*time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := c.GetTaskStatus(ctx, "", "task-1")
+	assert.ErrorIs(t, err, ErrRateLimited)
+	assert.Equal(t, 3, agent.requestCount())
+}
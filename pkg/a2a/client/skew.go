@@ -0,0 +1,110 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/craine-io/openribcage/internal/auth"
+)
+
+// PingResult reports the outcome of a Ping
+type PingResult struct {
+	URL        string        `json:"url"`
+	StatusCode int           `json:"status_code"`
+	Latency    time.Duration `json:"latency"`
+
+	// ServerTime is the agent's Date header, zero if the agent sent none
+	ServerTime time.Time `json:"server_time,omitempty"`
+
+	// ClockSkew is the agent clock minus the local clock, estimated at the
+	// midpoint of the exchange. It is only meaningful when ServerTime is set.
+	ClockSkew time.Duration `json:"clock_skew"`
+
+	// SkewExceeded is set when ClockSkew is beyond Config.MaxClockSkew
+	SkewExceeded bool `json:"skew_exceeded"`
+}
+
+// Ping tests connectivity to an A2A agent with a GET request and reports
+// latency and clock skew. Any HTTP response counts as reachable.
+func (c *Client) Ping(ctx context.Context, agentURL string) (*PingResult, error) {
+	if err := c.checkOpen(); err != nil {
+		return nil, err
+	}
+	if err := c.hosts.CheckURL(agentURL); err != nil {
+		return nil, err
+	}
+	ctx, cancel := c.WithDefaultTimeout(ctx)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", agentURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	c.setHeaders(req)
+	if err := c.auth.AddAuthHeaders(req, c.config.Credentials); err != nil {
+		return nil, fmt.Errorf("failed to add auth headers: %w", err)
+	}
+	if err := c.dryRun(req, nil); err != nil {
+		return nil, err
+	}
+
+	if err := c.runRequestHooks(req); err != nil {
+		return nil, err
+	}
+	start := time.Now()
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("ping failed: %w", auth.RedactError(err, auth.RedactParam(c.config.Credentials)))
+	}
+	resp.Body.Close()
+	if err := c.runResponseHooks(resp); err != nil {
+		return nil, err
+	}
+	latency := time.Since(start)
+
+	result := &PingResult{
+		URL:        agentURL,
+		StatusCode: resp.StatusCode,
+		Latency:    latency,
+	}
+	if skew, serverTime, ok := clockSkew(resp, start, latency); ok {
+		result.ServerTime = serverTime
+		result.ClockSkew = skew
+		result.SkewExceeded = c.checkSkew(agentURL, skew)
+	}
+	return result, nil
+}
+
+// clockSkew estimates the difference between the server's Date header and
+// the local clock at the midpoint of a request that started at start and
+// took latency. ok is false if the response has no valid Date header.
+func clockSkew(resp *http.Response, start time.Time, latency time.Duration) (skew time.Duration, serverTime time.Time, ok bool) {
+	date := resp.Header.Get("Date")
+	if date == "" {
+		return 0, time.Time{}, false
+	}
+	serverTime, err := http.ParseTime(date)
+	if err != nil {
+		return 0, time.Time{}, false
+	}
+	return serverTime.Sub(start.Add(latency / 2)), serverTime, true
+}
+
+// checkSkew logs a warning and returns true when skew exceeds the
+// configured MaxClockSkew. Detection is disabled when MaxClockSkew is zero.
+func (c *Client) checkSkew(url string, skew time.Duration) bool {
+	if c.config.MaxClockSkew <= 0 {
+		return false
+	}
+	if skew < 0 {
+		skew = -skew
+	}
+	if skew <= c.config.MaxClockSkew {
+		return false
+	}
+	c.logger.Warnf("Clock skew of %s detected for %s (threshold %s)",
+		skew.Round(time.Second), auth.RedactURL(url, auth.RedactParam(c.config.Credentials)), c.config.MaxClockSkew)
+	return true
+}
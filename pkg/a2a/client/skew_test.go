@@ -0,0 +1,81 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newDateServer starts an agent whose Date header is offset from the
+// local clock, or omitted when omit is set
+func newDateServer(t *testing.T, offset time.Duration, omit bool) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if omit {
+			w.Header()["Date"] = nil
+		} else {
+			w.Header().Set("Date", time.Now().Add(offset).UTC().Format(http.TimeFormat))
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// TestPingClockSkew tests that Ping reports the agent's clock skew and
+// flags it beyond MaxClockSkew
+func TestPingClockSkew(t *testing.T) {
+	ahead := newDateServer(t, time.Hour, false)
+
+	c := newTestClient(t, Config{Timeout: 5 * time.Second, MaxClockSkew: time.Minute})
+	result, err := c.Ping(context.Background(), ahead.URL)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, result.StatusCode)
+	assert.InDelta(t, time.Hour.Seconds(), result.ClockSkew.Seconds(), 2)
+	assert.False(t, result.ServerTime.IsZero())
+	assert.True(t, result.SkewExceeded)
+
+	behind := newDateServer(t, -time.Hour, false)
+	result, err = c.Ping(context.Background(), behind.URL)
+	require.NoError(t, err)
+	assert.InDelta(t, -time.Hour.Seconds(), result.ClockSkew.Seconds(), 2)
+	assert.True(t, result.SkewExceeded)
+
+	// Detection is disabled without a threshold
+	c = newTestClient(t, Config{Timeout: 5 * time.Second})
+	result, err = c.Ping(context.Background(), ahead.URL)
+	require.NoError(t, err)
+	assert.False(t, result.SkewExceeded)
+}
+
+// TestPingWithoutDate tests that an agent without a Date header reports no skew
+func TestPingWithoutDate(t *testing.T) {
+	server := newDateServer(t, 0, true)
+	c := newTestClient(t, Config{Timeout: 5 * time.Second, MaxClockSkew: time.Nanosecond})
+
+	result, err := c.Ping(context.Background(), server.URL)
+	require.NoError(t, err)
+	assert.True(t, result.ServerTime.IsZero())
+	assert.Zero(t, result.ClockSkew)
+	assert.False(t, result.SkewExceeded)
+}
+
+// TestClockSkew tests estimating skew at the midpoint of an exchange
+func TestClockSkew(t *testing.T) {
+	start := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	resp := &http.Response{Header: http.Header{}}
+
+	resp.Header.Set("Date", start.Add(10*time.Second).Format(http.TimeFormat))
+	skew, serverTime, ok := clockSkew(resp, start, 2*time.Second)
+	assert.True(t, ok)
+	assert.Equal(t, 9*time.Second, skew)
+	assert.Equal(t, start.Add(10*time.Second), serverTime)
+
+	resp.Header.Set("Date", "yesterday")
+	_, _, ok = clockSkew(resp, start, 0)
+	assert.False(t, ok)
+}
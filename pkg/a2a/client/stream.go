@@ -0,0 +1,67 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/craine-io/openribcage/pkg/a2a/streaming"
+	"github.com/craine-io/openribcage/pkg/a2a/types"
+)
+
+// maxErrorBody limits how much of an error response body is read
+const maxErrorBody = 4 << 10
+
+// streamStatusError describes a non-200 streaming response, including the
+// agent's JSON-RPC error or the start of the response body
+func streamStatusError(resp *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+
+	var rpcResp types.JSONRPCResponse
+	if json.Unmarshal(body, &rpcResp) == nil && rpcResp.Error != nil {
+		return fmt.Errorf("unexpected status: %s: %w", resp.Status, types.NewJSONRPCErrorError("", rpcResp.Error))
+	}
+	if text := strings.TrimSpace(string(body)); text != "" {
+		return fmt.Errorf("unexpected status: %s: %s", resp.Status, text)
+	}
+	return fmt.Errorf("unexpected status: %s", resp.Status)
+}
+
+// isJSONResponse reports whether an agent answered a streaming request
+// with a single JSON document rather than an event stream
+func isJSONResponse(resp *http.Response) bool {
+	mediaType, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	return err == nil && (mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"))
+}
+
+// decodeJSONStream decodes a single JSON-RPC response sent in place of an
+// event stream. A JSON-RPC error is returned as a *streaming.RPCError; a
+// result is returned as the stream's only, final event.
+func decodeJSONStream(resp *http.Response, method string, maxSize int, strict bool) (*types.StreamResponse, error) {
+	if maxSize <= 0 {
+		maxSize = streaming.DefaultMaxLineSize
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, int64(maxSize)+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if len(body) > maxSize {
+		return nil, &streaming.LineTooLongError{Limit: maxSize}
+	}
+	if len(strings.TrimSpace(string(body))) == 0 {
+		return nil, ErrEmptyResponse
+	}
+
+	if err := checkEventVersion(method, body, strict); err != nil {
+		return nil, err
+	}
+	event, err := streaming.DecodeEvent("", body)
+	if err != nil {
+		return nil, err
+	}
+	event.Done = true
+	return event, nil
+}
@@ -0,0 +1,141 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/craine-io/openribcage/pkg/a2a/streaming"
+	"github.com/craine-io/openribcage/pkg/a2a/types"
+)
+
+// newStaticServer starts an agent that answers every request with status,
+// contentType and body
+func newStaticServer(t *testing.T, status int, contentType, body string) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if contentType != "" {
+			w.Header().Set("Content-Type", contentType)
+		}
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// TestStreamStatusError tests that failed stream requests report the
+// agent's JSON-RPC error or response body
+func TestStreamStatusError(t *testing.T) {
+	msg := &types.Message{Role: "user", Parts: []types.Part{{Type: "text", Text: "hello"}}}
+
+	server := newStaticServer(t, http.StatusBadRequest, "application/json", `{"jsonrpc":"2.0","id":null,"error":{"code":-32602,"message":"invalid params"}}`)
+	c := newTestClient(t, Config{BaseURL: server.URL, Timeout: 5 * time.Second})
+	_, err := drainStream(c.StreamMessage(context.Background(), "", msg))
+	var rpcErr *types.JSONRPCErrorError
+	require.ErrorAs(t, err, &rpcErr)
+	assert.Equal(t, -32602, rpcErr.Code())
+	assert.ErrorContains(t, err, "unexpected status: 400 Bad Request")
+
+	server = newStaticServer(t, http.StatusBadGateway, "text/plain", "upstream down\n")
+	c = newTestClient(t, Config{BaseURL: server.URL, Timeout: 5 * time.Second})
+	_, err = drainStream(c.StreamMessage(context.Background(), "", msg))
+	assert.EqualError(t, err, "unexpected status: 502 Bad Gateway: upstream down")
+
+	server = newStaticServer(t, http.StatusServiceUnavailable, "", "")
+	c = newTestClient(t, Config{BaseURL: server.URL, Timeout: 5 * time.Second})
+	_, err = drainStream(c.StreamMessage(context.Background(), "", msg))
+	assert.EqualError(t, err, "unexpected status: 503 Service Unavailable")
+}
+
+// TestStreamJSONResponse tests agents that answer a stream request with
+// a single JSON-RPC response
+func TestStreamJSONResponse(t *testing.T) {
+	msg := &types.Message{Role: "user", Parts: []types.Part{{Type: "text", Text: "hello"}}}
+
+	server := newStaticServer(t, http.StatusOK, "application/json; charset=utf-8", `{"jsonrpc":"2.0","id":"1","result":{"type":"status","data":"completed"}}`)
+	c := newTestClient(t, Config{BaseURL: server.URL, Timeout: 5 * time.Second})
+	events, err := drainStream(c.StreamMessage(context.Background(), "", msg))
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, "completed", events[0].Data)
+	assert.True(t, events[0].Done)
+
+	server = newStaticServer(t, http.StatusOK, "application/vnd.a2a+json", `{"jsonrpc":"2.0","id":"1","error":{"code":-32001,"message":"task not found"}}`)
+	c = newTestClient(t, Config{BaseURL: server.URL, Timeout: 5 * time.Second})
+	events, err = drainStream(c.StreamMessage(context.Background(), "", msg))
+	assert.Empty(t, events)
+	var rpcErr *streaming.RPCError
+	require.ErrorAs(t, err, &rpcErr)
+	assert.Equal(t, -32001, rpcErr.Code)
+
+	server = newStaticServer(t, http.StatusOK, "application/json", " \n")
+	c = newTestClient(t, Config{BaseURL: server.URL, Timeout: 5 * time.Second})
+	_, err = drainStream(c.StreamMessage(context.Background(), "", msg))
+	assert.ErrorIs(t, err, ErrEmptyResponse)
+
+	server = newStaticServer(t, http.StatusOK, "application/json", `{"jsonrpc":"2.0","id":"1","result":{"type":"status","data":"completed"}}`)
+	c = newTestClient(t, Config{BaseURL: server.URL, Timeout: 5 * time.Second, MaxStreamLineSize: 16})
+	_, err = drainStream(c.StreamMessage(context.Background(), "", msg))
+	var tooLong *streaming.LineTooLongError
+	assert.ErrorAs(t, err, &tooLong)
+}
+
+// TestStreamUnknownEvents tests the client's unknown event policy
+func TestStreamUnknownEvents(t *testing.T) {
+	server := newSSEServer(t, `{"type":"heartbeat"}`, `{"type":"status-update","data":"done","done":true}`)
+	msg := &types.Message{Role: "user", Parts: []types.Part{{Type: "text", Text: "hello"}}}
+
+	c := newTestClient(t, Config{BaseURL: server.URL, Timeout: 5 * time.Second, UnknownEvents: streaming.DropUnknown})
+	events, err := drainStream(c.StreamMessage(context.Background(), "", msg))
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, "status-update", events[0].Type)
+
+	c = newTestClient(t, Config{BaseURL: server.URL, Timeout: 5 * time.Second, UnknownEvents: streaming.ErrorOnUnknown})
+	events, err = drainStream(c.StreamMessage(context.Background(), "", msg))
+	assert.Empty(t, events)
+	var unknownErr *streaming.UnknownEventError
+	assert.ErrorAs(t, err, &unknownErr)
+
+	c = newTestClient(t, Config{BaseURL: server.URL, Timeout: 5 * time.Second, UnknownEvents: "ignore"})
+	_, err = c.GetTaskStatus(context.Background(), "", "task-1")
+	assert.ErrorContains(t, err, "unsupported unknown event policy")
+}
+
+// TestResubscribeTask tests resuming the event stream of an existing task
+func TestResubscribeTask(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     interface{}       `json:"id"`
+			Method string            `json:"method"`
+			Params map[string]string `json:"params"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, types.A2AMethods.TasksResubscribe, req.Method)
+		assert.Equal(t, "task-1", req.Params["id"])
+		assert.Contains(t, r.Header.Get("Accept"), "text/event-stream")
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, event := range []string{
+			`{"type":"status","data":{"state":"working"}}`,
+			`{"type":"status","data":{"state":"completed"},"done":true}`,
+		} {
+			fmt.Fprintf(w, "data: {\"jsonrpc\":\"2.0\",\"id\":%q,\"result\":%s}\n\n", req.ID, event)
+		}
+	}))
+	defer server.Close()
+
+	c := newTestClient(t, Config{BaseURL: server.URL, Timeout: 5 * time.Second})
+	received, err := drainStream(c.ResubscribeTask(context.Background(), "", "task-1"))
+	require.NoError(t, err)
+	require.Len(t, received, 2)
+	assert.Equal(t, "status", received[0].Type)
+	assert.True(t, received[1].Done)
+}
@@ -0,0 +1,65 @@
+package client
+
+import (
+	"context"
+	"sync"
+)
+
+// taskLocks serializes calls that mutate the same task
+type taskLocks struct {
+	mu    sync.Mutex
+	locks map[string]*taskLock
+}
+
+// taskLock is a per-task semaphore shared by its waiters
+type taskLock struct {
+	sem  chan struct{}
+	refs int
+}
+
+// acquire waits for the lock on key, giving up when ctx is done.
+// The returned function releases the lock.
+func (l *taskLocks) acquire(ctx context.Context, key string) (func(), error) {
+	l.mu.Lock()
+	if l.locks == nil {
+		l.locks = make(map[string]*taskLock)
+	}
+	lock, ok := l.locks[key]
+	if !ok {
+		lock = &taskLock{sem: make(chan struct{}, 1)}
+		l.locks[key] = lock
+	}
+	lock.refs++
+	l.mu.Unlock()
+
+	select {
+	case lock.sem <- struct{}{}:
+		return func() {
+			<-lock.sem
+			l.unref(key, lock)
+		}, nil
+	case <-ctx.Done():
+		l.unref(key, lock)
+		return nil, ctx.Err()
+	}
+}
+
+// unref drops a reference to lock, forgetting it when nobody holds or awaits it
+func (l *taskLocks) unref(key string, lock *taskLock) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	lock.refs--
+	if lock.refs == 0 {
+		delete(l.locks, key)
+	}
+}
+
+// lockTask serializes mutations of a task when Config.SerializeTasks is
+// set, so that a call on a task waits for the previous one to finish.
+// It returns a function that releases the lock.
+func (c *Client) lockTask(ctx context.Context, agentID, taskID string) (func(), error) {
+	if !c.config.SerializeTasks || taskID == "" {
+		return func() {}, nil
+	}
+	return c.tasks.acquire(ctx, agentID+"\x00"+taskID)
+}
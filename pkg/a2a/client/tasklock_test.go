@@ -0,0 +1,78 @@
+package client
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/craine-io/openribcage/pkg/a2a/types"
+)
+
+// TestTaskLocks tests that a task lock is exclusive per key, honours
+// cancellation and is forgotten once released
+func TestTaskLocks(t *testing.T) {
+	var l taskLocks
+	ctx := context.Background()
+
+	release, err := l.acquire(ctx, "task-1")
+	require.NoError(t, err)
+
+	other, err := l.acquire(ctx, "task-2")
+	require.NoError(t, err)
+	other()
+
+	waitCtx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	_, err = l.acquire(waitCtx, "task-1")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	acquired := make(chan func())
+	go func() {
+		next, err := l.acquire(ctx, "task-1")
+		assert.NoError(t, err)
+		acquired <- next
+	}()
+	select {
+	case <-acquired:
+		t.Fatal("lock acquired while held")
+	case <-time.After(20 * time.Millisecond):
+	}
+	release()
+	(<-acquired)()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	assert.Empty(t, l.locks)
+}
+
+// TestSerializeTasks tests that a turn on a task waits for the previous
+// turn on the same task when SerializeTasks is set
+func TestSerializeTasks(t *testing.T) {
+	agent := newFlakyAgent(t)
+	c := newTestClient(t, Config{BaseURL: agent.URL, Timeout: 5 * time.Second, SerializeTasks: true})
+	req := &types.TaskRequest{ID: "task-1", Message: &types.Message{Role: "user", Parts: []types.Part{{Type: "text", Text: "hello"}}}}
+
+	held, release := agent.holdNext()
+	first := make(chan error, 1)
+	go func() {
+		_, err := c.SendTask(context.Background(), "", req)
+		first <- err
+	}()
+	<-held
+
+	second := make(chan error, 1)
+	go func() {
+		_, err := c.SendTask(context.Background(), "", req)
+		second <- err
+	}()
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, 1, agent.hitCount())
+
+	release()
+	require.NoError(t, <-first)
+	require.NoError(t, <-second)
+	assert.Equal(t, 2, agent.hitCount())
+}
@@ -0,0 +1,45 @@
+package client
+
+import (
+	"context"
+	"time"
+)
+
+// WithDefaultTimeout bounds ctx by the client's configured Timeout unless
+// ctx already has a deadline, in which case that deadline is kept even if
+// it is longer. With no Timeout configured ctx is returned unbounded. The
+// returned cancel function must always be called.
+func (c *Client) WithDefaultTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if _, ok := ctx.Deadline(); ok || c.config.Timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, c.config.Timeout)
+}
+
+// MethodTimeout returns the timeout configured for a JSON-RPC method:
+// its MethodTimeouts entry, then StreamTimeout for streaming methods, then
+// Timeout for the rest. Zero means unbounded.
+func (c *Client) MethodTimeout(method string) time.Duration {
+	if d, ok := c.config.MethodTimeouts[method]; ok {
+		return d
+	}
+	if streamingMethods[method] {
+		return c.config.StreamTimeout
+	}
+	return c.config.Timeout
+}
+
+// withMethodTimeout bounds ctx for a call to method. A MethodTimeouts or
+// StreamTimeout budget is always applied, so the earlier of it and the
+// caller's deadline wins; the default Timeout only applies when the
+// caller set no deadline, as with WithDefaultTimeout.
+func (c *Client) withMethodTimeout(ctx context.Context, method string) (context.Context, context.CancelFunc) {
+	_, override := c.config.MethodTimeouts[method]
+	if !override && !streamingMethods[method] {
+		return c.WithDefaultTimeout(ctx)
+	}
+	if d := c.MethodTimeout(method); d > 0 {
+		return context.WithTimeout(ctx, d)
+	}
+	return context.WithCancel(ctx)
+}
@@ -0,0 +1,98 @@
+package client
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/craine-io/openribcage/pkg/a2a/types"
+)
+
+// TestWithDefaultTimeout tests that the client timeout applies only to
+// contexts without a deadline
+func TestWithDefaultTimeout(t *testing.T) {
+	c := newTestClient(t, Config{Timeout: time.Minute})
+
+	ctx, cancel := c.WithDefaultTimeout(context.Background())
+	defer cancel()
+	deadline, ok := ctx.Deadline()
+	require.True(t, ok)
+	assert.WithinDuration(t, time.Now().Add(time.Minute), deadline, 5*time.Second)
+
+	parent, cancelParent := context.WithTimeout(context.Background(), time.Hour)
+	defer cancelParent()
+	ctx, cancel = c.WithDefaultTimeout(parent)
+	defer cancel()
+	deadline, ok = ctx.Deadline()
+	require.True(t, ok)
+	parentDeadline, _ := parent.Deadline()
+	assert.Equal(t, parentDeadline, deadline)
+
+	c = newTestClient(t, Config{})
+	ctx, cancel = c.WithDefaultTimeout(context.Background())
+	_, ok = ctx.Deadline()
+	assert.False(t, ok)
+	cancel()
+	assert.Error(t, ctx.Err())
+}
+
+// TestUnaryTimeout tests that unary calls are bounded by the client
+// timeout unless the caller sets a deadline
+func TestUnaryTimeout(t *testing.T) {
+	agent := newAgentServer(t, func(req *types.JSONRPCRequest) (interface{}, *types.JSONRPCError) {
+		time.Sleep(200 * time.Millisecond)
+		return taskResult(req)
+	})
+	c := newTestClient(t, Config{BaseURL: agent.URL, Timeout: 50 * time.Millisecond})
+
+	_, err := c.GetTaskStatus(context.Background(), "", "task-1")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, err = c.GetTaskStatus(ctx, "", "task-1")
+	assert.NoError(t, err)
+}
+
+// TestMethodTimeouts tests that per-method timeouts bound calls
+// independently of the default timeout and the caller's deadline
+func TestMethodTimeouts(t *testing.T) {
+	agent := newAgentServer(t, func(req *types.JSONRPCRequest) (interface{}, *types.JSONRPCError) {
+		time.Sleep(300 * time.Millisecond)
+		return taskResult(req)
+	})
+
+	c := newTestClient(t, Config{
+		BaseURL:        agent.URL,
+		Timeout:        5 * time.Second,
+		StreamTimeout:  100 * time.Millisecond,
+		MethodTimeouts: map[string]time.Duration{types.A2AMethods.TasksStatus: 100 * time.Millisecond},
+	})
+
+	assert.Equal(t, 100*time.Millisecond, c.MethodTimeout(types.A2AMethods.TasksStatus))
+	assert.Equal(t, 100*time.Millisecond, c.MethodTimeout(types.A2AMethods.TasksStream))
+	assert.Equal(t, 5*time.Second, c.MethodTimeout(types.A2AMethods.TasksCancel))
+
+	// The method timeout applies even though the caller's deadline is later
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	_, err := c.GetTaskStatus(ctx, "", "task-1")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	// Methods without an override use the default timeout
+	require.NoError(t, c.CancelTask(ctx, "", "task-1"))
+
+	// An earlier caller deadline wins over the default timeout
+	short, cancelShort := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancelShort()
+	assert.ErrorIs(t, c.CancelTask(short, "", "task-1"), context.DeadlineExceeded)
+
+	// Streams are bounded by the stream timeout
+	events, errs := c.StreamTask(ctx, "", batchRequest("task-1"))
+	for range events {
+	}
+	assert.ErrorIs(t, <-errs, context.DeadlineExceeded)
+}
@@ -0,0 +1,362 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/craine-io/openribcage/pkg/a2a/types"
+	"github.com/craine-io/openribcage/pkg/httpcompress"
+)
+
+// DefaultUploadChunkSize is the chunk size used when Config.UploadChunkSize is unset
+const DefaultUploadChunkSize = 1 << 20
+
+// ErrNoUploadEndpoint is returned by UploadFile when the agent advertises
+// no upload endpoint
+var ErrNoUploadEndpoint = errors.New("agent advertises no upload endpoint")
+
+// uploadOffsetHeader carries the number of bytes an upload has received
+const uploadOffsetHeader = "Upload-Offset"
+
+// uploadSession is an upload created on an agent's endpoint. It is kept
+// until the upload completes so that a failed upload can be resumed.
+type uploadSession struct {
+	ID  string `json:"id"`
+	URL string `json:"url"`
+}
+
+// uploads tracks unfinished uploads by endpoint and content hash
+type uploads struct {
+	mu       sync.Mutex
+	sessions map[string]*uploadSession
+}
+
+// UploadFile uploads a file's inline content to the agent's upload
+// endpoint in chunks of Config.UploadChunkSize and returns the URL by
+// which the agent can reference it. A file that already has a URL is not
+// uploaded again.
+//
+// The upload endpoint is asked to create an upload with a POST of the
+// file's name, MIME type and size, answering with the upload's id and
+// final url. Chunks are then sent with PATCH to <endpoint>/<id>, each
+// carrying its position in an Upload-Offset header; the endpoint answers
+// with the new offset. Failed chunks are retried under the client's retry
+// policy after asking the endpoint with HEAD how much it received, and an
+// upload that still fails is resumed from that point when UploadFile is
+// called again with the same content.
+//
+// ErrNoUploadEndpoint is returned if Config.UploadEndpoint reports no
+// endpoint for the agent.
+func (c *Client) UploadFile(ctx context.Context, agentID string, file *types.FilePart) (string, error) {
+	if file == nil {
+		return "", errors.New("file is required")
+	}
+	if file.URL != "" && len(file.Content) == 0 {
+		return file.URL, nil
+	}
+	if len(file.Content) == 0 {
+		return "", fmt.Errorf("file %s has no content to upload", file.Name)
+	}
+
+	endpoint := c.uploadEndpoint(agentID)
+	if endpoint == "" {
+		return "", ErrNoUploadEndpoint
+	}
+	if err := c.hosts.CheckProvidedURL(ctx, endpoint, c.agentURL(agentID)); err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(file.Content)
+	key := endpoint + " " + hex.EncodeToString(sum[:])
+
+	session, offset, err := c.resumeUpload(ctx, endpoint, key)
+	if err != nil {
+		return "", err
+	}
+	if session == nil {
+		if session, err = c.createUpload(ctx, endpoint, file); err != nil {
+			return "", err
+		}
+		c.uploads.store(key, session)
+	}
+
+	if err := c.sendChunks(ctx, endpoint, session, file.Content, offset); err != nil {
+		return "", fmt.Errorf("upload of %s failed at its last acknowledged offset, call UploadFile again to resume: %w", file.Name, err)
+	}
+	c.uploads.delete(key)
+	return session.URL, nil
+}
+
+// uploadEndpoint returns the agent's upload endpoint, if any
+func (c *Client) uploadEndpoint(agentID string) string {
+	if c.config.UploadEndpoint == nil {
+		return ""
+	}
+	return c.config.UploadEndpoint(agentID)
+}
+
+// resumeUpload returns an unfinished upload of the same content and the
+// offset the endpoint has received, or a nil session if there is none or
+// the endpoint no longer knows it
+func (c *Client) resumeUpload(ctx context.Context, endpoint, key string) (*uploadSession, int64, error) {
+	session := c.uploads.load(key)
+	if session == nil {
+		return nil, 0, nil
+	}
+	offset, err := c.uploadOffset(ctx, endpoint, session)
+	if err != nil {
+		var statusErr *uploadStatusError
+		if errors.As(err, &statusErr) && statusErr.code == http.StatusNotFound {
+			c.uploads.delete(key)
+			return nil, 0, nil
+		}
+		return nil, 0, err
+	}
+	c.logger.Debugf("Resuming upload %s at offset %d", session.ID, offset)
+	return session, offset, nil
+}
+
+// createUpload asks the endpoint to create an upload for file
+func (c *Client) createUpload(ctx context.Context, endpoint string, file *types.FilePart) (*uploadSession, error) {
+	body, err := json.Marshal(map[string]interface{}{
+		"name":     file.Name,
+		"mimeType": file.MimeType,
+		"size":     len(file.Content),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal upload request: %w", err)
+	}
+
+	resp, err := c.doUpload(ctx, http.MethodPost, endpoint, "application/json", body, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create upload: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var session uploadSession
+	if err := decodeBody(resp.Body, &session); err != nil {
+		return nil, fmt.Errorf("failed to create upload: %w", err)
+	}
+	if session.ID == "" || session.URL == "" {
+		return nil, errors.New("failed to create upload: endpoint returned no id or url")
+	}
+	return &session, nil
+}
+
+// sendChunks sends content from offset onwards, retrying failed chunks
+// from the offset the endpoint reports
+func (c *Client) sendChunks(ctx context.Context, endpoint string, session *uploadSession, content []byte, offset int64) error {
+	chunkSize := c.config.UploadChunkSize
+	if chunkSize <= 0 {
+		chunkSize = DefaultUploadChunkSize
+	}
+	chunkURL := strings.TrimSuffix(endpoint, "/") + "/" + session.ID
+
+	failures := 0
+	for offset < int64(len(content)) {
+		end := offset + chunkSize
+		if end > int64(len(content)) {
+			end = int64(len(content))
+		}
+
+		next, err := c.sendChunk(ctx, chunkURL, content[offset:end], offset)
+		if err == nil {
+			offset = next
+			failures = 0
+			continue
+		}
+
+		var statusErr *uploadStatusError
+		retryable := !errors.As(err, &statusErr) || statusErr.code >= 500
+		if !retryable || failures >= c.config.Retry.Attempts || ctx.Err() != nil {
+			return err
+		}
+		failures++
+		c.logger.Debugf("Retry attempt %d/%d for upload %s chunk at offset %d: %v", failures, c.config.Retry.Attempts, session.ID, offset, err)
+		if err := c.config.Retry.Wait(ctx, failures); err != nil {
+			return err
+		}
+		if offset, err = c.uploadOffset(ctx, endpoint, session); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sendChunk sends one chunk at offset and returns the endpoint's new offset
+func (c *Client) sendChunk(ctx context.Context, chunkURL string, chunk []byte, offset int64) (int64, error) {
+	header := http.Header{uploadOffsetHeader: {strconv.FormatInt(offset, 10)}}
+	resp, err := c.doUpload(ctx, http.MethodPatch, chunkURL, "application/offset+octet-stream", chunk, header)
+	if err != nil {
+		return 0, err
+	}
+	resp.Body.Close()
+
+	next, err := parseUploadOffset(resp)
+	if err != nil {
+		return 0, err
+	}
+	if next <= offset {
+		return 0, fmt.Errorf("upload made no progress at offset %d", offset)
+	}
+	return next, nil
+}
+
+// uploadOffset asks the endpoint how many bytes of an upload it has received
+func (c *Client) uploadOffset(ctx context.Context, endpoint string, session *uploadSession) (int64, error) {
+	chunkURL := strings.TrimSuffix(endpoint, "/") + "/" + session.ID
+	resp, err := c.doUpload(ctx, http.MethodHead, chunkURL, "", nil, nil)
+	if err != nil {
+		return 0, err
+	}
+	resp.Body.Close()
+	return parseUploadOffset(resp)
+}
+
+// parseUploadOffset reads the Upload-Offset header of an upload response
+func parseUploadOffset(resp *http.Response) (int64, error) {
+	offset, err := strconv.ParseInt(resp.Header.Get(uploadOffsetHeader), 10, 64)
+	if err != nil || offset < 0 {
+		return 0, fmt.Errorf("upload endpoint returned invalid %s %q", uploadOffsetHeader, resp.Header.Get(uploadOffsetHeader))
+	}
+	return offset, nil
+}
+
+// uploadStatusError reports a non-2xx answer from an upload endpoint
+type uploadStatusError struct {
+	code   int
+	status string
+}
+
+// Error implements the error interface
+func (e *uploadStatusError) Error() string {
+	return "unexpected status: " + e.status
+}
+
+// doUpload sends one request to an upload endpoint with the client's
+// headers and credentials. Non-2xx responses are returned as an
+// *uploadStatusError with the body closed.
+func (c *Client) doUpload(ctx context.Context, method, url, contentType string, body []byte, header http.Header) (*http.Response, error) {
+	if err := c.checkOpen(); err != nil {
+		return nil, err
+	}
+
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, url, reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	httpcompress.SetAcceptEncoding(req)
+	c.setHeaders(req)
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	if err := c.auth.AddAuthHeaders(req, c.config.Credentials); err != nil {
+		return nil, fmt.Errorf("failed to add auth headers: %w", err)
+	}
+	if err := c.dryRun(req, body); err != nil {
+		return nil, err
+	}
+
+	if err := c.runRequestHooks(req); err != nil {
+		return nil, err
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	if err := httpcompress.Decode(resp); err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+	if err := c.runResponseHooks(resp); err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		resp.Body.Close()
+		return nil, &uploadStatusError{code: resp.StatusCode, status: resp.Status}
+	}
+	return resp, nil
+}
+
+// uploadLargeFiles returns msg with the inline content of file parts
+// larger than Config.UploadThreshold uploaded and replaced by URLs. msg is
+// returned unchanged when uploads are disabled, in dry runs, or when the
+// agent has no upload endpoint, in which case the content is sent inline.
+func (c *Client) uploadLargeFiles(ctx context.Context, agentID string, msg *types.Message) (*types.Message, error) {
+	threshold := c.config.UploadThreshold
+	if threshold <= 0 || c.config.DryRun || msg == nil || c.uploadEndpoint(agentID) == "" {
+		return msg, nil
+	}
+
+	var out *types.Message
+	for i, part := range msg.Parts {
+		if part.File == nil || int64(len(part.File.Content)) <= threshold {
+			continue
+		}
+
+		url, err := c.UploadFile(ctx, agentID, part.File)
+		if err != nil {
+			return nil, err
+		}
+
+		if out == nil {
+			copied := *msg
+			copied.Parts = append([]types.Part(nil), msg.Parts...)
+			out = &copied
+		}
+		file := *part.File
+		if file.Size == 0 {
+			file.Size = int64(len(file.Content))
+		}
+		file.Content = nil
+		file.URL = url
+		out.Parts[i].File = &file
+	}
+
+	if out == nil {
+		return msg, nil
+	}
+	return out, nil
+}
+
+// load returns the unfinished upload stored under key, if any
+func (u *uploads) load(key string) *uploadSession {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.sessions[key]
+}
+
+// store records an unfinished upload under key
+func (u *uploads) store(key string, session *uploadSession) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if u.sessions == nil {
+		u.sessions = make(map[string]*uploadSession)
+	}
+	u.sessions[key] = session
+}
+
+// delete forgets the upload stored under key
+func (u *uploads) delete(key string) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	delete(u.sessions, key)
+}
@@ -0,0 +1,136 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/craine-io/openribcage/pkg/a2a/retry"
+	"github.com/craine-io/openribcage/pkg/a2a/types"
+)
+
+// TestUploadFile tests chunked, resumable uploads of large file parts
+func TestUploadFile(t *testing.T) {
+	content := bytes.Repeat([]byte("0123456789"), 100)
+
+	var mu sync.Mutex
+	received := map[string][]byte{}
+	var chunkSizes []int
+	failNext := true
+	var sentParts []types.Part
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/uploads", func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPost, r.Method)
+		var meta struct {
+			Name string `json:"name"`
+			Size int    `json:"size"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&meta))
+		assert.Equal(t, "big.bin", meta.Name)
+		assert.Equal(t, len(content), meta.Size)
+
+		mu.Lock()
+		received["u1"] = nil
+		mu.Unlock()
+		json.NewEncoder(w).Encode(map[string]string{"id": "u1", "url": "https://files.example.com/u1"})
+	})
+	mux.HandleFunc("/uploads/u1", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch r.Method {
+		case http.MethodHead:
+		case http.MethodPatch:
+			offset, err := strconv.Atoi(r.Header.Get("Upload-Offset"))
+			require.NoError(t, err)
+			require.Equal(t, len(received["u1"]), offset, "chunks must continue at the acknowledged offset")
+			chunk, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+			if failNext && offset > 0 {
+				// Keep half the chunk, then fail as if the connection dropped
+				failNext = false
+				received["u1"] = append(received["u1"], chunk[:len(chunk)/2]...)
+				w.WriteHeader(http.StatusBadGateway)
+				return
+			}
+			chunkSizes = append(chunkSizes, len(chunk))
+			received["u1"] = append(received["u1"], chunk...)
+		}
+		w.Header().Set("Upload-Offset", strconv.Itoa(len(received["u1"])))
+	})
+	mux.HandleFunc("/a2a", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     interface{} `json:"id"`
+			Params struct {
+				Message types.Message `json:"message"`
+			} `json:"params"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		mu.Lock()
+		sentParts = req.Params.Message.Parts
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(types.JSONRPCResponse{JSONRPC: "2.0", ID: req.ID, Result: json.RawMessage(`{"id":"task-1"}`)})
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	newClient := func(endpoint string) *Client {
+		return newTestClient(t, Config{
+			BaseURL:         server.URL + "/a2a",
+			Timeout:         5 * time.Second,
+			Retry:           retry.Policy{Attempts: 2, BaseDelay: time.Millisecond},
+			UploadThreshold: 100,
+			UploadChunkSize: 256,
+			UploadEndpoint:  func(string) string { return endpoint },
+		})
+	}
+	ctx := context.Background()
+	file := &types.FilePart{Name: "big.bin", MimeType: "application/octet-stream", Content: content}
+
+	c := newClient(server.URL + "/uploads")
+
+	url, err := c.UploadFile(ctx, "", file)
+	require.NoError(t, err)
+	assert.Equal(t, "https://files.example.com/u1", url)
+	assert.Equal(t, content, received["u1"], "the retried chunk must resume after the bytes already received")
+	for _, size := range chunkSizes {
+		assert.LessOrEqual(t, size, 256)
+	}
+
+	// Large parts are uploaded and sent by URL, small ones stay inline
+	small := &types.FilePart{Name: "small.txt", MimeType: "text/plain", Content: []byte("hi")}
+	msg := &types.Message{Role: "user", Parts: []types.Part{
+		{Type: "text", Text: "see attached"},
+		{Type: "file", File: file},
+		{Type: "file", File: small},
+	}}
+	_, err = c.SendTask(ctx, "", types.NewTaskRequest(msg))
+	require.NoError(t, err)
+	require.Len(t, sentParts, 3)
+	assert.Equal(t, "https://files.example.com/u1", sentParts[1].File.URL)
+	assert.Empty(t, sentParts[1].File.Content)
+	assert.Equal(t, int64(len(content)), sentParts[1].File.Size)
+	assert.Equal(t, small.Content, sentParts[2].File.Content)
+	assert.Equal(t, content, file.Content, "the caller's message must not be modified")
+
+	// Without an upload endpoint the content is sent inline
+	inline := newClient("")
+	_, err = inline.UploadFile(ctx, "", file)
+	assert.ErrorIs(t, err, ErrNoUploadEndpoint)
+	_, err = inline.SendTask(ctx, "", types.NewTaskRequest(msg))
+	require.NoError(t, err)
+	require.Len(t, sentParts, 3)
+	assert.Equal(t, content, sentParts[1].File.Content)
+	assert.Empty(t, sentParts[1].File.URL)
+}
@@ -0,0 +1,137 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/craine-io/openribcage/pkg/a2a/streaming"
+	"github.com/craine-io/openribcage/pkg/a2a/types"
+)
+
+// newSSEServer starts an agent that answers every stream with events, one
+// SSE data line each
+func newSSEServer(t *testing.T, events ...string) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, event := range events {
+			fmt.Fprintf(w, "data: %s\n\n", event)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// drainStream collects a stream's events and its error
+func drainStream(events <-chan *types.StreamResponse, errs <-chan error) ([]*types.StreamResponse, error) {
+	var received []*types.StreamResponse
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				return received, <-errs
+			}
+			received = append(received, event)
+		case <-timeout:
+			return received, errors.New("stream did not end")
+		}
+	}
+}
+
+// TestCheckResponseVersion tests the jsonrpc version check on unary responses
+func TestCheckResponseVersion(t *testing.T) {
+	var protoErr *ProtocolError
+
+	assert.NoError(t, checkResponse("tasks/send", "1", &types.JSONRPCResponse{JSONRPC: "2.0", ID: "1"}, true))
+	assert.NoError(t, checkResponse("tasks/send", "1", &types.JSONRPCResponse{ID: "1"}, false))
+	assert.ErrorAs(t, checkResponse("tasks/send", "1", &types.JSONRPCResponse{ID: "1"}, true), &protoErr)
+	assert.ErrorAs(t, checkResponse("tasks/send", "1", &types.JSONRPCResponse{JSONRPC: "1.0", ID: "1"}, false), &protoErr)
+	assert.ErrorAs(t, checkResponse("tasks/send", "1", &types.JSONRPCResponse{JSONRPC: "2.0", ID: "2"}, false), &protoErr)
+}
+
+// TestStreamVersion tests that stream events wrapped in JSON-RPC responses
+// are held to the same version check as unary responses
+func TestStreamVersion(t *testing.T) {
+	const (
+		valid    = `{"jsonrpc":"2.0","id":"1","result":{"type":"status","data":"working"}}`
+		missing  = `{"id":"1","result":{"type":"status","data":"working"}}`
+		mismatch = `{"jsonrpc":"1.0","id":"1","result":{"type":"status","data":"working"}}`
+		bare     = `{"type":"status","data":"done","done":true}`
+	)
+	msg := &types.Message{Role: "user", Parts: []types.Part{{Type: "text", Text: "hello"}}}
+
+	tests := []struct {
+		name    string
+		strict  bool
+		events  []string
+		want    int
+		wantErr string
+	}{
+		{"valid", true, []string{valid, bare}, 2, ""},
+		{"missing version, lenient", false, []string{missing, bare}, 2, ""},
+		{"missing version, strict", true, []string{missing, bare}, 0, "stream event is missing the jsonrpc version"},
+		{"mismatched version", false, []string{valid, mismatch, bare}, 1, `stream event has jsonrpc version "1.0", expected "2.0"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newSSEServer(t, tt.events...)
+			c := newTestClient(t, Config{BaseURL: server.URL, Timeout: 5 * time.Second, StrictJSONRPC: tt.strict})
+
+			events, err := drainStream(c.StreamMessage(context.Background(), "", msg))
+			assert.Len(t, events, tt.want)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			var protoErr *ProtocolError
+			require.ErrorAs(t, err, &protoErr)
+			assert.Equal(t, types.A2AMethods.MessageStream, protoErr.Method)
+			assert.Equal(t, tt.wantErr, protoErr.Reason)
+		})
+	}
+}
+
+// TestJSONStreamVersion tests the version check on a single JSON-RPC
+// response sent in place of an event stream
+func TestJSONStreamVersion(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"jsonrpc":"1.0","id":"1","result":{"type":"status","data":"done"}}`)
+	}))
+	defer server.Close()
+	c := newTestClient(t, Config{BaseURL: server.URL, Timeout: 5 * time.Second})
+
+	_, err := drainStream(c.ResubscribeTask(context.Background(), "", "task-1"))
+	var protoErr *ProtocolError
+	require.ErrorAs(t, err, &protoErr)
+	assert.Equal(t, types.A2AMethods.TasksResubscribe, protoErr.Method)
+}
+
+// TestStreamMaxLineSize tests that events longer than MaxStreamLineSize
+// fail the stream with a LineTooLongError
+func TestStreamMaxLineSize(t *testing.T) {
+	event := `{"jsonrpc":"2.0","id":"1","result":{"type":"status","data":"` + strings.Repeat("x", 256) + `","done":true}}`
+	server := newSSEServer(t, event)
+	msg := &types.Message{Role: "user", Parts: []types.Part{{Type: "text", Text: "hello"}}}
+
+	c := newTestClient(t, Config{BaseURL: server.URL, Timeout: 5 * time.Second, MaxStreamLineSize: 128})
+	events, err := drainStream(c.StreamMessage(context.Background(), "", msg))
+	assert.Empty(t, events)
+	var tooLong *streaming.LineTooLongError
+	require.ErrorAs(t, err, &tooLong)
+	assert.Equal(t, 128, tooLong.Limit)
+
+	c = newTestClient(t, Config{BaseURL: server.URL, Timeout: 5 * time.Second})
+	events, err = drainStream(c.StreamMessage(context.Background(), "", msg))
+	assert.NoError(t, err)
+	assert.Len(t, events, 1)
+}
@@ -0,0 +1,77 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+
+	"github.com/craine-io/openribcage/internal/auth"
+)
+
+// Warmup primes the connection pool for the given agents, opening their
+// TCP and TLS connections ahead of the first real request so that it can
+// reuse a warm connection. Each agent receives a HEAD request to its
+// endpoint; any HTTP response counts as success. With no agentIDs, BaseURL
+// is warmed. Agents are warmed concurrently and every failure is returned,
+// joined.
+func (c *Client) Warmup(ctx context.Context, agentIDs ...string) error {
+	if err := c.checkOpen(); err != nil {
+		return err
+	}
+	if len(agentIDs) == 0 {
+		agentIDs = []string{""}
+	}
+	ctx, cancel := c.WithDefaultTimeout(ctx)
+	defer cancel()
+
+	errs := make([]error, len(agentIDs))
+	var wg sync.WaitGroup
+	for i, agentID := range agentIDs {
+		wg.Add(1)
+		go func(i int, agentID string) {
+			defer wg.Done()
+			errs[i] = c.warmup(ctx, c.agentURL(agentID))
+		}(i, agentID)
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+// warmup sends a HEAD request to url, leaving its connection idle in the pool
+func (c *Client) warmup(ctx context.Context, url string) error {
+	if err := c.hosts.CheckURL(url); err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create warmup request for %s: %w", url, err)
+	}
+	c.setHeaders(req)
+	if err := c.auth.AddAuthHeaders(req, c.config.Credentials); err != nil {
+		return fmt.Errorf("failed to add auth headers: %w", err)
+	}
+	if err := c.dryRun(req, nil); err != nil {
+		return err
+	}
+
+	if err := c.runRequestHooks(req); err != nil {
+		return err
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("warmup of %s failed: %w", url, auth.RedactError(err, auth.RedactParam(c.config.Credentials)))
+	}
+	defer resp.Body.Close()
+	if err := c.runResponseHooks(resp); err != nil {
+		return err
+	}
+	// Drain the body so the connection is returned to the pool
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	c.logger.Debugf("Warmed up connection to %s (%s)", url, resp.Status)
+	return nil
+}
@@ -0,0 +1,38 @@
+package client
+
+import (
+	"context"
+	"net/http/httptrace"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestWarmupReusesConnection tests that a request after Warmup reuses the
+// pooled connection instead of dialing a new one
+func TestWarmupReusesConnection(t *testing.T) {
+	agent := newAgentServer(t, taskResult)
+	c := newTestClient(t, Config{BaseURL: agent.URL, Timeout: 5 * time.Second})
+
+	var mu sync.Mutex
+	var reused []bool
+	ctx := httptrace.WithClientTrace(context.Background(), &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) {
+			mu.Lock()
+			reused = append(reused, info.Reused)
+			mu.Unlock()
+		},
+	})
+
+	require.NoError(t, c.Warmup(ctx))
+	_, err := c.SendTask(ctx, "", batchRequest("task-1"))
+	require.NoError(t, err)
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []bool{false, true}, reused, "the task is sent on the warmed connection")
+	assert.Equal(t, 1, agent.requestCount())
+}
@@ -0,0 +1,78 @@
+// Package retry provides the retry policy shared by A2A components.
+//
+// A single Policy, usually derived from the A2A configuration, controls
+// how many times requests are retried and how long to back off between
+// attempts, so that the client and discoverer behave consistently.
+package retry
+
+import (
+	"context"
+	"math/rand"
+	"time"
+)
+
+// Policy describes exponential backoff with optional jitter
+type Policy struct {
+	// Attempts is the number of retries after the initial attempt
+	Attempts int `json:"attempts"`
+	// BaseDelay is the delay before the first retry
+	BaseDelay time.Duration `json:"base_delay"`
+	// MaxDelay caps the backoff delay (zero means uncapped)
+	MaxDelay time.Duration `json:"max_delay"`
+	// Jitter randomizes each delay by up to this fraction (0.0-1.0)
+	Jitter float64 `json:"jitter"`
+}
+
+// DefaultPolicy returns the policy used when none is configured
+func DefaultPolicy() Policy {
+	return Policy{
+		Attempts:  3,
+		BaseDelay: 1 * time.Second,
+		MaxDelay:  30 * time.Second,
+		Jitter:    0.1,
+	}
+}
+
+// Delay returns the backoff before the given retry attempt (1-based)
+func (p Policy) Delay(attempt int) time.Duration {
+	if attempt < 1 || p.BaseDelay <= 0 {
+		return 0
+	}
+
+	delay := p.BaseDelay
+	for i := 1; i < attempt; i++ {
+		delay *= 2
+		if p.MaxDelay > 0 && delay >= p.MaxDelay {
+			break
+		}
+	}
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+
+	if p.Jitter > 0 {
+		jitter := p.Jitter
+		if jitter > 1 {
+			jitter = 1
+		}
+		// Spread the delay uniformly across [delay*(1-jitter), delay*(1+jitter)]
+		spread := (rand.Float64()*2 - 1) * jitter * float64(delay)
+		delay += time.Duration(spread)
+	}
+
+	return delay
+}
+
+// Wait blocks for the backoff before the given retry attempt, returning
+// early with the context error if ctx is done
+func (p Policy) Wait(ctx context.Context, attempt int) error {
+	timer := time.NewTimer(p.Delay(attempt))
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,60 @@
+package retry
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestDelay tests exponential backoff and its cap without jitter
+func TestDelay(t *testing.T) {
+	p := Policy{Attempts: 5, BaseDelay: time.Second, MaxDelay: 5 * time.Second}
+
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{0, 0},
+		{1, time.Second},
+		{2, 2 * time.Second},
+		{3, 4 * time.Second},
+		{4, 5 * time.Second},
+		{40, 5 * time.Second},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, p.Delay(tt.attempt), "attempt %d", tt.attempt)
+	}
+
+	assert.Equal(t, time.Duration(0), Policy{}.Delay(1))
+	assert.Equal(t, 8*time.Second, Policy{BaseDelay: time.Second}.Delay(4))
+}
+
+// TestDelayJitter tests that jitter keeps delays within the configured spread
+func TestDelayJitter(t *testing.T) {
+	p := Policy{BaseDelay: time.Second, Jitter: 0.5}
+	for i := 0; i < 100; i++ {
+		delay := p.Delay(1)
+		assert.GreaterOrEqual(t, delay, 500*time.Millisecond)
+		assert.LessOrEqual(t, delay, 1500*time.Millisecond)
+	}
+
+	p.Jitter = 3
+	for i := 0; i < 100; i++ {
+		assert.LessOrEqual(t, p.Delay(1), 2*time.Second)
+	}
+}
+
+// TestWait tests that Wait sleeps for the backoff and honours cancellation
+func TestWait(t *testing.T) {
+	p := Policy{BaseDelay: 10 * time.Millisecond}
+	start := time.Now()
+	assert.NoError(t, p.Wait(context.Background(), 1))
+	assert.GreaterOrEqual(t, time.Since(start), 10*time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, Policy{BaseDelay: time.Hour}.Wait(ctx, 1), context.Canceled)
+}
@@ -0,0 +1,204 @@
+// Package session provides multi-turn conversations with an A2A agent.
+//
+// A Session sends every turn on the same task, so the agent keeps the
+// context of earlier turns, and records the exchanged messages so that an
+// avatar interface can show or persist the conversation. It depends only
+// on client.Client and is independent of the transport behind it.
+package session
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+
+	"github.com/craine-io/openribcage/pkg/a2a/client"
+	"github.com/craine-io/openribcage/pkg/a2a/types"
+)
+
+// ErrEmptyText is returned when a turn has no text to send
+var ErrEmptyText = errors.New("text is required")
+
+// Session is a conversation with one agent. It is safe for concurrent use,
+// although turns are normally sent one after another.
+type Session struct {
+	client  *client.Client
+	agentID string
+
+	mu      sync.Mutex
+	id      string
+	history []types.Message
+}
+
+// New starts a conversation with the agent agentID reached through c. An
+// empty agentID addresses the client's BaseURL directly.
+func New(c *client.Client, agentID string) *Session {
+	return &Session{
+		client:  c,
+		agentID: agentID,
+		id:      types.NewID(),
+	}
+}
+
+// ID returns the conversation ID, which is the task ID every turn is sent on
+func (s *Session) ID() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.id
+}
+
+// History returns a copy of the messages exchanged so far, oldest first
+func (s *Session) History() []types.Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]types.Message(nil), s.history...)
+}
+
+// Clear forgets the history and starts a new conversation ID, so the agent
+// no longer sees earlier turns either. Turns in flight when Clear is
+// called are not recorded.
+func (s *Session) Clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.id = types.NewID()
+	s.history = nil
+}
+
+// Send sends text as the next user turn and returns the agent's response.
+// The turn and the agent's reply are added to the history only if the
+// call succeeds.
+func (s *Session) Send(ctx context.Context, text string) (*types.TaskResponse, error) {
+	req, err := s.request(text)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.client.SendTask(ctx, s.agentID, req)
+	if err != nil {
+		return nil, err
+	}
+	s.record(req, resp.Message)
+	return resp, nil
+}
+
+// Stream sends text as the next user turn and delivers the agent's events
+// as they arrive. Once the stream ends without error, the turn and the
+// agent's reply are added to the history. The reply is the last message
+// carried by a status event or, failing that, the text of the streamed
+// artifacts.
+func (s *Session) Stream(ctx context.Context, text string) (<-chan *types.StreamResponse, <-chan error) {
+	out := make(chan *types.StreamResponse)
+	errs := make(chan error, 1)
+
+	req, err := s.request(text)
+	if err != nil {
+		close(out)
+		errs <- err
+		close(errs)
+		return out, errs
+	}
+
+	events, streamErrs := s.client.StreamTask(ctx, s.agentID, req)
+	go func() {
+		defer close(out)
+		defer close(errs)
+
+		var reply *types.Message
+		var artifacts []types.Artifact
+		for event := range events {
+			if msg, ok := eventMessage(event); ok {
+				reply = msg
+			}
+			if artifact, ok := event.Artifact(); ok {
+				artifacts = append(artifacts, *artifact)
+			}
+			select {
+			case out <- event:
+			case <-ctx.Done():
+			}
+		}
+		if err := <-streamErrs; err != nil {
+			errs <- err
+			return
+		}
+
+		if reply == nil {
+			reply = artifactMessage(types.MergeArtifacts(artifacts))
+		}
+		s.record(req, reply)
+	}()
+
+	return out, errs
+}
+
+// request builds the task request for the next user turn, threading the
+// conversation ID through the message
+func (s *Session) request(text string) (*types.TaskRequest, error) {
+	if text == "" {
+		return nil, ErrEmptyText
+	}
+	return types.NewTaskRequest(&types.Message{
+		Role:   "user",
+		Parts:  []types.Part{{Type: "text", Text: text}},
+		TaskID: s.ID(),
+	}), nil
+}
+
+// record appends a completed turn to the history unless the session was
+// cleared while it was in flight
+func (s *Session) record(req *types.TaskRequest, reply *types.Message) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if req.ID != s.id {
+		return
+	}
+	s.history = append(s.history, *req.Message)
+	if reply != nil {
+		s.history = append(s.history, *reply)
+	}
+}
+
+// eventMessage returns the agent message carried by a stream event, either
+// under status.message as in A2A TaskStatusUpdateEvent or under message
+func eventMessage(event *types.StreamResponse) (*types.Message, bool) {
+	if event.Data == nil {
+		return nil, false
+	}
+	data, err := json.Marshal(event.Data)
+	if err != nil {
+		return nil, false
+	}
+
+	var payload struct {
+		Status  json.RawMessage `json:"status"`
+		Message *types.Message  `json:"message"`
+	}
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return nil, false
+	}
+	// status may also be a bare state string, which carries no message
+	var status struct {
+		Message *types.Message `json:"message"`
+	}
+	_ = json.Unmarshal(payload.Status, &status)
+
+	for _, msg := range []*types.Message{status.Message, payload.Message} {
+		if msg != nil && len(msg.Parts) > 0 {
+			return msg, true
+		}
+	}
+	return nil, false
+}
+
+// artifactMessage turns streamed artifacts into an agent message, or
+// returns nil if they carry no parts
+func artifactMessage(artifacts []types.Artifact) *types.Message {
+	var parts []types.Part
+	for _, artifact := range artifacts {
+		parts = append(parts, artifact.Parts...)
+	}
+	if len(parts) == 0 {
+		return nil
+	}
+	return &types.Message{Role: "agent", Parts: parts}
+}
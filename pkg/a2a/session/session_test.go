@@ -0,0 +1,75 @@
+package session
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/craine-io/openribcage/pkg/a2a/client"
+	"github.com/craine-io/openribcage/pkg/a2a/types"
+)
+
+// TestSession tests that session turns share a task and build up history
+func TestSession(t *testing.T) {
+	var taskIDs []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     interface{}       `json:"id"`
+			Method string            `json:"method"`
+			Params types.TaskRequest `json:"params"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		taskIDs = append(taskIDs, req.Params.ID)
+		assert.Equal(t, req.Params.ID, req.Params.Message.TaskID)
+		reply := types.Message{Role: "agent", Parts: []types.Part{{Type: "text", Text: "re: " + req.Params.Message.Parts[0].Text}}}
+
+		if req.Method == types.A2AMethods.TasksStream {
+			w.Header().Set("Content-Type", "text/event-stream")
+			status, _ := json.Marshal(map[string]interface{}{"status": map[string]interface{}{"state": "completed", "message": reply}})
+			fmt.Fprintf(w, "data: {\"type\":\"status\",\"data\":%s,\"done\":true}\n\n", status)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		result, _ := json.Marshal(types.TaskResponse{ID: req.Params.ID, Status: types.TaskStateCompleted, Message: &reply})
+		json.NewEncoder(w).Encode(types.JSONRPCResponse{JSONRPC: "2.0", ID: req.ID, Result: result})
+	}))
+	defer server.Close()
+
+	a2aClient := client.New(client.Config{BaseURL: server.URL, Timeout: 5 * time.Second})
+	defer a2aClient.Close()
+	ctx := context.Background()
+
+	sess := New(a2aClient, "")
+	resp, err := sess.Send(ctx, "hello")
+	require.NoError(t, err)
+	assert.Equal(t, sess.ID(), resp.ID)
+
+	events, errs := sess.Stream(ctx, "again")
+	for range events {
+	}
+	require.NoError(t, <-errs)
+
+	assert.Equal(t, []string{sess.ID(), sess.ID()}, taskIDs)
+	history := sess.History()
+	require.Len(t, history, 4)
+	for i, want := range []string{"hello", "re: hello", "again", "re: again"} {
+		assert.Equal(t, want, history[i].Parts[0].Text)
+	}
+	assert.Equal(t, "user", history[0].Role)
+	assert.Equal(t, "agent", history[3].Role)
+
+	_, err = sess.Send(ctx, "")
+	assert.ErrorIs(t, err, ErrEmptyText)
+
+	previous := sess.ID()
+	sess.Clear()
+	assert.Empty(t, sess.History())
+	assert.NotEqual(t, previous, sess.ID())
+}
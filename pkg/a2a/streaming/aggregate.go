@@ -0,0 +1,106 @@
+package streaming
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/craine-io/openribcage/pkg/a2a/types"
+)
+
+// Source identifies an agent stream to aggregate
+type Source struct {
+	AgentID string
+	URL     string
+	Headers map[string]string
+}
+
+// AgentEvent is a stream event tagged with the agent that produced it
+type AgentEvent struct {
+	AgentID string                `json:"agent_id"`
+	Event   *types.StreamResponse `json:"event"`
+}
+
+// AgentError is a stream error tagged with the agent that produced it
+type AgentError struct {
+	AgentID string
+	Err     error
+}
+
+// Error implements the error interface
+func (e *AgentError) Error() string {
+	return fmt.Sprintf("agent %s: %v", e.AgentID, e.Err)
+}
+
+// Unwrap returns the underlying stream error
+func (e *AgentError) Unwrap() error {
+	return e.Err
+}
+
+// Aggregator merges the streams of several agents into a single channel,
+// ordered by arrival. Each source reconnects independently.
+type Aggregator struct {
+	events chan *AgentEvent
+	errors chan error
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// Aggregate subscribes to every source and merges their events. Both
+// channels are closed once all sources have finished or the aggregator
+// is closed.
+func (s *StreamClient) Aggregate(ctx context.Context, sources []Source) *Aggregator {
+	ctx, cancel := context.WithCancel(ctx)
+	a := &Aggregator{
+		events: make(chan *AgentEvent),
+		errors: make(chan error, len(sources)),
+		cancel: cancel,
+	}
+
+	for _, src := range sources {
+		a.wg.Add(1)
+		go a.forward(ctx, s, src)
+	}
+
+	go func() {
+		a.wg.Wait()
+		close(a.events)
+		close(a.errors)
+	}()
+
+	return a
+}
+
+// forward relays a single source's events and terminal error
+func (a *Aggregator) forward(ctx context.Context, s *StreamClient, src Source) {
+	defer a.wg.Done()
+
+	events, errs := s.Subscribe(ctx, src.URL, src.Headers)
+	for event := range events {
+		select {
+		case a.events <- &AgentEvent{AgentID: src.AgentID, Event: event}:
+		case <-ctx.Done():
+		}
+	}
+
+	// Cancellation via Close is a clean shutdown, not a source failure
+	if err := <-errs; err != nil && ctx.Err() == nil {
+		a.errors <- &AgentError{AgentID: src.AgentID, Err: err}
+	}
+}
+
+// Events returns the merged event channel
+func (a *Aggregator) Events() <-chan *AgentEvent {
+	return a.events
+}
+
+// Errors returns a channel of *AgentError, one per failed source
+func (a *Aggregator) Errors() <-chan error {
+	return a.errors
+}
+
+// Close cancels every source stream and waits for them to shut down
+func (a *Aggregator) Close() {
+	a.cancel()
+	a.wg.Wait()
+}
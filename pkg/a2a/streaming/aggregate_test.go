@@ -0,0 +1,95 @@
+package streaming
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newSSEAgent starts an agent that streams count events for name and then
+// a final done event
+func newSSEAgent(t *testing.T, name string, count int) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		for i := 0; i < count; i++ {
+			fmt.Fprintf(w, "id: %d\ndata: {\"type\":\"status\",\"data\":\"%s-%d\"}\n\n", i, name, i)
+		}
+		fmt.Fprintf(w, "id: %d\ndata: {\"type\":\"status\",\"data\":\"%s-done\",\"done\":true}\n\n", count, name)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// TestAggregate tests that events from every source are merged and tagged
+// with their agent, and that a failed source is reported without
+// affecting the others
+func TestAggregate(t *testing.T) {
+	k8s := newSSEAgent(t, "k8s", 2)
+	helm := newSSEAgent(t, "helm", 3)
+	missing := httptest.NewServer(http.NotFoundHandler())
+	defer missing.Close()
+
+	s := NewStreamClient(5 * time.Second)
+	a := s.Aggregate(context.Background(), []Source{
+		{AgentID: "k8s", URL: k8s.URL},
+		{AgentID: "helm", URL: helm.URL},
+		{AgentID: "missing", URL: missing.URL},
+	})
+	defer a.Close()
+
+	received := map[string][]interface{}{}
+	for event := range a.Events() {
+		received[event.AgentID] = append(received[event.AgentID], event.Event.Data)
+	}
+	assert.Equal(t, map[string][]interface{}{
+		"k8s":  {"k8s-0", "k8s-1", "k8s-done"},
+		"helm": {"helm-0", "helm-1", "helm-2", "helm-done"},
+	}, received)
+
+	var errs []error
+	for err := range a.Errors() {
+		errs = append(errs, err)
+	}
+	require.Len(t, errs, 1)
+	var agentErr *AgentError
+	require.True(t, errors.As(errs[0], &agentErr))
+	assert.Equal(t, "missing", agentErr.AgentID)
+	assert.ErrorContains(t, errs[0], "agent missing: unexpected status: 404")
+}
+
+// TestAggregateClose tests that closing the aggregator ends every source
+// cleanly without reporting errors
+func TestAggregateClose(t *testing.T) {
+	release := make(chan struct{})
+	blocked := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "data: {\"type\":\"status\",\"data\":\"started\"}\n\n")
+		w.(http.Flusher).Flush()
+		select {
+		case <-r.Context().Done():
+		case <-release:
+		}
+	}))
+	defer blocked.Close()
+	defer close(release)
+
+	s := NewStreamClient(5 * time.Second)
+	a := s.Aggregate(context.Background(), []Source{{AgentID: "blocked", URL: blocked.URL}})
+
+	event := <-a.Events()
+	assert.Equal(t, "blocked", event.AgentID)
+	a.Close()
+
+	for range a.Events() {
+	}
+	for err := range a.Errors() {
+		t.Errorf("unexpected error after Close: %v", err)
+	}
+}
@@ -0,0 +1,99 @@
+package streaming
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/craine-io/openribcage/pkg/a2a/types"
+)
+
+// RPCError is a JSON-RPC error delivered as a stream event. It ends the
+// stream rather than triggering a reconnect.
+type RPCError struct {
+	Code    int
+	Message string
+	Data    interface{}
+}
+
+// Error implements the error interface
+func (e *RPCError) Error() string {
+	return fmt.Sprintf("stream JSON-RPC error: %s (code: %d)", e.Message, e.Code)
+}
+
+// Unwrap exposes the error as a *types.JSONRPCErrorError for errors.As
+// and the code sentinels
+func (e *RPCError) Unwrap() error {
+	return types.NewJSONRPCErrorError("", &types.JSONRPCError{Code: e.Code, Message: e.Message, Data: e.Data})
+}
+
+// rpcFrame is a JSON-RPC response wrapping a stream event
+type rpcFrame struct {
+	JSONRPC string              `json:"jsonrpc"`
+	Result  json.RawMessage     `json:"result"`
+	Error   *types.JSONRPCError `json:"error"`
+}
+
+// DecodeEvent decodes an SSE data payload into a StreamResponse. Payloads
+// may be a bare StreamResponse or a JSON-RPC response whose result is the
+// event; a JSON-RPC error is returned as an *RPCError. Non-JSON payloads
+// are passed through as raw text. The event type defaults to the SSE event
+// name, then "message".
+func DecodeEvent(event string, data []byte) (*types.StreamResponse, error) {
+	var resp types.StreamResponse
+
+	var frame rpcFrame
+	if err := json.Unmarshal(data, &frame); err == nil && frame.JSONRPC != "" && (frame.Result != nil || frame.Error != nil) {
+		if frame.Error != nil {
+			return nil, &RPCError{Code: frame.Error.Code, Message: frame.Error.Message, Data: frame.Error.Data}
+		}
+		if err := unwrapResult(frame.Result, &resp); err != nil {
+			return nil, err
+		}
+	} else if err := json.Unmarshal(data, &resp); err != nil {
+		resp = types.StreamResponse{Data: string(data)}
+	}
+
+	if resp.Type == "" {
+		resp.Type = event
+	}
+	if resp.Type == "" {
+		resp.Type = "message"
+	}
+	if resp.Timestamp.IsZero() {
+		resp.Timestamp = time.Now()
+	}
+
+	return &resp, nil
+}
+
+// unwrapResult decodes a JSON-RPC result into resp. Results that are not
+// shaped like a StreamResponse, such as A2A status and artifact update
+// events, are carried whole in Data, and their "final" flag marks the
+// response Done.
+func unwrapResult(result json.RawMessage, resp *types.StreamResponse) error {
+	if err := json.Unmarshal(result, resp); err != nil {
+		return fmt.Errorf("failed to unmarshal stream result: %w", err)
+	}
+	if resp.Data != nil {
+		return nil
+	}
+
+	var fields map[string]interface{}
+	if err := json.Unmarshal(result, &fields); err != nil {
+		return nil
+	}
+	resp.Data = fields
+	if final, ok := fields["final"].(bool); ok && final {
+		resp.Done = true
+	}
+	if resp.Type == "" {
+		switch {
+		case fields["artifact"] != nil:
+			resp.Type = "artifact-update"
+		case fields["status"] != nil:
+			resp.Type = "status-update"
+		}
+	}
+	return nil
+}
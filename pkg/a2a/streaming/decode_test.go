@@ -0,0 +1,72 @@
+package streaming
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/craine-io/openribcage/pkg/a2a/types"
+)
+
+// TestDecodeEvent tests decoding bare, JSON-RPC wrapped and non-JSON
+// stream payloads
+func TestDecodeEvent(t *testing.T) {
+	tests := []struct {
+		name     string
+		event    string
+		data     string
+		wantType string
+		wantData interface{}
+		wantDone bool
+	}{
+		{"bare", "", `{"type":"status","data":"working"}`, "status", "working", false},
+		{"bare done", "", `{"type":"status","data":"completed","done":true}`, "status", "completed", true},
+		{"wrapped", "", `{"jsonrpc":"2.0","id":"1","result":{"type":"status","data":"working"}}`, "status", "working", false},
+		{
+			"wrapped status update", "",
+			`{"jsonrpc":"2.0","id":"1","result":{"id":"task-1","status":{"state":"completed"},"final":true}}`,
+			"status-update",
+			map[string]interface{}{"id": "task-1", "status": map[string]interface{}{"state": "completed"}, "final": true},
+			true,
+		},
+		{
+			"wrapped artifact update", "",
+			`{"jsonrpc":"2.0","id":"1","result":{"id":"task-1","artifact":{"name":"out"}}}`,
+			"artifact-update",
+			map[string]interface{}{"id": "task-1", "artifact": map[string]interface{}{"name": "out"}},
+			false,
+		},
+		{"SSE event name", "progress", `{"data":50}`, "progress", float64(50), false},
+		{"plain text", "", "hello world", "message", "hello world", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := DecodeEvent(tt.event, []byte(tt.data))
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantType, resp.Type)
+			assert.Equal(t, tt.wantData, resp.Data)
+			assert.Equal(t, tt.wantDone, resp.Done)
+			assert.False(t, resp.Timestamp.IsZero())
+		})
+	}
+}
+
+// TestDecodeEventError tests that a JSON-RPC error frame is returned as an
+// *RPCError that unwraps to the JSON-RPC error
+func TestDecodeEventError(t *testing.T) {
+	_, err := DecodeEvent("", []byte(`{"jsonrpc":"2.0","id":"1","error":{"code":-32001,"message":"task not found"}}`))
+
+	var rpcErr *RPCError
+	require.True(t, errors.As(err, &rpcErr))
+	assert.Equal(t, -32001, rpcErr.Code)
+	assert.Equal(t, "stream JSON-RPC error: task not found (code: -32001)", err.Error())
+
+	var jsonErr *types.JSONRPCErrorError
+	assert.True(t, errors.As(err, &jsonErr))
+
+	_, err = DecodeEvent("", []byte(`{"jsonrpc":"2.0","id":"1","result":"not an event"}`))
+	assert.ErrorContains(t, err, "failed to unmarshal stream result")
+}
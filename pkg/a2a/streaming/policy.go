@@ -0,0 +1,73 @@
+package streaming
+
+import (
+	"fmt"
+
+	"github.com/craine-io/openribcage/pkg/a2a/types"
+)
+
+// KnownEventTypes are the stream event types produced by A2A agents and by
+// this package
+var KnownEventTypes = map[string]bool{
+	"message":         true,
+	"status-update":   true,
+	"artifact-update": true,
+	"task":            true,
+	"reconnecting":    true,
+	EventSnapshot:     true,
+	EventDelta:        true,
+	EventState:        true,
+}
+
+// UnknownEventPolicy decides what happens to events whose type is not in
+// KnownEventTypes
+type UnknownEventPolicy string
+
+const (
+	// PassUnknown delivers unknown events like any other (the default)
+	PassUnknown UnknownEventPolicy = "pass"
+
+	// DropUnknown silently discards unknown events
+	DropUnknown UnknownEventPolicy = "drop"
+
+	// ErrorOnUnknown ends the stream with an *UnknownEventError
+	ErrorOnUnknown UnknownEventPolicy = "error"
+)
+
+// UnknownEventError is returned under ErrorOnUnknown for an event of
+// an unexpected type
+type UnknownEventError struct {
+	Type string
+}
+
+// Error implements the error interface
+func (e *UnknownEventError) Error() string {
+	return fmt.Sprintf("unknown stream event type %q", e.Type)
+}
+
+// Apply reports whether event should be delivered under the policy, or
+// returns an *UnknownEventError when the stream must fail. Done events
+// are always delivered so streams still terminate.
+func (p UnknownEventPolicy) Apply(event *types.StreamResponse) (bool, error) {
+	if KnownEventTypes[event.Type] {
+		return true, nil
+	}
+	switch p {
+	case DropUnknown:
+		return event.Done, nil
+	case ErrorOnUnknown:
+		return false, &UnknownEventError{Type: event.Type}
+	default:
+		return true, nil
+	}
+}
+
+// Validate checks that p is a supported policy; the empty policy means pass
+func (p UnknownEventPolicy) Validate() error {
+	switch p {
+	case "", PassUnknown, DropUnknown, ErrorOnUnknown:
+		return nil
+	default:
+		return fmt.Errorf("unsupported unknown event policy %q (supported: pass, drop, error)", p)
+	}
+}
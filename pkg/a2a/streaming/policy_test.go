@@ -0,0 +1,87 @@
+package streaming
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/craine-io/openribcage/pkg/a2a/types"
+)
+
+// TestUnknownEventPolicy tests which events each policy delivers
+func TestUnknownEventPolicy(t *testing.T) {
+	known := &types.StreamResponse{Type: "status-update"}
+	unknown := &types.StreamResponse{Type: "heartbeat"}
+	unknownDone := &types.StreamResponse{Type: "heartbeat", Done: true}
+
+	tests := []struct {
+		policy  UnknownEventPolicy
+		event   *types.StreamResponse
+		deliver bool
+		wantErr bool
+	}{
+		{"", unknown, true, false},
+		{PassUnknown, unknown, true, false},
+		{DropUnknown, known, true, false},
+		{DropUnknown, unknown, false, false},
+		{DropUnknown, unknownDone, true, false},
+		{ErrorOnUnknown, known, true, false},
+		{ErrorOnUnknown, unknown, false, true},
+	}
+	for _, tt := range tests {
+		deliver, err := tt.policy.Apply(tt.event)
+		assert.Equal(t, tt.deliver, deliver, "%s %+v", tt.policy, tt.event)
+		if tt.wantErr {
+			assert.Equal(t, &UnknownEventError{Type: "heartbeat"}, err)
+		} else {
+			assert.NoError(t, err)
+		}
+	}
+
+	for _, policy := range []UnknownEventPolicy{"", PassUnknown, DropUnknown, ErrorOnUnknown} {
+		assert.NoError(t, policy.Validate())
+	}
+	assert.ErrorContains(t, UnknownEventPolicy("ignore").Validate(), `unsupported unknown event policy "ignore"`)
+}
+
+// TestSubscribeUnknownEvents tests applying the policy to a stream
+func TestSubscribeUnknownEvents(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "data: {\"type\":\"heartbeat\"}\n\n")
+		fmt.Fprint(w, "data: {\"type\":\"status-update\",\"data\":\"done\",\"done\":true}\n\n")
+	}))
+	defer server.Close()
+
+	collect := func(policy UnknownEventPolicy) ([]string, error) {
+		s := NewStreamClient(5 * time.Second)
+		s.SetUnknownEventPolicy(policy)
+		events, errs := s.Subscribe(context.Background(), server.URL, nil)
+		var received []string
+		for event := range events {
+			received = append(received, event.Type)
+		}
+		return received, <-errs
+	}
+
+	received, err := collect(PassUnknown)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"heartbeat", "status-update"}, received)
+
+	received, err = collect(DropUnknown)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"status-update"}, received)
+
+	received, err = collect(ErrorOnUnknown)
+	assert.Empty(t, received)
+	var unknownErr *UnknownEventError
+	require.True(t, errors.As(err, &unknownErr))
+	assert.Equal(t, "heartbeat", unknownErr.Type)
+}
@@ -0,0 +1,55 @@
+package streaming
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// DefaultMaxLineSize is the default limit on a single SSE line, such as a
+// "data:" line carrying a large JSON payload
+const DefaultMaxLineSize = 4 << 20
+
+// LineTooLongError is returned when an SSE line exceeds the configured limit
+type LineTooLongError struct {
+	Limit int
+}
+
+// Error implements the error interface
+func (e *LineTooLongError) Error() string {
+	return fmt.Sprintf("SSE line exceeds the maximum size of %d bytes; raise the stream line limit to accept larger events", e.Limit)
+}
+
+// Unwrap returns bufio.ErrTooLong
+func (e *LineTooLongError) Unwrap() error {
+	return bufio.ErrTooLong
+}
+
+// NewLineScanner returns a scanner over the lines of an SSE stream
+// accepting lines up to maxSize bytes (DefaultMaxLineSize when zero)
+func NewLineScanner(r io.Reader, maxSize int) *bufio.Scanner {
+	if maxSize <= 0 {
+		maxSize = DefaultMaxLineSize
+	}
+	initial := bufio.MaxScanTokenSize
+	if initial > maxSize {
+		initial = maxSize
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, initial), maxSize)
+	return scanner
+}
+
+// ScanError converts bufio.ErrTooLong from a scanner created with
+// NewLineScanner into a *LineTooLongError; other errors are returned as-is
+func ScanError(err error, maxSize int) error {
+	if !errors.Is(err, bufio.ErrTooLong) {
+		return err
+	}
+	if maxSize <= 0 {
+		maxSize = DefaultMaxLineSize
+	}
+	return &LineTooLongError{Limit: maxSize}
+}
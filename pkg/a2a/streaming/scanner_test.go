@@ -0,0 +1,60 @@
+package streaming
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestLineScanner tests the line size limit and its error
+func TestLineScanner(t *testing.T) {
+	scanner := NewLineScanner(strings.NewReader("short\n"+strings.Repeat("x", 64)+"\n"), 32)
+	require.True(t, scanner.Scan())
+	assert.Equal(t, "short", scanner.Text())
+	assert.False(t, scanner.Scan())
+
+	err := ScanError(scanner.Err(), 32)
+	var tooLong *LineTooLongError
+	require.True(t, errors.As(err, &tooLong))
+	assert.Equal(t, 32, tooLong.Limit)
+	assert.ErrorIs(t, err, bufio.ErrTooLong)
+	assert.ErrorContains(t, err, "maximum size of 32 bytes")
+
+	assert.Equal(t, &LineTooLongError{Limit: DefaultMaxLineSize}, ScanError(bufio.ErrTooLong, 0))
+	other := errors.New("connection reset")
+	assert.Equal(t, other, ScanError(other, 32))
+
+	// Lines beyond bufio.MaxScanTokenSize are accepted by default
+	long := strings.Repeat("x", 2*bufio.MaxScanTokenSize)
+	scanner = NewLineScanner(strings.NewReader(long+"\n"), 0)
+	require.True(t, scanner.Scan())
+	assert.Equal(t, long, scanner.Text())
+}
+
+// TestSubscribeMaxLineSize tests that an oversized event ends the stream
+// with a LineTooLongError instead of reconnecting
+func TestSubscribeMaxLineSize(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprintf(w, "data: {\"type\":\"status\",\"data\":\"%s\"}\n\n", strings.Repeat("x", 256))
+	}))
+	defer server.Close()
+
+	s := NewStreamClient(5 * time.Second)
+	s.SetMaxLineSize(128)
+	events, errs := s.Subscribe(context.Background(), server.URL, nil)
+	for ev := range events {
+		t.Fatalf("unexpected event %+v", ev)
+	}
+	var tooLong *LineTooLongError
+	assert.True(t, errors.As(<-errs, &tooLong))
+}
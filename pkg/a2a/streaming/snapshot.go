@@ -0,0 +1,172 @@
+package streaming
+
+import (
+	"context"
+	"strings"
+
+	"github.com/craine-io/openribcage/pkg/a2a/types"
+)
+
+// Event types used by Reconstruct
+const (
+	// EventSnapshot carries the full state of a task
+	EventSnapshot = "snapshot"
+
+	// EventDelta carries a change to apply onto the current state
+	EventDelta = "delta"
+
+	// EventState is emitted by Reconstruct with the full current state
+	EventState = "state"
+)
+
+// MergeFunc applies a delta onto the current state and returns the new
+// state. Implementations must not modify state or delta.
+type MergeFunc func(state, delta interface{}) interface{}
+
+// MergePatch applies delta to state as a JSON Merge Patch (RFC 7396):
+// objects are merged recursively, null removes a field and any other value
+// replaces it
+func MergePatch(state, delta interface{}) interface{} {
+	patch, ok := delta.(map[string]interface{})
+	if !ok {
+		return copyValue(delta)
+	}
+
+	target, ok := state.(map[string]interface{})
+	if !ok {
+		target = map[string]interface{}{}
+	}
+	merged := make(map[string]interface{}, len(target)+len(patch))
+	for k, v := range target {
+		merged[k] = copyValue(v)
+	}
+	for k, v := range patch {
+		if v == nil {
+			delete(merged, k)
+			continue
+		}
+		merged[k] = MergePatch(merged[k], v)
+	}
+	return merged
+}
+
+// Overlay replaces the top-level fields of state with those in delta,
+// without merging nested objects or removing fields
+func Overlay(state, delta interface{}) interface{} {
+	patch, ok := delta.(map[string]interface{})
+	if !ok {
+		return copyValue(delta)
+	}
+
+	merged := map[string]interface{}{}
+	if target, ok := state.(map[string]interface{}); ok {
+		for k, v := range target {
+			merged[k] = copyValue(v)
+		}
+	}
+	for k, v := range patch {
+		merged[k] = copyValue(v)
+	}
+	return merged
+}
+
+// Reconstruct rebuilds the full state of a stream that sends an initial
+// snapshot followed by deltas. After each snapshot or delta event it
+// emits an EventState event whose Data is the complete current state;
+// deltas are applied with merge (MergePatch when nil). Other events are
+// passed through unchanged. The returned channel is closed when events
+// is closed or ctx is done.
+func Reconstruct(ctx context.Context, events <-chan *types.StreamResponse, merge MergeFunc) <-chan *types.StreamResponse {
+	if merge == nil {
+		merge = MergePatch
+	}
+	out := make(chan *types.StreamResponse)
+
+	go func() {
+		defer close(out)
+
+		var state interface{}
+		for {
+			var event *types.StreamResponse
+			select {
+			case <-ctx.Done():
+				return
+			case e, ok := <-events:
+				if !ok {
+					return
+				}
+				event = e
+			}
+
+			switch eventKind(event.Type) {
+			case EventSnapshot:
+				state = copyValue(event.Data)
+			case EventDelta:
+				state = merge(state, event.Data)
+			default:
+				if !send(ctx, out, event) {
+					return
+				}
+				continue
+			}
+
+			full := *event
+			full.Type = EventState
+			full.Data = copyValue(state)
+			if !send(ctx, out, &full) {
+				return
+			}
+		}
+	}()
+
+	return out
+}
+
+// eventKind classifies an event type as a snapshot, a delta or neither.
+// Namespaced types such as "task-snapshot", "task.delta" and "state_patch"
+// are accepted; the kind must follow a '-', '_', '.' or ':' so that types
+// such as "dispatch" are not mistaken for patches.
+func eventKind(eventType string) string {
+	t := strings.ToLower(eventType)
+	if i := strings.LastIndexAny(t, "-_.:"); i >= 0 {
+		t = t[i+1:]
+	}
+	switch t {
+	case EventSnapshot:
+		return EventSnapshot
+	case EventDelta, "patch":
+		return EventDelta
+	default:
+		return ""
+	}
+}
+
+// send delivers an event unless ctx is done first
+func send(ctx context.Context, out chan<- *types.StreamResponse, event *types.StreamResponse) bool {
+	select {
+	case out <- event:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// copyValue deep-copies decoded JSON objects and arrays
+func copyValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		cp := make(map[string]interface{}, len(v))
+		for k, child := range v {
+			cp[k] = copyValue(child)
+		}
+		return cp
+	case []interface{}:
+		cp := make([]interface{}, len(v))
+		for i, child := range v {
+			cp[i] = copyValue(child)
+		}
+		return cp
+	default:
+		return v
+	}
+}
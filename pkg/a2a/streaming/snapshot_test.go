@@ -0,0 +1,122 @@
+package streaming
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/craine-io/openribcage/pkg/a2a/types"
+)
+
+// reconstruct feeds events through Reconstruct and collects its output
+func reconstruct(merge MergeFunc, events ...*types.StreamResponse) []*types.StreamResponse {
+	in := make(chan *types.StreamResponse, len(events))
+	for _, event := range events {
+		in <- event
+	}
+	close(in)
+
+	var out []*types.StreamResponse
+	for event := range Reconstruct(context.Background(), in, merge) {
+		out = append(out, event)
+	}
+	return out
+}
+
+// TestReconstruct tests that deltas are merged onto the snapshot, nested
+// objects included, and that other events pass through
+func TestReconstruct(t *testing.T) {
+	snapshot := map[string]interface{}{
+		"status": "working",
+		"result": map[string]interface{}{"pods": []interface{}{"a", "b"}, "ns": "default"},
+	}
+	out := reconstruct(nil,
+		&types.StreamResponse{Type: "task-snapshot", Data: snapshot},
+		&types.StreamResponse{Type: "status", Data: "heartbeat"},
+		&types.StreamResponse{Type: "task.delta", Data: map[string]interface{}{
+			"result": map[string]interface{}{"pods": []interface{}{"c"}, "ns": nil},
+		}},
+		&types.StreamResponse{Type: "state_patch", Data: map[string]interface{}{"status": "completed"}, Done: true},
+	)
+
+	require.Len(t, out, 4)
+	assert.Equal(t, EventState, out[0].Type)
+	assert.Equal(t, snapshot, out[0].Data)
+	assert.Equal(t, "status", out[1].Type)
+	assert.Equal(t, "heartbeat", out[1].Data)
+	assert.Equal(t, map[string]interface{}{
+		"status": "working",
+		"result": map[string]interface{}{"pods": []interface{}{"c"}},
+	}, out[2].Data)
+	assert.Equal(t, map[string]interface{}{
+		"status": "completed",
+		"result": map[string]interface{}{"pods": []interface{}{"c"}},
+	}, out[3].Data)
+	assert.True(t, out[3].Done)
+
+	// Emitted states are copies: the snapshot and earlier states are untouched
+	assert.Equal(t, []interface{}{"a", "b"}, snapshot["result"].(map[string]interface{})["pods"])
+	out[2].Data.(map[string]interface{})["result"].(map[string]interface{})["pods"].([]interface{})[0] = "z"
+	assert.Equal(t, []interface{}{"c"}, out[3].Data.(map[string]interface{})["result"].(map[string]interface{})["pods"])
+}
+
+// TestReconstructOverlay tests that Overlay replaces nested objects whole
+func TestReconstructOverlay(t *testing.T) {
+	out := reconstruct(Overlay,
+		&types.StreamResponse{Type: EventSnapshot, Data: map[string]interface{}{
+			"status": "working",
+			"result": map[string]interface{}{"ns": "default", "pods": 2.0},
+		}},
+		&types.StreamResponse{Type: EventDelta, Data: map[string]interface{}{
+			"result": map[string]interface{}{"pods": 3.0},
+		}},
+	)
+
+	require.Len(t, out, 2)
+	assert.Equal(t, map[string]interface{}{
+		"status": "working",
+		"result": map[string]interface{}{"pods": 3.0},
+	}, out[1].Data)
+}
+
+// TestEventKind tests that only whole, delimiter-qualified kinds are
+// recognised
+func TestEventKind(t *testing.T) {
+	tests := map[string]string{
+		"snapshot":      EventSnapshot,
+		"Task-Snapshot": EventSnapshot,
+		"delta":         EventDelta,
+		"task.delta":    EventDelta,
+		"state_patch":   EventDelta,
+		"a2a:patch":     EventDelta,
+		"dispatch":      "",
+		"nodelta":       "",
+		"presnapshot":   "",
+		"status":        "",
+	}
+	for eventType, want := range tests {
+		assert.Equal(t, want, eventKind(eventType), eventType)
+	}
+}
+
+// TestReconstructCancel tests that Reconstruct stops when ctx is done even
+// though events stays open
+func TestReconstructCancel(t *testing.T) {
+	in := make(chan *types.StreamResponse)
+	ctx, cancel := context.WithCancel(context.Background())
+	out := Reconstruct(ctx, in, nil)
+
+	in <- &types.StreamResponse{Type: EventSnapshot, Data: map[string]interface{}{"status": "working"}}
+	assert.Equal(t, EventState, (<-out).Type)
+
+	cancel()
+	select {
+	case _, ok := <-out:
+		assert.False(t, ok)
+	case <-time.After(time.Second):
+		t.Fatal("Reconstruct did not stop after cancellation")
+	}
+}
@@ -7,34 +7,103 @@ package streaming
 
 import (
 	"context"
+	"crypto/tls"
+	"errors"
 	"fmt"
+	"io"
+	"net"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/sirupsen/logrus"
 
+	"github.com/craine-io/openribcage/internal/auth"
 	"github.com/craine-io/openribcage/pkg/a2a/types"
+	"github.com/craine-io/openribcage/pkg/metrics"
 )
 
 // StreamClient handles A2A Server-Sent Events streaming
 type StreamClient struct {
-	client  *http.Client
-	logger  *logrus.Logger
-	timeout time.Duration
+	client        *http.Client
+	logger        *logrus.Logger
+	timeout       time.Duration
+	maxReconnects int
+	retryDelay    time.Duration
+	maxLineSize   int
+	unknown       UnknownEventPolicy
+	metrics       *metrics.Metrics
 }
 
-// NewStreamClient creates a new A2A streaming client
+// NewStreamClient creates a new A2A streaming client. The timeout bounds
+// connecting and waiting for response headers, not the stream itself,
+// so long-lived streams are not cut off.
 func NewStreamClient(timeout time.Duration) *StreamClient {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = (&net.Dialer{Timeout: timeout, KeepAlive: 30 * time.Second}).DialContext
+	transport.TLSHandshakeTimeout = timeout
+	transport.ResponseHeaderTimeout = timeout
+
 	return &StreamClient{
-		client: &http.Client{
-			Timeout: timeout,
-		},
-		logger:  logrus.New(),
-		timeout: timeout,
+		client:        &http.Client{Transport: transport},
+		logger:        logrus.New(),
+		timeout:       timeout,
+		maxReconnects: 5,
+		retryDelay:    3 * time.Second,
+	}
+}
+
+// SetHTTPClient replaces the http.Client used for streaming requests, e.g.
+// to supply a custom transport or test double. A nil client is ignored.
+func (s *StreamClient) SetHTTPClient(client *http.Client) {
+	if client != nil {
+		s.client = client
+	}
+}
+
+// SetTLSConfig applies cfg, e.g. a client certificate for mutual TLS, to
+// the transport used for streaming requests. A custom transport supplied through
+// SetHTTPClient that is not an *http.Transport is an error.
+func (s *StreamClient) SetTLSConfig(cfg *tls.Config) error {
+	transport, err := auth.TLSTransport(s.client.Transport, cfg)
+	if err != nil {
+		return err
 	}
+	client := *s.client
+	client.Transport = transport
+	s.client = &client
+	return nil
+}
+
+// SetMaxLineSize sets the largest SSE line accepted, in bytes. Zero
+// restores DefaultMaxLineSize.
+func (s *StreamClient) SetMaxLineSize(n int) {
+	s.maxLineSize = n
 }
 
-// Subscribe subscribes to an A2A agent's streaming endpoint
+// SetUnknownEventPolicy sets how events of unknown types are handled.
+// The default is PassUnknown.
+func (s *StreamClient) SetUnknownEventPolicy(policy UnknownEventPolicy) {
+	s.unknown = policy
+}
+
+// SetMetrics records reconnection attempts in m. A nil m disables metrics.
+func (s *StreamClient) SetMetrics(m *metrics.Metrics) {
+	s.metrics = m
+}
+
+// SetMaxReconnects sets how many consecutive reconnection attempts are
+// made after a stream drops. Zero disables reconnection.
+func (s *StreamClient) SetMaxReconnects(n int) {
+	s.maxReconnects = n
+}
+
+// Subscribe subscribes to an A2A agent's streaming endpoint.
+//
+// If the connection drops before a Done event is received, the stream is
+// transparently resumed using the Last-Event-ID header. A synthetic
+// "reconnecting" event is emitted before each attempt.
 func (s *StreamClient) Subscribe(ctx context.Context, url string, headers map[string]string) (<-chan *types.StreamResponse, <-chan error) {
 	responseChan := make(chan *types.StreamResponse)
 	errorChan := make(chan error, 1)
@@ -45,42 +114,263 @@ func (s *StreamClient) Subscribe(ctx context.Context, url string, headers map[st
 
 		s.logger.Debugf("Subscribing to A2A stream: %s", url)
 
-		// TODO: Implement SSE streaming
-		// This will be implemented in Issue #10
-		// 1. Create HTTP request with Accept: text/event-stream
-		// 2. Parse SSE events from response stream
-		// 3. Convert to StreamResponse objects
-		// 4. Handle connection errors and reconnection
+		resp, err := s.connect(ctx, url, headers, "")
+		if err != nil {
+			errorChan <- err
+			return
+		}
 
-		// Placeholder implementation
-		select {
-		case <-ctx.Done():
-			errorChan <- ctx.Err()
-		case <-time.After(1 * time.Second):
-			errorChan <- fmt.Errorf("SSE streaming not yet implemented")
+		state := &streamState{retry: s.retryDelay}
+		attempts := 0
+		for {
+			received := state.received
+			err := s.readEvents(ctx, resp.Body, state, responseChan)
+			resp.Body.Close()
+
+			if ctx.Err() != nil {
+				errorChan <- ctx.Err()
+				return
+			}
+			var rpcErr *RPCError
+			var unknownErr *UnknownEventError
+			var tooLong *LineTooLongError
+			if errors.As(err, &rpcErr) || errors.As(err, &unknownErr) || errors.As(err, &tooLong) {
+				errorChan <- err
+				return
+			}
+			if state.done {
+				return
+			}
+			if err != nil {
+				s.logger.Warnf("A2A stream interrupted: %v", err)
+			}
+
+			// Only count consecutive failures without progress
+			if state.received > received {
+				attempts = 0
+			}
+
+			resp, err = s.reconnectWithRetry(ctx, url, headers, state, &attempts, responseChan)
+			if err != nil {
+				errorChan <- err
+				return
+			}
 		}
 	}()
 
 	return responseChan, errorChan
 }
 
-// parseSSEEvent parses a Server-Sent Events line
-func (s *StreamClient) parseSSEEvent(line string) (*types.StreamResponse, error) {
-	// TODO: Implement SSE event parsing
-	// This will be implemented in Issue #10
-	// Parse lines like:
-	// data: {"id": "task-123", "type": "progress", "data": {...}}
-	// event: task_update
-	// id: 12345
+// reconnectWithRetry keeps attempting to reconnect until it succeeds, hits a
+// permanent error, or exhausts the configured attempts
+func (s *StreamClient) reconnectWithRetry(ctx context.Context, url string, headers map[string]string,
+	state *streamState, attempts *int, out chan<- *types.StreamResponse) (*http.Response, error) {
+	for {
+		*attempts++
+		if *attempts > s.maxReconnects {
+			return nil, fmt.Errorf("stream lost after %d reconnection attempts", s.maxReconnects)
+		}
+		s.metrics.ObserveReconnect()
+
+		notice := &types.StreamResponse{
+			Timestamp: time.Now(),
+			Type:      "reconnecting",
+			Data: map[string]interface{}{
+				"attempt":     *attempts,
+				"lastEventId": state.lastEventID,
+			},
+		}
+		select {
+		case out <- notice:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+
+		resp, err := s.reconnect(ctx, url, headers, state)
+		if err == nil {
+			return resp, nil
+		}
+
+		var statusErr *statusError
+		if errors.As(err, &statusErr) && statusErr.permanent() {
+			return nil, err
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		s.logger.Warnf("Reconnection attempt %d/%d failed: %v", *attempts, s.maxReconnects, err)
+	}
+}
+
+// connect opens an SSE connection, resuming after lastEventID when set
+func (s *StreamClient) connect(ctx context.Context, url string, headers map[string]string, lastEventID string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Accept", "text/event-stream")
+	req.Header.Set("Cache-Control", "no-cache")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("stream request failed: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, &statusError{code: resp.StatusCode, status: resp.Status}
+	}
+
+	return resp, nil
+}
+
+// statusError reports a non-200 response from a streaming endpoint
+type statusError struct {
+	code   int
+	status string
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("unexpected status: %s", e.status)
+}
+
+// permanent reports whether retrying the request cannot succeed
+func (e *statusError) permanent() bool {
+	return e.code >= 400 && e.code < 500
+}
+
+// streamState tracks per-stream SSE state that outlives a single event
+// and therefore survives reconnection
+type streamState struct {
+	lastEventID string
+	retry       time.Duration
+	received    int
+	done        bool
+}
 
-	return nil, fmt.Errorf("SSE parsing not yet implemented")
+// sseEvent accumulates the fields of a single Server-Sent Event
+type sseEvent struct {
+	event string
+	data  []string
+
+	// id is only recorded as the stream's last event ID once the event
+	// is dispatched, so a frame cut off mid-way is not skipped on resume
+	id    string
+	hasID bool
 }
 
-// reconnect handles reconnection logic for streaming
-func (s *StreamClient) reconnect(ctx context.Context, url string, headers map[string]string, lastEventID string) error {
-	// TODO: Implement reconnection logic
-	// This will be implemented in Issue #10
-	// Use Last-Event-ID header for resuming streams
+// readEvents reads SSE lines from r, assembling complete events and
+// emitting them on out until the stream ends or ctx is cancelled.
+// Incomplete trailing events are discarded, as required by the SSE spec.
+func (s *StreamClient) readEvents(ctx context.Context, r io.Reader, state *streamState, out chan<- *types.StreamResponse) error {
+	scanner := NewLineScanner(r, s.maxLineSize)
+	ev := &sseEvent{}
+
+	for scanner.Scan() {
+		line := strings.TrimSuffix(scanner.Text(), "\r")
+
+		if line != "" {
+			parseSSELine(ev, state, line)
+			continue
+		}
+
+		// A blank line terminates the event
+		if ev.hasID {
+			state.lastEventID = ev.id
+		}
+		resp, err := s.parseSSEEvent(ev)
+		ev = &sseEvent{}
+		if err != nil {
+			return err
+		}
+		if resp == nil {
+			continue
+		}
+		deliver, err := s.unknown.Apply(resp)
+		if err != nil {
+			return err
+		}
+		if !deliver {
+			continue
+		}
+
+		select {
+		case out <- resp:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+
+		state.received++
+		if resp.Done {
+			state.done = true
+			return nil
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return fmt.Errorf("failed to read stream: %w", ScanError(err, s.maxLineSize))
+	}
+	return nil
+}
+
+// parseSSELine applies a single non-blank SSE line to the event being assembled
+func parseSSELine(ev *sseEvent, state *streamState, line string) {
+	// Lines starting with a colon are comments
+	if strings.HasPrefix(line, ":") {
+		return
+	}
+
+	field, value, found := strings.Cut(line, ":")
+	if found {
+		value = strings.TrimPrefix(value, " ")
+	}
+
+	switch field {
+	case "event":
+		ev.event = value
+	case "data":
+		ev.data = append(ev.data, value)
+	case "id":
+		// IDs containing NULL are ignored per the SSE spec
+		if !strings.ContainsRune(value, 0) {
+			ev.id, ev.hasID = value, true
+		}
+	case "retry":
+		if ms, err := strconv.Atoi(value); err == nil && ms >= 0 {
+			state.retry = time.Duration(ms) * time.Millisecond
+		}
+	}
+}
+
+// parseSSEEvent converts an assembled SSE event into a StreamResponse.
+// Events without data are not dispatched and yield a nil response.
+func (s *StreamClient) parseSSEEvent(ev *sseEvent) (*types.StreamResponse, error) {
+	if len(ev.data) == 0 {
+		return nil, nil
+	}
+	return DecodeEvent(ev.event, []byte(strings.Join(ev.data, "\n")))
+}
+
+// reconnect waits for the server-provided retry interval and re-opens
+// the stream from the last event seen
+func (s *StreamClient) reconnect(ctx context.Context, url string, headers map[string]string, state *streamState) (*http.Response, error) {
+	s.logger.Debugf("Reconnecting to A2A stream %s in %s (Last-Event-ID: %q)", url, state.retry, state.lastEventID)
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-time.After(state.retry):
+	}
 
-	return fmt.Errorf("SSE reconnection not yet implemented")
+	return s.connect(ctx, url, headers, state.lastEventID)
 }
@@ -0,0 +1,106 @@
+package streaming
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSubscribeResumesIncompleteEvent tests that a connection dropped
+// between an event's id and its terminating blank line resumes from the
+// last event that was actually dispatched
+func TestSubscribeResumesIncompleteEvent(t *testing.T) {
+	var mu sync.Mutex
+	var lastEventIDs []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		lastEventIDs = append(lastEventIDs, r.Header.Get("Last-Event-ID"))
+		attempt := len(lastEventIDs)
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		if attempt == 1 {
+			fmt.Fprint(w, "retry: 10\nid: 1\ndata: {\"type\":\"status\",\"data\":\"one\"}\n\n")
+			fmt.Fprint(w, "id: 2\ndata: {\"type\":\"status\",\"data\":\"two\"}\n")
+			return
+		}
+		fmt.Fprint(w, "id: 2\ndata: {\"type\":\"status\",\"data\":\"two\",\"done\":true}\n\n")
+	}))
+	defer server.Close()
+
+	s := NewStreamClient(5 * time.Second)
+	events, errs := s.Subscribe(context.Background(), server.URL, nil)
+	var received []interface{}
+	for event := range events {
+		if event.Type != "reconnecting" {
+			received = append(received, event.Data)
+		}
+	}
+	require.NoError(t, <-errs)
+	assert.Equal(t, []interface{}{"one", "two"}, received)
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []string{"", "1"}, lastEventIDs)
+}
+
+// TestSubscribeOutlivesTimeout tests that the client timeout does not cut
+// off a stream that stays open longer than it
+func TestSubscribeOutlivesTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		for i := 0; i < 3; i++ {
+			fmt.Fprintf(w, "data: {\"type\":\"status\",\"data\":%d,\"done\":%t}\n\n", i, i == 2)
+			w.(http.Flusher).Flush()
+			time.Sleep(60 * time.Millisecond)
+		}
+	}))
+	defer server.Close()
+
+	s := NewStreamClient(100 * time.Millisecond)
+	events, errs := s.Subscribe(context.Background(), server.URL, nil)
+	var kinds []string
+	for event := range events {
+		kinds = append(kinds, event.Type)
+	}
+	require.NoError(t, <-errs)
+	assert.Equal(t, []string{"status", "status", "status"}, kinds)
+}
+
+// TestSetHTTPClient tests that an injected http.Client carries stream
+// requests
+func TestSetHTTPClient(t *testing.T) {
+	agent := newSSEAgent(t, "k8s", 1)
+	var mu sync.Mutex
+	requests := 0
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = func(req *http.Request) (*url.URL, error) {
+		mu.Lock()
+		requests++
+		mu.Unlock()
+		return nil, nil
+	}
+
+	s := NewStreamClient(5 * time.Second)
+	s.SetHTTPClient(&http.Client{Transport: transport})
+	s.SetHTTPClient(nil)
+	events, errs := s.Subscribe(context.Background(), agent.URL, nil)
+	var received []interface{}
+	for event := range events {
+		received = append(received, event.Data)
+	}
+	require.NoError(t, <-errs)
+	assert.Equal(t, []interface{}{"k8s-0", "k8s-done"}, received)
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, 1, requests)
+}
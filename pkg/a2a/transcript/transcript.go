@@ -0,0 +1,106 @@
+// Package transcript provides recorded A2A exchanges and their replay.
+//
+// A transcript is an ordered list of JSON-RPC calls made to an agent
+// together with the responses received. Replaying a transcript against a
+// live agent and diffing the responses is a cheap regression test after
+// agent upgrades.
+package transcript
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/craine-io/openribcage/pkg/a2a/types"
+	"github.com/craine-io/openribcage/pkg/jsondiff"
+)
+
+// DefaultIgnore lists volatile fields ignored when diffing replayed responses
+var DefaultIgnore = []string{"id", "timestamp", "started_at", "completed_at"}
+
+// Transcript is an ordered recording of JSON-RPC exchanges with an agent
+type Transcript struct {
+	Agent   string  `json:"agent,omitempty"`
+	Entries []Entry `json:"entries"`
+}
+
+// Entry is a single recorded JSON-RPC call and its response
+type Entry struct {
+	Method string              `json:"method"`
+	Params json.RawMessage     `json:"params,omitempty"`
+	Result json.RawMessage     `json:"result,omitempty"`
+	Error  *types.JSONRPCError `json:"error,omitempty"`
+}
+
+// Load reads a transcript from a JSON file
+func Load(path string) (*Transcript, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read transcript: %w", err)
+	}
+
+	var t Transcript
+	if err := json.Unmarshal(data, &t); err != nil {
+		return nil, fmt.Errorf("failed to parse transcript %s: %w", path, err)
+	}
+	return &t, nil
+}
+
+// Caller sends a raw JSON-RPC call to an agent
+type Caller interface {
+	Call(ctx context.Context, agentID, method string, params interface{}) (*types.JSONRPCResponse, error)
+}
+
+// Divergence describes how a replayed entry differed from its recording
+type Divergence struct {
+	Index       int                   `json:"index"`
+	Method      string                `json:"method"`
+	Differences []jsondiff.Difference `json:"differences,omitempty"`
+	Error       string                `json:"error,omitempty"`
+}
+
+// Replay re-sends every entry in order and diffs the live responses
+// against the recorded ones. Fields matching ignore are excluded from the
+// comparison (see jsondiff.Compare). Only divergent entries are returned.
+func Replay(ctx context.Context, caller Caller, agentID string, t *Transcript, ignore []string) ([]Divergence, error) {
+	var divergences []Divergence
+
+	for i, entry := range t.Entries {
+		if err := ctx.Err(); err != nil {
+			return divergences, err
+		}
+
+		var params interface{}
+		if len(entry.Params) > 0 {
+			params = entry.Params
+		}
+
+		resp, err := caller.Call(ctx, agentID, entry.Method, params)
+		if err != nil {
+			divergences = append(divergences, Divergence{Index: i, Method: entry.Method, Error: err.Error()})
+			continue
+		}
+
+		diffs, err := compareEntry(entry, resp, ignore)
+		if err != nil {
+			divergences = append(divergences, Divergence{Index: i, Method: entry.Method, Error: err.Error()})
+			continue
+		}
+		if len(diffs) > 0 {
+			divergences = append(divergences, Divergence{Index: i, Method: entry.Method, Differences: diffs})
+		}
+	}
+
+	return divergences, nil
+}
+
+// compareEntry diffs a live response against a recorded entry
+func compareEntry(entry Entry, resp *types.JSONRPCResponse, ignore []string) ([]jsondiff.Difference, error) {
+	if entry.Error != nil || resp.Error != nil {
+		recorded, _ := json.Marshal(entry.Error)
+		live, _ := json.Marshal(resp.Error)
+		return jsondiff.Compare(recorded, live, append([]string{"data"}, ignore...))
+	}
+	return jsondiff.Compare(entry.Result, resp.Result, ignore)
+}
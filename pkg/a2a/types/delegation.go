@@ -0,0 +1,94 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Delegation is a sub-task a coordinator agent handed to another agent.
+// The client can follow up on it by querying TaskID at AgentURL.
+type Delegation struct {
+	TaskID   string    `json:"taskId"`
+	Agent    string    `json:"agent,omitempty"`
+	AgentURL string    `json:"agentUrl,omitempty"`
+	Status   TaskState `json:"status,omitempty"`
+}
+
+// UnmarshalJSON accepts the field spellings used by common coordinators:
+// "taskId", "task_id" or "id" for the task, "agent", "agentId" or
+// "agentName" for the agent, and "agentUrl" or "url" for its endpoint
+func (d *Delegation) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		TaskID     string    `json:"taskId"`
+		TaskIDAlt  string    `json:"task_id"`
+		ID         string    `json:"id"`
+		Agent      string    `json:"agent"`
+		AgentID    string    `json:"agentId"`
+		AgentName  string    `json:"agentName"`
+		AgentURL   string    `json:"agentUrl"`
+		URL        string    `json:"url"`
+		Status     TaskState `json:"status"`
+		StateField TaskState `json:"state"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return fmt.Errorf("invalid delegation: %w", err)
+	}
+
+	*d = Delegation{
+		TaskID:   firstNonEmpty(raw.TaskID, raw.TaskIDAlt, raw.ID),
+		Agent:    firstNonEmpty(raw.Agent, raw.AgentID, raw.AgentName),
+		AgentURL: firstNonEmpty(raw.AgentURL, raw.URL),
+		Status:   TaskState(firstNonEmpty(string(raw.Status), string(raw.StateField))),
+	}
+	return nil
+}
+
+// firstNonEmpty returns the first non-empty string
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// delegationKeys are the fields that list a coordinator's sub-tasks
+var delegationKeys = []string{"delegations", "subTasks", "sub_tasks"}
+
+// findDelegations looks for sub-tasks in a result object, then in its
+// metadata. Entries without a task ID are dropped.
+func findDelegations(result map[string]json.RawMessage) ([]Delegation, error) {
+	raw := lookupDelegations(result)
+	if raw == nil {
+		var metadata map[string]json.RawMessage
+		if json.Unmarshal(result["metadata"], &metadata) == nil {
+			raw = lookupDelegations(metadata)
+		}
+	}
+	if raw == nil {
+		return nil, nil
+	}
+
+	var all []Delegation
+	if err := json.Unmarshal(raw, &all); err != nil {
+		return nil, err
+	}
+	delegations := all[:0]
+	for _, d := range all {
+		if d.TaskID != "" {
+			delegations = append(delegations, d)
+		}
+	}
+	return delegations, nil
+}
+
+// lookupDelegations returns the first delegation list present in fields
+func lookupDelegations(fields map[string]json.RawMessage) json.RawMessage {
+	for _, key := range delegationKeys {
+		if raw, ok := fields[key]; ok && string(raw) != "null" {
+			return raw
+		}
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestDelegationUnmarshal tests the accepted field spellings
+func TestDelegationUnmarshal(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+		want Delegation
+	}{
+		{"canonical", `{"taskId":"t1","agent":"helm","agentUrl":"http://helm","status":"working"}`, Delegation{TaskID: "t1", Agent: "helm", AgentURL: "http://helm", Status: TaskStateWorking}},
+		{"snake case", `{"task_id":"t1","agentId":"helm","url":"http://helm","state":"completed"}`, Delegation{TaskID: "t1", Agent: "helm", AgentURL: "http://helm", Status: TaskStateCompleted}},
+		{"id and name", `{"id":"t1","agentName":"helm"}`, Delegation{TaskID: "t1", Agent: "helm"}},
+		{"canonical wins", `{"taskId":"t1","id":"t2","agent":"helm","agentId":"k8s"}`, Delegation{TaskID: "t1", Agent: "helm"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got Delegation
+			require.NoError(t, json.Unmarshal([]byte(tt.json), &got))
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	var d Delegation
+	assert.ErrorContains(t, json.Unmarshal([]byte(`"t1"`), &d), "invalid delegation")
+}
+
+// TestTaskResponseDelegations tests collecting sub-tasks from the result
+// and its metadata
+func TestTaskResponseDelegations(t *testing.T) {
+	want := []Delegation{{TaskID: "t1", Agent: "helm"}, {TaskID: "t2", Agent: "k8s"}}
+	tests := []struct {
+		name string
+		json string
+		want []Delegation
+	}{
+		{"delegations", `{"id":"c1","delegations":[{"taskId":"t1","agent":"helm"},{"taskId":"t2","agent":"k8s"}]}`, want},
+		{"subTasks", `{"id":"c1","subTasks":[{"id":"t1","agent":"helm"},{"agent":"orphan"},{"id":"t2","agent":"k8s"}]}`, want},
+		{"metadata", `{"id":"c1","metadata":{"sub_tasks":[{"task_id":"t1","agent":"helm"},{"task_id":"t2","agent":"k8s"}]}}`, want},
+		{"null list", `{"id":"c1","subTasks":null,"metadata":{"delegations":[{"taskId":"t1","agent":"helm"},{"taskId":"t2","agent":"k8s"}]}}`, want},
+		{"none", `{"id":"c1","status":"completed"}`, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var resp TaskResponse
+			require.NoError(t, json.Unmarshal([]byte(tt.json), &resp))
+			assert.Equal(t, "c1", resp.ID)
+			assert.Equal(t, tt.want, resp.Delegations)
+		})
+	}
+
+	var resp TaskResponse
+	assert.Error(t, json.Unmarshal([]byte(`{"id":"c1","subTasks":"t1"}`), &resp))
+}
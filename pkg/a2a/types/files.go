@@ -0,0 +1,160 @@
+package types
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+
+	"github.com/craine-io/openribcage/pkg/netguard"
+)
+
+// DefaultMaxFetchSize limits file downloads made by FilePart.Fetch
+const DefaultMaxFetchSize = 10 << 20
+
+var (
+	// ErrFileTooLarge is returned when a fetched file exceeds the size limit
+	ErrFileTooLarge = errors.New("file exceeds maximum size")
+
+	// ErrFileMismatch is returned when a fetched file does not match the
+	// MimeType or Size reported in its FilePart
+	ErrFileMismatch = errors.New("file does not match its description")
+)
+
+// FetchOptions controls FilePart downloads
+type FetchOptions struct {
+	// MaxSize limits the download (default DefaultMaxFetchSize)
+	MaxSize int64
+
+	// HostPolicy is checked before following the agent-provided URL and
+	// each redirect from it (default netguard.DefaultPolicy, which blocks
+	// private networks)
+	HostPolicy *netguard.Policy
+
+	// Origin is the URL of the agent that sent the file; files on the
+	// agent's own host are allowed even if it is private
+	Origin string
+}
+
+// Fetch returns the file's content, downloading it from URL when Content
+// is empty. See FetchTo.
+func (f *FilePart) Fetch(ctx context.Context, httpClient *http.Client) ([]byte, error) {
+	var buf bytes.Buffer
+	if _, err := f.FetchTo(ctx, httpClient, &buf, nil); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// FetchTo writes the file's content to w, streaming it from URL when
+// Content is empty, and returns the number of bytes written. Downloads are
+// limited to opts.MaxSize, and a reported MimeType or Size that does not
+// match the response yields ErrFileMismatch. A nil opts uses the defaults.
+func (f *FilePart) FetchTo(ctx context.Context, httpClient *http.Client, w io.Writer, opts *FetchOptions) (int64, error) {
+	if opts == nil {
+		opts = &FetchOptions{}
+	}
+	maxSize := opts.MaxSize
+	if maxSize <= 0 {
+		maxSize = DefaultMaxFetchSize
+	}
+
+	if len(f.Content) > 0 {
+		n, err := w.Write(f.Content)
+		return int64(n), err
+	}
+	if f.URL == "" {
+		return 0, fmt.Errorf("file %s has neither content nor URL", f.Name)
+	}
+	if f.Size > maxSize {
+		return 0, fmt.Errorf("%w: %s reports %d bytes (max %d)", ErrFileTooLarge, f.Name, f.Size, maxSize)
+	}
+
+	policy := opts.HostPolicy
+	if policy == nil {
+		policy = netguard.DefaultPolicy()
+	}
+	if err := policy.CheckProvidedURL(ctx, f.URL, opts.Origin); err != nil {
+		return 0, err
+	}
+
+	httpClient = checkedRedirects(httpClient, policy, opts.Origin)
+	req, err := http.NewRequestWithContext(ctx, "GET", f.URL, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create request: %w", err)
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to download %s: %w", f.Name, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("failed to download %s: unexpected status: %s", f.Name, resp.Status)
+	}
+	if resp.ContentLength > maxSize {
+		return 0, fmt.Errorf("%w: %s is %d bytes (max %d)", ErrFileTooLarge, f.Name, resp.ContentLength, maxSize)
+	}
+	if err := checkMimeType(f.MimeType, resp.Header.Get("Content-Type")); err != nil {
+		return 0, fmt.Errorf("%w: %s: %v", ErrFileMismatch, f.Name, err)
+	}
+
+	n, err := io.Copy(w, io.LimitReader(resp.Body, maxSize+1))
+	if err != nil {
+		return n, fmt.Errorf("failed to download %s: %w", f.Name, err)
+	}
+	if n > maxSize {
+		return n, fmt.Errorf("%w: %s exceeds %d bytes", ErrFileTooLarge, f.Name, maxSize)
+	}
+	if f.Size > 0 && n != f.Size {
+		return n, fmt.Errorf("%w: %s is %d bytes, expected %d", ErrFileMismatch, f.Name, n, f.Size)
+	}
+	return n, nil
+}
+
+// checkedRedirects returns a copy of httpClient (http.DefaultClient if nil)
+// that checks every redirect target against policy, so that a file URL
+// cannot redirect past the host policy
+func checkedRedirects(httpClient *http.Client, policy *netguard.Policy, origin string) *http.Client {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	checked := *httpClient
+	next := httpClient.CheckRedirect
+	checked.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if err := policy.CheckProvidedURL(req.Context(), req.URL.String(), origin); err != nil {
+			return err
+		}
+		if next != nil {
+			return next(req, via)
+		}
+		if len(via) >= 10 {
+			return errors.New("stopped after 10 redirects")
+		}
+		return nil
+	}
+	return &checked
+}
+
+// checkMimeType compares a reported MIME type with a response Content-Type,
+// ignoring parameters. Either being empty or generic binary is accepted.
+func checkMimeType(reported, contentType string) error {
+	if reported == "" || contentType == "" {
+		return nil
+	}
+	want, _, err := mime.ParseMediaType(reported)
+	if err != nil {
+		return nil
+	}
+	got, _, err := mime.ParseMediaType(contentType)
+	if err != nil || got == "application/octet-stream" {
+		return nil
+	}
+	if want != got {
+		return fmt.Errorf("content type is %s, expected %s", got, want)
+	}
+	return nil
+}
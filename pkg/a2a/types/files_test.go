@@ -0,0 +1,128 @@
+package types
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/craine-io/openribcage/pkg/netguard"
+)
+
+// newFileServer serves "report" at /file and redirects /redirect to target
+func newFileServer(t *testing.T, target func() string) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/file":
+			w.Header().Set("Content-Type", "text/plain")
+			w.Write([]byte("report"))
+		case "/redirect":
+			http.Redirect(w, r, target(), http.StatusFound)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// localhostURL rewrites a 127.0.0.1 test server URL to use localhost, a
+// different host that still reaches the server
+func localhostURL(serverURL string) string {
+	return strings.Replace(serverURL, "127.0.0.1", "localhost", 1)
+}
+
+// TestFetchToRedirectPolicy tests that the host policy is enforced on
+// every redirect hop, not just the URL in the FilePart
+func TestFetchToRedirectPolicy(t *testing.T) {
+	target := ""
+	server := newFileServer(t, func() string { return target })
+	ctx := context.Background()
+	file := &FilePart{Name: "report.txt", URL: server.URL + "/redirect"}
+
+	// A redirect on the agent's own host is followed
+	target = server.URL + "/file"
+	var buf bytes.Buffer
+	n, err := file.FetchTo(ctx, nil, &buf, &FetchOptions{Origin: server.URL})
+	require.NoError(t, err)
+	assert.Equal(t, int64(6), n)
+	assert.Equal(t, "report", buf.String())
+
+	// A redirect to a denied host fails
+	target = localhostURL(server.URL) + "/file"
+	_, err = file.FetchTo(ctx, nil, &bytes.Buffer{}, &FetchOptions{
+		Origin:     server.URL,
+		HostPolicy: &netguard.Policy{Deny: []string{"localhost"}, AllowPrivate: true},
+	})
+	assert.ErrorIs(t, err, netguard.ErrHostNotAllowed)
+
+	// So does a redirect to a private host other than the agent's
+	_, err = file.FetchTo(ctx, nil, &bytes.Buffer{}, &FetchOptions{Origin: server.URL})
+	assert.ErrorIs(t, err, netguard.ErrHostNotAllowed)
+}
+
+// TestFetchToKeepsClientRedirectPolicy tests that a caller's CheckRedirect
+// still runs after the host policy, and that the caller's client is not
+// modified
+func TestFetchToKeepsClientRedirectPolicy(t *testing.T) {
+	var server *httptest.Server
+	server = newFileServer(t, func() string { return server.URL + "/file" })
+	errNoRedirects := errors.New("no redirects")
+	httpClient := &http.Client{CheckRedirect: func(req *http.Request, via []*http.Request) error { return errNoRedirects }}
+
+	file := &FilePart{Name: "report.txt", URL: server.URL + "/redirect"}
+	_, err := file.FetchTo(context.Background(), httpClient, &bytes.Buffer{}, &FetchOptions{Origin: server.URL})
+	assert.ErrorIs(t, err, errNoRedirects)
+	assert.Equal(t, errNoRedirects, httpClient.CheckRedirect(nil, nil))
+}
+
+// TestFetch tests downloading files and the size and type checks
+func TestFetch(t *testing.T) {
+	server := newFileServer(t, func() string { return "" })
+	ctx := context.Background()
+
+	inline := &FilePart{Name: "inline.txt", Content: []byte("inline")}
+	data, err := inline.Fetch(ctx, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "inline", string(data), "inline content is returned without a request")
+
+	file := &FilePart{Name: "report.txt", URL: server.URL + "/file", MimeType: "text/plain; charset=utf-8", Size: 6}
+	var buf bytes.Buffer
+	_, err = file.FetchTo(ctx, nil, &buf, &FetchOptions{Origin: server.URL})
+	require.NoError(t, err)
+	assert.Equal(t, "report", buf.String())
+
+	tests := []struct {
+		name string
+		file FilePart
+		opts FetchOptions
+		want error
+	}{
+		{"reported size over the limit", FilePart{Size: 100}, FetchOptions{MaxSize: 10}, ErrFileTooLarge},
+		{"download over the limit", FilePart{}, FetchOptions{MaxSize: 3}, ErrFileTooLarge},
+		{"size mismatch", FilePart{Size: 5}, FetchOptions{}, ErrFileMismatch},
+		{"type mismatch", FilePart{MimeType: "image/png"}, FetchOptions{}, ErrFileMismatch},
+		{"private host", FilePart{}, FetchOptions{Origin: "http://agent.example.com"}, netguard.ErrHostNotAllowed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file := tt.file
+			file.Name = "report.txt"
+			file.URL = server.URL + "/file"
+			if tt.opts.Origin == "" {
+				tt.opts.Origin = server.URL
+			}
+			_, err := file.FetchTo(ctx, nil, &bytes.Buffer{}, &tt.opts)
+			assert.ErrorIs(t, err, tt.want)
+		})
+	}
+
+	_, err = (&FilePart{Name: "empty"}).Fetch(ctx, nil)
+	assert.ErrorContains(t, err, "neither content nor URL")
+}
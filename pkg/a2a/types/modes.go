@@ -0,0 +1,53 @@
+package types
+
+import (
+	"mime"
+	"strings"
+)
+
+// MatchMode reports whether MIME types a and b are compatible, ignoring
+// parameters and case and honoring "*" wildcards on either side. A bare
+// type such as "text" is treated as "text/*".
+func MatchMode(a, b string) bool {
+	aType, aSub := splitMediaType(a)
+	bType, bSub := splitMediaType(b)
+	return (aType == "*" || bType == "*" || aType == bType) &&
+		(aSub == "*" || bSub == "*" || aSub == bSub)
+}
+
+// AcceptsInputMode reports whether the agent declares support for input of
+// the given MIME type, through its default input modes or any skill's
+func (ac *AgentCard) AcceptsInputMode(mode string) bool {
+	if containsMode(ac.DefaultInputModes, mode) {
+		return true
+	}
+	for _, skill := range ac.Skills {
+		if containsMode(skill.InputModes, mode) {
+			return true
+		}
+	}
+	return false
+}
+
+// containsMode reports whether any of modes matches mode
+func containsMode(modes []string, mode string) bool {
+	for _, m := range modes {
+		if MatchMode(m, mode) {
+			return true
+		}
+	}
+	return false
+}
+
+// splitMediaType returns the lower-cased type and subtype of a MIME type
+func splitMediaType(mode string) (string, string) {
+	if mediaType, _, err := mime.ParseMediaType(mode); err == nil {
+		mode = mediaType
+	}
+	mode = strings.ToLower(strings.TrimSpace(mode))
+	typ, sub, ok := strings.Cut(mode, "/")
+	if !ok {
+		return typ, "*"
+	}
+	return typ, sub
+}
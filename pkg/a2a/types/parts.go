@@ -0,0 +1,197 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// PartKind identifies what a message part carries
+type PartKind string
+
+// Part kinds understood by this package. Parts of other kinds are kept
+// as-is and report their type as their kind.
+const (
+	PartKindText  PartKind = "text"
+	PartKindData  PartKind = "data"
+	PartKindFile  PartKind = "file"
+	PartKindImage PartKind = "image"
+	PartKindAudio PartKind = "audio"
+)
+
+// IsKnown reports whether k is one of the part kinds defined above
+func (k PartKind) IsKnown() bool {
+	switch k {
+	case PartKindText, PartKindData, PartKindFile, PartKindImage, PartKindAudio:
+		return true
+	}
+	return false
+}
+
+// ImagePart is an image attached to a message, either inline or by URL
+type ImagePart struct {
+	MimeType string `json:"mimeType"`
+	Width    int    `json:"width,omitempty"`
+	Height   int    `json:"height,omitempty"`
+	AltText  string `json:"altText,omitempty"`
+	URL      string `json:"url,omitempty"`
+	Content  []byte `json:"content,omitempty"`
+}
+
+// AudioPart is an audio clip attached to a message, either inline or by URL
+type AudioPart struct {
+	MimeType   string `json:"mimeType"`
+	DurationMS int64  `json:"durationMs,omitempty"`
+	SampleRate int    `json:"sampleRate,omitempty"`
+	Channels   int    `json:"channels,omitempty"`
+	Transcript string `json:"transcript,omitempty"`
+	URL        string `json:"url,omitempty"`
+	Content    []byte `json:"content,omitempty"`
+}
+
+// Duration returns the clip's length
+func (a *AudioPart) Duration() time.Duration {
+	return time.Duration(a.DurationMS) * time.Millisecond
+}
+
+// NewImagePart creates an image part
+func NewImagePart(image *ImagePart) Part {
+	return Part{Type: string(PartKindImage), Image: image}
+}
+
+// NewAudioPart creates an audio part
+func NewAudioPart(audio *AudioPart) Part {
+	return Part{Type: string(PartKindAudio), Audio: audio}
+}
+
+// Kind returns what the part carries. Parts without a type are classified
+// by their populated fields; JSON parts are data parts.
+func (p *Part) Kind() PartKind {
+	switch {
+	case p.Type == MimeTypeJSON:
+		return PartKindData
+	case p.Type != "":
+		return PartKind(p.Type)
+	case p.Image != nil:
+		return PartKindImage
+	case p.Audio != nil:
+		return PartKindAudio
+	case p.File != nil:
+		return PartKindFile
+	case p.Data != nil:
+		return PartKindData
+	}
+	return PartKindText
+}
+
+// Summary describes the part in one line, e.g. "image (image/png,
+// 640x480)", for display where the content itself cannot be shown
+func (p *Part) Summary() string {
+	var details []string
+	add := func(detail string) {
+		if detail != "" {
+			details = append(details, detail)
+		}
+	}
+
+	kind := p.Kind()
+	switch {
+	case kind == PartKindImage && p.Image != nil:
+		add(p.Image.MimeType)
+		if p.Image.Width > 0 && p.Image.Height > 0 {
+			add(fmt.Sprintf("%dx%d", p.Image.Width, p.Image.Height))
+		}
+		if p.Image.AltText != "" {
+			add(fmt.Sprintf("%q", p.Image.AltText))
+		}
+	case kind == PartKindAudio && p.Audio != nil:
+		add(p.Audio.MimeType)
+		if p.Audio.DurationMS > 0 {
+			add(p.Audio.Duration().String())
+		}
+		if p.Audio.Transcript != "" {
+			add(fmt.Sprintf("%q", p.Audio.Transcript))
+		}
+	case kind == PartKindFile && p.File != nil:
+		add(p.File.Name)
+		add(p.File.MimeType)
+		if p.File.Size > 0 {
+			add(fmt.Sprintf("%d bytes", p.File.Size))
+		}
+	case kind == PartKindData:
+		add(p.MimeType)
+		if p.Schema != "" {
+			add("schema " + p.Schema)
+		}
+	case kind == PartKindText:
+		add(fmt.Sprintf("%d chars", len(p.Text)))
+	}
+
+	if len(details) == 0 {
+		return string(kind)
+	}
+	return fmt.Sprintf("%s (%s)", kind, strings.Join(details, ", "))
+}
+
+// partAlias has Part's fields without its JSON methods
+type partAlias Part
+
+// partFields are the JSON names of Part's own fields
+var partFields = jsonFieldNames(reflect.TypeOf(partAlias{}))
+
+// jsonFieldNames returns the JSON names of a struct type's fields
+func jsonFieldNames(t reflect.Type) map[string]bool {
+	names := make(map[string]bool, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			names[name] = true
+		}
+	}
+	return names
+}
+
+// UnmarshalJSON decodes a part, keeping fields this package does not know,
+// such as the payload of an unknown part type, in Extra
+func (p *Part) UnmarshalJSON(data []byte) error {
+	var alias partAlias
+	if err := json.Unmarshal(data, &alias); err != nil {
+		return err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	for name := range fields {
+		if partFields[name] {
+			delete(fields, name)
+		}
+	}
+
+	*p = Part(alias)
+	if len(fields) > 0 {
+		p.Extra = fields
+	}
+	return nil
+}
+
+// MarshalJSON encodes a part together with the unknown fields in Extra
+func (p Part) MarshalJSON() ([]byte, error) {
+	data, err := json.Marshal(partAlias(p))
+	if err != nil || len(p.Extra) == 0 {
+		return data, err
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	for name, value := range p.Extra {
+		if _, ok := fields[name]; !ok {
+			fields[name] = value
+		}
+	}
+	return json.Marshal(fields)
+}
@@ -0,0 +1,67 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestMixedPartsRoundTrip tests that messages mixing text, data, file,
+// image, audio and unknown parts survive JSON encoding unchanged
+func TestMixedPartsRoundTrip(t *testing.T) {
+	original := `{
+		"role": "agent",
+		"parts": [
+			{"type": "text", "text": "Here is what I found"},
+			{"type": "data", "data": {"score": 0.9}, "mimeType": "application/json"},
+			{"type": "file", "file": {"name": "report.pdf", "mime_type": "application/pdf", "size": 2048, "url": "https://files.example.com/report.pdf"}},
+			{"type": "image", "image": {"mimeType": "image/png", "width": 640, "height": 480, "altText": "avatar", "content": "iVBORw0K"}},
+			{"type": "audio", "audio": {"mimeType": "audio/wav", "durationMs": 3200, "sampleRate": 16000, "channels": 1, "transcript": "hello"}},
+			{"type": "video", "video": {"mimeType": "video/mp4", "frames": 240}, "metadata": {"source": "camera"}}
+		]
+	}`
+
+	var msg Message
+	require.NoError(t, json.Unmarshal([]byte(original), &msg))
+	require.Len(t, msg.Parts, 6)
+
+	kinds := make([]PartKind, len(msg.Parts))
+	for i := range msg.Parts {
+		kinds[i] = msg.Parts[i].Kind()
+	}
+	assert.Equal(t, []PartKind{
+		PartKindText, PartKindData, PartKindFile,
+		PartKindImage, PartKindAudio, "video",
+	}, kinds)
+	assert.False(t, kinds[5].IsKnown())
+
+	image := msg.Parts[3].Image
+	require.NotNil(t, image)
+	assert.Equal(t, 640, image.Width)
+	assert.Equal(t, 480, image.Height)
+	audio := msg.Parts[4].Audio
+	require.NotNil(t, audio)
+	assert.Equal(t, 3200*time.Millisecond, audio.Duration())
+	assert.Equal(t, "image (image/png, 640x480, \"avatar\")", msg.Parts[3].Summary())
+	assert.Equal(t, "audio (audio/wav, 3.2s, \"hello\")", msg.Parts[4].Summary())
+	assert.Equal(t, "video", msg.Parts[5].Summary())
+	assert.Contains(t, msg.Parts[5].Extra, "video")
+
+	encoded, err := json.Marshal(&msg)
+	require.NoError(t, err)
+	assert.JSONEq(t, original, string(encoded))
+
+	built := Message{Role: "user", Parts: []Part{
+		{Type: "text", Text: "describe this"},
+		NewImagePart(&ImagePart{MimeType: "image/jpeg", Content: []byte{0xff, 0xd8}}),
+		NewAudioPart(&AudioPart{MimeType: "audio/ogg", DurationMS: 1500}),
+	}}
+	encoded, err = json.Marshal(built)
+	require.NoError(t, err)
+	var decoded Message
+	require.NoError(t, json.Unmarshal(encoded, &decoded))
+	assert.Equal(t, built, decoded)
+}
@@ -0,0 +1,52 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+)
+
+// PushNotificationAuthentication tells an agent how to authenticate to a
+// push notification webhook
+type PushNotificationAuthentication struct {
+	Schemes     []string `json:"schemes"`
+	Credentials string   `json:"credentials,omitempty"`
+}
+
+// PushNotificationConfig is a webhook an agent calls with task updates.
+// Token is echoed back in each notification so the receiver can check
+// that it belongs to the task.
+type PushNotificationConfig struct {
+	URL            string                          `json:"url"`
+	Token          string                          `json:"token,omitempty"`
+	Authentication *PushNotificationAuthentication `json:"authentication,omitempty"`
+}
+
+// Validate checks that the webhook is an absolute http or https URL and
+// that authentication, when set, names at least one scheme
+func (c *PushNotificationConfig) Validate() error {
+	if c.URL == "" {
+		return errors.New("push notification url is required")
+	}
+	u, err := url.Parse(c.URL)
+	if err != nil {
+		return fmt.Errorf("invalid push notification url: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("invalid push notification url %q: scheme must be http or https", c.URL)
+	}
+	if u.Host == "" {
+		return fmt.Errorf("invalid push notification url %q: host is required", c.URL)
+	}
+	if c.Authentication != nil && len(c.Authentication.Schemes) == 0 {
+		return errors.New("push notification authentication requires at least one scheme")
+	}
+	return nil
+}
+
+// TaskPushNotificationConfig associates a push notification config with a
+// task, as sent and returned by the tasks/pushNotification methods
+type TaskPushNotificationConfig struct {
+	ID                     string                 `json:"id"`
+	PushNotificationConfig PushNotificationConfig `json:"pushNotificationConfig"`
+}
@@ -0,0 +1,94 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+)
+
+// JSONRPCErrorCodes contains the standard JSON-RPC 2.0 error codes
+var JSONRPCErrorCodes = struct {
+	ParseError     int
+	InvalidRequest int
+	MethodNotFound int
+	InvalidParams  int
+	InternalError  int
+}{
+	ParseError:     -32700,
+	InvalidRequest: -32600,
+	MethodNotFound: -32601,
+	InvalidParams:  -32602,
+	InternalError:  -32603,
+}
+
+// ErrorCode is a sentinel matching any JSON-RPC error with that code
+// through errors.Is
+type ErrorCode int
+
+// Error implements the error interface
+func (c ErrorCode) Error() string {
+	return fmt.Sprintf("JSON-RPC error code %d", int(c))
+}
+
+// Sentinels for the standard and A2A-specific JSON-RPC error codes
+var (
+	ErrParseError                   error = ErrorCode(JSONRPCErrorCodes.ParseError)
+	ErrInvalidRequest               error = ErrorCode(JSONRPCErrorCodes.InvalidRequest)
+	ErrMethodNotFound               error = ErrorCode(JSONRPCErrorCodes.MethodNotFound)
+	ErrInvalidParams                error = ErrorCode(JSONRPCErrorCodes.InvalidParams)
+	ErrInternalError                error = ErrorCode(JSONRPCErrorCodes.InternalError)
+	ErrTaskNotFound                 error = ErrorCode(A2AErrorCodes.TaskNotFound)
+	ErrTaskNotCancelable            error = ErrorCode(A2AErrorCodes.TaskNotCancelable)
+	ErrPushNotificationNotSupported error = ErrorCode(A2AErrorCodes.PushNotificationNotSupported)
+	ErrUnsupportedOperation         error = ErrorCode(A2AErrorCodes.UnsupportedOperation)
+	ErrContentTypeNotSupported      error = ErrorCode(A2AErrorCodes.ContentTypeNotSupported)
+)
+
+// JSONRPCErrorError is the Go error for a JSON-RPC error returned by an
+// agent. Use errors.As to inspect it, or errors.Is with the sentinels
+// above to match its code.
+type JSONRPCErrorError struct {
+	// Method is the JSON-RPC method that failed, if known
+	Method string
+	// Err is the error object sent by the agent
+	Err *JSONRPCError
+}
+
+// NewJSONRPCErrorError wraps the error object an agent returned for method
+func NewJSONRPCErrorError(method string, err *JSONRPCError) *JSONRPCErrorError {
+	return &JSONRPCErrorError{Method: method, Err: err}
+}
+
+// Error implements the error interface
+func (e *JSONRPCErrorError) Error() string {
+	return fmt.Sprintf("JSON-RPC error: %s (code: %d)", e.Err.Message, e.Err.Code)
+}
+
+// Code returns the JSON-RPC error code
+func (e *JSONRPCErrorError) Code() int {
+	return e.Err.Code
+}
+
+// Message returns the agent's error message
+func (e *JSONRPCErrorError) Message() string {
+	return e.Err.Message
+}
+
+// Data returns the agent's additional error data, if any
+func (e *JSONRPCErrorError) Data() interface{} {
+	return e.Err.Data
+}
+
+// Is matches an ErrorCode sentinel with the same code
+func (e *JSONRPCErrorError) Is(target error) bool {
+	code, ok := target.(ErrorCode)
+	return ok && int(code) == e.Err.Code
+}
+
+// JSONRPCErrorCode returns the code of the JSON-RPC error in err's chain
+func JSONRPCErrorCode(err error) (int, bool) {
+	var rpcErr *JSONRPCErrorError
+	if !errors.As(err, &rpcErr) {
+		return 0, false
+	}
+	return rpcErr.Code(), true
+}
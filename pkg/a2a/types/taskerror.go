@@ -0,0 +1,209 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// TaskErrorCategory classifies why a task failed
+type TaskErrorCategory string
+
+const (
+	TaskErrorUnknown     TaskErrorCategory = ""
+	TaskErrorInput       TaskErrorCategory = "input"
+	TaskErrorInternal    TaskErrorCategory = "internal"
+	TaskErrorTimeout     TaskErrorCategory = "timeout"
+	TaskErrorUnavailable TaskErrorCategory = "unavailable"
+	TaskErrorRateLimited TaskErrorCategory = "rate-limited"
+)
+
+// TaskError describes why a task failed. Agents send either a plain
+// message string or an object with a message and optional category,
+// code and retryable hint.
+type TaskError struct {
+	Message  string            `json:"message"`
+	Category TaskErrorCategory `json:"category,omitempty"`
+	Code     string            `json:"code,omitempty"`
+
+	// Retryable is the agent's own retry hint, overriding the category
+	Retryable *bool `json:"retryable,omitempty"`
+}
+
+// Error implements the error interface
+func (e *TaskError) Error() string {
+	if e.Category == TaskErrorUnknown {
+		return fmt.Sprintf("task failed: %s", e.Message)
+	}
+	return fmt.Sprintf("task failed (%s): %s", e.Category, e.Message)
+}
+
+// IsRetryable reports whether resubmitting the task may succeed. The
+// agent's explicit hint wins; otherwise timeouts, internal errors,
+// unavailability and rate limiting are retryable, while input errors and
+// uncategorized failures are not.
+func (e *TaskError) IsRetryable() bool {
+	if e.Retryable != nil {
+		return *e.Retryable
+	}
+	switch e.Category {
+	case TaskErrorTimeout, TaskErrorInternal, TaskErrorUnavailable, TaskErrorRateLimited:
+		return true
+	default:
+		return false
+	}
+}
+
+// UnmarshalJSON accepts a message string or an error object. The category
+// may also be sent as "type" or "kind" and is normalized for case and
+// separators; unrecognized categories are kept as-is.
+func (e *TaskError) UnmarshalJSON(data []byte) error {
+	var message string
+	if err := json.Unmarshal(data, &message); err == nil {
+		*e = TaskError{Message: message}
+		return nil
+	}
+
+	var obj struct {
+		Message   string          `json:"message"`
+		Category  string          `json:"category"`
+		Type      string          `json:"type"`
+		Kind      string          `json:"kind"`
+		Code      json.RawMessage `json:"code"`
+		Retryable *bool           `json:"retryable"`
+	}
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return fmt.Errorf("task error must be a string or object: %w", err)
+	}
+
+	category := obj.Category
+	if category == "" {
+		category = obj.Type
+	}
+	if category == "" {
+		category = obj.Kind
+	}
+
+	*e = TaskError{
+		Message:   obj.Message,
+		Category:  normalizeCategory(category),
+		Code:      strings.Trim(string(obj.Code), `"`),
+		Retryable: obj.Retryable,
+	}
+	return nil
+}
+
+// normalizeCategory maps category spellings such as "INPUT" or
+// "rate_limited" onto the TaskErrorCategory constants
+func normalizeCategory(category string) TaskErrorCategory {
+	normalized := strings.NewReplacer("_", "-", " ", "-").Replace(strings.ToLower(strings.TrimSpace(category)))
+	switch normalized {
+	case "invalid-input", "validation", "bad-request":
+		return TaskErrorInput
+	case "deadline-exceeded":
+		return TaskErrorTimeout
+	case "ratelimited", "rate-limit":
+		return TaskErrorRateLimited
+	}
+	return TaskErrorCategory(normalized)
+}
+
+// taskStatusError extracts the error carried by a status object, as in
+// {"state": "failed", "error": {...}} or {"state": "failed", "message": "..."}
+func taskStatusError(status json.RawMessage) *TaskError {
+	var obj struct {
+		Error   *TaskError      `json:"error"`
+		Message json.RawMessage `json:"message"`
+	}
+	if json.Unmarshal(status, &obj) != nil {
+		return nil
+	}
+	if obj.Error != nil {
+		return obj.Error
+	}
+
+	var message string
+	if json.Unmarshal(obj.Message, &message) == nil && message != "" {
+		return &TaskError{Message: message}
+	}
+	return nil
+}
+
+// taskErr returns err for a failed task, or a generic TaskError if the
+// agent gave no reason. It returns nil for tasks that did not fail.
+func taskErr(state TaskState, err *TaskError) error {
+	if state != TaskStateFailed {
+		return nil
+	}
+	if err == nil {
+		return &TaskError{Message: "no error reported by agent"}
+	}
+	return err
+}
+
+// Err returns the task's *TaskError if it failed, or nil
+func (r *TaskResponse) Err() error {
+	return taskErr(r.Status, r.Error)
+}
+
+// UnmarshalJSON decodes a task response, taking the error from the status
+// object when the agent reports it there and collecting delegated sub-tasks
+func (r *TaskResponse) UnmarshalJSON(data []byte) error {
+	type plain TaskResponse
+	aux := struct {
+		*plain
+		Status json.RawMessage `json:"status"`
+	}{plain: (*plain)(r)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	if r.Delegations == nil {
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(data, &fields); err != nil {
+			return err
+		}
+		delegations, err := findDelegations(fields)
+		if err != nil {
+			return err
+		}
+		r.Delegations = delegations
+	}
+	if len(aux.Status) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(aux.Status, &r.Status); err != nil {
+		return err
+	}
+	if r.Error == nil {
+		r.Error = taskStatusError(aux.Status)
+	}
+	return nil
+}
+
+// Err returns the task's *TaskError if it failed, or nil
+func (s *TaskStatus) Err() error {
+	return taskErr(s.Status, s.Error)
+}
+
+// UnmarshalJSON decodes a task status, taking the error from the status
+// object when the agent reports it there
+func (s *TaskStatus) UnmarshalJSON(data []byte) error {
+	type plain TaskStatus
+	aux := struct {
+		*plain
+		Status json.RawMessage `json:"status"`
+	}{plain: (*plain)(s)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	if len(aux.Status) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(aux.Status, &s.Status); err != nil {
+		return err
+	}
+	if s.Error == nil {
+		s.Error = taskStatusError(aux.Status)
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package types
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestTaskErrorUnmarshal tests decoding string and object task errors
+func TestTaskErrorUnmarshal(t *testing.T) {
+	retryable := false
+	tests := []struct {
+		name string
+		json string
+		want TaskError
+	}{
+		{"string", `"boom"`, TaskError{Message: "boom"}},
+		{"object", `{"message":"bad prompt","category":"input","code":"E42"}`, TaskError{Message: "bad prompt", Category: TaskErrorInput, Code: "E42"}},
+		{"numeric code", `{"message":"x","code":503}`, TaskError{Message: "x", Code: "503"}},
+		{"type alias", `{"message":"x","type":"TIMEOUT"}`, TaskError{Message: "x", Category: TaskErrorTimeout}},
+		{"kind alias", `{"message":"x","kind":"rate_limited"}`, TaskError{Message: "x", Category: TaskErrorRateLimited}},
+		{"synonym", `{"message":"x","category":"deadline exceeded"}`, TaskError{Message: "x", Category: TaskErrorTimeout}},
+		{"unrecognized", `{"message":"x","category":"Quota"}`, TaskError{Message: "x", Category: "quota"}},
+		{"retry hint", `{"message":"x","category":"internal","retryable":false}`, TaskError{Message: "x", Category: TaskErrorInternal, Retryable: &retryable}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got TaskError
+			require.NoError(t, json.Unmarshal([]byte(tt.json), &got))
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	var got TaskError
+	assert.ErrorContains(t, json.Unmarshal([]byte(`42`), &got), "task error must be a string or object")
+}
+
+// TestTaskErrorRetryable tests the category defaults and the agent's hint
+func TestTaskErrorRetryable(t *testing.T) {
+	for _, category := range []TaskErrorCategory{TaskErrorTimeout, TaskErrorInternal, TaskErrorUnavailable, TaskErrorRateLimited} {
+		assert.True(t, (&TaskError{Category: category}).IsRetryable(), category)
+	}
+	for _, category := range []TaskErrorCategory{TaskErrorUnknown, TaskErrorInput, "quota"} {
+		assert.False(t, (&TaskError{Category: category}).IsRetryable(), category)
+	}
+
+	yes, no := true, false
+	assert.True(t, (&TaskError{Category: TaskErrorInput, Retryable: &yes}).IsRetryable())
+	assert.False(t, (&TaskError{Category: TaskErrorTimeout, Retryable: &no}).IsRetryable())
+
+	assert.Equal(t, "task failed: boom", (&TaskError{Message: "boom"}).Error())
+	assert.Equal(t, "task failed (timeout): boom", (&TaskError{Message: "boom", Category: TaskErrorTimeout}).Error())
+}
+
+// TestTaskResponseErr tests taking the task error from the response or
+// its status object
+func TestTaskResponseErr(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+		want error
+	}{
+		{"completed", `{"id":"t1","status":"completed"}`, nil},
+		{"top-level error", `{"id":"t1","status":"failed","error":{"message":"bad","category":"input"}}`, &TaskError{Message: "bad", Category: TaskErrorInput}},
+		{"status error", `{"id":"t1","status":{"state":"failed","error":"oom"}}`, &TaskError{Message: "oom"}},
+		{"status message", `{"id":"t1","status":{"state":"failed","message":"oom"}}`, &TaskError{Message: "oom"}},
+		{"no reason", `{"id":"t1","status":"failed"}`, &TaskError{Message: "no error reported by agent"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var resp TaskResponse
+			require.NoError(t, json.Unmarshal([]byte(tt.json), &resp))
+			assert.Equal(t, tt.want, resp.Err())
+
+			var status TaskStatus
+			require.NoError(t, json.Unmarshal([]byte(tt.json), &status))
+			assert.Equal(t, tt.want, status.Err())
+		})
+	}
+
+	var resp TaskResponse
+	require.NoError(t, json.Unmarshal([]byte(`{"id":"t1","status":"failed","error":"boom"}`), &resp))
+	var taskErr *TaskError
+	require.True(t, errors.As(resp.Err(), &taskErr))
+	assert.Equal(t, "boom", taskErr.Message)
+}
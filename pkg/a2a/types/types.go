@@ -7,7 +7,15 @@ package types
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strings"
+	"sync"
 	"time"
+
+	"github.com/google/uuid"
 )
 
 // JSONRPCRequest represents a JSON-RPC 2.0 request
@@ -33,42 +41,167 @@ type JSONRPCError struct {
 	Data    interface{} `json:"data,omitempty"`
 }
 
+// ErrEmptyResponse is returned when an agent answers with an empty or
+// whitespace-only body, as opposed to malformed JSON
+var ErrEmptyResponse = errors.New("agent returned an empty response")
+
 // TaskRequest represents an A2A task request
 type TaskRequest struct {
 	ID      string   `json:"id"`
 	Message *Message `json:"message"`
 }
 
+// idGenerator produces task and request IDs
+var (
+	idGeneratorMu sync.RWMutex
+	idGenerator   = func() string { return uuid.New().String() }
+)
+
+// SetIDGenerator replaces the function used to generate task and JSON-RPC
+// request IDs. The default generates random UUIDs. A nil fn restores it.
+func SetIDGenerator(fn func() string) {
+	idGeneratorMu.Lock()
+	defer idGeneratorMu.Unlock()
+	if fn == nil {
+		fn = func() string { return uuid.New().String() }
+	}
+	idGenerator = fn
+}
+
+// NewID generates an ID using the configured ID generator
+func NewID() string {
+	idGeneratorMu.RLock()
+	defer idGeneratorMu.RUnlock()
+	return idGenerator()
+}
+
+// NewTaskRequest creates a task request for message. The message's TaskID
+// is used as the task ID when set; otherwise a new ID is generated.
+func NewTaskRequest(message *Message) *TaskRequest {
+	id := ""
+	if message != nil {
+		id = message.TaskID
+	}
+	if id == "" {
+		id = NewID()
+	}
+	return &TaskRequest{ID: id, Message: message}
+}
+
+// ToMessageSend converts the request to the message used by message/send,
+// carrying the task ID on the message so the agent continues the same task
+func (r *TaskRequest) ToMessageSend() *Message {
+	msg := &Message{}
+	if r.Message != nil {
+		*msg = *r.Message
+	}
+	msg.TaskID = r.ID
+	return msg
+}
+
 // TaskResponse represents an A2A task response
 type TaskResponse struct {
-	ID      string   `json:"id"`
-	Message *Message `json:"message,omitempty"`
-	Status  string   `json:"status"`
-	Error   string   `json:"error,omitempty"`
+	ID      string     `json:"id"`
+	Message *Message   `json:"message,omitempty"`
+	Status  TaskState  `json:"status"`
+	Error   *TaskError `json:"error,omitempty"`
+
+	// Artifacts are outputs produced by the task alongside the message
+	Artifacts []Artifact `json:"artifacts,omitempty"`
+
+	// Delegations lists the sub-tasks a coordinator agent handed to other
+	// agents, read from "delegations" or "subTasks" in the result or its
+	// metadata
+	Delegations []Delegation `json:"delegations,omitempty"`
+
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
 }
 
 // TaskStatus represents the status of an A2A task
 type TaskStatus struct {
-	ID          string    `json:"id"`
-	Status      string    `json:"status"`
-	Progress    float64   `json:"progress,omitempty"`
-	StartedAt   time.Time `json:"started_at,omitempty"`
-	CompletedAt time.Time `json:"completed_at,omitempty"`
-	Error       string    `json:"error,omitempty"`
+	ID          string     `json:"id"`
+	Status      TaskState  `json:"status"`
+	Progress    float64    `json:"progress,omitempty"`
+	StartedAt   time.Time  `json:"started_at,omitempty"`
+	CompletedAt time.Time  `json:"completed_at,omitempty"`
+	Error       *TaskError `json:"error,omitempty"`
+
+	// Artifacts are the outputs produced by the task so far
+	Artifacts []Artifact `json:"artifacts,omitempty"`
 }
 
 // Message represents an A2A message with role and parts
 type Message struct {
 	Role  string `json:"role"`
 	Parts []Part `json:"parts"`
+
+	// TaskID associates a message/send message with an existing task
+	TaskID string `json:"taskId,omitempty"`
 }
 
-// Part represents a message part (text, file, or data)
+// Part represents a message part (text, data, file, image or audio)
 type Part struct {
-	Type string      `json:"type"`
-	Text string      `json:"text,omitempty"`
-	Data interface{} `json:"data,omitempty"`
-	File *FilePart   `json:"file,omitempty"`
+	Type  string      `json:"type"`
+	Text  string      `json:"text,omitempty"`
+	Data  interface{} `json:"data,omitempty"`
+	File  *FilePart   `json:"file,omitempty"`
+	Image *ImagePart  `json:"image,omitempty"`
+	Audio *AudioPart  `json:"audio,omitempty"`
+
+	// MimeType and Schema annotate structured data parts
+	MimeType string `json:"mimeType,omitempty"`
+	Schema   string `json:"schema,omitempty"`
+
+	// Extra holds fields not defined above, such as the payload of a part
+	// type this package does not know, so they survive a round trip
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// MimeTypeJSON is the MIME type of structured JSON data parts
+const MimeTypeJSON = "application/json"
+
+// NewJSONPart creates a data part carrying JSON-encodable v, optionally
+// annotated with the URI of the JSON Schema it conforms to
+func NewJSONPart(v interface{}, schemaURI string) Part {
+	return Part{
+		Type:     "data",
+		Data:     v,
+		MimeType: MimeTypeJSON,
+		Schema:   schemaURI,
+	}
+}
+
+// IsJSON reports whether the part carries structured JSON data, either as
+// an annotated data part or as an explicit application/json part
+func (p *Part) IsJSON() bool {
+	if p.Type == MimeTypeJSON {
+		return true
+	}
+	return p.Type == "data" && p.MimeType == MimeTypeJSON
+}
+
+// ValidateJSON checks that a JSON part's data is encodable and that its
+// schema hint, if any, is an absolute URI. Non-JSON parts are ignored.
+func (p *Part) ValidateJSON() error {
+	if !p.IsJSON() {
+		return nil
+	}
+
+	if _, err := json.Marshal(p.Data); err != nil {
+		return fmt.Errorf("JSON part data is not encodable: %w", err)
+	}
+
+	if p.Schema != "" {
+		u, err := url.Parse(p.Schema)
+		if err != nil {
+			return fmt.Errorf("invalid JSON part schema URI: %w", err)
+		}
+		if !u.IsAbs() {
+			return fmt.Errorf("JSON part schema URI must be absolute: %s", p.Schema)
+		}
+	}
+
+	return nil
 }
 
 // FilePart represents a file attachment in a message
@@ -89,53 +222,180 @@ type StreamResponse struct {
 	Done      bool        `json:"done,omitempty"`
 }
 
+// Artifact represents an output generated by a task. Large artifacts may
+// be streamed as a sequence of chunks, each appended to the previous one,
+// with LastChunk set on the final chunk.
+type Artifact struct {
+	Name        string      `json:"name,omitempty"`
+	Description string      `json:"description,omitempty"`
+	Parts       []Part      `json:"parts"`
+	Index       int         `json:"index"`
+	Append      bool        `json:"append,omitempty"`
+	LastChunk   bool        `json:"lastChunk,omitempty"`
+	Metadata    interface{} `json:"metadata,omitempty"`
+}
+
+// MergeArtifacts reassembles artifacts delivered in chunks. A chunk with
+// Append set extends the earlier artifact with the same index; the merged
+// artifact keeps the LastChunk flag of its latest chunk. Order of first
+// appearance is preserved.
+func MergeArtifacts(chunks []Artifact) []Artifact {
+	if len(chunks) == 0 {
+		return chunks
+	}
+
+	merged := make([]Artifact, 0, len(chunks))
+	byIndex := make(map[int]int)
+	for _, chunk := range chunks {
+		if i, ok := byIndex[chunk.Index]; ok && chunk.Append {
+			merged[i].Parts = append(merged[i].Parts, chunk.Parts...)
+			merged[i].LastChunk = chunk.LastChunk
+			continue
+		}
+		chunk.Parts = append([]Part(nil), chunk.Parts...)
+		chunk.Append = false
+		byIndex[chunk.Index] = len(merged)
+		merged = append(merged, chunk)
+	}
+	return merged
+}
+
+// Artifact returns the artifact carried by an artifact update event. The
+// event data may be the artifact itself or an object with an "artifact"
+// field, as in A2A TaskArtifactUpdateEvent.
+func (r *StreamResponse) Artifact() (*Artifact, bool) {
+	if !strings.Contains(strings.ToLower(r.Type), "artifact") || r.Data == nil {
+		return nil, false
+	}
+
+	data, err := json.Marshal(r.Data)
+	if err != nil {
+		return nil, false
+	}
+
+	var event struct {
+		Artifact *Artifact `json:"artifact"`
+	}
+	if err := json.Unmarshal(data, &event); err == nil && event.Artifact != nil {
+		return event.Artifact, true
+	}
+
+	var artifact Artifact
+	if err := json.Unmarshal(data, &artifact); err != nil {
+		return nil, false
+	}
+	return &artifact, true
+}
+
 // AgentCard represents an A2A agent card (.well-known/agent.json)
 type AgentCard struct {
 	Name               string               `json:"name"`
 	Description        string               `json:"description"`
 	URL                string               `json:"url"`
 	Version            string               `json:"version"`
-	Capabilities       interface{}          `json:"capabilities"`
+	Capabilities       AgentCapabilities    `json:"capabilities"`
 	Authentication     *AgentAuthentication `json:"authentication,omitempty"`
 	DefaultInputModes  []string             `json:"defaultInputModes,omitempty"`
 	DefaultOutputModes []string             `json:"defaultOutputModes,omitempty"`
 	Skills             []AgentSkill         `json:"skills,omitempty"`
 	Endpoints          []Endpoint           `json:"endpoints,omitempty"`
+	JWKSURL            string               `json:"jwksUrl,omitempty"`
 	Metadata           interface{}          `json:"metadata,omitempty"`
 }
 
-// GetCapabilities normalizes the Capabilities field to a []string, supporting both array and object formats.
-func (ac *AgentCard) GetCapabilities() []string {
-	switch caps := ac.Capabilities.(type) {
-	case []interface{}:
-		var result []string
-		for _, cap := range caps {
-			if str, ok := cap.(string); ok {
-				result = append(result, str)
-			}
+// FindSkill returns the skill with the given ID
+func (ac *AgentCard) FindSkill(id string) (*AgentSkill, bool) {
+	for i := range ac.Skills {
+		if ac.Skills[i].ID == id {
+			return &ac.Skills[i], true
 		}
-		return result
-	case []string:
-		return caps
-	case map[string]interface{}:
-		var result []string
-		for cap, enabled := range caps {
-			if b, ok := enabled.(bool); ok && b {
-				result = append(result, cap)
-			}
+	}
+	return nil, false
+}
+
+// UploadEndpoint returns the URL of the agent's file upload endpoint, an
+// endpoint of type "upload", or "" if it advertises none
+func (ac *AgentCard) UploadEndpoint() string {
+	for _, endpoint := range ac.Endpoints {
+		if endpoint.Type == "upload" {
+			return endpoint.URL
 		}
-		return result
-	case map[string]bool:
-		var result []string
-		for cap, enabled := range caps {
-			if enabled {
-				result = append(result, cap)
+	}
+	return ""
+}
+
+// HasSkill reports whether the agent offers the skill with the given ID
+func (ac *AgentCard) HasSkill(id string) bool {
+	_, ok := ac.FindSkill(id)
+	return ok
+}
+
+// GetCapabilities returns the names of the capabilities the agent supports
+// (e.g. "streaming", "pushNotifications") in a stable order. It returns an
+// empty, non-nil slice when no capability is enabled.
+func (ac *AgentCard) GetCapabilities() []string {
+	caps := []string{}
+	if ac.Capabilities.Streaming {
+		caps = append(caps, "streaming")
+	}
+	if ac.Capabilities.PushNotifications {
+		caps = append(caps, "pushNotifications")
+	}
+	if ac.Capabilities.StateTransitionHistory {
+		caps = append(caps, "stateTransitionHistory")
+	}
+	return caps
+}
+
+// AgentCapabilities describes the optional A2A features an agent supports
+type AgentCapabilities struct {
+	Streaming              bool `json:"streaming"`
+	PushNotifications      bool `json:"pushNotifications"`
+	StateTransitionHistory bool `json:"stateTransitionHistory"`
+}
+
+// Has reports whether the named capability is supported
+func (c AgentCapabilities) Has(name string) bool {
+	switch name {
+	case "streaming":
+		return c.Streaming
+	case "pushNotifications":
+		return c.PushNotifications
+	case "stateTransitionHistory":
+		return c.StateTransitionHistory
+	default:
+		return false
+	}
+}
+
+// UnmarshalJSON accepts both the spec object form and the legacy array
+// form, where listed capability names are treated as enabled. Unknown
+// names in the array form are ignored.
+func (c *AgentCapabilities) UnmarshalJSON(data []byte) error {
+	var names []string
+	if err := json.Unmarshal(data, &names); err == nil {
+		*c = AgentCapabilities{}
+		for _, name := range names {
+			switch name {
+			case "streaming":
+				c.Streaming = true
+			case "pushNotifications":
+				c.PushNotifications = true
+			case "stateTransitionHistory":
+				c.StateTransitionHistory = true
 			}
 		}
-		return result
-	default:
-		return []string{}
+		return nil
+	}
+
+	// Alias avoids recursing into this method
+	type capabilities AgentCapabilities
+	var obj capabilities
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return fmt.Errorf("invalid capabilities: %w", err)
 	}
+	*c = AgentCapabilities(obj)
+	return nil
 }
 
 // Endpoint represents an A2A agent endpoint
@@ -156,6 +416,55 @@ type Agent struct {
 	Status       AgentStatus `json:"status"`
 	LastSeen     time.Time   `json:"last_seen"`
 	DiscoveredAt time.Time   `json:"discovered_at"`
+
+	// CardFetchedAt records when Card was last retrieved from the agent
+	CardFetchedAt time.Time `json:"card_fetched_at,omitempty"`
+}
+
+// AgentSummary is a condensed, JSON-friendly view of an agent shared by
+// CLI output and API responses
+type AgentSummary struct {
+	ID                     string      `json:"id"`
+	Name                   string      `json:"name"`
+	URL                    string      `json:"url"`
+	Version                string      `json:"version,omitempty"`
+	Status                 AgentStatus `json:"status"`
+	Streaming              bool        `json:"streaming"`
+	PushNotifications      bool        `json:"push_notifications"`
+	StateTransitionHistory bool        `json:"state_transition_history"`
+	Skills                 []string    `json:"skills"`
+	LastSeen               time.Time   `json:"last_seen"`
+}
+
+// NewAgentSummary flattens an agent and its card into an AgentSummary
+func NewAgentSummary(agent *Agent) AgentSummary {
+	summary := AgentSummary{
+		ID:       agent.ID,
+		Name:     agent.Name,
+		URL:      agent.URL,
+		Status:   agent.Status,
+		Skills:   []string{},
+		LastSeen: agent.LastSeen,
+	}
+
+	if card := agent.Card; card != nil {
+		if summary.Name == "" {
+			summary.Name = card.Name
+		}
+		summary.Version = card.Version
+		summary.Streaming = card.Capabilities.Streaming
+		summary.PushNotifications = card.Capabilities.PushNotifications
+		summary.StateTransitionHistory = card.Capabilities.StateTransitionHistory
+		for _, skill := range card.Skills {
+			name := skill.Name
+			if name == "" {
+				name = skill.ID
+			}
+			summary.Skills = append(summary.Skills, name)
+		}
+	}
+
+	return summary
 }
 
 // AgentStatus represents the status of an agent
@@ -168,21 +477,116 @@ const (
 	AgentStatusDiscovering AgentStatus = "discovering"
 )
 
+// TaskState represents the lifecycle state of an A2A task
+type TaskState string
+
+const (
+	TaskStateSubmitted     TaskState = "submitted"
+	TaskStateWorking       TaskState = "working"
+	TaskStateInputRequired TaskState = "input-required"
+	TaskStateCompleted     TaskState = "completed"
+	TaskStateCanceled      TaskState = "canceled"
+	TaskStateFailed        TaskState = "failed"
+	TaskStateUnknown       TaskState = "unknown"
+)
+
+// String returns the state's wire value
+func (s TaskState) String() string {
+	return string(s)
+}
+
+// IsTerminal reports whether the task can make no further progress
+func (s TaskState) IsTerminal() bool {
+	switch s {
+	case TaskStateCompleted, TaskStateCanceled, TaskStateFailed:
+		return true
+	}
+	return false
+}
+
+// IsKnown reports whether s is one of the A2A-standard states
+func (s TaskState) IsKnown() bool {
+	switch s {
+	case TaskStateSubmitted, TaskStateWorking, TaskStateInputRequired,
+		TaskStateCompleted, TaskStateCanceled, TaskStateFailed, TaskStateUnknown:
+		return true
+	}
+	return false
+}
+
+// UnmarshalJSON accepts a state string, or a status object with a "state"
+// field as sent by newer agents. Known states are normalized for case and
+// spelling ("CANCELLED", "input_required"); unknown values are kept as-is.
+func (s *TaskState) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		var status struct {
+			State string `json:"state"`
+		}
+		if err := json.Unmarshal(data, &status); err != nil {
+			return fmt.Errorf("task state must be a string or status object: %w", err)
+		}
+		value = status.State
+	}
+
+	normalized := TaskState(strings.ReplaceAll(strings.ToLower(strings.TrimSpace(value)), "_", "-"))
+	if normalized == "cancelled" {
+		normalized = TaskStateCanceled
+	}
+	if normalized.IsKnown() {
+		*s = normalized
+	} else {
+		*s = TaskState(value)
+	}
+	return nil
+}
+
 // A2AMethods contains the standard A2A protocol methods
 var A2AMethods = struct {
-	TasksSend     string
-	TasksStream   string
-	TasksStatus   string
-	TasksCancel   string
-	MessageSend   string
-	MessageStream string
+	TasksSend                string
+	TasksStream              string
+	TasksStatus              string
+	TasksCancel              string
+	MessageSend              string
+	MessageStream            string
+	TasksPushNotificationSet string
+	TasksPushNotificationGet string
+	TasksResubscribe         string
 }{
-	TasksSend:     "tasks/send",
-	TasksStream:   "tasks/sendSubscribe",
-	TasksStatus:   "tasks/status",
-	TasksCancel:   "tasks/cancel",
-	MessageSend:   "message/send",
-	MessageStream: "message/stream",
+	TasksSend:                "tasks/send",
+	TasksStream:              "tasks/sendSubscribe",
+	TasksStatus:              "tasks/status",
+	TasksCancel:              "tasks/cancel",
+	MessageSend:              "message/send",
+	MessageStream:            "message/stream",
+	TasksPushNotificationSet: "tasks/pushNotification/set",
+	TasksPushNotificationGet: "tasks/pushNotification/get",
+	TasksResubscribe:         "tasks/resubscribe",
+}
+
+// AllA2AMethods returns every method declared in A2AMethods, in declaration order
+func AllA2AMethods() []string {
+	v := reflect.ValueOf(A2AMethods)
+	methods := make([]string, 0, v.NumField())
+	for i := 0; i < v.NumField(); i++ {
+		methods = append(methods, v.Field(i).String())
+	}
+	return methods
+}
+
+// A2AErrorCodes contains the A2A-specific JSON-RPC error codes
+var A2AErrorCodes = struct {
+	TaskNotFound                 int
+	TaskNotCancelable            int
+	PushNotificationNotSupported int
+	UnsupportedOperation         int
+	ContentTypeNotSupported      int
+}{
+	TaskNotFound:                 -32001,
+	TaskNotCancelable:            -32002,
+	PushNotificationNotSupported: -32003,
+	UnsupportedOperation:         -32004,
+	ContentTypeNotSupported:      -32005,
 }
 
 // AgentAuthentication represents authentication requirements for an A2A agent
@@ -195,6 +599,14 @@ type AgentAuthentication struct {
 // AgentSkill represents a skill provided by an A2A agent
 // (copied from agentcard.go)
 type AgentSkill struct {
-	ID   string `json:"id"`
-	Name string `json:"name"`
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+	Examples    []string `json:"examples,omitempty"`
+
+	// InputModes and OutputModes override the card's default modes for
+	// this skill when set
+	InputModes  []string `json:"inputModes,omitempty"`
+	OutputModes []string `json:"outputModes,omitempty"`
 }
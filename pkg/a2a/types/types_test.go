@@ -0,0 +1,217 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestJSONPart tests creating, encoding and decoding annotated JSON parts
+func TestJSONPart(t *testing.T) {
+	part := NewJSONPart(map[string]interface{}{"replicas": 3}, "https://schemas.example.com/scale.json")
+	assert.True(t, part.IsJSON())
+	assert.NoError(t, part.ValidateJSON())
+
+	data, err := json.Marshal(part)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"type":"data","data":{"replicas":3},"mimeType":"application/json","schema":"https://schemas.example.com/scale.json"}`, string(data))
+
+	var decoded Part
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.True(t, decoded.IsJSON())
+	assert.Equal(t, "https://schemas.example.com/scale.json", decoded.Schema)
+}
+
+// TestPartIsJSON tests which parts are treated as structured JSON
+func TestPartIsJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		part Part
+		want bool
+	}{
+		{"annotated data", Part{Type: "data", MimeType: MimeTypeJSON}, true},
+		{"explicit type", Part{Type: MimeTypeJSON}, true},
+		{"plain data", Part{Type: "data"}, false},
+		{"text", Part{Type: "text", MimeType: MimeTypeJSON}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.part.IsJSON())
+		})
+	}
+}
+
+// TestPartValidateJSON tests validation of JSON part data and schema hints
+func TestPartValidateJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		part    Part
+		wantErr string
+	}{
+		{"no schema", NewJSONPart([]int{1, 2}, ""), ""},
+		{"relative schema", NewJSONPart(1, "schemas/scale.json"), "must be absolute"},
+		{"malformed schema", NewJSONPart(1, "http://[::1"), "invalid JSON part schema URI"},
+		{"unencodable data", NewJSONPart(make(chan int), ""), "not encodable"},
+		{"non-JSON part", Part{Type: "data", Data: make(chan int), Schema: "relative"}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.part.ValidateJSON()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+// TestGetCapabilities tests that capabilities are listed in a stable order
+// and that a card without any yields an empty, non-nil list
+func TestGetCapabilities(t *testing.T) {
+	card := &AgentCard{}
+	caps := card.GetCapabilities()
+	assert.NotNil(t, caps)
+	assert.Empty(t, caps)
+
+	data, err := json.Marshal(caps)
+	require.NoError(t, err)
+	assert.Equal(t, "[]", string(data))
+
+	card.Capabilities.StateTransitionHistory = true
+	card.Capabilities.Streaming = true
+	card.Capabilities.PushNotifications = true
+	assert.Equal(t, []string{"streaming", "pushNotifications", "stateTransitionHistory"}, card.GetCapabilities())
+}
+
+// TestNewAgentSummary tests flattening an agent and its card
+func TestNewAgentSummary(t *testing.T) {
+	seen := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	agent := &Agent{
+		ID:       "k8s",
+		URL:      "http://k8s:8083",
+		Status:   AgentStatusOnline,
+		LastSeen: seen,
+		Card: &AgentCard{
+			Name:         "k8s-agent",
+			Version:      "1.2.0",
+			Capabilities: AgentCapabilities{Streaming: true, StateTransitionHistory: true},
+			Skills:       []AgentSkill{{ID: "deploy", Name: "Deploy"}, {ID: "rollback"}},
+		},
+	}
+
+	assert.Equal(t, AgentSummary{
+		ID:                     "k8s",
+		Name:                   "k8s-agent",
+		URL:                    "http://k8s:8083",
+		Version:                "1.2.0",
+		Status:                 AgentStatusOnline,
+		Streaming:              true,
+		StateTransitionHistory: true,
+		Skills:                 []string{"Deploy", "rollback"},
+		LastSeen:               seen,
+	}, NewAgentSummary(agent))
+
+	// The agent's own name wins, and agents without a card list no skills
+	summary := NewAgentSummary(&Agent{ID: "bare", Name: "bare-agent"})
+	assert.Equal(t, "bare-agent", summary.Name)
+	assert.Equal(t, []string{}, summary.Skills)
+	data, err := json.Marshal(summary)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"skills":[]`)
+}
+
+// TestAllA2AMethods tests that every declared method is listed once, in
+// declaration order
+func TestAllA2AMethods(t *testing.T) {
+	methods := AllA2AMethods()
+	assert.Equal(t, A2AMethods.TasksSend, methods[0])
+	assert.Contains(t, methods, A2AMethods.TasksResubscribe)
+
+	seen := map[string]bool{}
+	for _, method := range methods {
+		assert.False(t, seen[method], "duplicate method %s", method)
+		seen[method] = true
+	}
+}
+
+// TestTaskRequestConversions tests converting between task requests and
+// message/send messages
+func TestTaskRequestConversions(t *testing.T) {
+	SetIDGenerator(func() string { return "generated" })
+	defer SetIDGenerator(nil)
+
+	msg := &Message{Role: "user", Parts: []Part{{Type: "text", Text: "hello"}}}
+	req := NewTaskRequest(msg)
+	assert.Equal(t, "generated", req.ID)
+	assert.Same(t, msg, req.Message)
+	assert.Equal(t, "generated", NewTaskRequest(nil).ID)
+
+	sent := req.ToMessageSend()
+	assert.Equal(t, "generated", sent.TaskID)
+	assert.Equal(t, msg.Parts, sent.Parts)
+	assert.Empty(t, msg.TaskID, "the request's message is not modified")
+
+	// A message for an existing task keeps its ID in both directions
+	resumed := NewTaskRequest(&Message{Role: "user", TaskID: "task-1"})
+	assert.Equal(t, "task-1", resumed.ID)
+	assert.Equal(t, "task-1", resumed.ToMessageSend().TaskID)
+	assert.Equal(t, &Message{TaskID: "task-2"}, (&TaskRequest{ID: "task-2"}).ToMessageSend())
+}
+
+// TestSetIDGenerator tests that a nil generator restores random IDs
+func TestSetIDGenerator(t *testing.T) {
+	SetIDGenerator(func() string { return "fixed" })
+	assert.Equal(t, "fixed", NewID())
+
+	SetIDGenerator(nil)
+	first, second := NewID(), NewID()
+	assert.Len(t, first, 36)
+	assert.NotEqual(t, first, second)
+}
+
+// TestTaskStateUnmarshal tests decoding task states from strings and
+// status objects, normalizing known spellings
+func TestTaskStateUnmarshal(t *testing.T) {
+	tests := []struct {
+		data string
+		want TaskState
+	}{
+		{`"working"`, TaskStateWorking},
+		{`"COMPLETED"`, TaskStateCompleted},
+		{`"input_required"`, TaskStateInputRequired},
+		{`"cancelled"`, TaskStateCanceled},
+		{`{"state":"failed","message":"boom"}`, TaskStateFailed},
+		{`"Paused"`, TaskState("Paused")},
+	}
+
+	for _, tt := range tests {
+		var state TaskState
+		require.NoError(t, json.Unmarshal([]byte(tt.data), &state), tt.data)
+		assert.Equal(t, tt.want, state, tt.data)
+	}
+
+	var state TaskState
+	assert.ErrorContains(t, json.Unmarshal([]byte(`42`), &state), "task state must be a string or status object")
+
+	var status TaskStatus
+	require.NoError(t, json.Unmarshal([]byte(`{"id":"task-1","status":{"state":"Working"}}`), &status))
+	assert.Equal(t, TaskStateWorking, status.Status)
+}
+
+// TestTaskStateClassification tests terminal and known states
+func TestTaskStateClassification(t *testing.T) {
+	for _, state := range []TaskState{TaskStateCompleted, TaskStateCanceled, TaskStateFailed} {
+		assert.True(t, state.IsTerminal(), state.String())
+	}
+	for _, state := range []TaskState{TaskStateSubmitted, TaskStateWorking, TaskStateInputRequired, TaskStateUnknown, "Paused"} {
+		assert.False(t, state.IsTerminal(), state.String())
+	}
+	assert.True(t, TaskStateUnknown.IsKnown())
+	assert.False(t, TaskState("Paused").IsKnown())
+}
@@ -0,0 +1,84 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidMessage is wrapped by every error returned by Message.Validate
+var ErrInvalidMessage = errors.New("invalid message")
+
+// MessageRoles are the roles a message may have
+var MessageRoles = []string{"user", "agent"}
+
+// Validate checks that the message has a known role and at least one part,
+// and that every part carries content appropriate to its kind. Parts of
+// unknown kinds are passed through unchecked.
+func (m *Message) Validate() error {
+	if m == nil {
+		return fmt.Errorf("%w: message is required", ErrInvalidMessage)
+	}
+	if m.Role == "" {
+		return fmt.Errorf("%w: role is required", ErrInvalidMessage)
+	}
+	known := false
+	for _, role := range MessageRoles {
+		if m.Role == role {
+			known = true
+			break
+		}
+	}
+	if !known {
+		return fmt.Errorf("%w: unknown role %q (use one of %v)", ErrInvalidMessage, m.Role, MessageRoles)
+	}
+	if len(m.Parts) == 0 {
+		return fmt.Errorf("%w: at least one part is required", ErrInvalidMessage)
+	}
+	for i := range m.Parts {
+		if err := m.Parts[i].Validate(); err != nil {
+			return fmt.Errorf("%w: part %d: %v", ErrInvalidMessage, i, err)
+		}
+	}
+	return nil
+}
+
+// Validate checks that the part carries content appropriate to its kind:
+// text for text parts, data for data parts, and inline content or a URL
+// for file, image and audio parts
+func (p *Part) Validate() error {
+	switch kind := p.Kind(); kind {
+	case PartKindText:
+		if p.Text == "" {
+			return errors.New("text part has no text")
+		}
+	case PartKindData:
+		if p.Data == nil {
+			return errors.New("data part has no data")
+		}
+		return p.ValidateJSON()
+	case PartKindFile:
+		if p.File == nil {
+			return errors.New("file part has no file")
+		}
+		return checkSource(kind, p.File.Content, p.File.URL)
+	case PartKindImage:
+		if p.Image == nil {
+			return errors.New("image part has no image")
+		}
+		return checkSource(kind, p.Image.Content, p.Image.URL)
+	case PartKindAudio:
+		if p.Audio == nil {
+			return errors.New("audio part has no audio")
+		}
+		return checkSource(kind, p.Audio.Content, p.Audio.URL)
+	}
+	return nil
+}
+
+// checkSource requires a media part to carry inline content or a URL
+func checkSource(kind PartKind, content []byte, url string) error {
+	if len(content) == 0 && url == "" {
+		return fmt.Errorf("%s part has neither content nor URL", kind)
+	}
+	return nil
+}
@@ -6,83 +6,467 @@
 package agentcard
 
 import (
+	"bytes"
 	"context"
+	"crypto/tls"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"mime"
 	"net/http"
 	"net/url"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/sirupsen/logrus"
 
+	"github.com/craine-io/openribcage/internal/auth"
+	"github.com/craine-io/openribcage/pkg/a2a/retry"
 	"github.com/craine-io/openribcage/pkg/a2a/types"
+	"github.com/craine-io/openribcage/pkg/httpcompress"
+	"github.com/craine-io/openribcage/pkg/metrics"
+	"github.com/craine-io/openribcage/pkg/netguard"
 )
 
+// WellKnownPath is the standard AgentCard location relative to an agent URL
+const WellKnownPath = "/.well-known/agent.json"
+
+// DefaultCardPaths are the AgentCard paths tried by a Discoverer, in order.
+// The empty path fetches the agent URL itself.
+var DefaultCardPaths = []string{
+	WellKnownPath,
+	"/.well-known/agent-card.json",
+	"",
+}
+
+// ErrEmptyResponse is returned when an agent serves an empty or
+// whitespace-only AgentCard body
+var ErrEmptyResponse = types.ErrEmptyResponse
+
+// PathError records a failed attempt to fetch an AgentCard from one URL
+type PathError struct {
+	URL string
+	Err error
+}
+
+// DiscoveryError is returned when no configured path yields a valid AgentCard
+type DiscoveryError struct {
+	AgentURL string
+	Attempts []PathError
+}
+
+// Error implements the error interface
+func (e *DiscoveryError) Error() string {
+	tried := make([]string, len(e.Attempts))
+	for i, attempt := range e.Attempts {
+		tried[i] = attempt.Err.Error()
+	}
+	return fmt.Sprintf("no valid AgentCard found for %s after trying %d paths: %s", e.AgentURL, len(e.Attempts), strings.Join(tried, "; "))
+}
+
+// Unwrap returns the error of every attempt
+func (e *DiscoveryError) Unwrap() []error {
+	errs := make([]error, len(e.Attempts))
+	for i, attempt := range e.Attempts {
+		errs[i] = attempt.Err
+	}
+	return errs
+}
+
 // Discoverer handles AgentCard discovery and validation
 type Discoverer struct {
-	client     *http.Client
-	logger     *logrus.Logger
-	timeout    time.Duration
-	maxRetries int
-	retryDelay time.Duration
+	client  *http.Client
+	logger  *logrus.Logger
+	timeout time.Duration
+	retry   retry.Policy
+	auth    *auth.Authenticator
+	creds   *auth.Credentials
+
+	// headCheck issues a HEAD request before fetching a card
+	headCheck bool
+
+	// hosts restricts which agent hosts may be contacted
+	hosts *netguard.Policy
+
+	// paths are the AgentCard paths tried, in order, for each agent
+	paths []string
+
+	// concurrency bounds the goroutines used by DiscoverMany
+	concurrency int
+
+	// keys verifies AgentCard signatures when set
+	keys KeyResolver
+
+	// jwks supplies signature keys from each card's jwksUrl when set
+	jwks *JWKSCache
+
+	// metrics counts discovery successes and failures when set
+	metrics *metrics.Metrics
+
+	// validators records ETag/Last-Modified per AgentCard URL for
+	// conditional requests
+	mu         sync.Mutex
+	validators map[string]*cardValidators
+}
+
+// cardValidators are the HTTP cache validators of a previously fetched card
+type cardValidators struct {
+	etag         string
+	lastModified string
+	card         *types.AgentCard
 }
 
 // NewDiscoverer creates a new AgentCard discoverer
-func NewDiscoverer(timeout time.Duration) *Discoverer {
-	return &Discoverer{
+func NewDiscoverer(timeout time.Duration, opts ...Option) *Discoverer {
+	d := &Discoverer{
 		client: &http.Client{
 			Timeout: timeout,
 		},
-		logger:     logrus.New(),
-		timeout:    timeout,
-		maxRetries: 3,
-		retryDelay: time.Second * 2,
+		logger:      logrus.New(),
+		timeout:     timeout,
+		retry:       retry.DefaultPolicy(),
+		auth:        auth.NewAuthenticator(),
+		validators:  make(map[string]*cardValidators),
+		hosts:       netguard.DefaultPolicy(),
+		paths:       append([]string(nil), DefaultCardPaths...),
+		concurrency: DefaultConcurrency,
+	}
+	for _, opt := range opts {
+		opt(d)
+	}
+	return d
+}
+
+// SetCredentials sets the credentials applied to AgentCard requests
+func (d *Discoverer) SetCredentials(creds *auth.Credentials) {
+	d.creds = creds
+}
+
+// SetHTTPClient replaces the http.Client used for AgentCard requests, e.g.
+// to supply a custom transport or test double. A nil client is ignored.
+func (d *Discoverer) SetHTTPClient(client *http.Client) {
+	if client != nil {
+		d.client = client
+	}
+}
+
+// SetTLSConfig applies cfg, e.g. a client certificate for mutual TLS, to
+// the transport used for AgentCard requests. A custom transport supplied through
+// SetHTTPClient that is not an *http.Transport is an error.
+func (d *Discoverer) SetTLSConfig(cfg *tls.Config) error {
+	transport, err := auth.TLSTransport(d.client.Transport, cfg)
+	if err != nil {
+		return err
+	}
+	client := *d.client
+	client.Transport = transport
+	d.client = &client
+	return nil
+}
+
+// SetProxyURL routes AgentCard requests through an http, https or socks5
+// proxy. An empty proxyURL restores the HTTP_PROXY/HTTPS_PROXY
+// environment variables. A custom transport supplied through SetHTTPClient
+// that is not an *http.Transport cannot be given a proxy and is an error.
+func (d *Discoverer) SetProxyURL(proxyURL string) error {
+	proxy, err := netguard.Proxy(proxyURL)
+	if err != nil {
+		return err
+	}
+	var transport *http.Transport
+	switch base := d.client.Transport.(type) {
+	case nil:
+		transport = http.DefaultTransport.(*http.Transport).Clone()
+	case *http.Transport:
+		transport = base.Clone()
+	default:
+		return fmt.Errorf("cannot set a proxy on transport of type %T", base)
 	}
+	transport.Proxy = proxy
+
+	client := *d.client
+	client.Transport = transport
+	d.client = &client
+	return nil
+}
+
+// SetRetryPolicy sets the retry policy used when fetching AgentCards
+func (d *Discoverer) SetRetryPolicy(policy retry.Policy) {
+	d.retry = policy
+}
+
+// SetHeadCheck enables a HEAD pre-check before each AgentCard GET. A 404
+// or a non-JSON Content-Type skips the GET entirely; agents that reject
+// HEAD (405 or 501) are fetched with GET as usual. Off by default.
+func (d *Discoverer) SetHeadCheck(enabled bool) {
+	d.headCheck = enabled
+}
+
+// SetHostPolicy sets the host allowlist/denylist checked before any request.
+// The default is netguard.DefaultPolicy; nil allows every host.
+func (d *Discoverer) SetHostPolicy(policy *netguard.Policy) {
+	d.hosts = policy
+}
+
+// SetCardPaths sets the AgentCard paths tried, in order, relative to the
+// agent URL. An empty path fetches the agent URL itself.
+func (d *Discoverer) SetCardPaths(paths []string) {
+	d.paths = append([]string(nil), paths...)
+}
+
+// SetMetrics records discovery successes and failures in m. A nil m
+// disables metrics.
+func (d *Discoverer) SetMetrics(m *metrics.Metrics) {
+	d.metrics = m
 }
 
 // Discover discovers an AgentCard from an agent URL
 func (d *Discoverer) Discover(ctx context.Context, agentURL string) (*types.AgentCard, error) {
+	card, _, _, err := d.discover(ctx, agentURL)
+	return card, err
+}
+
+// DiscoverIfChanged discovers an AgentCard like Discover, reporting
+// unchanged when the agent answered a conditional request with 304 Not
+// Modified. The previously fetched card is returned in that case.
+func (d *Discoverer) DiscoverIfChanged(ctx context.Context, agentURL string) (card *types.AgentCard, unchanged bool, err error) {
+	card, unchanged, _, err = d.discover(ctx, agentURL)
+	return card, unchanged, err
+}
+
+// discover runs discoverPaths and records the outcome in the configured metrics
+func (d *Discoverer) discover(ctx context.Context, agentURL string) (*types.AgentCard, bool, *cardResponse, error) {
+	card, unchanged, resp, err := d.discoverPaths(ctx, agentURL)
+	d.metrics.ObserveDiscovery(err)
+	return card, unchanged, resp, err
+}
+
+// discoverPaths tries each configured card path in order and returns the
+// first valid AgentCard. If only one path is configured its error is
+// returned as-is; otherwise a *DiscoveryError lists every path tried.
+func (d *Discoverer) discoverPaths(ctx context.Context, agentURL string) (*types.AgentCard, bool, *cardResponse, error) {
 	d.logger.Debugf("Discovering AgentCard from: %s", agentURL)
 
-	// 1. Construct .well-known/agent.json URL
-	agentCardURL := BuildAgentCardURL(agentURL)
+	paths := d.paths
+	if len(paths) == 0 {
+		paths = DefaultCardPaths
+	}
+
+	discoveryErr := &DiscoveryError{AgentURL: agentURL}
+	for _, path := range paths {
+		agentCardURL := BuildCardURL(agentURL, path)
+		card, unchanged, resp, err := d.discoverAt(ctx, agentURL, agentCardURL)
+		if err == nil {
+			if len(paths) > 1 {
+				d.logger.Infof("Found AgentCard for %s at path %q", agentURL, path)
+			}
+			return card, unchanged, resp, nil
+		}
+
+		// Policy, signature and cancellation errors end discovery
+		if len(paths) == 1 || errors.Is(err, netguard.ErrHostNotAllowed) || errors.Is(err, ErrInvalidSignature) || ctx.Err() != nil {
+			return nil, false, nil, err
+		}
+		d.logger.Debugf("No AgentCard at %s: %v", agentCardURL, err)
+		discoveryErr.Attempts = append(discoveryErr.Attempts, PathError{URL: agentCardURL, Err: err})
+	}
+
+	return nil, false, nil, discoveryErr
+}
+
+// discoverAt fetches and validates the AgentCard at agentCardURL. If the
+// card was fetched before with an ETag or Last-Modified header, the
+// request is conditional and a 304 response yields a copy of the previous
+// card. Relative endpoint URLs are resolved against agentURL.
+func (d *Discoverer) discoverAt(ctx context.Context, agentURL, agentCardURL string) (*types.AgentCard, bool, *cardResponse, error) {
+	// 1. Check the AgentCard URL against the host policy
 	d.logger.Debugf("AgentCard URL: %s", agentCardURL)
+	if err := d.hosts.CheckURL(agentCardURL); err != nil {
+		return nil, false, nil, err
+	}
+
+	// 2. Optionally check the card exists before downloading it
+	if d.headCheck {
+		if err := d.precheck(ctx, agentCardURL); err != nil {
+			return nil, false, nil, fmt.Errorf("failed to fetch AgentCard from %s: %w", agentCardURL, err)
+		}
+	}
 
-	// 2. Make HTTP GET request with retry logic
-	data, err := d.fetchWithRetry(ctx, agentCardURL)
+	// 3. Make HTTP GET request with retry logic
+	previous := d.lookupValidators(agentCardURL)
+	resp, err := d.fetchWithRetry(ctx, agentCardURL, previous)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch AgentCard from %s: %w", agentCardURL, err)
+		return nil, false, nil, fmt.Errorf("failed to fetch AgentCard from %s: %w", agentCardURL, err)
+	}
+	if resp.notModified {
+		d.logger.Debugf("AgentCard not modified: %s", agentCardURL)
+		return copyCard(previous.card), true, resp, nil
+	}
+
+	if isEmpty(resp.data) {
+		return nil, false, nil, fmt.Errorf("failed to fetch AgentCard from %s: %w", agentCardURL, ErrEmptyResponse)
+	}
+
+	// 4. Verify the card's signature, then parse JSON response into AgentCard
+	if d.keys != nil || d.jwks != nil {
+		if err := d.verifySignature(ctx, resp.data, resp.signature, agentCardURL); err != nil {
+			return nil, false, nil, fmt.Errorf("AgentCard from %s rejected: %w", agentCardURL, err)
+		}
 	}
 
-	// 3. Parse JSON response into AgentCard
 	var card types.AgentCard
-	if err := json.Unmarshal(data, &card); err != nil {
-		return nil, fmt.Errorf("failed to parse AgentCard JSON: %w", err)
+	if err := json.Unmarshal(resp.data, &card); err != nil {
+		return nil, false, nil, fmt.Errorf("failed to parse AgentCard JSON: %w", err)
 	}
 
-	// 4. Validate AgentCard format
+	// 5. Resolve relative endpoints and validate AgentCard format
+	if err := ResolveEndpoints(&card, BuildCardURL(agentURL, "")); err != nil {
+		return nil, false, nil, fmt.Errorf("AgentCard validation failed: %w", err)
+	}
 	if err := d.Validate(&card); err != nil {
-		return nil, fmt.Errorf("AgentCard validation failed: %w", err)
+		return nil, false, nil, fmt.Errorf("AgentCard validation failed: %w", err)
+	}
+
+	// 6. Keep advertised endpoints off private networks
+	for i, endpoint := range card.Endpoints {
+		if err := d.hosts.CheckProvidedURL(ctx, endpoint.URL, agentCardURL); err != nil {
+			return nil, false, nil, fmt.Errorf("AgentCard validation failed: endpoint %d: %w", i, err)
+		}
 	}
 
+	d.storeValidators(agentCardURL, resp, &card)
+
 	d.logger.Infof("Successfully discovered AgentCard: %s (version: %s)", card.Name, card.Version)
-	return &card, nil
+	return &card, false, resp, nil
+}
+
+// FetchRaw returns the raw body of the first configured card path that
+// serves JSON, without parsing or validating it as an AgentCard. It is
+// meant for conformance checks such as ValidateStrict.
+func (d *Discoverer) FetchRaw(ctx context.Context, agentURL string) ([]byte, error) {
+	paths := d.paths
+	if len(paths) == 0 {
+		paths = DefaultCardPaths
+	}
+
+	discoveryErr := &DiscoveryError{AgentURL: agentURL}
+	for _, path := range paths {
+		agentCardURL := BuildCardURL(agentURL, path)
+		if err := d.hosts.CheckURL(agentCardURL); err != nil {
+			return nil, err
+		}
+
+		resp, err := d.fetchWithRetry(ctx, agentCardURL, nil)
+		if err == nil && isEmpty(resp.data) {
+			err = ErrEmptyResponse
+		} else if err == nil && !json.Valid(resp.data) {
+			err = fmt.Errorf("response from %s is not JSON", agentCardURL)
+		}
+		if err == nil {
+			return resp.data, nil
+		}
+		if len(paths) == 1 || ctx.Err() != nil {
+			return nil, fmt.Errorf("failed to fetch AgentCard from %s: %w", agentCardURL, err)
+		}
+		discoveryErr.Attempts = append(discoveryErr.Attempts, PathError{URL: agentCardURL, Err: err})
+	}
+
+	return nil, discoveryErr
+}
+
+// lookupValidators returns the cache validators recorded for url, if any
+func (d *Discoverer) lookupValidators(url string) *cardValidators {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.validators[url]
+}
+
+// storeValidators records the ETag and Last-Modified of a fetched card.
+// Responses without either header clear any previous record.
+func (d *Discoverer) storeValidators(url string, resp *cardResponse, card *types.AgentCard) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if resp.etag == "" && resp.lastModified == "" {
+		delete(d.validators, url)
+		return
+	}
+	if d.validators == nil {
+		d.validators = make(map[string]*cardValidators)
+	}
+	d.validators[url] = &cardValidators{
+		etag:         resp.etag,
+		lastModified: resp.lastModified,
+		card:         copyCard(card),
+	}
+}
+
+// precheck issues a HEAD request for an AgentCard URL. It returns an error
+// when the GET can be skipped: the card does not exist or is not JSON.
+func (d *Discoverer) precheck(ctx context.Context, url string) error {
+	req, err := http.NewRequestWithContext(ctx, "HEAD", url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", "openribcage/1.0 (A2A-Protocol-Client)")
+	if err := d.auth.AddAuthHeaders(req, d.creds); err != nil {
+		return fmt.Errorf("failed to add auth headers: %w", err)
+	}
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		// Let the GET and its retries decide
+		d.logger.Debugf("HEAD pre-check failed for %s: %v", url, auth.RedactError(err, auth.RedactParam(d.creds)))
+		return nil
+	}
+	resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusNotFound:
+		return fmt.Errorf("AgentCard not found (404) at %s", url)
+	case http.StatusMethodNotAllowed, http.StatusNotImplemented:
+		d.logger.Debugf("HEAD not supported by %s, falling back to GET", url)
+		return nil
+	}
+
+	if contentType := resp.Header.Get("Content-Type"); resp.StatusCode == http.StatusOK && contentType != "" && !isJSONContentType(contentType) {
+		return fmt.Errorf("AgentCard at %s has unsupported content type %q", url, contentType)
+	}
+	return nil
 }
 
-// fetchWithRetry performs HTTP GET with retry logic
-func (d *Discoverer) fetchWithRetry(ctx context.Context, url string) ([]byte, error) {
+// isJSONContentType reports whether a Content-Type header denotes JSON
+func isJSONContentType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// cardResponse is a fetched AgentCard body with its HTTP caching headers
+type cardResponse struct {
+	data         []byte
+	etag         string
+	lastModified string
+	cacheControl string
+	signature    string
+	notModified  bool
+}
+
+// fetchWithRetry performs HTTP GET with retry logic. When previous is set
+// the request is made conditional on its ETag and Last-Modified.
+func (d *Discoverer) fetchWithRetry(ctx context.Context, url string, previous *cardValidators) (*cardResponse, error) {
 	var lastErr error
 
-	for attempt := 0; attempt <= d.maxRetries; attempt++ {
+	for attempt := 0; attempt <= d.retry.Attempts; attempt++ {
 		if attempt > 0 {
-			d.logger.Debugf("Retry attempt %d/%d for %s", attempt, d.maxRetries, url)
-			select {
-			case <-ctx.Done():
-				return nil, ctx.Err()
-			case <-time.After(d.retryDelay):
-				// Continue with retry
+			d.logger.Debugf("Retry attempt %d/%d for %s", attempt, d.retry.Attempts, url)
+			if err := d.retry.Wait(ctx, attempt); err != nil {
+				return nil, err
 			}
 		}
 
@@ -95,13 +479,35 @@ func (d *Discoverer) fetchWithRetry(ctx context.Context, url string) ([]byte, er
 		// Set appropriate headers for AgentCard discovery
 		req.Header.Set("Accept", "application/json")
 		req.Header.Set("User-Agent", "openribcage/1.0 (A2A-Protocol-Client)")
+		httpcompress.SetAcceptEncoding(req)
+		if previous != nil {
+			if previous.etag != "" {
+				req.Header.Set("If-None-Match", previous.etag)
+			}
+			if previous.lastModified != "" {
+				req.Header.Set("If-Modified-Since", previous.lastModified)
+			}
+		}
+		if err := d.auth.AddAuthHeaders(req, d.creds); err != nil {
+			return nil, fmt.Errorf("failed to add auth headers: %w", err)
+		}
 
 		resp, err := d.client.Do(req)
 		if err != nil {
-			lastErr = fmt.Errorf("HTTP request failed: %w", err)
+			lastErr = fmt.Errorf("HTTP request failed: %w", auth.RedactError(err, auth.RedactParam(d.creds)))
 			continue
 		}
 		defer resp.Body.Close()
+		if err := httpcompress.Decode(resp); err != nil {
+			return nil, err
+		}
+
+		cached := &cardResponse{
+			etag:         resp.Header.Get("ETag"),
+			lastModified: resp.Header.Get("Last-Modified"),
+			cacheControl: resp.Header.Get("Cache-Control"),
+			signature:    resp.Header.Get(SignatureHeader),
+		}
 
 		// Check for successful response
 		if resp.StatusCode == http.StatusOK {
@@ -110,7 +516,12 @@ func (d *Discoverer) fetchWithRetry(ctx context.Context, url string) ([]byte, er
 				lastErr = fmt.Errorf("failed to read response body: %w", err)
 				continue
 			}
-			return data, nil
+			cached.data = data
+			return cached, nil
+		}
+		if resp.StatusCode == http.StatusNotModified && previous != nil {
+			cached.notModified = true
+			return cached, nil
 		}
 
 		// Handle specific HTTP status codes
@@ -131,7 +542,7 @@ func (d *Discoverer) fetchWithRetry(ctx context.Context, url string) ([]byte, er
 		}
 	}
 
-	return nil, fmt.Errorf("failed after %d attempts: %w", d.maxRetries+1, lastErr)
+	return nil, fmt.Errorf("failed after %d attempts: %w", d.retry.Attempts+1, lastErr)
 }
 
 // Validate validates an AgentCard format and content
@@ -153,15 +564,55 @@ func (d *Discoverer) Validate(card *types.AgentCard) error {
 		}
 	}
 
-	// 3. Validate capabilities format (basic check)
-	// Capabilities is now an object, not a slice. Check that at least one field is present (all are bools, so just check for presence).
-	// Optionally, you could check that the struct is not the zero value (all false), but that's not required by spec.
-	// So, no loop needed here.
+	// 3. Skill IDs must be present and unique so tasks can be routed by ID
+	seen := make(map[string]bool, len(card.Skills))
+	for i, skill := range card.Skills {
+		if skill.ID == "" {
+			return fmt.Errorf("invalid skill %d: skill id is required", i)
+		}
+		if seen[skill.ID] {
+			return fmt.Errorf("invalid skill %d: duplicate skill id %q", i, skill.ID)
+		}
+		seen[skill.ID] = true
+	}
+
+	// 4. Capabilities are typed booleans, so there is nothing further to
+	// check once the card has been unmarshalled. All-false is valid per spec.
 
 	d.logger.Debugf("AgentCard validation successful: %s", card.Name)
 	return nil
 }
 
+// ResolveEndpoints rewrites relative endpoint URLs in card as absolute URLs
+// resolved against the agent's base URL. A relative path such as "a2a" is
+// resolved beneath the base URL's path, while "/a2a" replaces it. Absolute
+// URLs, including ones with unsupported schemes, are left for Validate.
+func ResolveEndpoints(card *types.AgentCard, baseURL string) error {
+	base, err := url.Parse(baseURL)
+	if err != nil {
+		return fmt.Errorf("invalid agent base URL: %w", err)
+	}
+	if !strings.HasSuffix(base.Path, "/") {
+		base.Path += "/"
+	}
+
+	for i := range card.Endpoints {
+		endpoint := &card.Endpoints[i]
+		if endpoint.URL == "" {
+			continue
+		}
+		ref, err := url.Parse(endpoint.URL)
+		if err != nil {
+			return fmt.Errorf("invalid endpoint %d: invalid endpoint URL: %w", i, err)
+		}
+		if ref.IsAbs() {
+			continue
+		}
+		endpoint.URL = base.ResolveReference(ref).String()
+	}
+	return nil
+}
+
 // validateEndpoint validates a single endpoint
 func (d *Discoverer) validateEndpoint(endpoint *types.Endpoint) error {
 	// Validate URL format
@@ -179,7 +630,7 @@ func (d *Discoverer) validateEndpoint(endpoint *types.Endpoint) error {
 	}
 
 	// Validate endpoint type
-	validTypes := []string{"a2a", "streaming", "webhook"}
+	validTypes := []string{"a2a", "streaming", "webhook", "upload"}
 	if !contains(validTypes, endpoint.Type) {
 		return fmt.Errorf("unsupported endpoint type: %s (supported: %v)", endpoint.Type, validTypes)
 	}
@@ -208,19 +659,15 @@ func contains(slice []string, item string) bool {
 
 // isValidA2AMethod checks if a method is a valid A2A protocol method
 func isValidA2AMethod(method string) bool {
-	validMethods := []string{
-		types.A2AMethods.TasksSend,
-		types.A2AMethods.TasksStream,
-		types.A2AMethods.TasksStatus,
-		types.A2AMethods.TasksCancel,
-		types.A2AMethods.MessageSend,
-		types.A2AMethods.MessageStream,
-	}
-	return contains(validMethods, method)
+	return contains(types.AllA2AMethods(), method)
 }
 
 // Parse parses AgentCard JSON data
 func (d *Discoverer) Parse(data []byte) (*types.AgentCard, error) {
+	if isEmpty(data) {
+		return nil, ErrEmptyResponse
+	}
+
 	var card types.AgentCard
 	if err := json.Unmarshal(data, &card); err != nil {
 		return nil, fmt.Errorf("failed to parse AgentCard JSON: %w", err)
@@ -233,8 +680,19 @@ func (d *Discoverer) Parse(data []byte) (*types.AgentCard, error) {
 	return &card, nil
 }
 
+// isEmpty reports whether data is empty or only whitespace
+func isEmpty(data []byte) bool {
+	return len(bytes.TrimSpace(data)) == 0
+}
+
 // BuildAgentCardURL constructs the AgentCard URL from a base agent URL
 func BuildAgentCardURL(agentURL string) string {
+	return BuildCardURL(agentURL, WellKnownPath)
+}
+
+// BuildCardURL constructs the URL of an AgentCard served at path relative
+// to a base agent URL. An empty path returns the agent URL itself.
+func BuildCardURL(agentURL, path string) string {
 	// Clean and parse the URL
 	agentURL = strings.TrimSpace(agentURL)
 	if agentURL == "" {
@@ -250,11 +708,13 @@ func BuildAgentCardURL(agentURL string) string {
 	parsedURL, err := url.Parse(agentURL)
 	if err != nil {
 		// Fallback to simple string concatenation
-		return strings.TrimSuffix(agentURL, "/") + "/.well-known/agent.json"
+		return strings.TrimSuffix(agentURL, "/") + path
 	}
 
-	// Construct the .well-known path
-	parsedURL.Path = strings.TrimSuffix(parsedURL.Path, "/") + "/.well-known/agent.json"
+	// Construct the card path
+	if path != "" {
+		parsedURL.Path = strings.TrimSuffix(parsedURL.Path, "/") + path
+	}
 	return parsedURL.String()
 }
 
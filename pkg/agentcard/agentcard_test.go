@@ -0,0 +1,433 @@
+package agentcard
+
+import (
+	"compress/gzip"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/craine-io/openribcage/pkg/a2a/retry"
+	"github.com/craine-io/openribcage/pkg/a2a/types"
+	"github.com/craine-io/openribcage/pkg/httpcompress"
+	"github.com/craine-io/openribcage/test/fixtures"
+)
+
+// cardWithMethods returns the raw JSON of the full fixture card with a
+// single a2a endpoint advertising methods
+func cardWithMethods(t *testing.T, methods ...string) []byte {
+	raw, err := fixtures.Raw("full")
+	require.NoError(t, err)
+	var card map[string]interface{}
+	require.NoError(t, json.Unmarshal(raw, &card))
+	card["endpoints"] = []map[string]interface{}{{
+		"type":    "a2a",
+		"url":     "https://agents.example.com/full-agent",
+		"methods": methods,
+	}}
+
+	raw, err = json.Marshal(card)
+	require.NoError(t, err)
+	return raw
+}
+
+// TestSchemaMethodsMatchTypes tests that the schema's endpoint method enum
+// lists exactly the methods declared in types.A2AMethods
+func TestSchemaMethodsMatchTypes(t *testing.T) {
+	root, err := loadSchema()
+	require.NoError(t, err)
+
+	var enum []string
+	for _, value := range root.Defs["Endpoint"].Properties["methods"].Items.Enum {
+		enum = append(enum, value.(string))
+	}
+	assert.Equal(t, types.AllA2AMethods(), enum)
+}
+
+// TestValidatePushNotificationMethods tests that a card advertising the
+// push notification methods validates
+func TestValidatePushNotificationMethods(t *testing.T) {
+	d := NewDiscoverer(5 * time.Second)
+	raw := cardWithMethods(t, types.A2AMethods.TasksSend, types.A2AMethods.TasksPushNotificationSet, types.A2AMethods.TasksPushNotificationGet)
+
+	card, err := d.Parse(raw)
+	require.NoError(t, err)
+	assert.NoError(t, d.Validate(card))
+	assert.NoError(t, d.ValidateStrict(card, raw))
+}
+
+// TestValidateUnknownMethod tests that a card advertising an unknown
+// method is rejected by both validators
+func TestValidateUnknownMethod(t *testing.T) {
+	d := NewDiscoverer(5 * time.Second)
+	raw := cardWithMethods(t, "tasks/unknown")
+
+	var card types.AgentCard
+	require.NoError(t, json.Unmarshal(raw, &card))
+	assert.ErrorContains(t, d.Validate(&card), "invalid A2A method: tasks/unknown")
+
+	var schemaErr *SchemaError
+	require.ErrorAs(t, d.ValidateStrict(&card, raw), &schemaErr)
+	assert.Contains(t, schemaErr.Violations[0].Pointer, "/endpoints/0/methods/0")
+}
+
+// TestValidateResubscribeMethod tests that a card advertising
+// tasks/resubscribe validates
+func TestValidateResubscribeMethod(t *testing.T) {
+	d := NewDiscoverer(5 * time.Second)
+	raw := cardWithMethods(t, types.A2AMethods.TasksStream, types.A2AMethods.TasksResubscribe)
+
+	card, err := d.Parse(raw)
+	require.NoError(t, err)
+	assert.NoError(t, d.Validate(card))
+	assert.NoError(t, d.ValidateStrict(card, raw))
+}
+
+// TestAgentCardSkills tests skill lookup and skill ID validation
+func TestAgentCardSkills(t *testing.T) {
+	discoverer := NewDiscoverer(5 * time.Second)
+
+	card := &types.AgentCard{
+		Name:    "k8s-agent",
+		Version: "1.0.0",
+		Skills: []types.AgentSkill{
+			{ID: "diagnose", Name: "Diagnose", Tags: []string{"k8s"}},
+			{ID: "scale", Name: "Scale"},
+		},
+	}
+	require.NoError(t, discoverer.Validate(card))
+
+	skill, ok := card.FindSkill("scale")
+	require.True(t, ok)
+	assert.Equal(t, "Scale", skill.Name)
+	assert.True(t, card.HasSkill("diagnose"))
+
+	_, ok = card.FindSkill("deploy")
+	assert.False(t, ok)
+	assert.False(t, card.HasSkill("deploy"))
+
+	t.Run("duplicate skill id", func(t *testing.T) {
+		dup := *card
+		dup.Skills = append([]types.AgentSkill{}, card.Skills...)
+		dup.Skills = append(dup.Skills, types.AgentSkill{ID: "scale", Name: "Scale again"})
+		err := discoverer.Validate(&dup)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `duplicate skill id "scale"`)
+	})
+
+	t.Run("missing skill id", func(t *testing.T) {
+		missing := *card
+		missing.Skills = []types.AgentSkill{{Name: "Anonymous"}}
+		err := discoverer.Validate(&missing)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "skill id is required")
+	})
+}
+
+// TestValidateUploadEndpoint tests that a card advertising an upload
+// endpoint validates
+func TestValidateUploadEndpoint(t *testing.T) {
+	d := NewDiscoverer(5 * time.Second)
+	var doc map[string]interface{}
+	require.NoError(t, json.Unmarshal(cardWithMethods(t, types.A2AMethods.TasksSend), &doc))
+	doc["endpoints"] = append(doc["endpoints"].([]interface{}), map[string]interface{}{
+		"type": "upload",
+		"url":  "https://agents.example.com/full-agent/uploads",
+	})
+	raw, err := json.Marshal(doc)
+	require.NoError(t, err)
+
+	card, err := d.Parse(raw)
+	require.NoError(t, err)
+	assert.Equal(t, "https://agents.example.com/full-agent/uploads", card.UploadEndpoint())
+	assert.NoError(t, d.ValidateStrict(card, raw))
+}
+
+// TestDiscoverRetry tests that card fetches follow the discoverer's retry
+// policy for server errors and give up at once on client errors
+func TestDiscoverRetry(t *testing.T) {
+	raw, err := fixtures.Raw("minimal")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		failures int
+		status   int
+		wantHits int
+		wantErr  bool
+	}{
+		{"recovers after server errors", 2, http.StatusBadGateway, 3, false},
+		{"gives up after the last attempt", 5, http.StatusInternalServerError, 3, true},
+		{"client errors are not retried", 5, http.StatusNotFound, 1, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mu sync.Mutex
+			hits := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				mu.Lock()
+				hits++
+				fail := hits <= tt.failures
+				mu.Unlock()
+				if fail {
+					http.Error(w, "failing", tt.status)
+					return
+				}
+				w.Header().Set("Content-Type", "application/json")
+				w.Write(raw)
+			}))
+			defer server.Close()
+
+			d := NewDiscoverer(5 * time.Second)
+			d.SetRetryPolicy(retry.Policy{Attempts: 2, BaseDelay: time.Millisecond})
+			d.SetCardPaths([]string{"/.well-known/agent.json"})
+
+			_, err := d.Discover(context.Background(), server.URL)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			assert.Equal(t, tt.wantHits, hits)
+		})
+	}
+}
+
+// TestHeadCheck tests that the HEAD pre-check skips the GET for missing
+// and non-JSON cards and falls back to GET when HEAD is not supported
+func TestHeadCheck(t *testing.T) {
+	raw, err := fixtures.Raw("minimal")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		enabled     bool
+		headStatus  int
+		contentType string
+		wantMethods []string
+		wantErr     string
+	}{
+		{"disabled", false, http.StatusNotFound, "", []string{"GET"}, ""},
+		{"card exists", true, http.StatusOK, "application/json", []string{"HEAD", "GET"}, ""},
+		{"card missing", true, http.StatusNotFound, "", []string{"HEAD"}, "AgentCard not found (404)"},
+		{"not JSON", true, http.StatusOK, "text/html", []string{"HEAD"}, `unsupported content type "text/html"`},
+		{"HEAD not allowed", true, http.StatusMethodNotAllowed, "", []string{"HEAD", "GET"}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mu sync.Mutex
+			var methods []string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				mu.Lock()
+				methods = append(methods, r.Method)
+				mu.Unlock()
+				if r.Method == "HEAD" {
+					if tt.contentType != "" {
+						w.Header().Set("Content-Type", tt.contentType)
+					}
+					w.WriteHeader(tt.headStatus)
+					return
+				}
+				w.Header().Set("Content-Type", "application/json")
+				w.Write(raw)
+			}))
+			defer server.Close()
+
+			d := NewDiscoverer(5 * time.Second)
+			d.SetRetryPolicy(retry.Policy{})
+			d.SetCardPaths([]string{"/.well-known/agent.json"})
+			d.SetHeadCheck(tt.enabled)
+
+			_, err := d.Discover(context.Background(), server.URL)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.wantErr)
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			assert.Equal(t, tt.wantMethods, methods)
+		})
+	}
+}
+
+// TestResolveEndpoints tests resolving relative endpoint URLs against the
+// agent's base URL
+func TestResolveEndpoints(t *testing.T) {
+	card := &types.AgentCard{Endpoints: []types.Endpoint{
+		{Type: "a2a", URL: "a2a"},
+		{Type: "a2a", URL: "/root/a2a"},
+		{Type: "a2a", URL: "../sibling"},
+		{Type: "a2a", URL: "https://other.example.com/a2a"},
+		{Type: "a2a", URL: "ftp://files.example.com"},
+		{Type: "a2a", URL: ""},
+	}}
+	require.NoError(t, ResolveEndpoints(card, "http://agents.example.com/api/k8s"))
+
+	var urls []string
+	for _, endpoint := range card.Endpoints {
+		urls = append(urls, endpoint.URL)
+	}
+	assert.Equal(t, []string{
+		"http://agents.example.com/api/k8s/a2a",
+		"http://agents.example.com/root/a2a",
+		"http://agents.example.com/api/sibling",
+		"https://other.example.com/a2a",
+		"ftp://files.example.com",
+		"",
+	}, urls)
+
+	card = &types.AgentCard{Endpoints: []types.Endpoint{{Type: "a2a", URL: "%zz"}}}
+	assert.ErrorContains(t, ResolveEndpoints(card, "http://agents.example.com"), "invalid endpoint 0")
+	assert.ErrorContains(t, ResolveEndpoints(card, "http://[::1"), "invalid agent base URL")
+}
+
+// TestDiscoverRelativeEndpoints tests that discovered cards have their
+// relative endpoints resolved against the agent URL
+func TestDiscoverRelativeEndpoints(t *testing.T) {
+	var agentURL string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"name":      "relative-agent",
+			"version":   "1.0.0",
+			"url":       agentURL,
+			"endpoints": []map[string]string{{"type": "a2a", "url": "rpc"}},
+		})
+	}))
+	defer server.Close()
+	agentURL = server.URL + "/agents/k8s"
+
+	d := NewDiscoverer(5 * time.Second)
+	d.SetRetryPolicy(retry.Policy{})
+	d.SetCardPaths([]string{"/.well-known/agent.json"})
+	card, err := d.Discover(context.Background(), agentURL)
+	require.NoError(t, err)
+	assert.Equal(t, server.URL+"/agents/k8s/rpc", card.Endpoints[0].URL)
+}
+
+// TestEmptyCard tests that empty and whitespace-only cards are reported
+// as ErrEmptyResponse rather than malformed JSON
+func TestEmptyCard(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(" \r\n\t"))
+	}))
+	defer server.Close()
+
+	d := NewDiscoverer(5 * time.Second)
+	d.SetRetryPolicy(retry.Policy{})
+	d.SetCardPaths([]string{"/.well-known/agent.json"})
+
+	_, err := d.Discover(context.Background(), server.URL)
+	assert.ErrorIs(t, err, ErrEmptyResponse)
+	_, err = d.FetchRaw(context.Background(), server.URL)
+	assert.ErrorIs(t, err, ErrEmptyResponse)
+
+	_, err = d.Parse([]byte("  \n"))
+	assert.ErrorIs(t, err, ErrEmptyResponse)
+	_, err = d.Parse([]byte("{"))
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, ErrEmptyResponse)
+}
+
+// TestSetProxyURL tests that AgentCard requests go through the proxy
+func TestSetProxyURL(t *testing.T) {
+	raw, err := fixtures.Raw("minimal")
+	require.NoError(t, err)
+	var mu sync.Mutex
+	var proxied []string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		proxied = append(proxied, r.URL.String())
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(raw)
+	}))
+	defer proxy.Close()
+
+	d := NewDiscoverer(5 * time.Second)
+	d.SetRetryPolicy(retry.Policy{})
+	d.SetCardPaths([]string{"/.well-known/agent.json"})
+	assert.ErrorContains(t, d.SetProxyURL("ftp://proxy"), "unsupported proxy scheme")
+	require.NoError(t, d.SetProxyURL(proxy.URL))
+
+	_, err = d.Discover(context.Background(), "http://127.0.0.1:1")
+	require.NoError(t, err)
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []string{"http://127.0.0.1:1/.well-known/agent.json"}, proxied)
+}
+
+// roundTripFunc adapts a function to http.RoundTripper
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+// RoundTrip implements http.RoundTripper
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// TestSetHTTPClient tests that an injected http.Client carries AgentCard
+// requests, and that SetProxyURL and SetTLSConfig refuse to replace its
+// transport
+func TestSetHTTPClient(t *testing.T) {
+	raw, err := fixtures.Raw("minimal")
+	require.NoError(t, err)
+	var mu sync.Mutex
+	var requested []string
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		mu.Lock()
+		requested = append(requested, req.URL.String())
+		mu.Unlock()
+		rec := httptest.NewRecorder()
+		rec.Header().Set("Content-Type", "application/json")
+		rec.Write(raw)
+		return rec.Result(), nil
+	})
+
+	d := NewDiscoverer(5 * time.Second)
+	d.SetRetryPolicy(retry.Policy{})
+	d.SetCardPaths([]string{"/.well-known/agent.json"})
+	d.SetHTTPClient(&http.Client{Transport: transport})
+	assert.ErrorContains(t, d.SetProxyURL("http://proxy:3128"), "cannot set a proxy")
+	assert.ErrorContains(t, d.SetTLSConfig(&tls.Config{}), "cannot apply TLS config")
+
+	_, err = d.Discover(context.Background(), "http://127.0.0.1:1")
+	require.NoError(t, err)
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []string{"http://127.0.0.1:1/.well-known/agent.json"}, requested)
+}
+
+// TestDiscoverCompressed tests decoding of a gzip encoded AgentCard
+func TestDiscoverCompressed(t *testing.T) {
+	cardJSON, err := json.Marshal(&types.AgentCard{Name: "k8s-agent", Version: "1.0.0", Description: "served gzipped"})
+	require.NoError(t, err)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, httpcompress.AcceptEncoding, r.Header.Get("Accept-Encoding"))
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Encoding", "gzip")
+		gz := gzip.NewWriter(w)
+		gz.Write(cardJSON)
+		gz.Close()
+	}))
+	defer server.Close()
+
+	d := NewDiscoverer(5 * time.Second)
+	d.SetRetryPolicy(retry.Policy{})
+	d.SetCardPaths([]string{WellKnownPath})
+	card, err := d.Discover(context.Background(), server.URL)
+	require.NoError(t, err)
+	assert.Equal(t, "served gzipped", card.Description)
+}
@@ -0,0 +1,169 @@
+package agentcard
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/craine-io/openribcage/pkg/a2a/types"
+)
+
+// CachingDiscoverer wraps a Discoverer with an in-memory AgentCard cache
+// keyed by AgentCard URL.
+//
+// Cards are kept for the configured TTL unless the agent's Cache-Control
+// header says otherwise: max-age overrides the TTL, no-cache forces
+// revalidation on every use, and no-store disables caching. Expired cards
+// are refetched with the Discoverer's conditional requests, so unchanged
+// cards cost a 304. Concurrent discoveries of the same URL share a single
+// HTTP request.
+type CachingDiscoverer struct {
+	discoverer *Discoverer
+	ttl        time.Duration
+	maxSize    int
+
+	mu       sync.Mutex
+	entries  map[string]*cacheEntry
+	inflight map[string]*inflightDiscovery
+}
+
+// cacheEntry is a cached AgentCard
+type cacheEntry struct {
+	card      *types.AgentCard
+	storedAt  time.Time
+	expiresAt time.Time
+}
+
+// inflightDiscovery is a discovery shared by concurrent callers
+type inflightDiscovery struct {
+	done chan struct{}
+	card *types.AgentCard
+	err  error
+}
+
+// NewCachingDiscoverer creates a cache in front of discoverer.
+// A maxSize of zero or less leaves the cache unbounded.
+func NewCachingDiscoverer(discoverer *Discoverer, ttl time.Duration, maxSize int) *CachingDiscoverer {
+	return &CachingDiscoverer{
+		discoverer: discoverer,
+		ttl:        ttl,
+		maxSize:    maxSize,
+		entries:    make(map[string]*cacheEntry),
+		inflight:   make(map[string]*inflightDiscovery),
+	}
+}
+
+// Discover returns the AgentCard for agentURL, from the cache when fresh.
+// Each caller receives its own copy of the card.
+func (c *CachingDiscoverer) Discover(ctx context.Context, agentURL string) (*types.AgentCard, error) {
+	key := BuildAgentCardURL(agentURL)
+
+	c.mu.Lock()
+	if entry, ok := c.entries[key]; ok && time.Now().Before(entry.expiresAt) {
+		c.mu.Unlock()
+		return copyCard(entry.card), nil
+	}
+	call, ok := c.inflight[key]
+	if !ok {
+		call = &inflightDiscovery{done: make(chan struct{})}
+		c.inflight[key] = call
+		go c.fetch(ctx, agentURL, key, call)
+	}
+	c.mu.Unlock()
+
+	select {
+	case <-call.done:
+		if call.err != nil {
+			return nil, call.err
+		}
+		return copyCard(call.card), nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Invalidate removes the cached AgentCard for agentURL
+func (c *CachingDiscoverer) Invalidate(agentURL string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, BuildAgentCardURL(agentURL))
+}
+
+// fetch discovers a card on behalf of every caller waiting on call.
+// It runs detached from the first caller's cancellation so that other
+// waiters are not failed by it; the Discoverer's timeout still applies.
+func (c *CachingDiscoverer) fetch(ctx context.Context, agentURL, key string, call *inflightDiscovery) {
+	card, _, resp, err := c.discoverer.discover(context.WithoutCancel(ctx), agentURL)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	defer close(call.done)
+	delete(c.inflight, key)
+
+	if err != nil {
+		call.err = err
+		return
+	}
+	call.card = card
+
+	ttl, cacheable := cacheTTL(resp.cacheControl, c.ttl)
+	if !cacheable {
+		delete(c.entries, key)
+		return
+	}
+
+	now := time.Now()
+	if _, exists := c.entries[key]; !exists {
+		c.evict()
+	}
+	c.entries[key] = &cacheEntry{
+		card:      card,
+		storedAt:  now,
+		expiresAt: now.Add(ttl),
+	}
+}
+
+// evict makes room for one more entry by removing the oldest ones.
+// Callers must hold c.mu.
+func (c *CachingDiscoverer) evict() {
+	if c.maxSize <= 0 {
+		return
+	}
+	for len(c.entries) >= c.maxSize {
+		var oldestKey string
+		var oldest time.Time
+		for key, entry := range c.entries {
+			if oldestKey == "" || entry.storedAt.Before(oldest) {
+				oldestKey, oldest = key, entry.storedAt
+			}
+		}
+		delete(c.entries, oldestKey)
+	}
+}
+
+// cacheTTL applies a Cache-Control header to the default TTL. cacheable is
+// false for no-store; no-cache yields a zero TTL so every use refetches.
+func cacheTTL(cacheControl string, ttl time.Duration) (time.Duration, bool) {
+	for _, directive := range strings.Split(cacheControl, ",") {
+		name, value, _ := strings.Cut(strings.TrimSpace(strings.ToLower(directive)), "=")
+		switch name {
+		case "no-store":
+			return 0, false
+		case "no-cache":
+			return 0, true
+		case "max-age":
+			if seconds, err := strconv.Atoi(strings.Trim(value, `"`)); err == nil && seconds >= 0 {
+				ttl = time.Duration(seconds) * time.Second
+			}
+		}
+	}
+	return ttl, true
+}
+
+// copyCard returns a shallow copy of card so callers cannot modify the cached value
+func copyCard(card *types.AgentCard) *types.AgentCard {
+	cp := *card
+	return &cp
+}
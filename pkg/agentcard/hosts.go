@@ -0,0 +1,92 @@
+package agentcard
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// HostCheck reports the reachability and AgentCard status of a discovery host
+type HostCheck struct {
+	Host      string `json:"host"`
+	Reachable bool   `json:"reachable"`
+	ValidCard bool   `json:"valid_card"`
+	AgentName string `json:"agent_name,omitempty"`
+	Error     string `json:"error,omitempty"`
+
+	// Err is the failure behind Error, for inspection with errors.Is
+	Err error `json:"-"`
+}
+
+// OK reports whether the host is reachable and serves a valid AgentCard
+func (h HostCheck) OK() bool {
+	return h.Reachable && h.ValidCard
+}
+
+// CheckHosts checks each discovery host concurrently, reporting whether it
+// responds over HTTP and whether it serves a valid AgentCard. Results are
+// returned in the same order as hosts.
+func (d *Discoverer) CheckHosts(ctx context.Context, hosts []string) []HostCheck {
+	results := make([]HostCheck, len(hosts))
+
+	var wg sync.WaitGroup
+	for i, host := range hosts {
+		wg.Add(1)
+		go func(i int, host string) {
+			defer wg.Done()
+			results[i] = d.checkHost(ctx, host)
+		}(i, host)
+	}
+	wg.Wait()
+
+	return results
+}
+
+// checkHost checks a single discovery host
+func (d *Discoverer) checkHost(ctx context.Context, host string) HostCheck {
+	result := HostCheck{Host: host}
+
+	if err := d.ping(ctx, host); err != nil {
+		result.Error, result.Err = err.Error(), err
+		return result
+	}
+	result.Reachable = true
+
+	card, err := d.Discover(ctx, host)
+	if err != nil {
+		result.Error, result.Err = err.Error(), err
+		return result
+	}
+	result.ValidCard = true
+	result.AgentName = card.Name
+
+	return result
+}
+
+// ping checks that a host answers HTTP requests at all; any response
+// status counts as reachable
+func (d *Discoverer) ping(ctx context.Context, host string) error {
+	target := strings.TrimSpace(host)
+	if !strings.HasPrefix(target, "http://") && !strings.HasPrefix(target, "https://") {
+		target = "http://" + target
+	}
+	if err := d.hosts.CheckURL(target); err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", target, nil)
+	if err != nil {
+		return fmt.Errorf("invalid host: %w", err)
+	}
+	req.Header.Set("User-Agent", "openribcage/1.0 (A2A-Protocol-Client)")
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("host unreachable: %w", err)
+	}
+	resp.Body.Close()
+
+	return nil
+}
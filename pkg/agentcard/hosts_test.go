@@ -0,0 +1,61 @@
+package agentcard
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/craine-io/openribcage/pkg/a2a/retry"
+	"github.com/craine-io/openribcage/pkg/netguard"
+	"github.com/craine-io/openribcage/test/fixtures"
+)
+
+// TestCheckHosts tests that each host is reported as reachable with a
+// valid card, reachable without one, unreachable or not allowed, in the
+// order given
+func TestCheckHosts(t *testing.T) {
+	raw, err := fixtures.Raw("minimal")
+	require.NoError(t, err)
+	agent := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(raw)
+	}))
+	defer agent.Close()
+	empty := httptest.NewServer(http.NotFoundHandler())
+	defer empty.Close()
+	down := httptest.NewServer(http.NotFoundHandler())
+	down.Close()
+
+	d := NewDiscoverer(5 * time.Second)
+	d.SetRetryPolicy(retry.Policy{})
+	hosts := []string{
+		agent.URL,
+		strings.TrimPrefix(empty.URL, "http://"),
+		down.URL,
+		"169.254.169.254",
+	}
+	results := d.CheckHosts(context.Background(), hosts)
+	require.Len(t, results, 4)
+
+	assert.Equal(t, HostCheck{Host: agent.URL, Reachable: true, ValidCard: true, AgentName: "minimal-agent"}, results[0])
+	assert.True(t, results[0].OK())
+
+	assert.Equal(t, hosts[1], results[1].Host)
+	assert.True(t, results[1].Reachable)
+	assert.False(t, results[1].ValidCard)
+	assert.Contains(t, results[1].Error, "404")
+	assert.False(t, results[1].OK())
+
+	assert.False(t, results[2].Reachable)
+	assert.Contains(t, results[2].Error, "host unreachable")
+
+	assert.False(t, results[3].Reachable)
+	assert.ErrorIs(t, results[3].Err, netguard.ErrHostNotAllowed)
+	assert.Equal(t, results[3].Err.Error(), results[3].Error)
+}
@@ -0,0 +1,268 @@
+package agentcard
+
+import (
+	"context"
+	"crypto"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/craine-io/openribcage/pkg/netguard"
+)
+
+const (
+	// DefaultJWKSTTL is how long a fetched key set is used before refreshing
+	DefaultJWKSTTL = time.Hour
+
+	// DefaultJWKSRefetchInterval throttles re-fetches caused by unknown key IDs
+	DefaultJWKSRefetchInterval = 30 * time.Second
+
+	// maxJWKSSize limits key set downloads
+	maxJWKSSize = 1 << 20
+)
+
+// JWKSCache fetches and caches JSON Web Key Sets by URL.
+//
+// A key set is served from the cache for its TTL. For up to another TTL
+// after that, the stale set is still served while it is refreshed in the
+// background; older sets are re-fetched before use. A signature whose key
+// ID is not in the cached set triggers an immediate re-fetch, at most once
+// per refetch interval, so rotated keys are picked up without waiting for
+// the TTL.
+type JWKSCache struct {
+	client  *http.Client
+	logger  *logrus.Logger
+	hosts   *netguard.Policy
+	ttl     time.Duration
+	refetch time.Duration
+	now     func() time.Time
+
+	mu   sync.Mutex
+	sets map[string]*jwksEntry
+}
+
+// jwksEntry is a cached key set
+type jwksEntry struct {
+	keys       StaticKeys
+	fetched    time.Time
+	refreshing bool
+}
+
+// NewJWKSCache creates a JWKS cache. A zero ttl uses DefaultJWKSTTL.
+func NewJWKSCache(timeout, ttl time.Duration) *JWKSCache {
+	if ttl <= 0 {
+		ttl = DefaultJWKSTTL
+	}
+	return &JWKSCache{
+		client: &http.Client{
+			Timeout: timeout,
+		},
+		logger:  logrus.New(),
+		hosts:   netguard.DefaultPolicy(),
+		ttl:     ttl,
+		refetch: DefaultJWKSRefetchInterval,
+		now:     time.Now,
+		sets:    make(map[string]*jwksEntry),
+	}
+}
+
+// SetHostPolicy sets the host allowlist/denylist checked before fetching
+// a key set. The default is netguard.DefaultPolicy; nil allows every host.
+func (c *JWKSCache) SetHostPolicy(policy *netguard.Policy) {
+	c.hosts = policy
+}
+
+// SetRefetchInterval sets the minimum time between re-fetches triggered by
+// unknown key IDs. Zero re-fetches on every miss.
+func (c *JWKSCache) SetRefetchInterval(interval time.Duration) {
+	c.refetch = interval
+}
+
+// SetClock replaces the cache's time source, for tests
+func (c *JWKSCache) SetClock(now func() time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = now
+}
+
+// Invalidate drops the cached key set for jwksURL
+func (c *JWKSCache) Invalidate(jwksURL string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.sets, jwksURL)
+}
+
+// Purge drops every cached key set
+func (c *JWKSCache) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.sets = make(map[string]*jwksEntry)
+}
+
+// Keys returns the key set published at jwksURL, from the cache when fresh
+func (c *JWKSCache) Keys(ctx context.Context, jwksURL string) (StaticKeys, error) {
+	c.mu.Lock()
+	entry, ok := c.sets[jwksURL]
+	if ok {
+		age := c.now().Sub(entry.fetched)
+		if age < c.ttl {
+			c.mu.Unlock()
+			return entry.keys, nil
+		}
+		if age < 2*c.ttl {
+			if !entry.refreshing {
+				entry.refreshing = true
+				go c.refresh(jwksURL)
+			}
+			c.mu.Unlock()
+			return entry.keys, nil
+		}
+	}
+	c.mu.Unlock()
+
+	return c.fetch(ctx, jwksURL)
+}
+
+// Resolver returns a KeyResolver backed by the key set at jwksURL
+func (c *JWKSCache) Resolver(jwksURL string) KeyResolver {
+	return &jwksResolver{cache: c, url: jwksURL}
+}
+
+// refresh re-fetches a stale key set in the background
+func (c *JWKSCache) refresh(jwksURL string) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.client.Timeout)
+	defer cancel()
+
+	if _, err := c.fetch(ctx, jwksURL); err != nil {
+		c.logger.Warnf("Failed to refresh JWKS %s: %v", jwksURL, err)
+		c.mu.Lock()
+		if entry, ok := c.sets[jwksURL]; ok {
+			entry.refreshing = false
+		}
+		c.mu.Unlock()
+	}
+}
+
+// refetchAllowed reports whether an unknown key ID may trigger a re-fetch
+func (c *JWKSCache) refetchAllowed(jwksURL string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.sets[jwksURL]
+	return !ok || c.now().Sub(entry.fetched) >= c.refetch
+}
+
+// fetch downloads, parses and caches the key set at jwksURL
+func (c *JWKSCache) fetch(ctx context.Context, jwksURL string) (StaticKeys, error) {
+	if err := c.hosts.CheckURL(jwksURL); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", jwksURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create JWKS request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch JWKS from %s: %w", jwksURL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch JWKS from %s: unexpected status: %s", jwksURL, resp.Status)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxJWKSSize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read JWKS from %s: %w", jwksURL, err)
+	}
+	keys, err := ParseJWKS(data)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.sets[jwksURL] = &jwksEntry{keys: keys, fetched: c.now()}
+	c.mu.Unlock()
+
+	c.logger.Debugf("Fetched %d keys from JWKS %s", len(keys), jwksURL)
+	return keys, nil
+}
+
+// jwksResolver resolves keys from one cached key set
+type jwksResolver struct {
+	cache *JWKSCache
+	url   string
+}
+
+// Key implements KeyResolver, re-fetching the key set once when kid is unknown
+func (r *jwksResolver) Key(ctx context.Context, kid, alg string) (crypto.PublicKey, error) {
+	keys, err := r.cache.Keys(ctx, r.url)
+	if err != nil {
+		return nil, err
+	}
+	key, err := keys.Key(ctx, kid, alg)
+	if err == nil || !r.cache.refetchAllowed(r.url) {
+		return key, err
+	}
+
+	r.cache.logger.Debugf("Key %q not in JWKS %s, re-fetching", kid, r.url)
+	keys, err = r.cache.fetch(ctx, r.url)
+	if err != nil {
+		return nil, err
+	}
+	return keys.Key(ctx, kid, alg)
+}
+
+// WithJWKS verifies AgentCard signatures with keys published at the card's
+// jwksUrl, fetched through cache. The URL must pass the host policy's
+// checks for agent-provided URLs. Cards without a jwksUrl are verified
+// with the keys given to WithSignatureVerification, if any, and rejected
+// otherwise.
+func WithJWKS(cache *JWKSCache) Option {
+	return func(d *Discoverer) {
+		d.jwks = cache
+	}
+}
+
+// cardKeys returns the KeyResolver for a raw AgentCard fetched from agentCardURL
+func (d *Discoverer) cardKeys(ctx context.Context, raw []byte, agentCardURL string) (KeyResolver, error) {
+	if d.jwks == nil {
+		return d.keys, nil
+	}
+
+	var card struct {
+		JWKSURL string `json:"jwksUrl"`
+	}
+	if err := json.Unmarshal(raw, &card); err != nil {
+		return nil, fmt.Errorf("failed to parse AgentCard JSON: %w", err)
+	}
+	if strings.TrimSpace(card.JWKSURL) == "" {
+		if d.keys == nil {
+			return nil, fmt.Errorf("AgentCard has no jwksUrl")
+		}
+		return d.keys, nil
+	}
+
+	base, err := url.Parse(agentCardURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid AgentCard URL: %w", err)
+	}
+	ref, err := url.Parse(strings.TrimSpace(card.JWKSURL))
+	if err != nil {
+		return nil, fmt.Errorf("invalid jwksUrl: %w", err)
+	}
+	jwksURL := base.ResolveReference(ref).String()
+	if err := d.hosts.CheckProvidedURL(ctx, jwksURL, agentCardURL); err != nil {
+		return nil, fmt.Errorf("jwksUrl: %w", err)
+	}
+	return d.jwks.Resolver(jwksURL), nil
+}
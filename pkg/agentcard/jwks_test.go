@@ -0,0 +1,175 @@
+package agentcard
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/craine-io/openribcage/pkg/netguard"
+)
+
+// jwksServer publishes a changeable set of Ed25519 keys and counts fetches
+type jwksServer struct {
+	*httptest.Server
+
+	mu      sync.Mutex
+	keys    map[string]ed25519.PublicKey
+	fetches int
+}
+
+// newJWKSServer starts a jwksServer publishing one key per kid
+func newJWKSServer(t *testing.T, kids ...string) *jwksServer {
+	s := &jwksServer{keys: map[string]ed25519.PublicKey{}}
+	for _, kid := range kids {
+		s.addKey(t, kid)
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.fetches++
+		var set struct {
+			Keys []map[string]string `json:"keys"`
+		}
+		for kid, key := range s.keys {
+			set.Keys = append(set.Keys, map[string]string{"kty": "OKP", "crv": "Ed25519", "kid": kid, "x": base64.RawURLEncoding.EncodeToString(key)})
+		}
+		json.NewEncoder(w).Encode(set)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// addKey publishes a new key under kid
+func (s *jwksServer) addKey(t *testing.T, kid string) {
+	key, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.keys[kid] = key
+}
+
+// fetchCount returns how many times the key set was fetched
+func (s *jwksServer) fetchCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.fetches
+}
+
+// fakeClock is a manually advanced time source
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// TestJWKSCacheTTL tests that key sets are served from the cache while
+// fresh, refreshed in the background while stale and re-fetched when expired
+func TestJWKSCacheTTL(t *testing.T) {
+	server := newJWKSServer(t, "key-1")
+	clock := &fakeClock{now: time.Now()}
+	cache := NewJWKSCache(5*time.Second, time.Hour)
+	cache.SetClock(clock.Now)
+	ctx := context.Background()
+
+	keys, err := cache.Keys(ctx, server.URL)
+	require.NoError(t, err)
+	assert.Contains(t, keys, "key-1")
+	_, err = cache.Keys(ctx, server.URL)
+	require.NoError(t, err)
+	assert.Equal(t, 1, server.fetchCount())
+
+	// Stale: the cached set is served and refreshed in the background
+	clock.Advance(90 * time.Minute)
+	server.addKey(t, "key-2")
+	keys, err = cache.Keys(ctx, server.URL)
+	require.NoError(t, err)
+	assert.NotContains(t, keys, "key-2")
+	assert.Eventually(t, func() bool { return server.fetchCount() == 2 }, 5*time.Second, 10*time.Millisecond)
+	assert.Eventually(t, func() bool {
+		keys, err := cache.Keys(ctx, server.URL)
+		return err == nil && keys["key-2"] != nil
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// Expired: the set is fetched before use
+	clock.Advance(3 * time.Hour)
+	server.addKey(t, "key-3")
+	keys, err = cache.Keys(ctx, server.URL)
+	require.NoError(t, err)
+	assert.Contains(t, keys, "key-3")
+	assert.Equal(t, 3, server.fetchCount())
+
+	cache.Invalidate(server.URL)
+	_, err = cache.Keys(ctx, server.URL)
+	require.NoError(t, err)
+	assert.Equal(t, 4, server.fetchCount())
+}
+
+// TestJWKSResolverRotation tests that an unknown key ID re-fetches the key
+// set, at most once per refetch interval
+func TestJWKSResolverRotation(t *testing.T) {
+	server := newJWKSServer(t, "key-1")
+	clock := &fakeClock{now: time.Now()}
+	cache := NewJWKSCache(5*time.Second, time.Hour)
+	cache.SetClock(clock.Now)
+	resolver := cache.Resolver(server.URL)
+	ctx := context.Background()
+
+	_, err := resolver.Key(ctx, "key-1", "EdDSA")
+	require.NoError(t, err)
+
+	// Within the refetch interval an unknown key does not re-fetch
+	server.addKey(t, "key-2")
+	_, err = resolver.Key(ctx, "key-2", "EdDSA")
+	assert.ErrorContains(t, err, `no trusted key with ID "key-2"`)
+	assert.Equal(t, 1, server.fetchCount())
+
+	clock.Advance(DefaultJWKSRefetchInterval)
+	key, err := resolver.Key(ctx, "key-2", "EdDSA")
+	require.NoError(t, err)
+	assert.NotNil(t, key)
+	assert.Equal(t, 2, server.fetchCount())
+}
+
+// TestJWKSCacheErrors tests host policy and fetch failures
+func TestJWKSCacheErrors(t *testing.T) {
+	cache := NewJWKSCache(5*time.Second, 0)
+	ctx := context.Background()
+
+	_, err := cache.Keys(ctx, "http://169.254.169.254/jwks.json")
+	assert.ErrorIs(t, err, netguard.ErrHostNotAllowed)
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "down", http.StatusInternalServerError)
+	}))
+	defer failing.Close()
+	_, err = cache.Keys(ctx, failing.URL)
+	assert.ErrorContains(t, err, "unexpected status: 500")
+
+	invalid := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"keys":[{"kty":"OKP","crv":"Ed25519","kid":"short","x":"AAAA"}]}`))
+	}))
+	defer invalid.Close()
+	_, err = cache.Keys(ctx, invalid.URL)
+	assert.ErrorContains(t, err, "invalid Ed25519 key length")
+}
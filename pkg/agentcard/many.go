@@ -0,0 +1,121 @@
+package agentcard
+
+import (
+	"context"
+	"sync"
+
+	"github.com/craine-io/openribcage/pkg/a2a/types"
+)
+
+// DefaultConcurrency is the number of concurrent discoveries run by DiscoverMany
+const DefaultConcurrency = 8
+
+// SetConcurrency sets the number of concurrent discoveries run by DiscoverMany
+func (d *Discoverer) SetConcurrency(n int) {
+	d.concurrency = n
+}
+
+// DiscoveryResult is the outcome of discovering a single agent
+type DiscoveryResult struct {
+	URL  string
+	Card *types.AgentCard
+	Err  error
+}
+
+// DiscoverManyOptions configures DiscoverManyWithOptions
+type DiscoverManyOptions struct {
+	// OnProgress, when set, is called after each discovery completes with
+	// the number completed so far and the number to run. Calls are serial
+	// and done increases by one each time.
+	OnProgress func(done, total int, last DiscoveryResult)
+
+	// Known lists URLs that are already discovered, e.g. from an existing
+	// registry, so that an interrupted scan can resume. They are skipped
+	// and left out of the results.
+	Known map[string]bool
+}
+
+// DiscoverMany discovers AgentCards for many agents using a bounded pool of
+// goroutines, returning the cards and per-URL errors separately. Each
+// discovery is limited to the discoverer's timeout, so a slow agent only
+// delays its own result. URLs not yet started when ctx is done report the
+// context's error.
+func (d *Discoverer) DiscoverMany(ctx context.Context, urls []string) (map[string]*types.AgentCard, map[string]error) {
+	return d.DiscoverManyWithOptions(ctx, urls, DiscoverManyOptions{})
+}
+
+// DiscoverManyWithOptions is DiscoverMany with progress reporting and
+// resumption. When ctx is done, in-flight discoveries finish or fail and
+// the partial results are returned; URLs not yet started report the
+// context's error.
+func (d *Discoverer) DiscoverManyWithOptions(ctx context.Context, urls []string, opts DiscoverManyOptions) (map[string]*types.AgentCard, map[string]error) {
+	if len(opts.Known) > 0 {
+		pending := make([]string, 0, len(urls))
+		for _, u := range urls {
+			if !opts.Known[u] {
+				pending = append(pending, u)
+			}
+		}
+		urls = pending
+	}
+
+	workers := d.concurrency
+	if workers < 1 {
+		workers = DefaultConcurrency
+	}
+	if workers > len(urls) {
+		workers = len(urls)
+	}
+
+	var (
+		mu    sync.Mutex
+		cards = make(map[string]*types.AgentCard)
+		errs  = make(map[string]error)
+		done  int
+		wg    sync.WaitGroup
+	)
+
+	jobs := make(chan string)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for u := range jobs {
+				discoverCtx, cancel := context.WithTimeout(ctx, d.timeout)
+				card, err := d.Discover(discoverCtx, u)
+				cancel()
+
+				mu.Lock()
+				if err != nil {
+					errs[u] = err
+				} else {
+					cards[u] = card
+				}
+				done++
+				if opts.OnProgress != nil {
+					opts.OnProgress(done, len(urls), DiscoveryResult{URL: u, Card: card, Err: err})
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for i, u := range urls {
+		select {
+		case jobs <- u:
+			continue
+		case <-ctx.Done():
+		}
+
+		mu.Lock()
+		for _, skipped := range urls[i:] {
+			errs[skipped] = ctx.Err()
+		}
+		mu.Unlock()
+		break
+	}
+	close(jobs)
+	wg.Wait()
+
+	return cards, errs
+}
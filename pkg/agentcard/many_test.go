@@ -0,0 +1,115 @@
+package agentcard
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/craine-io/openribcage/pkg/a2a/retry"
+	"github.com/craine-io/openribcage/test/fixtures"
+)
+
+// cardServers starts n agents serving the minimal fixture card and returns
+// their URLs and a function counting the requests each one received
+func cardServers(t *testing.T, n int) ([]string, func(url string) int) {
+	raw, err := fixtures.Raw("minimal")
+	require.NoError(t, err)
+
+	var mu sync.Mutex
+	hits := map[string]int{}
+	urls := make([]string, n)
+	for i := range urls {
+		var server *httptest.Server
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			hits[server.URL]++
+			mu.Unlock()
+			w.Header().Set("Content-Type", "application/json")
+			w.Write(raw)
+		}))
+		t.Cleanup(server.Close)
+		urls[i] = server.URL
+	}
+	return urls, func(url string) int {
+		mu.Lock()
+		defer mu.Unlock()
+		return hits[url]
+	}
+}
+
+// newManyDiscoverer returns a discoverer without retries that only tries
+// the well-known card path
+func newManyDiscoverer() *Discoverer {
+	d := NewDiscoverer(5 * time.Second)
+	d.SetRetryPolicy(retry.Policy{})
+	d.SetCardPaths([]string{WellKnownPath})
+	d.SetConcurrency(2)
+	return d
+}
+
+// TestDiscoverManyProgress tests that progress is reported once per
+// discovery, serially and in increasing order, failures included
+func TestDiscoverManyProgress(t *testing.T) {
+	urls, _ := cardServers(t, 3)
+	missing := httptest.NewServer(http.NotFoundHandler())
+	defer missing.Close()
+	urls = append(urls, missing.URL)
+
+	var done []int
+	reported := map[string]bool{}
+	cards, errs := newManyDiscoverer().DiscoverManyWithOptions(context.Background(), urls, DiscoverManyOptions{
+		OnProgress: func(n, total int, last DiscoveryResult) {
+			done = append(done, n)
+			assert.Equal(t, 4, total)
+			assert.Equal(t, last.Err != nil, last.Card == nil, last.URL)
+			reported[last.URL] = true
+		},
+	})
+
+	assert.Equal(t, []int{1, 2, 3, 4}, done)
+	assert.Len(t, reported, 4)
+	assert.Len(t, cards, 3)
+	require.Len(t, errs, 1)
+	assert.Error(t, errs[missing.URL])
+}
+
+// TestDiscoverManyResume tests that known URLs are neither fetched nor
+// counted, so an interrupted scan resumes where it left off
+func TestDiscoverManyResume(t *testing.T) {
+	urls, hits := cardServers(t, 3)
+
+	var total int
+	cards, errs := newManyDiscoverer().DiscoverManyWithOptions(context.Background(), urls, DiscoverManyOptions{
+		Known:      map[string]bool{urls[0]: true},
+		OnProgress: func(_, n int, _ DiscoveryResult) { total = n },
+	})
+
+	assert.Empty(t, errs)
+	assert.Len(t, cards, 2)
+	assert.NotContains(t, cards, urls[0])
+	assert.Equal(t, 2, total)
+	assert.Equal(t, 0, hits(urls[0]))
+	assert.Equal(t, 1, hits(urls[1]))
+}
+
+// TestDiscoverManyCancelled tests that every URL reports the context's
+// error once ctx is done and that no agent is contacted
+func TestDiscoverManyCancelled(t *testing.T) {
+	urls, hits := cardServers(t, 4)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	cards, errs := newManyDiscoverer().DiscoverMany(ctx, urls)
+	assert.Empty(t, cards)
+	require.Len(t, errs, 4)
+	for _, u := range urls {
+		assert.ErrorIs(t, errs[u], context.Canceled)
+		assert.Equal(t, 0, hits(u))
+	}
+}
@@ -0,0 +1,284 @@
+package agentcard
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/craine-io/openribcage/pkg/a2a/types"
+)
+
+// agentCardSchema is the A2A AgentCard JSON Schema, extended with the
+// endpoints advertised by openribcage-compatible agents
+//
+//go:embed schema/agentcard.schema.json
+var agentCardSchema []byte
+
+// SchemaViolation is a single JSON Schema violation in an AgentCard
+type SchemaViolation struct {
+	// Pointer is the JSON Pointer (RFC 6901) of the offending value
+	Pointer string `json:"pointer"`
+	Message string `json:"message"`
+}
+
+// SchemaError lists every schema violation found by ValidateStrict
+type SchemaError struct {
+	Violations []SchemaViolation `json:"violations"`
+}
+
+// Error implements the error interface
+func (e *SchemaError) Error() string {
+	msgs := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		msgs[i] = fmt.Sprintf("%s: %s", pointerOrRoot(v.Pointer), v.Message)
+	}
+	return fmt.Sprintf("AgentCard has %d schema violation(s): %s", len(e.Violations), strings.Join(msgs, "; "))
+}
+
+// pointerOrRoot renders the root pointer readably
+func pointerOrRoot(pointer string) string {
+	if pointer == "" {
+		return "(root)"
+	}
+	return pointer
+}
+
+// schema is the subset of JSON Schema understood by the strict validator
+type schema struct {
+	Ref                  string             `json:"$ref"`
+	Type                 schemaTypes        `json:"type"`
+	Required             []string           `json:"required"`
+	Properties           map[string]*schema `json:"properties"`
+	AdditionalProperties json.RawMessage    `json:"additionalProperties"`
+	Items                *schema            `json:"items"`
+	Enum                 []interface{}      `json:"enum"`
+	Format               string             `json:"format"`
+	MinLength            *int               `json:"minLength"`
+	Defs                 map[string]*schema `json:"$defs"`
+}
+
+// schemaTypes accepts both "type": "x" and "type": ["x", "y"]
+type schemaTypes []string
+
+// UnmarshalJSON implements json.Unmarshaler
+func (t *schemaTypes) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*t = schemaTypes{single}
+		return nil
+	}
+	var many []string
+	if err := json.Unmarshal(data, &many); err != nil {
+		return err
+	}
+	*t = many
+	return nil
+}
+
+var (
+	rootSchema     *schema
+	rootSchemaErr  error
+	rootSchemaOnce sync.Once
+)
+
+// loadSchema parses the embedded AgentCard schema once
+func loadSchema() (*schema, error) {
+	rootSchemaOnce.Do(func() {
+		rootSchema = &schema{}
+		if err := json.Unmarshal(agentCardSchema, rootSchema); err != nil {
+			rootSchemaErr = fmt.Errorf("invalid embedded AgentCard schema: %w", err)
+		}
+	})
+	return rootSchema, rootSchemaErr
+}
+
+// ValidateStrict checks an AgentCard for strict conformance: the raw JSON
+// is validated against the embedded A2A AgentCard schema, and the parsed
+// card against the checks done by Validate. Every violation is returned in
+// a *SchemaError. If card is nil it is parsed from raw.
+func (d *Discoverer) ValidateStrict(card *types.AgentCard, raw []byte) error {
+	root, err := loadSchema()
+	if err != nil {
+		return err
+	}
+
+	var doc interface{}
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return fmt.Errorf("failed to parse AgentCard JSON: %w", err)
+	}
+
+	v := &schemaValidator{root: root}
+	v.validate(root, doc, "")
+
+	if card == nil {
+		card = &types.AgentCard{}
+		if err := json.Unmarshal(raw, card); err != nil {
+			v.add("", fmt.Sprintf("does not decode as an AgentCard: %v", err))
+			card = nil
+		}
+	}
+	if card != nil {
+		if err := d.Validate(card); err != nil {
+			v.add("", err.Error())
+		}
+	}
+
+	if len(v.violations) > 0 {
+		return &SchemaError{Violations: v.violations}
+	}
+	return nil
+}
+
+// schemaValidator collects violations while walking a document
+type schemaValidator struct {
+	root       *schema
+	violations []SchemaViolation
+}
+
+// add records a violation
+func (v *schemaValidator) add(pointer, message string) {
+	v.violations = append(v.violations, SchemaViolation{Pointer: pointer, Message: message})
+}
+
+// validate checks value at pointer against s
+func (v *schemaValidator) validate(s *schema, value interface{}, pointer string) {
+	if s.Ref != "" {
+		resolved, ok := v.resolve(s.Ref)
+		if !ok {
+			v.add(pointer, fmt.Sprintf("unresolvable schema reference %s", s.Ref))
+			return
+		}
+		s = resolved
+	}
+
+	if len(s.Type) > 0 && !typeMatches(s.Type, value) {
+		v.add(pointer, fmt.Sprintf("expected %s, got %s", strings.Join(s.Type, " or "), jsonType(value)))
+		return
+	}
+
+	if len(s.Enum) > 0 && !enumContains(s.Enum, value) {
+		v.add(pointer, fmt.Sprintf("value %v is not one of %v", value, s.Enum))
+	}
+
+	switch val := value.(type) {
+	case string:
+		if s.MinLength != nil && len(val) < *s.MinLength {
+			v.add(pointer, fmt.Sprintf("must be at least %d characters", *s.MinLength))
+		}
+		if s.Format == "uri" {
+			if u, err := url.Parse(val); err != nil || !u.IsAbs() {
+				v.add(pointer, "must be an absolute URI")
+			}
+		}
+
+	case []interface{}:
+		if s.Items != nil {
+			for i, item := range val {
+				v.validate(s.Items, item, fmt.Sprintf("%s/%d", pointer, i))
+			}
+		}
+
+	case map[string]interface{}:
+		for _, name := range s.Required {
+			if _, ok := val[name]; !ok {
+				v.add(pointer, fmt.Sprintf("missing required property %q", name))
+			}
+		}
+
+		names := make([]string, 0, len(val))
+		for name := range val {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			child := pointer + "/" + escapePointer(name)
+			if prop, ok := s.Properties[name]; ok {
+				v.validate(prop, val[name], child)
+				continue
+			}
+			v.validateAdditional(s.AdditionalProperties, val[name], child)
+		}
+	}
+}
+
+// validateAdditional applies an additionalProperties keyword to a property
+// not listed in properties
+func (v *schemaValidator) validateAdditional(additional json.RawMessage, value interface{}, pointer string) {
+	if len(additional) == 0 {
+		return
+	}
+	var allowed bool
+	if err := json.Unmarshal(additional, &allowed); err == nil {
+		if !allowed {
+			v.add(pointer, "property is not allowed")
+		}
+		return
+	}
+	var s schema
+	if err := json.Unmarshal(additional, &s); err == nil {
+		v.validate(&s, value, pointer)
+	}
+}
+
+// resolve looks up a local "#/$defs/Name" reference
+func (v *schemaValidator) resolve(ref string) (*schema, bool) {
+	name, ok := strings.CutPrefix(ref, "#/$defs/")
+	if !ok {
+		return nil, false
+	}
+	s, ok := v.root.Defs[name]
+	return s, ok
+}
+
+// typeMatches reports whether value has one of the JSON types
+func typeMatches(allowed []string, value interface{}) bool {
+	actual := jsonType(value)
+	for _, t := range allowed {
+		if t == actual || (t == "number" && actual == "integer") {
+			return true
+		}
+	}
+	return false
+}
+
+// jsonType returns the JSON Schema type name of a decoded JSON value
+func jsonType(value interface{}) string {
+	switch val := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		if val == float64(int64(val)) {
+			return "integer"
+		}
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	default:
+		return "object"
+	}
+}
+
+// enumContains reports whether value equals one of the enum values
+func enumContains(enum []interface{}, value interface{}) bool {
+	for _, e := range enum {
+		if reflect.DeepEqual(e, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// escapePointer escapes a property name for use in a JSON Pointer
+func escapePointer(name string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(name, "~", "~0"), "/", "~1")
+}
@@ -0,0 +1,58 @@
+package agentcard
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/craine-io/openribcage/test/fixtures"
+)
+
+// TestValidateStrict tests that the full fixture card conforms to the
+// schema while the minimal one lacks required properties
+func TestValidateStrict(t *testing.T) {
+	d := NewDiscoverer(5 * time.Second)
+	raw, err := fixtures.Raw("full")
+	require.NoError(t, err)
+	assert.NoError(t, d.ValidateStrict(nil, raw))
+
+	raw, err = fixtures.Raw("minimal")
+	require.NoError(t, err)
+	var schemaErr *SchemaError
+	require.ErrorAs(t, d.ValidateStrict(nil, raw), &schemaErr)
+	assert.Contains(t, schemaErr.Violations, SchemaViolation{Message: `missing required property "skills"`})
+}
+
+// TestValidateStrictViolations tests that unknown keys, wrong types and
+// missing properties are all reported with their JSON Pointers
+func TestValidateStrictViolations(t *testing.T) {
+	raw, err := fixtures.Raw("full")
+	require.NoError(t, err)
+	var card map[string]interface{}
+	require.NoError(t, json.Unmarshal(raw, &card))
+	card["capabilities"] = map[string]interface{}{"streaming": "yes", "teleport~/": true}
+	delete(card, "version")
+	raw, err = json.Marshal(card)
+	require.NoError(t, err)
+
+	err = NewDiscoverer(5*time.Second).ValidateStrict(nil, raw)
+	var schemaErr *SchemaError
+	require.ErrorAs(t, err, &schemaErr)
+
+	assert.Contains(t, schemaErr.Violations, SchemaViolation{Pointer: "/capabilities/streaming", Message: "expected boolean, got string"})
+	assert.Contains(t, schemaErr.Violations, SchemaViolation{Pointer: "/capabilities/teleport~0~1", Message: "property is not allowed"})
+	assert.Contains(t, schemaErr.Violations, SchemaViolation{Message: `missing required property "version"`})
+}
+
+// TestValidateStrictInvalidJSON tests that unparseable input is an error
+// rather than a schema violation
+func TestValidateStrictInvalidJSON(t *testing.T) {
+	err := NewDiscoverer(5*time.Second).ValidateStrict(nil, []byte("{"))
+	require.Error(t, err)
+	var schemaErr *SchemaError
+	assert.False(t, errors.As(err, &schemaErr))
+}
@@ -0,0 +1,315 @@
+package agentcard
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"math/big"
+	"strings"
+)
+
+// SignatureHeader carries a detached JWS over the AgentCard response body
+const SignatureHeader = "X-Agent-Card-Signature"
+
+var (
+	// ErrUnsignedCard is returned when signature verification is enabled
+	// and an AgentCard carries no signature
+	ErrUnsignedCard = errors.New("AgentCard is not signed")
+
+	// ErrInvalidSignature is returned when an AgentCard signature does not verify
+	ErrInvalidSignature = errors.New("invalid AgentCard signature")
+)
+
+// KeyResolver supplies the trusted public key for a JWS key ID and algorithm
+type KeyResolver interface {
+	Key(ctx context.Context, kid, alg string) (crypto.PublicKey, error)
+}
+
+// StaticKeys is a fixed set of trusted public keys indexed by key ID.
+// When a signature has no key ID, a set holding exactly one key uses it.
+type StaticKeys map[string]crypto.PublicKey
+
+// Key implements KeyResolver
+func (k StaticKeys) Key(ctx context.Context, kid, alg string) (crypto.PublicKey, error) {
+	if key, ok := k[kid]; ok {
+		return key, nil
+	}
+	if kid == "" && len(k) == 1 {
+		for _, key := range k {
+			return key, nil
+		}
+	}
+	return nil, fmt.Errorf("no trusted key with ID %q", kid)
+}
+
+// Option configures a Discoverer
+type Option func(*Discoverer)
+
+// WithSignatureVerification requires every discovered AgentCard to carry a
+// valid JWS signature from a key supplied by keys. The signature is read
+// from the X-Agent-Card-Signature header as a detached JWS over the
+// response body, or from the card's "signature" field as a detached JWS
+// over the card without that field, encoded as compact JSON with sorted
+// keys. Unsigned cards are rejected.
+func WithSignatureVerification(keys KeyResolver) Option {
+	return func(d *Discoverer) {
+		d.keys = keys
+	}
+}
+
+// jwsHeader is the protected header of a JWS
+type jwsHeader struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+}
+
+// verifySignature checks the signature of a raw AgentCard. headerSig is
+// the value of the SignatureHeader response header, if any.
+func (d *Discoverer) verifySignature(ctx context.Context, raw []byte, headerSig, agentCardURL string) error {
+	payload, signature := raw, strings.TrimSpace(headerSig)
+	if signature == "" {
+		var err error
+		payload, signature, err = embeddedSignature(raw)
+		if err != nil {
+			return err
+		}
+	}
+	if signature == "" {
+		return ErrUnsignedCard
+	}
+
+	keys, err := d.cardKeys(ctx, raw, agentCardURL)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+	}
+	if err := verifyDetachedJWS(ctx, signature, payload, keys); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+	}
+	return nil
+}
+
+// embeddedSignature extracts a card's "signature" field and returns the
+// canonical payload it signs: the card without that field, encoded with
+// sorted keys and no insignificant whitespace
+func embeddedSignature(raw []byte) ([]byte, string, error) {
+	var fields map[string]interface{}
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil, "", fmt.Errorf("failed to parse AgentCard JSON: %w", err)
+	}
+
+	signature, _ := fields["signature"].(string)
+	if signature == "" {
+		return nil, "", nil
+	}
+	delete(fields, "signature")
+
+	payload, err := json.Marshal(fields)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to encode AgentCard payload: %w", err)
+	}
+	return payload, signature, nil
+}
+
+// verifyDetachedJWS verifies a compact JWS with a detached payload
+// ("header..signature") over payload
+func verifyDetachedJWS(ctx context.Context, jws string, payload []byte, keys KeyResolver) error {
+	parts := strings.Split(jws, ".")
+	if len(parts) != 3 {
+		return fmt.Errorf("malformed JWS")
+	}
+	if parts[1] != "" && parts[1] != base64.RawURLEncoding.EncodeToString(payload) {
+		return fmt.Errorf("JWS payload does not match the AgentCard")
+	}
+
+	headerJSON, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return fmt.Errorf("malformed JWS header: %w", err)
+	}
+	var header jwsHeader
+	if err := json.Unmarshal(headerJSON, &header); err != nil {
+		return fmt.Errorf("malformed JWS header: %w", err)
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return fmt.Errorf("malformed JWS signature: %w", err)
+	}
+
+	key, err := keys.Key(ctx, header.Kid, header.Alg)
+	if err != nil {
+		return err
+	}
+
+	signingInput := parts[0] + "." + base64.RawURLEncoding.EncodeToString(payload)
+	return verifyJWS(header.Alg, key, []byte(signingInput), sig)
+}
+
+// verifyJWS verifies a JWS signature for the supported algorithms
+func verifyJWS(alg string, key crypto.PublicKey, signingInput, sig []byte) error {
+	if alg == "EdDSA" {
+		edKey, ok := key.(ed25519.PublicKey)
+		if !ok {
+			return fmt.Errorf("key type %T does not match algorithm %s", key, alg)
+		}
+		if !ed25519.Verify(edKey, signingInput, sig) {
+			return fmt.Errorf("signature mismatch")
+		}
+		return nil
+	}
+
+	hashFunc, newHash, err := jwsHash(alg)
+	if err != nil {
+		return err
+	}
+	h := newHash()
+	h.Write(signingInput)
+	digest := h.Sum(nil)
+
+	switch alg[:2] {
+	case "RS", "PS":
+		rsaKey, ok := key.(*rsa.PublicKey)
+		if !ok {
+			return fmt.Errorf("key type %T does not match algorithm %s", key, alg)
+		}
+		if alg[:2] == "PS" {
+			err = rsa.VerifyPSS(rsaKey, hashFunc, digest, sig, nil)
+		} else {
+			err = rsa.VerifyPKCS1v15(rsaKey, hashFunc, digest, sig)
+		}
+		if err != nil {
+			return fmt.Errorf("signature mismatch")
+		}
+
+	case "ES":
+		ecKey, ok := key.(*ecdsa.PublicKey)
+		if !ok {
+			return fmt.Errorf("key type %T does not match algorithm %s", key, alg)
+		}
+		size := (ecKey.Curve.Params().BitSize + 7) / 8
+		if len(sig) != 2*size {
+			return fmt.Errorf("invalid %s signature length", alg)
+		}
+		r := new(big.Int).SetBytes(sig[:size])
+		s := new(big.Int).SetBytes(sig[size:])
+		if !ecdsa.Verify(ecKey, digest, r, s) {
+			return fmt.Errorf("signature mismatch")
+		}
+	}
+	return nil
+}
+
+// jwsHash returns the hash used by a JWS algorithm
+func jwsHash(alg string) (crypto.Hash, func() hash.Hash, error) {
+	if len(alg) == 5 {
+		switch alg[:2] {
+		case "RS", "PS", "ES":
+			switch alg[2:] {
+			case "256":
+				return crypto.SHA256, sha256.New, nil
+			case "384":
+				return crypto.SHA384, sha512.New384, nil
+			case "512":
+				return crypto.SHA512, sha512.New, nil
+			}
+		}
+	}
+	return 0, nil, fmt.Errorf("unsupported JWS algorithm %q", alg)
+}
+
+// jwk is a JSON Web Key (RFC 7517) holding a public key
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Crv string `json:"crv"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// ParseJWKS parses a JSON Web Key Set into trusted keys. RSA, EC (P-256,
+// P-384, P-521) and Ed25519 keys are supported; other keys are skipped.
+func ParseJWKS(data []byte) (StaticKeys, error) {
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("failed to parse JWKS: %w", err)
+	}
+
+	keys := make(StaticKeys, len(set.Keys))
+	for i, k := range set.Keys {
+		key, err := k.publicKey()
+		if err != nil {
+			return nil, fmt.Errorf("invalid JWKS key %d: %w", i, err)
+		}
+		if key != nil {
+			keys[k.Kid] = key
+		}
+	}
+	return keys, nil
+}
+
+// publicKey decodes the key, returning nil for unsupported key types
+func (k jwk) publicKey() (crypto.PublicKey, error) {
+	decode := base64.RawURLEncoding.DecodeString
+
+	switch k.Kty {
+	case "RSA":
+		n, err := decode(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("invalid modulus: %w", err)
+		}
+		e, err := decode(k.E)
+		if err != nil {
+			return nil, fmt.Errorf("invalid exponent: %w", err)
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}, nil
+
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, nil
+		}
+		x, err := decode(k.X)
+		if err != nil {
+			return nil, fmt.Errorf("invalid x coordinate: %w", err)
+		}
+		y, err := decode(k.Y)
+		if err != nil {
+			return nil, fmt.Errorf("invalid y coordinate: %w", err)
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}, nil
+
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			return nil, nil
+		}
+		x, err := decode(k.X)
+		if err != nil {
+			return nil, fmt.Errorf("invalid public key: %w", err)
+		}
+		if len(x) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("invalid Ed25519 key length")
+		}
+		return ed25519.PublicKey(x), nil
+	}
+	return nil, nil
+}
@@ -0,0 +1,162 @@
+// Package jsondiff provides structural comparison of JSON documents.
+//
+// Differences are reported with JSON Pointer paths (RFC 6901) so they can
+// be located in either document. Volatile fields such as timestamps and
+// generated IDs can be excluded with an ignore list.
+package jsondiff
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Difference describes a single divergence between two JSON documents.
+// A nil Expected or Actual means the value is absent on that side.
+type Difference struct {
+	Path     string      `json:"path"`
+	Expected interface{} `json:"expected"`
+	Actual   interface{} `json:"actual"`
+}
+
+// String returns a human-readable description of the difference
+func (d Difference) String() string {
+	return fmt.Sprintf("%s: expected %s, got %s", d.Path, render(d.Expected), render(d.Actual))
+}
+
+// Compare decodes and compares two JSON documents.
+//
+// Each ignore entry is either a JSON Pointer (starting with "/") matching
+// an exact path, or a bare key name matching that key at any depth.
+func Compare(expected, actual []byte, ignore []string) ([]Difference, error) {
+	var exp, act interface{}
+	if len(expected) > 0 {
+		if err := json.Unmarshal(expected, &exp); err != nil {
+			return nil, fmt.Errorf("invalid expected JSON: %w", err)
+		}
+	}
+	if len(actual) > 0 {
+		if err := json.Unmarshal(actual, &act); err != nil {
+			return nil, fmt.Errorf("invalid actual JSON: %w", err)
+		}
+	}
+	return CompareValues(exp, act, ignore), nil
+}
+
+// CompareValues compares two decoded JSON values (as produced by
+// encoding/json into interface{}), using the same ignore rules as Compare
+func CompareValues(expected, actual interface{}, ignore []string) []Difference {
+	m := newMatcher(ignore)
+	var diffs []Difference
+	walk("", expected, actual, m, &diffs)
+	return diffs
+}
+
+// matcher decides which paths are ignored
+type matcher struct {
+	paths map[string]bool
+	keys  map[string]bool
+}
+
+func newMatcher(ignore []string) *matcher {
+	m := &matcher{paths: make(map[string]bool), keys: make(map[string]bool)}
+	for _, entry := range ignore {
+		if strings.HasPrefix(entry, "/") {
+			m.paths[entry] = true
+		} else if entry != "" {
+			m.keys[entry] = true
+		}
+	}
+	return m
+}
+
+func (m *matcher) ignored(path, key string) bool {
+	return m.paths[path] || (key != "" && m.keys[key])
+}
+
+// walk recursively compares expected and actual at path
+func walk(path string, expected, actual interface{}, m *matcher, diffs *[]Difference) {
+	switch exp := expected.(type) {
+	case map[string]interface{}:
+		act, ok := actual.(map[string]interface{})
+		if !ok {
+			break
+		}
+		for _, key := range unionKeys(exp, act) {
+			child := path + "/" + escape(key)
+			if m.ignored(child, key) {
+				continue
+			}
+			walk(child, exp[key], act[key], m, diffs)
+		}
+		return
+
+	case []interface{}:
+		act, ok := actual.([]interface{})
+		if !ok {
+			break
+		}
+		n := len(exp)
+		if len(act) > n {
+			n = len(act)
+		}
+		for i := 0; i < n; i++ {
+			child := path + "/" + strconv.Itoa(i)
+			if m.ignored(child, "") {
+				continue
+			}
+			var e, a interface{}
+			if i < len(exp) {
+				e = exp[i]
+			}
+			if i < len(act) {
+				a = act[i]
+			}
+			walk(child, e, a, m, diffs)
+		}
+		return
+	}
+
+	if !reflect.DeepEqual(expected, actual) {
+		if path == "" {
+			path = "/"
+		}
+		*diffs = append(*diffs, Difference{Path: path, Expected: expected, Actual: actual})
+	}
+}
+
+// unionKeys returns the sorted keys present in either map
+func unionKeys(a, b map[string]interface{}) []string {
+	seen := make(map[string]bool, len(a)+len(b))
+	keys := make([]string, 0, len(a)+len(b))
+	for _, m := range []map[string]interface{}{a, b} {
+		for k := range m {
+			if !seen[k] {
+				seen[k] = true
+				keys = append(keys, k)
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// escape encodes a key as a JSON Pointer reference token
+func escape(key string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(key, "~", "~0"), "/", "~1")
+}
+
+// render formats a value for display
+func render(v interface{}) string {
+	if v == nil {
+		return "<absent>"
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(data)
+}
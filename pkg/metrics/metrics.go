@@ -0,0 +1,111 @@
+// Package metrics provides Prometheus instrumentation for openribcage.
+//
+// Metrics are only collected when a *Metrics created by New is handed to
+// the A2A client, the streaming client or the AgentCard discoverer. Every
+// method is a no-op on a nil *Metrics, so instrumented code never needs to
+// check whether metrics are enabled.
+package metrics
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// namespace prefixes every metric name
+const namespace = "openribcage"
+
+// Discovery results used as the "result" label
+const (
+	resultSuccess = "success"
+	resultFailure = "failure"
+)
+
+// Metrics holds the collectors for A2A calls and AgentCard discovery
+type Metrics struct {
+	requests   *prometheus.CounterVec
+	errors     *prometheus.CounterVec
+	latency    *prometheus.HistogramVec
+	discovery  *prometheus.CounterVec
+	reconnects prometheus.Counter
+}
+
+// New creates the collectors and registers them with reg, which is
+// usually prometheus.DefaultRegisterer or a registry owned by the
+// embedding application
+func New(reg prometheus.Registerer) (*Metrics, error) {
+	m := &Metrics{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "a2a",
+			Name:      "requests_total",
+			Help:      "A2A requests sent, by JSON-RPC method and agent.",
+		}, []string{"method", "agent"}),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "a2a",
+			Name:      "errors_total",
+			Help:      "A2A requests that failed, by JSON-RPC method and agent.",
+		}, []string{"method", "agent"}),
+		latency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "a2a",
+			Name:      "request_duration_seconds",
+			Help:      "A2A request latency, by JSON-RPC method and agent. Streams are measured until they end.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "agent"}),
+		discovery: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "agentcard",
+			Name:      "discoveries_total",
+			Help:      "AgentCard discoveries, by result (success or failure).",
+		}, []string{"result"}),
+		reconnects: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "stream",
+			Name:      "reconnects_total",
+			Help:      "SSE stream reconnection attempts.",
+		}),
+	}
+
+	for _, c := range []prometheus.Collector{m.requests, m.errors, m.latency, m.discovery, m.reconnects} {
+		if err := reg.Register(c); err != nil {
+			return nil, fmt.Errorf("failed to register metrics: %w", err)
+		}
+	}
+	return m, nil
+}
+
+// ObserveRequest records one A2A request to agent, its latency, and
+// whether it failed, including with a JSON-RPC error
+func (m *Metrics) ObserveRequest(method, agent string, latency time.Duration, failed bool) {
+	if m == nil {
+		return
+	}
+	m.requests.WithLabelValues(method, agent).Inc()
+	m.latency.WithLabelValues(method, agent).Observe(latency.Seconds())
+	if failed {
+		m.errors.WithLabelValues(method, agent).Inc()
+	}
+}
+
+// ObserveDiscovery records the outcome of an AgentCard discovery
+func (m *Metrics) ObserveDiscovery(err error) {
+	if m == nil {
+		return
+	}
+	result := resultSuccess
+	if err != nil {
+		result = resultFailure
+	}
+	m.discovery.WithLabelValues(result).Inc()
+}
+
+// ObserveReconnect records an SSE reconnection attempt
+func (m *Metrics) ObserveReconnect() {
+	if m == nil {
+		return
+	}
+	m.reconnects.Inc()
+}
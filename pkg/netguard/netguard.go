@@ -0,0 +1,205 @@
+// Package netguard provides host-based access control for outgoing A2A
+// requests.
+//
+// A Policy combines an allowlist and a denylist of host patterns and is
+// checked before any network call, so disallowed hosts are never contacted.
+// URLs supplied by agents rather than by the user, such as advertised
+// endpoints, are additionally kept off private networks (SSRF protection).
+package netguard
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+)
+
+// ErrHostNotAllowed is returned when a host is rejected by a Policy
+var ErrHostNotAllowed = errors.New("host not allowed")
+
+// DefaultDeny blocks cloud metadata endpoints, which must never be
+// reachable through agent traffic
+var DefaultDeny = []string{
+	"169.254.169.254/32",
+	"fd00:ec2::254/128",
+	"metadata.google.internal",
+}
+
+// Policy restricts the hosts that may be contacted.
+//
+// Patterns may be an exact host name or IP address, a CIDR range such as
+// "10.0.0.0/8", or a wildcard such as "*.example.com" matching any
+// subdomain. CIDR ranges only match hosts given as IP literals; host names
+// are not resolved. Deny takes precedence over Allow, and an empty Allow
+// permits every host that is not denied.
+type Policy struct {
+	Allow []string `json:"allow,omitempty" yaml:"allow,omitempty"`
+	Deny  []string `json:"deny,omitempty" yaml:"deny,omitempty"`
+
+	// AllowPrivate lets agent-provided URLs point at private, loopback and
+	// link-local addresses. See CheckProvidedURL.
+	AllowPrivate bool `json:"allow_private,omitempty" yaml:"allow_private,omitempty"`
+}
+
+// DefaultPolicy returns a policy that allows every host except DefaultDeny
+func DefaultPolicy() *Policy {
+	return &Policy{
+		Deny: append([]string(nil), DefaultDeny...),
+	}
+}
+
+// Check returns an error wrapping ErrHostNotAllowed if host may not be
+// contacted. The host may include a port. A nil Policy allows all hosts.
+func (p *Policy) Check(host string) error {
+	if p == nil {
+		return nil
+	}
+
+	host = normalizeHost(host)
+	if host == "" {
+		return fmt.Errorf("%w: empty host", ErrHostNotAllowed)
+	}
+
+	for _, pattern := range p.Deny {
+		if matches(pattern, host) {
+			return fmt.Errorf("%w: %s is denied by %q", ErrHostNotAllowed, host, pattern)
+		}
+	}
+
+	if len(p.Allow) == 0 {
+		return nil
+	}
+	for _, pattern := range p.Allow {
+		if matches(pattern, host) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s is not in the allowlist", ErrHostNotAllowed, host)
+}
+
+// CheckURL checks the host of rawURL. URLs without a scheme are treated
+// as http, as in agent discovery.
+func (p *Policy) CheckURL(rawURL string) error {
+	if p == nil {
+		return nil
+	}
+
+	rawURL = strings.TrimSpace(rawURL)
+	if !strings.Contains(rawURL, "://") {
+		rawURL = "http://" + rawURL
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("invalid URL: %w", err)
+	}
+	return p.Check(u.Host)
+}
+
+// CheckProvidedURL checks a URL supplied by an agent, such as an endpoint
+// advertised in its AgentCard, before the client follows it. In addition
+// to Check, the host must not resolve to a private, loopback or link-local
+// address unless AllowPrivate is set or it is the host of origin, the URL
+// the agent was discovered from. Names that fail to resolve are rejected,
+// since a later lookup could return a private address.
+func (p *Policy) CheckProvidedURL(ctx context.Context, rawURL, origin string) error {
+	if p == nil {
+		return nil
+	}
+
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return fmt.Errorf("invalid URL: %w", err)
+	}
+	if err := p.Check(u.Host); err != nil {
+		return err
+	}
+	if p.AllowPrivate {
+		return nil
+	}
+
+	host := normalizeHost(u.Host)
+	if originURL, err := url.Parse(origin); err == nil && normalizeHost(originURL.Host) == host {
+		return nil
+	}
+
+	ips := []net.IP{net.ParseIP(host)}
+	if ips[0] == nil {
+		addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+		if err != nil {
+			return fmt.Errorf("%w: cannot resolve %s: %v", ErrHostNotAllowed, host, err)
+		}
+		ips = ips[:0]
+		for _, addr := range addrs {
+			ips = append(ips, addr.IP)
+		}
+	}
+
+	for _, ip := range ips {
+		if IsPrivate(ip) {
+			return fmt.Errorf("%w: %s resolves to non-public address %s", ErrHostNotAllowed, host, ip)
+		}
+	}
+	return nil
+}
+
+// sharedAddressSpace is the carrier-grade NAT range (RFC 6598)
+var sharedAddressSpace = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// IsPrivate reports whether ip is a private, loopback, link-local,
+// shared or unspecified address
+func IsPrivate(ip net.IP) bool {
+	return ip.IsPrivate() || ip.IsLoopback() || ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() || ip.IsUnspecified() || sharedAddressSpace.Contains(ip)
+}
+
+// Validate checks that every pattern in the policy is well formed
+func (p *Policy) Validate() error {
+	if p == nil {
+		return nil
+	}
+	for _, pattern := range append(append([]string(nil), p.Allow...), p.Deny...) {
+		if strings.Contains(pattern, "/") {
+			if _, _, err := net.ParseCIDR(pattern); err != nil {
+				return fmt.Errorf("invalid CIDR pattern %q: %w", pattern, err)
+			}
+		} else if strings.TrimSpace(pattern) == "" {
+			return fmt.Errorf("empty host pattern")
+		}
+	}
+	return nil
+}
+
+// normalizeHost strips any port and IPv6 brackets and lower-cases host
+func normalizeHost(host string) string {
+	host = strings.TrimSpace(host)
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	host = strings.TrimSuffix(strings.TrimPrefix(host, "["), "]")
+	return strings.TrimSuffix(strings.ToLower(host), ".")
+}
+
+// matches reports whether a normalized host matches pattern
+func matches(pattern, host string) bool {
+	pattern = strings.ToLower(strings.TrimSpace(pattern))
+
+	if strings.Contains(pattern, "/") {
+		_, network, err := net.ParseCIDR(pattern)
+		if err != nil {
+			return false
+		}
+		ip := net.ParseIP(host)
+		return ip != nil && network.Contains(ip)
+	}
+
+	if suffix, ok := strings.CutPrefix(pattern, "*."); ok {
+		return strings.HasSuffix(host, "."+suffix)
+	}
+
+	if ip := net.ParseIP(pattern); ip != nil {
+		return ip.Equal(net.ParseIP(host))
+	}
+	return host == pattern
+}
@@ -0,0 +1,93 @@
+package netguard
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestCheck tests allow and deny patterns against hosts
+func TestCheck(t *testing.T) {
+	p := &Policy{
+		Allow: []string{"*.example.com", "10.0.0.0/8", "agent.local"},
+		Deny:  []string{"blocked.example.com", "10.0.0.5"},
+	}
+
+	tests := []struct {
+		host string
+		ok   bool
+	}{
+		{"api.example.com", true},
+		{"API.Example.COM.", true},
+		{"a.b.example.com:8443", true},
+		{"example.com", false},
+		{"blocked.example.com", false},
+		{"10.1.2.3", true},
+		{"10.0.0.5:8080", false},
+		{"agent.local", true},
+		{"11.0.0.1", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		err := p.Check(tt.host)
+		if tt.ok {
+			assert.NoError(t, err, tt.host)
+		} else {
+			assert.ErrorIs(t, err, ErrHostNotAllowed, tt.host)
+		}
+	}
+
+	var nilPolicy *Policy
+	assert.NoError(t, nilPolicy.Check("anything"))
+}
+
+// TestDefaultPolicy tests that the default policy only blocks metadata endpoints
+func TestDefaultPolicy(t *testing.T) {
+	p := DefaultPolicy()
+	assert.NoError(t, p.CheckURL("http://agents.example.com:8083/a2a"))
+	assert.NoError(t, p.CheckURL("localhost:8083"))
+	assert.ErrorIs(t, p.CheckURL("http://169.254.169.254/latest/meta-data"), ErrHostNotAllowed)
+	assert.ErrorIs(t, p.CheckURL("http://[fd00:ec2::254]/"), ErrHostNotAllowed)
+	assert.ErrorIs(t, p.CheckURL("metadata.google.internal"), ErrHostNotAllowed)
+
+	// Changing a copy does not affect DefaultDeny
+	p.Deny[0] = "changed"
+	assert.Equal(t, "169.254.169.254/32", DefaultDeny[0])
+}
+
+// TestCheckProvidedURL tests that agent-provided URLs may not point at
+// private addresses other than the agent's own host
+func TestCheckProvidedURL(t *testing.T) {
+	ctx := context.Background()
+	p := DefaultPolicy()
+
+	assert.NoError(t, p.CheckProvidedURL(ctx, "https://93.184.216.34/a2a", "https://agents.example.com"))
+	assert.ErrorIs(t, p.CheckProvidedURL(ctx, "http://10.0.0.1/a2a", "https://agents.example.com"), ErrHostNotAllowed)
+	assert.ErrorIs(t, p.CheckProvidedURL(ctx, "http://127.0.0.1:9000/a2a", "http://127.0.0.2"), ErrHostNotAllowed)
+	assert.NoError(t, p.CheckProvidedURL(ctx, "http://127.0.0.1:9000/a2a", "http://127.0.0.1:8083/card"))
+	assert.ErrorIs(t, p.CheckProvidedURL(ctx, "http://169.254.169.254/", "http://169.254.169.254/"), ErrHostNotAllowed)
+	assert.ErrorIs(t, p.CheckProvidedURL(ctx, "http://agent.invalid/a2a", "https://agents.example.com"), ErrHostNotAllowed)
+
+	p.AllowPrivate = true
+	assert.NoError(t, p.CheckProvidedURL(ctx, "http://10.0.0.1/a2a", "https://agents.example.com"))
+}
+
+// TestIsPrivate tests classification of non-public addresses
+func TestIsPrivate(t *testing.T) {
+	for _, ip := range []string{"10.1.1.1", "172.16.0.1", "192.168.1.1", "127.0.0.1", "::1", "169.254.1.1", "fe80::1", "100.64.0.1", "0.0.0.0", "fd00::1"} {
+		assert.True(t, IsPrivate(net.ParseIP(ip)), ip)
+	}
+	for _, ip := range []string{"93.184.216.34", "8.8.8.8", "2606:4700::1111", "100.128.0.1"} {
+		assert.False(t, IsPrivate(net.ParseIP(ip)), ip)
+	}
+}
+
+// TestValidate tests rejection of malformed patterns
+func TestValidate(t *testing.T) {
+	assert.NoError(t, (&Policy{Allow: []string{"*.example.com", "10.0.0.0/8"}}).Validate())
+	assert.ErrorContains(t, (&Policy{Deny: []string{"10.0.0.0/33"}}).Validate(), "invalid CIDR pattern")
+	assert.ErrorContains(t, (&Policy{Allow: []string{" "}}).Validate(), "empty host pattern")
+}
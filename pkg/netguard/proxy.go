@@ -0,0 +1,32 @@
+package netguard
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Proxy returns a proxy function for http.Transport that sends every
+// request through proxyURL. http, https, socks5 and socks5h proxies are
+// supported. An empty proxyURL falls back to http.ProxyFromEnvironment.
+func Proxy(proxyURL string) (func(*http.Request) (*url.URL, error), error) {
+	proxyURL = strings.TrimSpace(proxyURL)
+	if proxyURL == "" {
+		return http.ProxyFromEnvironment, nil
+	}
+
+	u, err := url.Parse(proxyURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy URL: %w", err)
+	}
+	switch u.Scheme {
+	case "http", "https", "socks5", "socks5h":
+	default:
+		return nil, fmt.Errorf("unsupported proxy scheme %q (supported: http, https, socks5)", u.Scheme)
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("invalid proxy URL %q: missing host", proxyURL)
+	}
+	return http.ProxyURL(u), nil
+}
@@ -0,0 +1,92 @@
+package registry
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/craine-io/openribcage/pkg/a2a/types"
+)
+
+// NormalizeURL returns the canonical form of an agent URL used to detect
+// duplicate registrations: the scheme and host are lower-cased, default
+// ports and trailing slashes are dropped, and so is any fragment. URLs
+// that cannot be parsed are only stripped of trailing slashes.
+func NormalizeURL(rawURL string) string {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil || u.Host == "" {
+		return strings.TrimRight(rawURL, "/")
+	}
+
+	u.Scheme = strings.ToLower(u.Scheme)
+	host, port := strings.ToLower(u.Hostname()), u.Port()
+	if (u.Scheme == "http" && port == "80") || (u.Scheme == "https" && port == "443") {
+		port = ""
+	}
+	if strings.Contains(host, ":") {
+		host = "[" + host + "]"
+	}
+	if port != "" {
+		host += ":" + port
+	}
+	u.Host = host
+	u.Path = strings.TrimRight(u.Path, "/")
+	u.RawPath = ""
+	u.Fragment = ""
+	return u.String()
+}
+
+// findByURL returns the agent registered under the same normalized URL.
+// The caller must hold r.mu.
+func (r *Registry) findByURL(agentURL string) (*types.Agent, bool) {
+	if agentURL == "" {
+		return nil, false
+	}
+	want := NormalizeURL(agentURL)
+	for _, agent := range r.agents {
+		if NormalizeURL(agent.URL) == want {
+			return agent, true
+		}
+	}
+	return nil, false
+}
+
+// AgentID derives an ID for a new agent from its name, adding a numeric
+// suffix when the ID is taken by an agent at a different URL. An agent
+// already registered at agentURL keeps its ID.
+func (r *Registry) AgentID(name, agentURL string) string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if existing, ok := r.findByURL(agentURL); ok {
+		return existing.ID
+	}
+
+	base := slugify(name)
+	if base == "" {
+		base = "agent"
+	}
+	id := base
+	for n := 2; ; n++ {
+		if _, taken := r.agents[id]; !taken {
+			return id
+		}
+		id = base + "-" + strconv.Itoa(n)
+	}
+}
+
+// slugify lowercases s and replaces runs of other characters with dashes
+func slugify(s string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(s) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+			dash = false
+		} else if !dash && b.Len() > 0 {
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+	return strings.TrimSuffix(b.String(), "-")
+}
@@ -0,0 +1,53 @@
+package registry
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/craine-io/openribcage/pkg/a2a/types"
+)
+
+// TestRegistryDeduplicatesByURL tests that re-registering an agent under
+// a different ID but the same URL updates the existing entry
+func TestRegistryDeduplicatesByURL(t *testing.T) {
+	reg := NewRegistry(time.Minute)
+	discovered := time.Now().Add(-time.Hour)
+
+	first := &types.Agent{
+		ID:           "helm-agent",
+		URL:          "https://agents.example.com:443/helm/",
+		Card:         &types.AgentCard{Name: "helm-agent", Version: "1.0.0"},
+		DiscoveredAt: discovered,
+	}
+	require.NoError(t, reg.Register(first))
+
+	for _, url := range []string{
+		"https://agents.example.com/helm",
+		"HTTPS://Agents.Example.com/helm/",
+	} {
+		again := &types.Agent{
+			ID:           "helm-agent-2",
+			URL:          url,
+			Card:         &types.AgentCard{Name: "helm-agent", Version: "1.1.0"},
+			DiscoveredAt: time.Now(),
+		}
+		require.NoError(t, reg.Register(again))
+		assert.Equal(t, "helm-agent", again.ID, url)
+
+		agents := reg.List()
+		require.Len(t, agents, 1, url)
+		assert.Equal(t, "1.1.0", agents[0].Card.Version)
+		assert.True(t, agents[0].DiscoveredAt.Equal(discovered), "DiscoveredAt should be preserved")
+		assert.False(t, agents[0].LastSeen.IsZero())
+	}
+
+	assert.Equal(t, "helm-agent", reg.AgentID("Helm Agent", "https://agents.example.com/helm/"))
+	assert.Equal(t, "helm-agent-2", reg.AgentID("Helm Agent", "http://agents.example.com:8080/helm"))
+
+	other := &types.Agent{ID: "helm-agent-2", URL: "http://agents.example.com:8080/helm"}
+	require.NoError(t, reg.Register(other))
+	assert.Len(t, reg.List(), 2)
+}
@@ -0,0 +1,66 @@
+package registry
+
+import (
+	"time"
+
+	"github.com/craine-io/openribcage/pkg/a2a/types"
+)
+
+// EventType identifies the kind of registry change
+type EventType string
+
+const (
+	EventAdded         EventType = "added"
+	EventUpdated       EventType = "updated"
+	EventRemoved       EventType = "removed"
+	EventStatusChanged EventType = "status_changed"
+)
+
+// subscriberBuffer is the channel capacity given to each subscriber
+const subscriberBuffer = 64
+
+// RegistryEvent describes a change to a registered agent
+type RegistryEvent struct {
+	Type      EventType    `json:"type"`
+	Agent     *types.Agent `json:"agent"`
+	Timestamp time.Time    `json:"timestamp"`
+}
+
+// Subscribe returns a buffered channel receiving every subsequent registry
+// change. Producers never block on subscribers: if a subscriber falls more
+// than its buffer behind, further events are dropped for it. Call
+// Unsubscribe to release the channel.
+func (r *Registry) Subscribe() <-chan RegistryEvent {
+	r.subMu.Lock()
+	defer r.subMu.Unlock()
+
+	ch := make(chan RegistryEvent, subscriberBuffer)
+	r.subscribers[ch] = ch
+	return ch
+}
+
+// Unsubscribe stops delivery to a channel returned by Subscribe and closes it
+func (r *Registry) Unsubscribe(ch <-chan RegistryEvent) {
+	r.subMu.Lock()
+	defer r.subMu.Unlock()
+
+	if sub, ok := r.subscribers[ch]; ok {
+		delete(r.subscribers, ch)
+		close(sub)
+	}
+}
+
+// publish delivers an event to every subscriber without blocking
+func (r *Registry) publish(eventType EventType, agent *types.Agent) {
+	r.subMu.Lock()
+	defer r.subMu.Unlock()
+
+	event := RegistryEvent{Type: eventType, Agent: agent, Timestamp: time.Now()}
+	for _, sub := range r.subscribers {
+		select {
+		case sub <- event:
+		default:
+			r.logger.Debugf("Dropping %s event for slow registry subscriber", eventType)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package registry
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/craine-io/openribcage/pkg/a2a/types"
+)
+
+// nextEvent receives the next event from ch or fails the test
+func nextEvent(t *testing.T, ch <-chan RegistryEvent) RegistryEvent {
+	t.Helper()
+	select {
+	case event, ok := <-ch:
+		require.True(t, ok, "subscription closed")
+		return event
+	case <-time.After(time.Second):
+		t.Fatal("no registry event received")
+		return RegistryEvent{}
+	}
+}
+
+// TestSubscribe tests that subscribers receive every change in order
+func TestSubscribe(t *testing.T) {
+	r := NewRegistry(time.Minute)
+	events := r.Subscribe()
+	defer r.Unsubscribe(events)
+
+	require.NoError(t, r.Register(&types.Agent{ID: "k8s", URL: "http://k8s:8083", Status: types.AgentStatusOnline}))
+	require.NoError(t, r.Register(&types.Agent{ID: "k8s", URL: "http://k8s:8083", Status: types.AgentStatusOnline}))
+	require.NoError(t, r.UpdateStatus("k8s", types.AgentStatusOffline))
+	require.NoError(t, r.UpdateStatus("k8s", types.AgentStatusOffline))
+	require.NoError(t, r.Unregister("k8s"))
+
+	for _, want := range []EventType{EventAdded, EventUpdated, EventStatusChanged, EventRemoved} {
+		event := nextEvent(t, events)
+		assert.Equal(t, want, event.Type)
+		assert.Equal(t, "k8s", event.Agent.ID)
+		assert.False(t, event.Timestamp.IsZero())
+	}
+	select {
+	case event := <-events:
+		t.Fatalf("unexpected %s event: an unchanged status is not published", event.Type)
+	default:
+	}
+}
+
+// TestUnsubscribe tests that unsubscribing closes the channel and stops
+// delivery without affecting other subscribers
+func TestUnsubscribe(t *testing.T) {
+	r := NewRegistry(time.Minute)
+	first, second := r.Subscribe(), r.Subscribe()
+	defer r.Unsubscribe(second)
+
+	r.Unsubscribe(first)
+	r.Unsubscribe(first)
+	_, ok := <-first
+	assert.False(t, ok, "the channel is closed")
+
+	require.NoError(t, r.Register(&types.Agent{ID: "k8s", URL: "http://k8s:8083"}))
+	assert.Equal(t, EventAdded, nextEvent(t, second).Type)
+}
+
+// TestSubscribeSlowConsumer tests that a full subscriber drops events
+// instead of blocking registry changes
+func TestSubscribeSlowConsumer(t *testing.T) {
+	r := NewRegistry(time.Minute)
+	events := r.Subscribe()
+	defer r.Unsubscribe(events)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < subscriberBuffer+10; i++ {
+			r.Register(&types.Agent{ID: "k8s", URL: "http://k8s:8083"})
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("registry blocked on a slow subscriber")
+	}
+	assert.Len(t, events, subscriberBuffer)
+}
@@ -0,0 +1,115 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/craine-io/openribcage/pkg/a2a/client"
+	"github.com/craine-io/openribcage/pkg/a2a/types"
+)
+
+// DefaultHealthCheckConcurrency is the number of agents pinged at once when
+// StartHealthChecks is given no concurrency
+const DefaultHealthCheckConcurrency = 4
+
+// Pinger checks that an agent is reachable; *client.Client implements it
+type Pinger interface {
+	Ping(ctx context.Context, agentURL string) (*client.PingResult, error)
+}
+
+// StartHealthChecks pings every registered agent each interval, at most
+// concurrency at a time, and updates its status: online when it answers,
+// error when it answers with a 5xx status and offline when it cannot be
+// reached. Only online agents have LastSeen refreshed, so unreachable
+// agents still age out through StartCleanup. It blocks until ctx is done.
+func (r *Registry) StartHealthChecks(ctx context.Context, pinger Pinger, interval time.Duration, concurrency int) {
+	if concurrency <= 0 {
+		concurrency = DefaultHealthCheckConcurrency
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.checkHealth(ctx, pinger, concurrency)
+		}
+	}
+}
+
+// checkHealth pings every registered agent once and records the results
+func (r *Registry) checkHealth(ctx context.Context, pinger Pinger, concurrency int) {
+	agents := r.List()
+
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for _, agent := range agents {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			wg.Wait()
+			return
+		}
+
+		wg.Add(1)
+		go func(id, url string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			status := pingStatus(pinger.Ping(ctx, url))
+			if ctx.Err() != nil {
+				return
+			}
+			if err := r.setStatus(id, status, status == types.AgentStatusOnline); err != nil {
+				r.logger.Debugf("Health check of agent %s: %v", id, err)
+			}
+		}(agent.ID, agent.URL)
+	}
+	wg.Wait()
+}
+
+// pingStatus maps the outcome of a ping to an agent status
+func pingStatus(result *client.PingResult, err error) types.AgentStatus {
+	switch {
+	case err != nil:
+		return types.AgentStatusOffline
+	case result.StatusCode >= http.StatusInternalServerError:
+		return types.AgentStatusError
+	default:
+		return types.AgentStatusOnline
+	}
+}
+
+// setStatus updates an agent's status, refreshing LastSeen when seen is
+// set. The agent is replaced by an updated copy rather than modified in
+// place, so agents already returned by Get or List are never written to
+// while a caller reads them.
+func (r *Registry) setStatus(agentID string, status types.AgentStatus, seen bool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, exists := r.agents[agentID]
+	if !exists {
+		return fmt.Errorf("agent not found: %s", agentID)
+	}
+
+	agent := *existing
+	agent.Status = status
+	if seen {
+		agent.LastSeen = time.Now()
+	}
+	r.agents[agentID] = &agent
+
+	if existing.Status != status {
+		r.publish(EventStatusChanged, &agent)
+	}
+
+	r.logger.Debugf("Updated agent %s status to %s", agentID, status)
+	return r.persist(&agent)
+}
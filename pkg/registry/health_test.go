@@ -0,0 +1,64 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/craine-io/openribcage/pkg/a2a/client"
+	"github.com/craine-io/openribcage/pkg/a2a/types"
+)
+
+// fakePinger answers pings with a fixed outcome per agent URL
+type fakePinger map[string]int
+
+// Ping implements Pinger; a zero status code fails the ping
+func (p fakePinger) Ping(ctx context.Context, agentURL string) (*client.PingResult, error) {
+	code := p[agentURL]
+	if code == 0 {
+		return nil, errors.New("connection refused")
+	}
+	return &client.PingResult{URL: agentURL, StatusCode: code}, nil
+}
+
+// TestRegistryHealthChecks tests that polling updates agent status
+func TestRegistryHealthChecks(t *testing.T) {
+	reg := NewRegistry(time.Minute)
+	for _, id := range []string{"up", "down", "broken"} {
+		require.NoError(t, reg.Register(&types.Agent{ID: id, URL: "http://" + id, Status: types.AgentStatusDiscovering}))
+	}
+	pinger := fakePinger{"http://up": 200, "http://broken": 503}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		reg.StartHealthChecks(ctx, pinger, 10*time.Millisecond, 2)
+		close(done)
+	}()
+
+	want := map[string]types.AgentStatus{
+		"up":     types.AgentStatusOnline,
+		"down":   types.AgentStatusOffline,
+		"broken": types.AgentStatusError,
+	}
+	assert.Eventually(t, func() bool {
+		// Summaries copies agents under the registry lock
+		for _, summary := range reg.Summaries() {
+			if summary.Status != want[summary.ID] {
+				return false
+			}
+		}
+		return true
+	}, time.Second, 10*time.Millisecond)
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("health checks did not stop with the context")
+	}
+}
@@ -0,0 +1,103 @@
+package registry
+
+import (
+	"fmt"
+
+	"github.com/craine-io/openribcage/pkg/a2a/types"
+)
+
+// ConflictStrategy decides what happens when an imported agent
+// already exists in the registry
+type ConflictStrategy string
+
+const (
+	// SkipExisting keeps the agent already in the registry
+	SkipExisting ConflictStrategy = "skip-existing"
+	// Overwrite replaces the existing agent with the imported one
+	Overwrite ConflictStrategy = "overwrite"
+	// Newest keeps whichever agent has the more recent CardFetchedAt
+	Newest ConflictStrategy = "newest"
+)
+
+// MergeSummary reports the outcome of a Merge
+type MergeSummary struct {
+	Added   int `json:"added"`
+	Updated int `json:"updated"`
+	Skipped int `json:"skipped"`
+}
+
+// String returns a human-readable summary
+func (s MergeSummary) String() string {
+	return fmt.Sprintf("%d added, %d updated, %d skipped", s.Added, s.Updated, s.Skipped)
+}
+
+// Merge imports a catalog of agents into the registry, resolving
+// conflicts with the given strategy. As in Register, an agent conflicts
+// with an existing one that has the same ID or normalized URL; a
+// replacement takes over the existing ID and DiscoveredAt.
+func (r *Registry) Merge(catalog []*types.Agent, strategy ConflictStrategy) (MergeSummary, error) {
+	var summary MergeSummary
+
+	switch strategy {
+	case SkipExisting, Overwrite, Newest:
+	default:
+		return summary, fmt.Errorf("unsupported conflict strategy: %s", strategy)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, agent := range catalog {
+		if agent == nil || agent.ID == "" {
+			summary.Skipped++
+			continue
+		}
+
+		existing, exists := r.agents[agent.ID]
+		if !exists {
+			existing, exists = r.findByURL(agent.URL)
+		}
+		if exists && !shouldReplace(existing, agent, strategy) {
+			summary.Skipped++
+			continue
+		}
+		if exists {
+			agent.ID = existing.ID
+			if !existing.DiscoveredAt.IsZero() {
+				agent.DiscoveredAt = existing.DiscoveredAt
+			}
+		}
+
+		if !exists {
+			if err := r.makeRoom(); err != nil {
+				return summary, err
+			}
+		}
+		r.agents[agent.ID] = agent
+		if err := r.persist(agent); err != nil {
+			return summary, err
+		}
+		if exists {
+			summary.Updated++
+			r.publish(EventUpdated, agent)
+		} else {
+			summary.Added++
+			r.publish(EventAdded, agent)
+		}
+	}
+
+	r.logger.Infof("Merged agent catalog (%s): %s", strategy, summary)
+	return summary, nil
+}
+
+// shouldReplace reports whether incoming should replace existing under strategy
+func shouldReplace(existing, incoming *types.Agent, strategy ConflictStrategy) bool {
+	switch strategy {
+	case Overwrite:
+		return true
+	case Newest:
+		return incoming.CardFetchedAt.After(existing.CardFetchedAt)
+	default:
+		return false
+	}
+}
@@ -0,0 +1,127 @@
+package registry
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/craine-io/openribcage/pkg/a2a/types"
+)
+
+// TestMerge tests each conflict strategy on agents that share an ID
+func TestMerge(t *testing.T) {
+	older := time.Now().Add(-time.Hour)
+	newer := time.Now()
+
+	tests := []struct {
+		strategy ConflictStrategy
+		fetched  time.Time
+		want     string
+	}{
+		{SkipExisting, newer, "existing"},
+		{Overwrite, older, "incoming"},
+		{Newest, older, "existing"},
+		{Newest, newer, "incoming"},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.strategy), func(t *testing.T) {
+			r := NewRegistry(time.Minute)
+			require.NoError(t, r.Register(&types.Agent{ID: "k8s", Name: "existing", URL: "http://k8s:8083", CardFetchedAt: older.Add(time.Minute)}))
+
+			summary, err := r.Merge([]*types.Agent{
+				{ID: "k8s", Name: "incoming", URL: "http://k8s:8083", CardFetchedAt: tt.fetched},
+				{ID: "helm", Name: "helm", URL: "http://helm:8083"},
+				nil,
+			}, tt.strategy)
+			require.NoError(t, err)
+
+			agent, err := r.Get("k8s")
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, agent.Name)
+			assert.Equal(t, 1, summary.Added)
+			assert.Equal(t, 2, summary.Updated+summary.Skipped)
+			assert.Len(t, r.List(), 2)
+		})
+	}
+
+	_, err := NewRegistry(time.Minute).Merge(nil, "latest")
+	assert.ErrorContains(t, err, "unsupported conflict strategy")
+}
+
+// TestMergeDeduplicatesByURL tests that a catalog entry registered under a
+// different ID but the same normalized URL updates the existing agent
+// instead of being added twice
+func TestMergeDeduplicatesByURL(t *testing.T) {
+	discovered := time.Now().Add(-24 * time.Hour)
+	r := NewRegistry(time.Minute)
+	require.NoError(t, r.Register(&types.Agent{ID: "k8s-agent", Name: "k8s", URL: "http://K8S:80/api/a2a/", DiscoveredAt: discovered}))
+
+	summary, err := r.Merge([]*types.Agent{
+		{ID: "catalog-k8s", Name: "k8s (catalog)", URL: "http://k8s/api/a2a"},
+	}, Overwrite)
+	require.NoError(t, err)
+	assert.Equal(t, MergeSummary{Updated: 1}, summary)
+
+	agents := r.List()
+	require.Len(t, agents, 1)
+	assert.Equal(t, "k8s-agent", agents[0].ID, "the existing ID is kept")
+	assert.Equal(t, "k8s (catalog)", agents[0].Name)
+	assert.True(t, discovered.Equal(agents[0].DiscoveredAt))
+	_, err = r.Get("catalog-k8s")
+	assert.Error(t, err)
+
+	summary, err = r.Merge([]*types.Agent{
+		{ID: "other", Name: "ignored", URL: "http://k8s/api/a2a/"},
+	}, SkipExisting)
+	require.NoError(t, err)
+	assert.Equal(t, MergeSummary{Skipped: 1}, summary)
+	assert.Len(t, r.List(), 1)
+}
+
+// TestMergePersists tests that merged agents are written through to the
+// store and announced to subscribers
+func TestMergePersists(t *testing.T) {
+	store := NewFileStore(filepath.Join(t.TempDir(), "agents.json"))
+	r, err := NewRegistryWithStore(time.Minute, store)
+	require.NoError(t, err)
+	require.NoError(t, r.Register(&types.Agent{ID: "k8s", Name: "k8s", URL: "http://k8s:8083"}))
+
+	events := r.Subscribe()
+	defer r.Unsubscribe(events)
+
+	summary, err := r.Merge([]*types.Agent{
+		{ID: "k8s", Name: "k8s v2", URL: "http://k8s:8083"},
+		{ID: "helm", Name: "helm", URL: "http://helm:8083"},
+	}, Overwrite)
+	require.NoError(t, err)
+	assert.Equal(t, "1 added, 1 updated, 0 skipped", summary.String())
+
+	assert.Equal(t, EventUpdated, (<-events).Type)
+	assert.Equal(t, EventAdded, (<-events).Type)
+
+	stored, err := store.LoadAll()
+	require.NoError(t, err)
+	names := map[string]string{}
+	for _, agent := range stored {
+		names[agent.ID] = agent.Name
+	}
+	assert.Equal(t, map[string]string{"k8s": "k8s v2", "helm": "helm"}, names)
+}
+
+// TestMergeFull tests that a full registry rejects new agents but still
+// accepts updates
+func TestMergeFull(t *testing.T) {
+	r := NewRegistry(time.Minute)
+	r.SetMaxAgents(1)
+	require.NoError(t, r.Register(&types.Agent{ID: "k8s", Name: "k8s", URL: "http://k8s:8083"}))
+
+	summary, err := r.Merge([]*types.Agent{
+		{ID: "k8s", Name: "k8s v2", URL: "http://k8s:8083"},
+		{ID: "helm", Name: "helm", URL: "http://helm:8083"},
+	}, Overwrite)
+	assert.ErrorIs(t, err, ErrRegistryFull)
+	assert.Equal(t, MergeSummary{Updated: 1}, summary)
+}
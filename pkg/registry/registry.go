@@ -7,7 +7,10 @@ package registry
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
@@ -16,39 +19,155 @@ import (
 	"github.com/craine-io/openribcage/pkg/a2a/types"
 )
 
+// ErrRegistryFull is returned when registering a new agent would exceed
+// the registry's capacity
+var ErrRegistryFull = errors.New("registry is full")
+
 // Registry manages discovered A2A agents
 type Registry struct {
 	mu      sync.RWMutex
 	agents  map[string]*types.Agent
 	logger  *logrus.Logger
 	cleanup time.Duration
+	store   Store
+
+	maxAgents   int
+	evictOldest bool
+	scorer      RouteScorer
+
+	subMu       sync.Mutex
+	subscribers map[<-chan RegistryEvent]chan RegistryEvent
 }
 
-// NewRegistry creates a new agent registry
+// NewRegistry creates a new in-memory agent registry
 func NewRegistry(cleanupInterval time.Duration) *Registry {
 	return &Registry{
-		agents:  make(map[string]*types.Agent),
-		logger:  logrus.New(),
-		cleanup: cleanupInterval,
+		agents:      make(map[string]*types.Agent),
+		logger:      logrus.New(),
+		cleanup:     cleanupInterval,
+		subscribers: make(map[<-chan RegistryEvent]chan RegistryEvent),
+	}
+}
+
+// NewRegistryWithStore creates an agent registry backed by store.
+// The registry is hydrated from the store and writes every change through to it.
+func NewRegistryWithStore(cleanupInterval time.Duration, store Store) (*Registry, error) {
+	r := NewRegistry(cleanupInterval)
+	r.store = store
+
+	agents, err := store.LoadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load agents from store: %w", err)
+	}
+	for _, agent := range agents {
+		r.agents[agent.ID] = agent
+	}
+
+	r.logger.Debugf("Loaded %d agents from store", len(agents))
+	return r, nil
+}
+
+// SetMaxAgents caps the number of agents in the registry; zero or less
+// means unlimited. Once full, new agents are rejected with ErrRegistryFull
+// unless SetEvictOldest is enabled. Updates to existing agents are always
+// allowed.
+func (r *Registry) SetMaxAgents(max int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.maxAgents = max
+}
+
+// SetEvictOldest makes a full registry evict the least recently seen agent
+// to make room for a new one instead of rejecting it
+func (r *Registry) SetEvictOldest(evict bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.evictOldest = evict
+}
+
+// makeRoom ensures a new agent fits, evicting the least recently seen agent
+// if allowed. The caller must hold r.mu.
+func (r *Registry) makeRoom() error {
+	if r.maxAgents <= 0 || len(r.agents) < r.maxAgents {
+		return nil
+	}
+	if !r.evictOldest {
+		return fmt.Errorf("%w: limit of %d agents reached", ErrRegistryFull, r.maxAgents)
 	}
+
+	var oldest *types.Agent
+	for _, agent := range r.agents {
+		if oldest == nil || agent.LastSeen.Before(oldest.LastSeen) {
+			oldest = agent
+		}
+	}
+
+	r.logger.Infof("Registry full, evicting least recently seen agent: %s", oldest.ID)
+	delete(r.agents, oldest.ID)
+	r.publish(EventRemoved, oldest)
+	return r.forget(oldest.ID)
 }
 
-// Register registers a new agent in the registry
+// persist writes an agent through to the store, if one is configured
+func (r *Registry) persist(agent *types.Agent) error {
+	if r.store == nil {
+		return nil
+	}
+	if err := r.store.Save(agent); err != nil {
+		return fmt.Errorf("failed to persist agent %s: %w", agent.ID, err)
+	}
+	return nil
+}
+
+// forget removes an agent from the store, if one is configured
+func (r *Registry) forget(agentID string) error {
+	if r.store == nil {
+		return nil
+	}
+	if err := r.store.Delete(agentID); err != nil {
+		return fmt.Errorf("failed to delete agent %s from store: %w", agentID, err)
+	}
+	return nil
+}
+
+// Register registers a new agent in the registry. An agent whose ID or
+// normalized URL is already registered replaces that entry instead of
+// being added twice: it takes over the existing ID and DiscoveredAt, while
+// its Card and LastSeen are refreshed. agent is updated in place to match.
 func (r *Registry) Register(agent *types.Agent) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
 	r.logger.Infof("Registering agent: %s (%s)", agent.Name, agent.URL)
 
-	// TODO: Implement agent registration
-	// This will be implemented in Issue #3
-	// 1. Validate agent data
-	// 2. Check for duplicates
-	// 3. Add to registry
-	// 4. Update timestamps
+	// TODO: Validate agent data
 
+	existing, exists := r.agents[agent.ID]
+	if !exists {
+		existing, exists = r.findByURL(agent.URL)
+	}
+	if exists {
+		if existing.ID != agent.ID {
+			r.logger.Debugf("Agent %s has the same URL as %s, updating it", agent.ID, existing.ID)
+		}
+		agent.ID = existing.ID
+		if !existing.DiscoveredAt.IsZero() {
+			agent.DiscoveredAt = existing.DiscoveredAt
+		}
+	} else if err := r.makeRoom(); err != nil {
+		return err
+	}
+	if agent.LastSeen.IsZero() {
+		agent.LastSeen = time.Now()
+	}
 	r.agents[agent.ID] = agent
-	return nil
+
+	if exists {
+		r.publish(EventUpdated, agent)
+	} else {
+		r.publish(EventAdded, agent)
+	}
+	return r.persist(agent)
 }
 
 // Unregister removes an agent from the registry
@@ -58,12 +177,14 @@ func (r *Registry) Unregister(agentID string) error {
 
 	r.logger.Infof("Unregistering agent: %s", agentID)
 
-	if _, exists := r.agents[agentID]; !exists {
+	agent, exists := r.agents[agentID]
+	if !exists {
 		return fmt.Errorf("agent not found: %s", agentID)
 	}
 
 	delete(r.agents, agentID)
-	return nil
+	r.publish(EventRemoved, agent)
+	return r.forget(agentID)
 }
 
 // Get retrieves an agent by ID
@@ -92,6 +213,21 @@ func (r *Registry) List() []*types.Agent {
 	return agents
 }
 
+// Summaries returns a condensed view of every registered agent, sorted by name
+func (r *Registry) Summaries() []types.AgentSummary {
+	agents := r.List()
+
+	summaries := make([]types.AgentSummary, 0, len(agents))
+	for _, agent := range agents {
+		summaries = append(summaries, types.NewAgentSummary(agent))
+	}
+	sort.Slice(summaries, func(i, j int) bool {
+		return summaries[i].Name < summaries[j].Name
+	})
+
+	return summaries
+}
+
 // FindByCapability finds agents with specific capabilities
 func (r *Registry) FindByCapability(capability string) []*types.Agent {
 	r.mu.RLock()
@@ -99,13 +235,29 @@ func (r *Registry) FindByCapability(capability string) []*types.Agent {
 
 	var matches []*types.Agent
 	for _, agent := range r.agents {
-		if agent.Card != nil {
-			caps := agent.Card.GetCapabilities()
-			for _, cap := range caps {
-				if cap == capability {
-					matches = append(matches, agent)
-					break
-				}
+		if agent.Card != nil && agent.Card.Capabilities.Has(capability) {
+			matches = append(matches, agent)
+		}
+	}
+
+	return matches
+}
+
+// FindBySkill returns the agents whose AgentCard advertises a skill with
+// the given ID, compared case-insensitively
+func (r *Registry) FindBySkill(skillID string) []*types.Agent {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var matches []*types.Agent
+	for _, agent := range r.agents {
+		if agent.Card == nil {
+			continue
+		}
+		for _, skill := range agent.Card.Skills {
+			if strings.EqualFold(skill.ID, skillID) {
+				matches = append(matches, agent)
+				break
 			}
 		}
 	}
@@ -113,21 +265,27 @@ func (r *Registry) FindByCapability(capability string) []*types.Agent {
 	return matches
 }
 
-// UpdateStatus updates an agent's status
-func (r *Registry) UpdateStatus(agentID string, status types.AgentStatus) error {
-	r.mu.Lock()
-	defer r.mu.Unlock()
+// FindByInputMode returns the agents whose AgentCard declares support for
+// input of the given MIME type, e.g. "application/json". Wildcards such as
+// "text/*" match on either side; agents that declare no input modes are
+// not returned.
+func (r *Registry) FindByInputMode(mode string) []*types.Agent {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
 
-	agent, exists := r.agents[agentID]
-	if !exists {
-		return fmt.Errorf("agent not found: %s", agentID)
+	var matches []*types.Agent
+	for _, agent := range r.agents {
+		if agent.Card != nil && agent.Card.AcceptsInputMode(mode) {
+			matches = append(matches, agent)
+		}
 	}
 
-	agent.Status = status
-	agent.LastSeen = time.Now()
+	return matches
+}
 
-	r.logger.Debugf("Updated agent %s status to %s", agentID, status)
-	return nil
+// UpdateStatus updates an agent's status
+func (r *Registry) UpdateStatus(agentID string, status types.AgentStatus) error {
+	return r.setStatus(agentID, status, true)
 }
 
 // StartCleanup starts the cleanup goroutine for stale agents
@@ -157,6 +315,10 @@ func (r *Registry) cleanupStaleAgents() {
 		if now.Sub(agent.LastSeen) > staleThreshold {
 			r.logger.Warnf("Removing stale agent: %s", agent.Name)
 			delete(r.agents, id)
+			r.publish(EventRemoved, agent)
+			if err := r.forget(id); err != nil {
+				r.logger.Warnf("%v", err)
+			}
 		}
 	}
 }
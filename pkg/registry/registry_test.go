@@ -0,0 +1,66 @@
+package registry
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/craine-io/openribcage/pkg/a2a/types"
+)
+
+// TestSummaries tests that every agent is summarized, sorted by name
+func TestSummaries(t *testing.T) {
+	r := NewRegistry(time.Minute)
+	assert.Equal(t, []types.AgentSummary{}, r.Summaries())
+
+	require.NoError(t, r.Register(&types.Agent{ID: "k8s", Name: "k8s", URL: "http://k8s:8083", Card: &types.AgentCard{Capabilities: types.AgentCapabilities{Streaming: true}}}))
+	require.NoError(t, r.Register(&types.Agent{ID: "argo", Name: "argo", URL: "http://argo:8083"}))
+	require.NoError(t, r.Register(&types.Agent{ID: "helm", Name: "helm", URL: "http://helm:8083"}))
+
+	summaries := r.Summaries()
+	require.Len(t, summaries, 3)
+	var names []string
+	for _, summary := range summaries {
+		names = append(names, summary.Name)
+	}
+	assert.Equal(t, []string{"argo", "helm", "k8s"}, names)
+	assert.True(t, summaries[2].Streaming)
+	assert.Equal(t, "http://k8s:8083", summaries[2].URL)
+}
+
+// TestRegistryCapacity tests MaxAgents enforcement and eviction
+func TestRegistryCapacity(t *testing.T) {
+	now := time.Now()
+	agent := func(id string, lastSeen time.Time) *types.Agent {
+		return &types.Agent{ID: id, Name: id, LastSeen: lastSeen}
+	}
+
+	reg := NewRegistry(time.Minute)
+	reg.SetMaxAgents(2)
+	require.NoError(t, reg.Register(agent("a", now.Add(-time.Hour))))
+	require.NoError(t, reg.Register(agent("b", now)))
+	assert.Len(t, reg.List(), 2)
+
+	// At capacity, existing agents can still be updated
+	require.NoError(t, reg.Register(agent("a", now)))
+
+	// Beyond capacity, new agents are rejected
+	err := reg.Register(agent("c", now))
+	assert.ErrorIs(t, err, ErrRegistryFull)
+	assert.Len(t, reg.List(), 2)
+
+	t.Run("evict oldest", func(t *testing.T) {
+		reg := NewRegistry(time.Minute)
+		reg.SetMaxAgents(2)
+		reg.SetEvictOldest(true)
+		require.NoError(t, reg.Register(agent("a", now.Add(-time.Hour))))
+		require.NoError(t, reg.Register(agent("b", now)))
+		require.NoError(t, reg.Register(agent("c", now)))
+
+		assert.Len(t, reg.List(), 2)
+		_, err := reg.Get("a")
+		assert.Error(t, err, "least recently seen agent should be evicted")
+	})
+}
@@ -0,0 +1,113 @@
+package registry
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/craine-io/openribcage/pkg/a2a/types"
+)
+
+// ErrNoRoute is returned by Route when no registered agent qualifies for a task
+var ErrNoRoute = errors.New("no agent can handle the task")
+
+// RouteScorer rates how well an agent suits a task; Route picks the
+// highest score among the qualifying agents
+type RouteScorer func(task *types.TaskRequest, agent *types.Agent) float64
+
+// SetRouteScorer sets the scorer Route uses to choose between qualifying
+// agents. With none set, or after setting nil, the most recently seen
+// agent wins.
+func (r *Registry) SetRouteScorer(scorer RouteScorer) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.scorer = scorer
+}
+
+// Route picks the agent to send task to. Only online agents whose
+// AgentCard provides every entry of required qualify, where an entry is
+// either a capability name such as "streaming" or a skill ID. Qualifying
+// agents are ranked by the route scorer, then by how recently they were
+// seen, then by ID. If none qualifies, the returned error wraps ErrNoRoute
+// and says why each agent was passed over.
+func (r *Registry) Route(task *types.TaskRequest, required []string) (*types.Agent, error) {
+	if task == nil {
+		return nil, errors.New("task is required")
+	}
+
+	r.mu.RLock()
+	scorer := r.scorer
+	var candidates []*types.Agent
+	var rejected []string
+	for _, agent := range r.agents {
+		if reason := routeRejection(agent, required); reason != "" {
+			rejected = append(rejected, fmt.Sprintf("%s %s", agent.ID, reason))
+			continue
+		}
+		candidates = append(candidates, agent)
+	}
+	r.mu.RUnlock()
+
+	if len(candidates) == 0 {
+		if len(rejected) == 0 {
+			return nil, fmt.Errorf("%w: no agents are registered", ErrNoRoute)
+		}
+		sort.Strings(rejected)
+		return nil, fmt.Errorf("%w: %s", ErrNoRoute, strings.Join(rejected, "; "))
+	}
+
+	// Score outside the lock so that scorers may query the registry
+	scores := make(map[string]float64, len(candidates))
+	if scorer != nil {
+		for _, agent := range candidates {
+			scores[agent.ID] = scorer(task, agent)
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if scores[a.ID] != scores[b.ID] {
+			return scores[a.ID] > scores[b.ID]
+		}
+		if !a.LastSeen.Equal(b.LastSeen) {
+			return a.LastSeen.After(b.LastSeen)
+		}
+		return a.ID < b.ID
+	})
+
+	r.logger.Debugf("Routed task %s to agent %s (%d candidates)", task.ID, candidates[0].ID, len(candidates))
+	return candidates[0], nil
+}
+
+// routeRejection returns why agent cannot take a task needing required,
+// or "" if it can
+func routeRejection(agent *types.Agent, required []string) string {
+	if agent.Status != types.AgentStatusOnline {
+		return fmt.Sprintf("is %s", agent.Status)
+	}
+	if agent.Card == nil {
+		return "has no AgentCard"
+	}
+
+	var missing []string
+	for _, name := range required {
+		if !agent.Card.Capabilities.Has(name) && !hasSkill(agent.Card, name) {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return "lacks " + strings.Join(missing, ", ")
+	}
+	return ""
+}
+
+// hasSkill reports whether card advertises a skill with the given ID,
+// compared case-insensitively like FindBySkill
+func hasSkill(card *types.AgentCard, skillID string) bool {
+	for _, skill := range card.Skills {
+		if strings.EqualFold(skill.ID, skillID) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,60 @@
+package registry
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/craine-io/openribcage/pkg/a2a/types"
+)
+
+// TestRoute tests picking an agent for a task by capabilities and skills
+func TestRoute(t *testing.T) {
+	now := time.Now()
+	agent := func(id string, status types.AgentStatus, lastSeen time.Time, streaming bool, skills ...string) *types.Agent {
+		card := &types.AgentCard{Name: id, Version: "1.0.0", Capabilities: types.AgentCapabilities{Streaming: streaming}}
+		for _, skill := range skills {
+			card.Skills = append(card.Skills, types.AgentSkill{ID: skill, Name: skill})
+		}
+		return &types.Agent{ID: id, Name: id, URL: "http://" + id + ".example.com", Card: card, Status: status, LastSeen: lastSeen}
+	}
+	task := types.NewTaskRequest(&types.Message{Role: "user", Parts: []types.Part{{Type: "text", Text: "scale the deployment"}}})
+
+	r := NewRegistry(time.Minute)
+	_, err := r.Route(task, nil)
+	assert.ErrorIs(t, err, ErrNoRoute)
+
+	require.NoError(t, r.Register(agent("old", types.AgentStatusOnline, now.Add(-time.Hour), true, "diagnose", "scale")))
+	require.NoError(t, r.Register(agent("new", types.AgentStatusOnline, now, true, "diagnose")))
+	require.NoError(t, r.Register(agent("basic", types.AgentStatusOnline, now, false, "scale")))
+	require.NoError(t, r.Register(agent("down", types.AgentStatusOffline, now.Add(time.Minute), true, "diagnose", "scale")))
+
+	// The most recently seen online agent wins ties
+	got, err := r.Route(task, []string{"streaming"})
+	require.NoError(t, err)
+	assert.Equal(t, "new", got.ID)
+
+	// Skills and capabilities combine, matching skills case-insensitively
+	got, err = r.Route(task, []string{"streaming", "Scale"})
+	require.NoError(t, err)
+	assert.Equal(t, "old", got.ID)
+
+	// A scorer overrides recency
+	r.SetRouteScorer(func(task *types.TaskRequest, a *types.Agent) float64 {
+		return float64(len(a.Card.Skills))
+	})
+	got, err = r.Route(task, []string{"diagnose"})
+	require.NoError(t, err)
+	assert.Equal(t, "old", got.ID)
+	r.SetRouteScorer(nil)
+
+	_, err = r.Route(task, []string{"pushNotifications", "deploy"})
+	require.ErrorIs(t, err, ErrNoRoute)
+	assert.Contains(t, err.Error(), "down is offline")
+	assert.Contains(t, err.Error(), "new lacks pushNotifications, deploy")
+
+	_, err = r.Route(nil, nil)
+	assert.Error(t, err)
+}
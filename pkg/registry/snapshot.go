@@ -0,0 +1,80 @@
+package registry
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+
+	"github.com/craine-io/openribcage/pkg/a2a/types"
+)
+
+// Export returns a JSON snapshot of every registered agent, including
+// cards and status, sorted by ID
+func (r *Registry) Export() ([]byte, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	agents := make([]*types.Agent, 0, len(r.agents))
+	for _, agent := range r.agents {
+		agents = append(agents, agent)
+	}
+	sort.Slice(agents, func(i, j int) bool { return agents[i].ID < agents[j].ID })
+
+	data, err := json.MarshalIndent(agents, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode registry snapshot: %w", err)
+	}
+	return data, nil
+}
+
+// Import registers every agent in a snapshot produced by Export. Invalid
+// entries are skipped rather than aborting the import: the returned error
+// then joins one error per skipped entry, while the valid agents are still
+// registered. An error is also returned if data is not a snapshot at all,
+// in which case nothing is imported.
+func (r *Registry) Import(data []byte) error {
+	var agents []*types.Agent
+	if err := json.Unmarshal(data, &agents); err != nil {
+		return fmt.Errorf("failed to parse registry snapshot: %w", err)
+	}
+
+	var errs []error
+	imported := 0
+	for i, agent := range agents {
+		if err := validateAgent(agent); err != nil {
+			errs = append(errs, fmt.Errorf("skipped agent %d: %w", i, err))
+			continue
+		}
+		if err := r.Register(agent); err != nil {
+			errs = append(errs, fmt.Errorf("skipped agent %s: %w", agent.ID, err))
+			continue
+		}
+		imported++
+	}
+
+	r.logger.Infof("Imported %d of %d agents from snapshot", imported, len(agents))
+	return errors.Join(errs...)
+}
+
+// validateAgent checks that an imported agent can be registered and
+// contacted
+func validateAgent(agent *types.Agent) error {
+	if agent == nil {
+		return errors.New("agent is null")
+	}
+	if agent.ID == "" {
+		return errors.New("agent id is required")
+	}
+	u, err := url.Parse(agent.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("agent %s: invalid url %q", agent.ID, agent.URL)
+	}
+	switch agent.Status {
+	case "", types.AgentStatusOnline, types.AgentStatusOffline, types.AgentStatusError, types.AgentStatusDiscovering:
+	default:
+		return fmt.Errorf("agent %s: unknown status %q", agent.ID, agent.Status)
+	}
+	return nil
+}
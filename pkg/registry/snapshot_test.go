@@ -0,0 +1,47 @@
+package registry
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/craine-io/openribcage/pkg/a2a/types"
+)
+
+// TestRegistryExportImport tests that a snapshot round-trips and that
+// invalid entries are skipped
+func TestRegistryExportImport(t *testing.T) {
+	reg := NewRegistry(time.Minute)
+	require.NoError(t, reg.Register(&types.Agent{
+		ID:     "helm-agent",
+		Name:   "helm-agent",
+		URL:    "http://localhost:8083/api/a2a/kagent/helm-agent",
+		Card:   &types.AgentCard{Name: "helm-agent", Version: "1.0.0"},
+		Status: types.AgentStatusOnline,
+	}))
+
+	data, err := reg.Export()
+	require.NoError(t, err)
+
+	restored := NewRegistry(time.Minute)
+	require.NoError(t, restored.Import(data))
+	agent, err := restored.Get("helm-agent")
+	require.NoError(t, err)
+	assert.Equal(t, "1.0.0", agent.Card.Version)
+	assert.Equal(t, types.AgentStatusOnline, agent.Status)
+
+	mixed := []byte(`[
+		{"id": "k8s-agent", "url": "http://localhost:8083/api/a2a/kagent/k8s-agent"},
+		{"id": "", "url": "http://localhost:8083/api/a2a/kagent/nameless"},
+		{"id": "bad-url", "url": "ftp://example.com"}
+	]`)
+	err = restored.Import(mixed)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "agent id is required")
+	assert.Contains(t, err.Error(), "invalid url")
+	assert.Len(t, restored.List(), 2)
+
+	assert.Error(t, restored.Import([]byte("not json")))
+}
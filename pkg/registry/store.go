@@ -0,0 +1,162 @@
+package registry
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/craine-io/openribcage/pkg/a2a/types"
+)
+
+// Store persists registered agents across restarts
+type Store interface {
+	// Save creates or replaces an agent
+	Save(agent *types.Agent) error
+	// Load returns a single agent by ID
+	Load(agentID string) (*types.Agent, error)
+	// Delete removes an agent; deleting a missing agent is not an error
+	Delete(agentID string) error
+	// LoadAll returns every stored agent
+	LoadAll() ([]*types.Agent, error)
+}
+
+// DefaultStorePath returns the registry file shared between CLI
+// invocations, ~/.openribcage/agents.json
+func DefaultStorePath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate home directory: %w", err)
+	}
+	return filepath.Join(home, ".openribcage", "agents.json"), nil
+}
+
+// FileStore is a Store backed by a single JSON file.
+// Writes replace the file atomically so a crash never leaves it truncated.
+type FileStore struct {
+	mu   sync.Mutex
+	path string
+}
+
+// NewFileStore creates a JSON file store at path. The file is created on first write.
+func NewFileStore(path string) *FileStore {
+	return &FileStore{path: path}
+}
+
+// Save creates or replaces an agent
+func (s *FileStore) Save(agent *types.Agent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	agents, err := s.read()
+	if err != nil {
+		return err
+	}
+	agents[agent.ID] = agent
+	return s.write(agents)
+}
+
+// Load returns a single agent by ID
+func (s *FileStore) Load(agentID string) (*types.Agent, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	agents, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+
+	agent, exists := agents[agentID]
+	if !exists {
+		return nil, fmt.Errorf("agent not found: %s", agentID)
+	}
+	return agent, nil
+}
+
+// Delete removes an agent
+func (s *FileStore) Delete(agentID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	agents, err := s.read()
+	if err != nil {
+		return err
+	}
+	if _, exists := agents[agentID]; !exists {
+		return nil
+	}
+	delete(agents, agentID)
+	return s.write(agents)
+}
+
+// LoadAll returns every stored agent
+func (s *FileStore) LoadAll() ([]*types.Agent, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	agents, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]*types.Agent, 0, len(agents))
+	for _, agent := range agents {
+		list = append(list, agent)
+	}
+	return list, nil
+}
+
+// read loads the agent map from disk; a missing file is an empty store
+func (s *FileStore) read() (map[string]*types.Agent, error) {
+	agents := make(map[string]*types.Agent)
+
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return agents, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read agent store: %w", err)
+	}
+	if len(data) == 0 {
+		return agents, nil
+	}
+
+	if err := json.Unmarshal(data, &agents); err != nil {
+		return nil, fmt.Errorf("failed to parse agent store %s: %w", s.path, err)
+	}
+	return agents, nil
+}
+
+// write atomically replaces the store file with agents
+func (s *FileStore) write(agents map[string]*types.Agent) error {
+	data, err := json.MarshalIndent(agents, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode agent store: %w", err)
+	}
+
+	dir := filepath.Dir(s.path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create agent store directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(dir, filepath.Base(s.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write agent store: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write agent store: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write agent store: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("failed to write agent store: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package registry
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/craine-io/openribcage/pkg/a2a/types"
+)
+
+// TestFileStore tests saving, loading and deleting agents in a JSON file
+func TestFileStore(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "nested", "agents.json")
+	store := NewFileStore(path)
+
+	agents, err := store.LoadAll()
+	require.NoError(t, err)
+	assert.Empty(t, agents, "a missing file is an empty store")
+
+	require.NoError(t, store.Save(&types.Agent{ID: "k8s", Name: "k8s", URL: "http://k8s:8083"}))
+	require.NoError(t, store.Save(&types.Agent{ID: "helm", Name: "helm", URL: "http://helm:8083"}))
+	require.NoError(t, store.Save(&types.Agent{ID: "k8s", Name: "k8s-v2", URL: "http://k8s:8083"}))
+
+	agent, err := store.Load("k8s")
+	require.NoError(t, err)
+	assert.Equal(t, "k8s-v2", agent.Name)
+	_, err = store.Load("missing")
+	assert.ErrorContains(t, err, "agent not found: missing")
+
+	require.NoError(t, store.Delete("helm"))
+	require.NoError(t, store.Delete("helm"), "deleting a missing agent is not an error")
+
+	// A second store on the same file sees the changes
+	agents, err = NewFileStore(path).LoadAll()
+	require.NoError(t, err)
+	require.Len(t, agents, 1)
+	assert.Equal(t, "k8s", agents[0].ID)
+
+	entries, err := os.ReadDir(filepath.Dir(path))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "temporary files must not be left behind")
+}
+
+// TestFileStoreCorrupt tests that an unreadable store file is reported
+func TestFileStoreCorrupt(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "agents.json")
+	require.NoError(t, os.WriteFile(path, []byte("{not json"), 0o600))
+	store := NewFileStore(path)
+
+	_, err := store.LoadAll()
+	assert.ErrorContains(t, err, "failed to parse agent store")
+	assert.Error(t, store.Save(&types.Agent{ID: "k8s"}))
+
+	_, err = NewRegistryWithStore(time.Minute, store)
+	assert.ErrorContains(t, err, "failed to load agents from store")
+
+	require.NoError(t, os.WriteFile(path, nil, 0o600))
+	agents, err := store.LoadAll()
+	require.NoError(t, err)
+	assert.Empty(t, agents)
+}
+
+// TestRegistryWithStore tests that the registry is hydrated from its store
+// and writes changes through to it
+func TestRegistryWithStore(t *testing.T) {
+	store := NewFileStore(filepath.Join(t.TempDir(), "agents.json"))
+	require.NoError(t, store.Save(&types.Agent{ID: "k8s", Name: "k8s", URL: "http://k8s:8083"}))
+
+	r, err := NewRegistryWithStore(time.Minute, store)
+	require.NoError(t, err)
+	_, err = r.Get("k8s")
+	require.NoError(t, err)
+
+	require.NoError(t, r.Register(&types.Agent{ID: "helm", Name: "helm", URL: "http://helm:8083"}))
+	require.NoError(t, r.Unregister("k8s"))
+
+	agents, err := store.LoadAll()
+	require.NoError(t, err)
+	require.Len(t, agents, 1)
+	assert.Equal(t, "helm", agents[0].ID)
+	assert.False(t, agents[0].LastSeen.IsZero())
+
+	restarted, err := NewRegistryWithStore(time.Minute, store)
+	require.NoError(t, err)
+	assert.Len(t, restarted.List(), 1)
+}
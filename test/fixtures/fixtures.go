@@ -0,0 +1,70 @@
+// Package fixtures provides named AgentCard samples for tests.
+//
+// Fixtures are embedded JSON files in this directory, addressed by file
+// name without the .json extension:
+//
+//   - minimal: the smallest card accepted by Discoverer.Validate
+//   - full: a card using every field of the A2A schema
+//   - streaming: an agent advertising streaming
+//   - auth: an agent requiring OAuth2 authentication
+//   - invalid_missing_name, invalid_endpoint: cards that fail validation
+//   - agentcard_test: the legacy integration test card
+package fixtures
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/craine-io/openribcage/pkg/a2a/types"
+)
+
+//go:embed *.json
+var files embed.FS
+
+// Raw returns the bytes of the named fixture
+func Raw(name string) ([]byte, error) {
+	data, err := files.ReadFile(name + ".json")
+	if err != nil {
+		return nil, fmt.Errorf("unknown fixture %q: %w", name, err)
+	}
+	return data, nil
+}
+
+// Card parses the named fixture into an AgentCard without validating it
+func Card(name string) (*types.AgentCard, error) {
+	data, err := Raw(name)
+	if err != nil {
+		return nil, err
+	}
+
+	var card types.AgentCard
+	if err := json.Unmarshal(data, &card); err != nil {
+		return nil, fmt.Errorf("failed to parse fixture %q: %w", name, err)
+	}
+	return &card, nil
+}
+
+// MustCard is like Card but panics on error, for use in test tables
+func MustCard(name string) *types.AgentCard {
+	card, err := Card(name)
+	if err != nil {
+		panic(err)
+	}
+	return card
+}
+
+// Names returns the names of all fixtures, sorted
+func Names() []string {
+	entries, _ := files.ReadDir(".")
+
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		names = append(names, strings.TrimSuffix(entry.Name(), path.Ext(entry.Name())))
+	}
+	sort.Strings(names)
+	return names
+}
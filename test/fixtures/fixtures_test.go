@@ -0,0 +1,52 @@
+package fixtures
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/craine-io/openribcage/pkg/agentcard"
+)
+
+// strict lists the fixtures that also conform to the A2A JSON Schema
+var strict = map[string]bool{"full": true, "streaming": true, "auth": true}
+
+// TestFixturesValidate tests that every embedded fixture parses, and that
+// it passes Discoverer.Validate unless its name starts with "invalid_".
+// The fixtures in strict must pass ValidateStrict as well.
+func TestFixturesValidate(t *testing.T) {
+	names := Names()
+	require.NotEmpty(t, names)
+	discoverer := agentcard.NewDiscoverer(time.Second)
+
+	for _, name := range names {
+		t.Run(name, func(t *testing.T) {
+			card, err := Card(name)
+			require.NoError(t, err)
+			assert.Equal(t, card, MustCard(name))
+
+			if strings.HasPrefix(name, "invalid_") {
+				assert.Error(t, discoverer.Validate(card))
+			} else {
+				assert.NoError(t, discoverer.Validate(card))
+			}
+			if strict[name] {
+				raw, err := Raw(name)
+				require.NoError(t, err)
+				assert.NoError(t, discoverer.ValidateStrict(card, raw))
+			}
+		})
+	}
+}
+
+// TestUnknownFixture tests the errors for a missing fixture
+func TestUnknownFixture(t *testing.T) {
+	_, err := Raw("missing")
+	assert.ErrorContains(t, err, `unknown fixture "missing"`)
+	_, err = Card("missing")
+	assert.Error(t, err)
+	assert.Panics(t, func() { MustCard("missing") })
+}